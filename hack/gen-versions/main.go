@@ -11,6 +11,14 @@ import (
 
 const versionsGoPath = "pkg/components/versions.go"
 
+// dexVersion is the upstream Dex release run as the manager's identity broker. It is not part of the Calico or
+// Tigera releases, so it is pinned here.
+const dexVersion = "v2.22.0"
+
+// eckOperatorV1Version is the ECK operator release that manages the v1 Elasticsearch and Kibana APIs. It is deployed
+// instead of the Tigera release's ECK operator on clusters that serve those APIs, so it is pinned here.
+const eckOperatorV1Version = "1.0.1"
+
 func main() {
 	eeVersionsPath := flag.String("ee-versions", "", "path to os versions file")
 	osVersionsPath := flag.String("os-versions", "", "path to ee versions file")
@@ -58,6 +66,12 @@ func (c Components) get(component string) string {
 	panic(fmt.Sprintf("couldn't find value for '%s'", component))
 }
 
+// getOptional returns the version of a component that is not part of every release, or an empty string if the
+// release does not include it.
+func (c Components) getOptional(component string) string {
+	return c[component].Version
+}
+
 func loadVersions(versionsPath string) (Components, error) {
 	var c struct {
 		Components Components
@@ -125,12 +139,15 @@ func writeVersions(osVersions, eeVersions Components) error {
 		`	VersionManagerProxy   = "` + eeVersions.get("voltron") + `"`,
 		`	VersionManagerEsProxy = "` + eeVersions.get("es-proxy") + `"`,
 		"",
+		"	// Identity broker image.",
+		`	VersionDex = "` + dexVersion + `"`,
+		"",
 		"	// ECK Elasticsearch images",
 		`	VersionECKOperator = "` + eeVersions.get("elasticsearch-operator") + `"`,
+		`	VersionECKOperatorV1 = "` + eckOperatorV1Version + `"`,
 		`	VersionECKElasticsearch = "` + eeVersions.get("elasticsearch") + `"`,
 		`	VersionECKKibana = "` + eeVersions.get("eck-kibana") + `"`,
 		`	VersionKibana = "` + eeVersions.get("kibana") + `"`,
-		`	VersionEsCurator = "` + eeVersions.get("es-curator") + `"`,
 		"",
 		"	// Multicluster tunnel image.",
 		`	VersionGuardian = "` + eeVersions.get("guardian") + `"`,
@@ -4,6 +4,7 @@ import (
 	esalpha1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1alpha1"
 	kibanaalpha1 "github.com/elastic/cloud-on-k8s/pkg/apis/kibana/v1alpha1"
 	configv1 "github.com/openshift/api/config/v1"
+	routev1 "github.com/openshift/api/route/v1"
 	ocsv1 "github.com/openshift/api/security/v1"
 	tigera "github.com/tigera/api/pkg/apis/projectcalico/v3"
 	operator "github.com/tigera/operator/pkg/apis/operator/v1"
@@ -21,6 +22,7 @@ func init() {
 	AddToSchemes = append(AddToSchemes, apiextensions.AddToScheme)
 	AddToSchemes = append(AddToSchemes, tigera.AddToScheme)
 	AddToSchemes = append(AddToSchemes, ocsv1.AddToScheme)
+	AddToSchemes = append(AddToSchemes, routev1.AddToScheme)
 	AddToSchemes = append(AddToSchemes, esalpha1.SchemeBuilder.AddToScheme)
 	AddToSchemes = append(AddToSchemes, kibanaalpha1.SchemeBuilder.AddToScheme)
 }
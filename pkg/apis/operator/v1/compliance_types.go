@@ -4,6 +4,20 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// ComplianceReportTypeName is the name of a compliance report type.
+type ComplianceReportTypeName string
+
+const (
+	ComplianceReportTypeInventory     ComplianceReportTypeName = "inventory"
+	ComplianceReportTypeNetworkAccess ComplianceReportTypeName = "network-access"
+	ComplianceReportTypePolicyAudit   ComplianceReportTypeName = "policy-audit"
+	ComplianceReportTypeCISBenchmark  ComplianceReportTypeName = "cis-benchmark"
+)
+
+// ComplianceReportTemplateUISummaryKey is the key in a report template ConfigMap that holds the UI summary template.
+// All other keys in the ConfigMap are treated as download templates, with the key used as the template name.
+const ComplianceReportTemplateUISummaryKey = "ui-summary.json"
+
 const (
 	ComplianceStatusReady = "Ready"
 )
@@ -11,6 +25,76 @@ const (
 // ComplianceSpec defines the desired state of Tigera compliance reporting capabilities.
 // +k8s:openapi-gen=true
 type ComplianceSpec struct {
+	// ReportTypes configures the report types that are installed. The built-in report types (inventory, network-access,
+	// policy-audit and cis-benchmark) are installed unless disabled here. Additional report types may be added by
+	// providing a ConfigMap containing their templates.
+	// +optional
+	ReportTypes []ComplianceReportType `json:"reportTypes,omitempty"`
+
+	// Reports is a list of reports that are generated on a schedule from the installed report types.
+	// +optional
+	Reports []ComplianceReport `json:"reports,omitempty"`
+
+	// Snapshotter configures the compliance snapshotter.
+	// +optional
+	Snapshotter *ComplianceSnapshotter `json:"snapshotter,omitempty"`
+
+	// Benchmarker configures the compliance benchmarker.
+	// +optional
+	Benchmarker *ComplianceBenchmarker `json:"benchmarker,omitempty"`
+}
+
+// ComplianceReportType configures a single compliance report type.
+type ComplianceReportType struct {
+	// Name is the name of the report type. This is either one of the built-in report types or the name of a custom report type.
+	// +kubebuilder:validation:MinLength=1
+	Name ComplianceReportTypeName `json:"name"`
+
+	// Disabled prevents the report type from being installed.
+	// Default: false
+	// +optional
+	Disabled bool `json:"disabled,omitempty"`
+
+	// TemplatesConfigMap is the name of a ConfigMap in the tigera-operator namespace containing the templates for this report type. The ui-summary.json key holds the UI summary template and every other key is used as a download template. This is required for custom report types and replaces the templates of a built-in report type.
+	// +optional
+	TemplatesConfigMap string `json:"templatesConfigMap,omitempty"`
+}
+
+// ComplianceReport configures a report that is generated on a schedule.
+type ComplianceReport struct {
+	// Name is the name of the GlobalReport.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// ReportType is the name of the report type used to generate the report.
+	// +kubebuilder:validation:MinLength=1
+	ReportType ComplianceReportTypeName `json:"reportType"`
+
+	// Schedule is the cron schedule on which the report is generated, e.g. "0 0 * * *".
+	// +kubebuilder:validation:MinLength=1
+	Schedule string `json:"schedule"`
+
+	// JobNodeSelector restricts the nodes the report generation jobs are scheduled on.
+	// +optional
+	JobNodeSelector map[string]string `json:"jobNodeSelector,omitempty"`
+}
+
+// ComplianceSnapshotter configures the compliance snapshotter.
+type ComplianceSnapshotter struct {
+	// Hour is the hour of the day (0-23, UTC) at which the snapshotter takes its daily snapshot of the
+	// configuration of the cluster.
+	// Default: 0
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=23
+	Hour *int32 `json:"hour,omitempty"`
+}
+
+// ComplianceBenchmarker configures the compliance benchmarker.
+type ComplianceBenchmarker struct {
+	// NodeSelector restricts the nodes that are benchmarked. It is added to the default Linux node selector.
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
 }
 
 // ComplianceStatus defines the observed state of Tigera compliance reporting capabilities.
@@ -11,6 +11,122 @@ const (
 // IntrusionDetectionSpec defines the desired state of Tigera intrusion detection capabilities.
 // +k8s:openapi-gen=true
 type IntrusionDetectionSpec struct {
+	// ThreatFeeds is a list of threat feeds that are periodically pulled and used to detect suspicious traffic.
+	// +optional
+	ThreatFeeds []ThreatFeed `json:"threatFeeds,omitempty"`
+
+	// AlertForwarding configures destinations that security alerts are forwarded to.
+	// +optional
+	AlertForwarding *AlertForwarding `json:"alertForwarding,omitempty"`
+
+	// AnomalyDetection configures the anomaly detection jobs installed in Elasticsearch.
+	// +optional
+	AnomalyDetection *AnomalyDetectionSpec `json:"anomalyDetection,omitempty"`
+}
+
+// ThreatFeed defines a feed of suspicious IP addresses that is pulled over HTTP.
+type ThreatFeed struct {
+	// Name is the name of the GlobalThreatFeed.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// URL is the http or https location the feed is pulled from.
+	// +kubebuilder:validation:MinLength=1
+	URL string `json:"url"`
+
+	// PullInterval is how frequently the feed is pulled, e.g. "1h".
+	// Default: 24h
+	// +optional
+	PullInterval *metav1.Duration `json:"pullInterval,omitempty"`
+
+	// Auth configures a header, read from a Secret, that is sent when pulling the feed.
+	// +optional
+	Auth *HTTPHeaderSecret `json:"auth,omitempty"`
+
+	// GlobalNetworkSetLabels are the labels applied to the GlobalNetworkSet the feed is synced to. If not specified,
+	// the feed is not synced to a GlobalNetworkSet.
+	// +optional
+	GlobalNetworkSetLabels map[string]string `json:"globalNetworkSetLabels,omitempty"`
+}
+
+// HTTPHeaderSecret defines an HTTP header whose value is read from a key in a Secret in the tigera-operator namespace.
+type HTTPHeaderSecret struct {
+	// HeaderName is the name of the HTTP header.
+	// Default: Authorization
+	// +optional
+	HeaderName string `json:"headerName,omitempty"`
+
+	// SecretName is the name of the Secret in the tigera-operator namespace.
+	// +kubebuilder:validation:MinLength=1
+	SecretName string `json:"secretName"`
+
+	// SecretKey is the key in the Secret that holds the header value.
+	// +kubebuilder:validation:MinLength=1
+	SecretKey string `json:"secretKey"`
+}
+
+// AlertForwarding defines the destinations that security alerts are forwarded to.
+type AlertForwarding struct {
+	// Webhooks is a list of HTTP endpoints that alerts are posted to as JSON.
+	// +optional
+	Webhooks []AlertWebhook `json:"webhooks,omitempty"`
+
+	// If specified, alerts are forwarded to syslog.
+	// +optional
+	Syslog *SyslogStoreSpec `json:"syslog,omitempty"`
+}
+
+// AlertWebhook defines an HTTP endpoint that alerts are posted to.
+type AlertWebhook struct {
+	// Name identifies the webhook.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// URL is the http or https location alerts are posted to.
+	// +kubebuilder:validation:MinLength=1
+	URL string `json:"url"`
+
+	// Auth configures a header, read from a Secret, that is sent with each alert.
+	// +optional
+	Auth *HTTPHeaderSecret `json:"auth,omitempty"`
+}
+
+// AnomalyDetectionSpec configures the anomaly detection jobs installed in Elasticsearch.
+type AnomalyDetectionSpec struct {
+	// DisableDefaultJobs prevents the default anomaly detection jobs from being installed.
+	// Default: false
+	// +optional
+	DisableDefaultJobs bool `json:"disableDefaultJobs,omitempty"`
+
+	// Detectors is the list of anomaly detection jobs that are run. The job of each detector is opened and its
+	// datafeed started, and detectors removed from the list are stopped. Jobs that are not listed are left as they are.
+	// +optional
+	Detectors []AnomalyDetector `json:"detectors,omitempty"`
+}
+
+// AnomalyDetector configures an anomaly detection job in Elasticsearch.
+type AnomalyDetector struct {
+	// Name is the ID of the Elasticsearch machine learning job, such as one of the default jobs.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// TrainingPeriod is how far back in the logs the model of the detector is trained from when it is first started,
+	// e.g. "168h". After that, the model keeps training on the logs it runs detection on.
+	// Default: 168h
+	// +optional
+	TrainingPeriod *metav1.Duration `json:"trainingPeriod,omitempty"`
+
+	// DetectionInterval is how often the detector queries the logs that arrived since its previous run and runs
+	// detection on them, e.g. "5m".
+	// Default: the interval Elasticsearch derives from the bucket span of the job
+	// +optional
+	DetectionInterval *metav1.Duration `json:"detectionInterval,omitempty"`
+
+	// ModelMemoryLimit is the most memory the model of the detector may use in Elasticsearch, e.g. "512mb". The job is
+	// restarted to apply a new limit.
+	// Default: the limit the job was installed with
+	// +optional
+	ModelMemoryLimit string `json:"modelMemoryLimit,omitempty"`
 }
 
 // IntrusionDetectionStatus defines the observed state of Tigera intrusion detection capabilities.
@@ -18,6 +134,46 @@ type IntrusionDetectionSpec struct {
 type IntrusionDetectionStatus struct {
 	// State provides user-readable status.
 	State string `json:"state,omitempty"`
+
+	// ThreatFeeds reports the threat feeds that are failing to be pulled.
+	// +optional
+	ThreatFeeds []ThreatFeedStatus `json:"threatFeeds,omitempty"`
+
+	// Detectors reports the state of each anomaly detector.
+	// +optional
+	Detectors []AnomalyDetectorStatus `json:"detectors,omitempty"`
+}
+
+// ThreatFeedStatus reports the errors encountered while pulling a threat feed.
+type ThreatFeedStatus struct {
+	// Name is the name of the GlobalThreatFeed.
+	Name string `json:"name"`
+
+	// Errors are the error messages reported for the feed.
+	// +optional
+	Errors []string `json:"errors,omitempty"`
+}
+
+// AnomalyDetectorStatus reports the state of an anomaly detection job in Elasticsearch.
+type AnomalyDetectorStatus struct {
+	// Name is the ID of the Elasticsearch machine learning job.
+	Name string `json:"name"`
+
+	// JobState is the state of the job, e.g. opened, closed or failed.
+	// +optional
+	JobState string `json:"jobState,omitempty"`
+
+	// DatafeedState is the state of the datafeed of the job, e.g. started or stopped.
+	// +optional
+	DatafeedState string `json:"datafeedState,omitempty"`
+
+	// LatestRecordTime is the time of the most recent log the detector has processed.
+	// +optional
+	LatestRecordTime *metav1.Time `json:"latestRecordTime,omitempty"`
+
+	// Error describes why the detector is not running, if it is not.
+	// +optional
+	Error string `json:"error,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -28,6 +28,9 @@ type AdditionalLogStoreSpec struct {
 	// If specified, enables exporting of flow, audit, and DNS logs to syslog.
 	// +optional
 	Syslog *SyslogStoreSpec `json:"syslog,omitempty"`
+	// If specified, enables exporting of flow, audit, and DNS logs to the Splunk HTTP Event Collector.
+	// +optional
+	Splunk *SplunkStoreSpec `json:"splunk,omitempty"`
 }
 
 type AdditionalLogSourceSpec struct {
@@ -62,6 +65,13 @@ type SyslogStoreSpec struct {
 	PacketSize *int32 `json:"packetsize,omitempty"`
 }
 
+// SplunkStoreSpec defines configuration for exporting logs to the Splunk HTTP Event Collector. The token of the
+// collector is read from the "token" field of the log-collector-splunk-credentials Secret in the operator namespace.
+type SplunkStoreSpec struct {
+	// Location of the Splunk HTTP Event Collector. example: https://1.2.3.4:8088
+	Endpoint string `json:"endpoint"`
+}
+
 // EksConfigSpec defines configuration for fetching EKS audit logs.
 type EksCloudwatchLogsSpec struct {
 	// AWS Region EKS cluster is hosted in.
@@ -2,6 +2,7 @@ package v1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -23,14 +24,95 @@ type LogStorageStatus struct {
 	// KibanaHash represents the current revision and configuration of the installed Kibana dashboard. This
 	// is an opaque string which can be monitored for changes to perform actions when Kibana is modified.
 	KibanaHash string `json:"kibanaHash,omitempty"`
+
+	// IndexLifecyclePolicies reports whether the index lifecycle policy of each log type was applied to the
+	// Elasticsearch cluster.
+	// +optional
+	IndexLifecyclePolicies []IndexLifecyclePolicyStatus `json:"indexLifecyclePolicies,omitempty"`
+
+	// External reports whether the operator can reach the external Elasticsearch cluster, if one is configured.
+	// +optional
+	External *ExternalElasticsearchStatus `json:"external,omitempty"`
+
+	// Kibana reports the health of Kibana and the import of its saved objects.
+	// +optional
+	Kibana *KibanaStatus `json:"kibana,omitempty"`
+}
+
+// KibanaStatus reports the state of Kibana.
+type KibanaStatus struct {
+	// Enabled is false if Kibana is disabled, or not deployed because an external Elasticsearch cluster is used.
+	Enabled bool `json:"enabled"`
+
+	// Health is the health of Kibana as reported by the Elasticsearch operator. It is green once all replicas
+	// are available.
+	// +optional
+	Health string `json:"health,omitempty"`
+
+	// AvailableReplicas is the number of Kibana replicas that are ready.
+	// +optional
+	AvailableReplicas int32 `json:"availableReplicas,omitempty"`
+
+	// SavedObjects reports the import of each ConfigMap of saved objects.
+	// +optional
+	SavedObjects []KibanaSavedObjectsStatus `json:"savedObjects,omitempty"`
+}
+
+// KibanaSavedObjectsStatus reports the import of a ConfigMap of saved objects into Kibana.
+type KibanaSavedObjectsStatus struct {
+	// ConfigMapName is the name of the ConfigMap.
+	ConfigMapName string `json:"configMapName"`
+
+	// Hash identifies the contents of the ConfigMap that were last imported.
+	// +optional
+	Hash string `json:"hash,omitempty"`
+
+	// Message describes why the contents of the ConfigMap could not be imported.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// ExternalElasticsearchStatus reports the connectivity check of an external Elasticsearch cluster.
+type ExternalElasticsearchStatus struct {
+	// Reachable is true if the operator connected and authenticated to the cluster.
+	Reachable bool `json:"reachable"`
+
+	// Version is the Elasticsearch version of the cluster.
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// Message describes why the cluster could not be reached.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// IndexLifecyclePolicyStatus reports the state of an index lifecycle policy.
+type IndexLifecyclePolicyStatus struct {
+	// Name is the name of the policy.
+	Name string `json:"name"`
+
+	// Applied is true once the policy and its index template are applied to the Elasticsearch cluster and no index
+	// managed by the policy has failed a lifecycle step.
+	Applied bool `json:"applied"`
+
+	// Message describes why the policy is not applied.
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // LogStorageSpec defines the desired state of Tigera flow and DNS log storage.
 // +k8s:openapi-gen=true
 type LogStorageSpec struct {
 	// Nodes defines the configuration for a set of identical Elasticsearch cluster nodes, each of type master, data, and ingest.
+	// It must be set unless External is.
 	Nodes *Nodes `json:"nodes,omitempty"`
 
+	// External stores logs in an existing Elasticsearch cluster instead of one run by the operator. Neither
+	// Elasticsearch nor Kibana is deployed, and the Elasticsearch users of the components are created in the
+	// external cluster.
+	// +optional
+	External *ExternalElasticsearch `json:"external,omitempty"`
+
 	// Index defines the configuration for the indices in the Elasticsearch cluster.
 	// +optional
 	Indices *Indices `json:"indices,omitempty"`
@@ -38,6 +120,203 @@ type LogStorageSpec struct {
 	// Retention defines how long data is retained in the Elasticsearch cluster before it is cleared.
 	// +optional
 	Retention *Retention `json:"retention,omitempty"`
+
+	// StorageClassName is the name of the StorageClass the volumes of the Elasticsearch nodes are provisioned with.
+	// The volumes can only be grown later on if the StorageClass allows volume expansion.
+	// Default: tigera-elasticsearch
+	// +optional
+	StorageClassName string `json:"storageClassName,omitempty"`
+
+	// IndexLifecycle configures when log indices are rolled over and how much of the Elasticsearch storage logs
+	// may take up. How long logs are kept is configured by Retention.
+	// +optional
+	IndexLifecycle *IndexLifecycle `json:"indexLifecycle,omitempty"`
+
+	// Snapshots configures periodic snapshots of the Elasticsearch indices to a snapshot repository, from which
+	// indices can be restored with a LogStorageRestore.
+	// +optional
+	Snapshots *Snapshots `json:"snapshots,omitempty"`
+
+	// Users configures the Elasticsearch users that the components access Elasticsearch as. The operator creates
+	// a user with only the privileges it needs for each component.
+	// +optional
+	Users *ElasticsearchUsers `json:"users,omitempty"`
+
+	// Kibana configures the Kibana instance that is deployed with the Elasticsearch cluster run by the operator.
+	// +optional
+	Kibana *Kibana `json:"kibana,omitempty"`
+}
+
+// Kibana defines the Kibana instance of the Elasticsearch cluster. Kibana is served by the manager under
+// /tigera-kibana, which is always its base path. Users sign in to Kibana with their Elasticsearch credentials.
+// Single sign-on with the login of the manager is not supported yet, since it needs an OpenID Connect realm in
+// Elasticsearch that the operator does not configure.
+type Kibana struct {
+	// Disabled removes Kibana. The manager no longer links to Kibana, and the intrusion detection dashboards are
+	// not installed.
+	// +optional
+	Disabled bool `json:"disabled,omitempty"`
+
+	// Replicas is the number of Kibana pods to run.
+	// Default: 1
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// Resources defines the resource requirements of the Kibana container.
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// SavedObjectConfigMaps lists ConfigMaps in the tigera-operator namespace whose keys each hold saved objects,
+	// such as dashboards and visualizations, in the NDJSON format of a Kibana export. They are imported once
+	// Kibana is running, and again whenever a ConfigMap changes. Existing objects with the same ID are
+	// overwritten.
+	// +optional
+	SavedObjectConfigMaps []string `json:"savedObjectConfigMaps,omitempty"`
+}
+
+// ElasticsearchUsers defines how the Elasticsearch users of the components are managed.
+type ElasticsearchUsers struct {
+	// PasswordRotationDays is the number of days after which the password of each user is replaced. The
+	// components using a user are restarted with its new password.
+	// Default: 90
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	PasswordRotationDays *int32 `json:"passwordRotationDays,omitempty"`
+}
+
+// ExternalElasticsearch defines how to reach an Elasticsearch cluster that is not run by the operator.
+type ExternalElasticsearch struct {
+	// Endpoint is the HTTPS URL of the Elasticsearch cluster, as in https://logs.example.com:9243.
+	// +kubebuilder:validation:Pattern=^https://
+	Endpoint string `json:"endpoint"`
+
+	// CASecretName is the name of a Secret in the tigera-operator namespace whose ca.crt key holds the PEM encoded
+	// CA bundle that the certificate of the cluster is verified with.
+	CASecretName string `json:"caSecretName"`
+
+	// CredentialsSecretName is the name of a Secret in the tigera-operator namespace whose username and password
+	// keys hold the credentials of an Elasticsearch user allowed to manage security, index templates and index
+	// lifecycle policies.
+	CredentialsSecretName string `json:"credentialsSecretName"`
+}
+
+// IndexLifecycle defines the rollover of log indices and the storage limits of logs.
+type IndexLifecycle struct {
+	// RolloverSize is the size of the primary shards of an index at which logs are written to a new index.
+	// Default: 30Gi
+	// +optional
+	RolloverSize *resource.Quantity `json:"rolloverSize,omitempty"`
+
+	// RolloverAge is the age of an index at which logs are written to a new index, in days (d) or hours (h).
+	// Default: 1d
+	// +optional
+	// +kubebuilder:validation:Pattern=^[1-9][0-9]*(d|h)$
+	RolloverAge string `json:"rolloverAge,omitempty"`
+
+	// MaxTotalStoragePercent is the share of the Elasticsearch storage that all logs may take up. Once it is
+	// exceeded, the oldest indices are removed.
+	// Default: 80
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	MaxTotalStoragePercent *int32 `json:"maxTotalStoragePercent,omitempty"`
+
+	// MaxLogsStoragePercent is the share of the Elasticsearch storage that flow and DNS logs may take up. They
+	// often take up the most storage, so this limit lets compliance and security data be kept longer. Once it is
+	// exceeded, the oldest flow and DNS log indices are removed. It must not exceed MaxTotalStoragePercent.
+	// Default: 70
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	MaxLogsStoragePercent *int32 `json:"maxLogsStoragePercent,omitempty"`
+}
+
+// Snapshots defines the snapshot repository of the Elasticsearch cluster and when snapshots are taken.
+type Snapshots struct {
+	// Repository defines where snapshots are stored.
+	Repository SnapshotRepository `json:"repository"`
+
+	// Schedule is the cron schedule on which snapshots are taken.
+	// Default: 0 1 * * *
+	// +optional
+	Schedule string `json:"schedule,omitempty"`
+
+	// Retention is the number of days snapshots are kept before they are removed.
+	// Default: 30
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	Retention *int32 `json:"retention,omitempty"`
+
+	// Indices lists the index patterns that are included in snapshots.
+	// Default: tigera_secure_ee_*
+	// +optional
+	Indices []string `json:"indices,omitempty"`
+}
+
+// SnapshotRepositoryType is the type of storage snapshots are stored in.
+type SnapshotRepositoryType string
+
+const (
+	// SnapshotRepositoryFilesystem stores snapshots on a volume that is shared by all Elasticsearch nodes.
+	SnapshotRepositoryFilesystem SnapshotRepositoryType = "Filesystem"
+
+	// SnapshotRepositoryS3 stores snapshots in an S3 compatible object store.
+	SnapshotRepositoryS3 SnapshotRepositoryType = "S3"
+)
+
+// SnapshotRepository defines the storage of snapshots. The field matching the type must be set.
+type SnapshotRepository struct {
+	// Type is the type of storage snapshots are stored in.
+	// +kubebuilder:validation:Enum=Filesystem;S3
+	Type SnapshotRepositoryType `json:"type"`
+
+	// Filesystem configures the volume snapshots are stored on when the type is Filesystem.
+	// +optional
+	Filesystem *FilesystemSnapshotRepository `json:"filesystem,omitempty"`
+
+	// S3 configures the object store snapshots are stored in when the type is S3.
+	// +optional
+	S3 *S3SnapshotRepository `json:"s3,omitempty"`
+}
+
+// FilesystemSnapshotRepository defines the volume that is mounted on every Elasticsearch node to store snapshots on.
+type FilesystemSnapshotRepository struct {
+	// StorageClassName is the name of the StorageClass the volume is provisioned with. The StorageClass must
+	// support the ReadWriteMany access mode, since the volume is shared by all Elasticsearch nodes.
+	// +kubebuilder:validation:MinLength=1
+	StorageClassName string `json:"storageClassName"`
+
+	// StorageSize defines the size of the volume.
+	// Default: 100Gi
+	// +optional
+	StorageSize *resource.Quantity `json:"storageSize,omitempty"`
+}
+
+// S3SnapshotRepository defines the S3 compatible object store snapshots are stored in.
+type S3SnapshotRepository struct {
+	// Bucket is the name of the bucket snapshots are stored in. The bucket must exist.
+	// +kubebuilder:validation:MinLength=1
+	Bucket string `json:"bucket"`
+
+	// BasePath is the path within the bucket snapshots are stored under.
+	// +optional
+	BasePath string `json:"basePath,omitempty"`
+
+	// Endpoint is the URL of the object store, for stores other than AWS S3, e.g. https://minio.example.com:9000.
+	// Default: the AWS S3 endpoint
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// PathStyleAccess addresses the bucket in the path of requests rather than in the host name, as most S3
+	// compatible object stores require.
+	// +optional
+	PathStyleAccess bool `json:"pathStyleAccess,omitempty"`
+
+	// CredentialsSecretName is the name of the Secret in the tigera-operator namespace holding the credentials of
+	// the object store, under the keys access-key and secret-key.
+	// +kubebuilder:validation:MinLength=1
+	CredentialsSecretName string `json:"credentialsSecretName"`
 }
 
 // Nodes defines the configuration for a set of identical Elasticsearch cluster nodes, each of type master, data, and ingest.
@@ -48,8 +327,86 @@ type Nodes struct {
 	// ResourceRequirements defines the resource limits and requirements for the Elasticsearch cluster.
 	// +optional
 	ResourceRequirements *corev1.ResourceRequirements `json:"resourceRequirements,omitempty"`
+
+	// StorageSize defines the size of the volume of each node. It takes precedence over a storage request in
+	// ResourceRequirements. The size can be increased, but not decreased.
+	// Default: 10Gi
+	// +optional
+	StorageSize *resource.Quantity `json:"storageSize,omitempty"`
+
+	// NodeSets configures dedicated master, data and ingest nodes. When set, Count and ResourceRequirements are
+	// ignored.
+	// +optional
+	NodeSets *NodeSets `json:"nodeSets,omitempty"`
+
+	// ZoneAwareness spreads the Elasticsearch nodes over the given zones and allocates the replicas of each shard to
+	// a different zone than the primary.
+	// +optional
+	ZoneAwareness *ZoneAwareness `json:"zoneAwareness,omitempty"`
+
+	// PodAntiAffinity configures how strictly Elasticsearch nodes are kept off the same Kubernetes node. If not
+	// set, Elasticsearch nodes are preferably scheduled on different Kubernetes nodes.
+	// +optional
+	// +kubebuilder:validation:Enum=Preferred;Required
+	PodAntiAffinity PodAntiAffinityType `json:"podAntiAffinity,omitempty"`
 }
 
+// NodeSets defines dedicated sets of Elasticsearch nodes for each node role.
+type NodeSets struct {
+	// Master configures the master eligible nodes, which manage the cluster state. Use an odd count of at least
+	// three for a highly available cluster.
+	Master NodeSet `json:"master"`
+
+	// Data configures the nodes that hold the indices.
+	Data NodeSet `json:"data"`
+
+	// Ingest configures the nodes that pre-process documents before they are indexed. If not set, the data nodes
+	// take the ingest role.
+	// +optional
+	Ingest *NodeSet `json:"ingest,omitempty"`
+}
+
+// NodeSet defines a set of identical Elasticsearch nodes.
+type NodeSet struct {
+	// Count defines the number of nodes in the set.
+	// +kubebuilder:validation:Minimum=1
+	Count int32 `json:"count"`
+
+	// ResourceRequirements defines the resource limits and requirements of the Elasticsearch container of the nodes.
+	// +optional
+	ResourceRequirements *corev1.ResourceRequirements `json:"resourceRequirements,omitempty"`
+
+	// StorageSize defines the size of the volume of each node. The size can be increased, but not decreased.
+	// Default: 10Gi
+	// +optional
+	StorageSize *resource.Quantity `json:"storageSize,omitempty"`
+}
+
+// ZoneAwareness defines the zones that Elasticsearch nodes are spread over.
+type ZoneAwareness struct {
+	// NodeLabel is the label of the Kubernetes nodes holding their zone.
+	// Default: failure-domain.beta.kubernetes.io/zone
+	// +optional
+	NodeLabel string `json:"nodeLabel,omitempty"`
+
+	// Zones lists the values of the node label to spread the Elasticsearch nodes over. The nodes of each set are
+	// divided evenly over the zones.
+	// +kubebuilder:validation:MinItems=2
+	// +kubebuilder:validation:MaxItems=10
+	Zones []string `json:"zones"`
+}
+
+// PodAntiAffinityType defines how strictly Elasticsearch nodes are kept off the same Kubernetes node.
+type PodAntiAffinityType string
+
+const (
+	// PodAntiAffinityPreferred schedules Elasticsearch nodes on different Kubernetes nodes when possible.
+	PodAntiAffinityPreferred PodAntiAffinityType = "Preferred"
+
+	// PodAntiAffinityRequired never schedules two Elasticsearch nodes on the same Kubernetes node.
+	PodAntiAffinityRequired PodAntiAffinityType = "Required"
+)
+
 // Indices defines the configuration for the indices in an Elasticsearch cluster.
 type Indices struct {
 	// Replicas defines how many replicas each index will have. See https://www.elastic.co/guide/en/elasticsearch/reference/current/scalability.html
@@ -57,19 +414,24 @@ type Indices struct {
 	Replicas *int32 `json:"replicas,omitempty"`
 }
 
-// Retention defines how long data is retained in an Elasticsearch cluster before it is cleared.
+// Retention defines how long data is retained in an Elasticsearch cluster before it is cleared. The retention
+// periods are applied by the index lifecycle policies of the log types.
 type Retention struct {
 	// Flows configures the retention period for flow logs, in days.  Logs written on a day that started at least this long ago
 	// are removed.  To keep logs for at least x days, use a retention period of x+1.
 	// Default: 8
 	// +optional
-	Flows *int32 `json:"flows"`
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=3650
+	Flows *int32 `json:"flows,omitempty"`
 
 	// AuditReports configures the retention period for audit logs, in days.  Logs written on a day that started at least this long ago are
 	// removed.  To keep logs for at least x days, use a retention period of x+1.
 	// Default: 367
 	// +optional
-	AuditReports *int32 `json:"auditReports"`
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=3650
+	AuditReports *int32 `json:"auditReports,omitempty"`
 
 	// Snapshots configures the retention period for snapshots, in days. Snapshots are periodic captures
 	// of resources which along with audit events are used to generate reports.
@@ -78,7 +440,9 @@ type Retention struct {
 	// removed.  To keep logs for at least x days, use a retention period of x+1.
 	// Default: 367
 	// +optional
-	Snapshots *int32 `json:"snapshots"`
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=3650
+	Snapshots *int32 `json:"snapshots,omitempty"`
 
 	// ComplianceReports configures the retention period for compliance reports, in days. Reports are output
 	// from the analysis of the system state and audit events for compliance reporting.
@@ -87,7 +451,42 @@ type Retention struct {
 	// removed.  To keep logs for at least x days, use a retention period of x+1.
 	// Default: 367
 	// +optional
-	ComplianceReports *int32 `json:"complianceReports"`
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=3650
+	ComplianceReports *int32 `json:"complianceReports,omitempty"`
+
+	// DNSLogs configures the retention period for DNS logs, in days.  Logs written on a day that started at least this long ago
+	// are removed.  To keep logs for at least x days, use a retention period of x+1.
+	// Default: 8
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=3650
+	DNSLogs *int32 `json:"dnsLogs,omitempty"`
+
+	// IDSEvents configures the retention period for the events raised by intrusion detection, in days.  Events written
+	// on a day that started at least this long ago are removed.  To keep events for at least x days, use a retention
+	// period of x+1.
+	// Default: 365
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=3650
+	IDSEvents *int32 `json:"idsEvents,omitempty"`
+
+	// L7Logs configures the retention period for L7 logs, in days.  Logs written on a day that started at least this long ago
+	// are removed.  To keep logs for at least x days, use a retention period of x+1.
+	// Default: 8
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=3650
+	L7Logs *int32 `json:"l7Logs,omitempty"`
+
+	// BGPLogs configures the retention period for BGP logs, in days.  Logs written on a day that started at least this long ago
+	// are removed.  To keep logs for at least x days, use a retention period of x+1.
+	// Default: 8
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=3650
+	BGPLogs *int32 `json:"bgpLogs,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -123,6 +522,11 @@ func (ls LogStorage) Replicas() int {
 	return int(*ls.Spec.Indices.Replicas)
 }
 
+// KibanaEnabled returns true if Kibana is deployed with the Elasticsearch cluster.
+func (ls LogStorage) KibanaEnabled() bool {
+	return ls.Spec.External == nil && (ls.Spec.Kibana == nil || !ls.Spec.Kibana.Disabled)
+}
+
 func init() {
 	SchemeBuilder.Register(&LogStorage{}, &LogStorageList{})
 }
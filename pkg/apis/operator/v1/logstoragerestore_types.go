@@ -0,0 +1,92 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	LogStorageRestorePending   = "Pending"
+	LogStorageRestoreRunning   = "Running"
+	LogStorageRestoreCompleted = "Completed"
+	LogStorageRestoreFailed    = "Failed"
+)
+
+// LogStorageRestoreSpec defines the indices to restore from a snapshot.
+// +k8s:openapi-gen=true
+type LogStorageRestoreSpec struct {
+	// Snapshot is the name of the snapshot to restore from.
+	// Default: the most recent successful snapshot
+	// +optional
+	Snapshot string `json:"snapshot,omitempty"`
+
+	// Indices lists the indices or index patterns to restore.
+	// +kubebuilder:validation:MinItems=1
+	Indices []string `json:"indices"`
+
+	// RenamePattern is a regular expression matching the names of the restored indices. Together with
+	// RenameReplacement it restores indices under a different name, which is needed while an open index with
+	// the same name exists.
+	// +optional
+	RenamePattern string `json:"renamePattern,omitempty"`
+
+	// RenameReplacement is the name the indices matching RenamePattern are restored under. It can refer to the
+	// groups of the pattern, as in restored_$1.
+	// +optional
+	RenameReplacement string `json:"renameReplacement,omitempty"`
+}
+
+// LogStorageRestoreStatus defines the observed state of a restore.
+// +k8s:openapi-gen=true
+type LogStorageRestoreStatus struct {
+	// State is Pending, Running, Completed or Failed.
+	// +optional
+	State string `json:"state,omitempty"`
+
+	// Snapshot is the name of the snapshot the indices are restored from.
+	// +optional
+	Snapshot string `json:"snapshot,omitempty"`
+
+	// Message describes why the restore failed.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// StartTime is the time at which the restore started.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is the time at which the restore completed or failed.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +genclient
+// +genclient:nonNamespaced
+
+// LogStorageRestore restores indices of the LogStorage Elasticsearch cluster from a snapshot taken as configured
+// in the snapshots section of the LogStorage. Restores are run one at a time, in the order they are created.
+// A restore is run once; create a new LogStorageRestore to restore again.
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="State",type="string",JSONPath=".status.state",description="The state of the restore"
+// +kubebuilder:printcolumn:name="Snapshot",type="string",JSONPath=".status.snapshot",description="The snapshot the indices are restored from"
+type LogStorageRestore struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   LogStorageRestoreSpec   `json:"spec,omitempty"`
+	Status LogStorageRestoreStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// LogStorageRestoreList contains a list of LogStorageRestore.
+type LogStorageRestoreList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []LogStorageRestore `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&LogStorageRestore{}, &LogStorageRestoreList{})
+}
@@ -0,0 +1,115 @@
+package v1
+
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ManagedClusterSpec defines the desired state of ManagedCluster
+// +k8s:openapi-gen=true
+type ManagedClusterSpec struct {
+	// ManagementClusterAddr is the address at which the managed cluster reaches the tunnel endpoint of this
+	// management cluster. Ex.: "10.128.0.10:30449". It is written into the generated manifest.
+	// +kubebuilder:validation:MinLength=1
+	ManagementClusterAddr string `json:"managementClusterAddr"`
+
+	// LogStorage overrides the LogStorage settings of the management cluster for the logs of this managed cluster.
+	// +optional
+	LogStorage *ManagedClusterLogStorage `json:"logStorage,omitempty"`
+}
+
+// ManagedClusterLogStorage defines how the logs of a managed cluster are stored in the Elasticsearch of the
+// management cluster.
+type ManagedClusterLogStorage struct {
+	// IndexPrefix is the name the logs of this managed cluster are indexed under, as in
+	// tigera_secure_ee_flows.<indexPrefix>.<date>. It must be unique among the managed clusters and differ from the
+	// name the management cluster indexes its own logs under. It is written into the generated manifest.
+	// Default: the name of the ManagedCluster
+	// +optional
+	// +kubebuilder:validation:Pattern=^[a-z0-9]([-a-z0-9]*[a-z0-9])?$
+	// +kubebuilder:validation:MaxLength=63
+	IndexPrefix string `json:"indexPrefix,omitempty"`
+
+	// Retention overrides the retention periods of the LogStorage for the logs of this managed cluster. Periods
+	// that are not set are taken from the LogStorage.
+	// +optional
+	Retention *Retention `json:"retention,omitempty"`
+
+	// IngestionQuota limits how much log data of this managed cluster is kept in Elasticsearch. Once the indices
+	// of the managed cluster exceed this size, its oldest indices are removed, so that a noisy managed cluster
+	// cannot exhaust the storage shared with the other clusters.
+	// Default: no quota
+	// +optional
+	IngestionQuota *resource.Quantity `json:"ingestionQuota,omitempty"`
+}
+
+// ManagedClusterStatus defines the observed state of ManagedCluster
+// +k8s:openapi-gen=true
+type ManagedClusterStatus struct {
+	// ManifestSecretName is the name of the Secret in the tigera-operator namespace holding the manifest to
+	// apply on the managed cluster, under the key manifest.yaml. The manifest contains the
+	// ManagementClusterConnection and the tunnel certificate of the managed cluster.
+	// +optional
+	ManifestSecretName string `json:"manifestSecretName,omitempty"`
+
+	// CertificateFingerprint is the fingerprint of the tunnel certificate issued to the managed cluster.
+	// +optional
+	CertificateFingerprint string `json:"certificateFingerprint,omitempty"`
+
+	// CertificateExpiry is the time at which the tunnel certificate of the managed cluster expires. A new
+	// certificate is issued ahead of expiry, after which the manifest must be applied on the managed cluster again.
+	// +optional
+	CertificateExpiry *metav1.Time `json:"certificateExpiry,omitempty"`
+
+	// RenewalPending is true while the manifest holds a renewed tunnel certificate that the managed cluster has
+	// not switched to yet. Apply the manifest on the managed cluster, then annotate this ManagedCluster with
+	// certs.tigera.io/acknowledged-fingerprint set to the PendingCertificateFingerprint to complete the renewal.
+	// The managed cluster reports the fingerprint of the certificate it uses in its ManagementClusterConnection.
+	// +optional
+	RenewalPending bool `json:"renewalPending,omitempty"`
+
+	// PendingCertificateFingerprint is the fingerprint of the renewed tunnel certificate while RenewalPending.
+	// +optional
+	PendingCertificateFingerprint string `json:"pendingCertificateFingerprint,omitempty"`
+
+	// Connected is true while the managed cluster has an open tunnel to this management cluster.
+	// +optional
+	Connected bool `json:"connected,omitempty"`
+
+	// LastSeen is the last time the managed cluster was observed to be connected. While the managed cluster stays
+	// connected it is refreshed every 10 minutes.
+	// +optional
+	LastSeen *metav1.Time `json:"lastSeen,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +genclient
+// +genclient:nonNamespaced
+
+// ManagedCluster registers a managed cluster with a management cluster. The operator issues the managed
+// cluster a tunnel certificate and generates the manifest that connects it to this management cluster.
+// ManagedClusters are only reconciled when the cluster management type is Management.
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Connected",type="boolean",JSONPath=".status.connected",description="Whether the managed cluster is connected"
+// +kubebuilder:printcolumn:name="Last Seen",type="date",JSONPath=".status.lastSeen",description="The last time the managed cluster was connected"
+type ManagedCluster struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ManagedClusterSpec   `json:"spec,omitempty"`
+	Status ManagedClusterStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ManagedClusterList contains a list of ManagedCluster.
+type ManagedClusterList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ManagedCluster `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ManagedCluster{}, &ManagedClusterList{})
+}
@@ -12,6 +12,74 @@ type ManagementClusterConnectionSpec struct {
 	// should be able to access this address. This field is used by managed clusters only.
 	// +optional
 	ManagementClusterAddr string `json:"managementClusterAddr,omitempty"`
+
+	// ProxyURL is the URL of the HTTP proxy through which the managed cluster reaches the management cluster,
+	// e.g. "http://proxy.example.com:3128". Traffic to in-cluster services does not use the proxy.
+	// +optional
+	ProxyURL string `json:"proxyURL,omitempty"`
+
+	// Tunnel tunes how the tunnel to the management cluster is kept alive and re-established.
+	// +optional
+	Tunnel *TunnelConfig `json:"tunnel,omitempty"`
+}
+
+// TunnelConfig defines the keepalive and reconnect behavior of the tunnel to the management cluster.
+// By default the certificate of the management cluster is verified using the management cluster CA from the
+// tigera-managed-cluster-connection Secret. To verify it with another CA, create a ConfigMap named
+// tigera-management-cluster-ca-bundle in the tigera-operator namespace holding the CA bundle under the key ca.crt.
+// +k8s:openapi-gen=true
+type TunnelConfig struct {
+	// KeepAlive enables keepalive probes on the tunnel connection, which detect a broken tunnel and keep idle
+	// connections open through proxies and load balancers.
+	// Default: true
+	// +optional
+	KeepAlive *bool `json:"keepAlive,omitempty"`
+
+	// KeepAliveInterval is the time between keepalive probes.
+	// +optional
+	KeepAliveInterval *metav1.Duration `json:"keepAliveInterval,omitempty"`
+
+	// DialTimeout is how long a single attempt to connect to the management cluster may take.
+	// +optional
+	DialTimeout *metav1.Duration `json:"dialTimeout,omitempty"`
+
+	// ReconnectInterval is the time between attempts to re-establish the tunnel.
+	// +optional
+	ReconnectInterval *metav1.Duration `json:"reconnectInterval,omitempty"`
+
+	// ReconnectAttempts is the number of consecutive failed attempts after which the tunnel agent exits and
+	// is restarted.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	ReconnectAttempts *int32 `json:"reconnectAttempts,omitempty"`
+}
+
+// ManagementClusterConnectionStatus defines the observed state of ManagementClusterConnection
+// +k8s:openapi-gen=true
+type ManagementClusterConnectionStatus struct {
+	// Connected is true when the tunnel agent is running and the management cluster is reachable.
+	// +optional
+	Connected bool `json:"connected,omitempty"`
+
+	// Message describes why the connection is down.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// LastTransitionTime is the last time Connected changed. The status is only written when the connection
+	// changes, not on every probe.
+	// +optional
+	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
+
+	// CertificateFingerprint is the fingerprint of the tunnel certificate of this cluster. After applying a manifest
+	// with a renewed certificate, the ManagedCluster in the management cluster is annotated with this fingerprint to
+	// acknowledge the renewal.
+	// +optional
+	CertificateFingerprint string `json:"certificateFingerprint,omitempty"`
+
+	// CertificateExpiry is the time at which the tunnel certificate of this cluster expires. The management
+	// cluster issues a new certificate ahead of expiry; apply its regenerated manifest to rotate the certificate.
+	// +optional
+	CertificateExpiry *metav1.Time `json:"certificateExpiry,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -21,11 +89,14 @@ type ManagementClusterConnectionSpec struct {
 // ManagementClusterConnection represents a link between a managed cluster and a management cluster. At most one
 // instance of this resource is supported. It must be named "tigera-secure".
 // +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Connected",type="boolean",JSONPath=".status.connected",description="Whether the management cluster is connected"
 type ManagementClusterConnection struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
-	Spec ManagementClusterConnectionSpec `json:"spec,omitempty"`
+	Spec   ManagementClusterConnectionSpec   `json:"spec,omitempty"`
+	Status ManagementClusterConnectionStatus `json:"status,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -10,14 +10,369 @@ type ManagerSpec struct {
 	// Auth defines the authentication strategy for the Tigera Secure manager GUI.
 	// +optional
 	Auth *Auth `json:"auth,omitempty"`
+
+	// ClusterName is the name displayed for this cluster in the Tigera Secure manager GUI.
+	// Default: cluster
+	// +optional
+	ClusterName string `json:"clusterName,omitempty"`
+
+	// Features enables or disables optional features of the Tigera Secure manager GUI.
+	// +optional
+	Features *ManagerFeatures `json:"features,omitempty"`
+
+	// LogLevels configures the log level of the manager containers.
+	// +optional
+	LogLevels *ManagerLogLevels `json:"logLevels,omitempty"`
+
+	// Replicas is the number of manager pods to run. When more than one replica is requested the
+	// deployment is rolled one pod at a time so that the manager remains available during node drains.
+	// Default: 1
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// IdentityBroker deploys an identity broker (Dex) that lets users log in to the manager with
+	// LDAP, SAML or GitHub accounts. When set, auth type must be OIDC and the manager's OIDC
+	// settings are configured to use the broker.
+	// +optional
+	IdentityBroker *IdentityBroker `json:"identityBroker,omitempty"`
+
+	// Exposure configures how the manager is reachable from outside the cluster. By default the
+	// manager is only exposed through a ClusterIP service.
+	// +optional
+	Exposure *ManagerExposure `json:"exposure,omitempty"`
+
+	// RoleBindings grant manager users and identity provider groups one of the predefined
+	// manager profiles. The operator renders the ClusterRoles and ClusterRoleBindings for each
+	// binding and removes them when the binding is removed.
+	// +optional
+	RoleBindings []UIRoleBinding `json:"roleBindings,omitempty"`
+}
+
+// UIRoleBinding grants a set of users and groups a predefined manager profile.
+// +k8s:openapi-gen=true
+type UIRoleBinding struct {
+	// Name identifies the binding. It is used in the names of the generated RBAC resources and
+	// must be a valid DNS label.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Profile is the set of permissions granted.
+	// +kubebuilder:validation:Enum=Viewer,NetworkAdmin,ComplianceAuditor,TierEditor
+	Profile UIRoleProfile `json:"profile"`
+
+	// Groups are the identity provider groups granted the profile. Groups are matched as
+	// seen by Kubernetes, so when Auth.GroupsPrefix is set it is prepended to each group.
+	// +optional
+	Groups []string `json:"groups,omitempty"`
+
+	// Users are the users granted the profile. When Auth.UsernamePrefix is set it is prepended
+	// to each user.
+	// +optional
+	Users []string `json:"users,omitempty"`
+
+	// Tiers are the Calico policy tiers the binding may edit. Required, and only valid, for the
+	// TierEditor profile.
+	// +optional
+	Tiers []string `json:"tiers,omitempty"`
+}
+
+// UIRoleProfile is a predefined set of manager permissions. Valid options are:
+// Viewer, NetworkAdmin, ComplianceAuditor, TierEditor
+type UIRoleProfile string
+
+const (
+	// UIRoleProfileViewer grants read-only access to policies, flow logs and the manager.
+	UIRoleProfileViewer UIRoleProfile = "Viewer"
+	// UIRoleProfileNetworkAdmin grants full access to policies and tiers.
+	UIRoleProfileNetworkAdmin UIRoleProfile = "NetworkAdmin"
+	// UIRoleProfileComplianceAuditor grants read-only access plus access to compliance reports.
+	UIRoleProfileComplianceAuditor UIRoleProfile = "ComplianceAuditor"
+	// UIRoleProfileTierEditor grants read-only access plus edit access to the policies in Tiers.
+	UIRoleProfileTierEditor UIRoleProfile = "TierEditor"
+)
+
+// ManagerExposure defines how the manager is exposed outside the cluster.
+// +k8s:openapi-gen=true
+type ManagerExposure struct {
+	// Type is the kind of exposure. Route is only supported on OpenShift.
+	// +kubebuilder:validation:Enum=Ingress,Route,LoadBalancer,NodePort
+	Type ManagerExposureType `json:"type"`
+
+	// Ingress configures the Ingress when type is Ingress.
+	// +optional
+	Ingress *ManagerIngress `json:"ingress,omitempty"`
+
+	// Route configures the OpenShift Route when type is Route.
+	// +optional
+	Route *ManagerRoute `json:"route,omitempty"`
+
+	// NodePort is the node port the manager service listens on when type is NodePort or
+	// LoadBalancer. If not set, a port is allocated by Kubernetes.
+	// +optional
+	NodePort *int32 `json:"nodePort,omitempty"`
+}
+
+// ManagerExposureType is the kind of manager exposure. Valid options are:
+// Ingress, Route, LoadBalancer, NodePort
+type ManagerExposureType string
+
+const (
+	ManagerExposureTypeIngress      ManagerExposureType = "Ingress"
+	ManagerExposureTypeRoute        ManagerExposureType = "Route"
+	ManagerExposureTypeLoadBalancer ManagerExposureType = "LoadBalancer"
+	ManagerExposureTypeNodePort     ManagerExposureType = "NodePort"
+)
+
+// ManagerIngress defines the Ingress exposing the manager. The Ingress controller must
+// connect to the manager over HTTPS.
+// +k8s:openapi-gen=true
+type ManagerIngress struct {
+	// Host is the host name users reach the manager at.
+	// +kubebuilder:validation:MinLength=1
+	Host string `json:"host"`
+
+	// Class is the Ingress class, set as the kubernetes.io/ingress.class annotation.
+	// +optional
+	Class string `json:"class,omitempty"`
+
+	// TLSSecretName is the name of a Secret in the tigera-manager namespace holding the certificate
+	// served by the Ingress. If not set, the Ingress controller's default certificate is used.
+	// +optional
+	TLSSecretName string `json:"tlsSecretName,omitempty"`
+
+	// Annotations are added to the Ingress, for example to configure the Ingress controller.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// ManagerRoute defines the OpenShift Route exposing the manager. TLS is passed through to the manager.
+// +k8s:openapi-gen=true
+type ManagerRoute struct {
+	// Host is the host name users reach the manager at. If not set, OpenShift generates one.
+	// +optional
+	Host string `json:"host,omitempty"`
+}
+
+// IdentityBroker defines the configuration of the identity broker.
+// +k8s:openapi-gen=true
+type IdentityBroker struct {
+	// ManagerDomain is the URL at which users reach the manager, for example https://manager.example.com.
+	// The broker is served by the manager at <managerDomain>/dex. It may be omitted when the manager
+	// is exposed through an Ingress or a Route with a host, in which case that host is used.
+	// +optional
+	ManagerDomain string `json:"managerDomain,omitempty"`
+
+	// Connectors lists the upstream identity providers users can log in with.
+	// +kubebuilder:validation:MinItems=1
+	Connectors []IdentityConnector `json:"connectors"`
+}
+
+// IdentityConnector configures an upstream identity provider of the identity broker. Exactly one of
+// ldap, saml or github must be set, matching the type.
+// +k8s:openapi-gen=true
+type IdentityConnector struct {
+	// Name identifies the connector and is shown on the login page.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Type is the type of the identity provider.
+	// +kubebuilder:validation:Enum=LDAP,SAML,GitHub
+	Type IdentityConnectorType `json:"type"`
+
+	// SecretName is the name of a Secret in the tigera-operator namespace holding the connector's
+	// credentials. LDAP uses the keys bindDN, bindPW and optionally ca.crt, SAML uses ca.crt and
+	// GitHub uses clientID and clientSecret.
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+
+	// LDAP configures an LDAP connector.
+	// +optional
+	LDAP *LDAPConnector `json:"ldap,omitempty"`
+
+	// SAML configures a SAML 2.0 connector.
+	// +optional
+	SAML *SAMLConnector `json:"saml,omitempty"`
+
+	// GitHub configures a GitHub connector.
+	// +optional
+	GitHub *GitHubConnector `json:"github,omitempty"`
+}
+
+// IdentityConnectorType is the type of an identity broker connector. Valid
+// options are: LDAP, SAML, GitHub
+type IdentityConnectorType string
+
+const (
+	IdentityConnectorTypeLDAP   IdentityConnectorType = "LDAP"
+	IdentityConnectorTypeSAML   IdentityConnectorType = "SAML"
+	IdentityConnectorTypeGitHub IdentityConnectorType = "GitHub"
+)
+
+// LDAPConnector defines an LDAP identity provider.
+// +k8s:openapi-gen=true
+type LDAPConnector struct {
+	// Host is the host and port of the LDAP server, for example ldap.example.com:636.
+	// +kubebuilder:validation:MinLength=1
+	Host string `json:"host"`
+
+	// StartTLS connects without TLS and upgrades the connection with StartTLS.
+	// +optional
+	StartTLS bool `json:"startTLS,omitempty"`
+
+	// UserSearch configures how users are looked up.
+	UserSearch LDAPUserSearch `json:"userSearch"`
+
+	// GroupSearch configures how the groups of a user are looked up.
+	// +optional
+	GroupSearch *LDAPGroupSearch `json:"groupSearch,omitempty"`
+}
+
+// LDAPUserSearch defines how users are looked up in LDAP.
+// +k8s:openapi-gen=true
+type LDAPUserSearch struct {
+	// BaseDN is the directory to search users in, for example ou=people,dc=example,dc=com.
+	// +kubebuilder:validation:MinLength=1
+	BaseDN string `json:"baseDN"`
+
+	// Filter is an optional filter applied when searching users, for example (objectClass=person).
+	// +optional
+	Filter string `json:"filter,omitempty"`
+
+	// UsernameAttribute is the attribute matched against the username entered by the user.
+	// Default: uid
+	// +optional
+	UsernameAttribute string `json:"usernameAttribute,omitempty"`
+
+	// EmailAttribute is the attribute holding the user's email address.
+	// Default: mail
+	// +optional
+	EmailAttribute string `json:"emailAttribute,omitempty"`
+
+	// NameAttribute is the attribute holding the user's display name.
+	// Default: cn
+	// +optional
+	NameAttribute string `json:"nameAttribute,omitempty"`
+}
+
+// LDAPGroupSearch defines how the groups of a user are looked up in LDAP.
+// +k8s:openapi-gen=true
+type LDAPGroupSearch struct {
+	// BaseDN is the directory to search groups in, for example ou=groups,dc=example,dc=com.
+	// +kubebuilder:validation:MinLength=1
+	BaseDN string `json:"baseDN"`
+
+	// Filter is an optional filter applied when searching groups, for example (objectClass=groupOfNames).
+	// +optional
+	Filter string `json:"filter,omitempty"`
+
+	// UserAttribute is the user attribute whose value is stored in the group's member attribute.
+	// Default: DN
+	// +optional
+	UserAttribute string `json:"userAttribute,omitempty"`
+
+	// GroupAttribute is the group attribute listing its members.
+	// Default: member
+	// +optional
+	GroupAttribute string `json:"groupAttribute,omitempty"`
+
+	// NameAttribute is the attribute holding the group name.
+	// Default: cn
+	// +optional
+	NameAttribute string `json:"nameAttribute,omitempty"`
+}
+
+// SAMLConnector defines a SAML 2.0 identity provider.
+// +k8s:openapi-gen=true
+type SAMLConnector struct {
+	// SSOURL is the URL of the identity provider's single sign-on endpoint.
+	// +kubebuilder:validation:MinLength=1
+	SSOURL string `json:"ssoURL"`
+
+	// EntityIssuer is the issuer value sent in authentication requests.
+	// +optional
+	EntityIssuer string `json:"entityIssuer,omitempty"`
+
+	// UsernameAttribute is the assertion attribute holding the username.
+	// Default: name
+	// +optional
+	UsernameAttribute string `json:"usernameAttribute,omitempty"`
+
+	// EmailAttribute is the assertion attribute holding the user's email address.
+	// Default: email
+	// +optional
+	EmailAttribute string `json:"emailAttribute,omitempty"`
+
+	// GroupsAttribute is the assertion attribute holding the user's groups.
+	// +optional
+	GroupsAttribute string `json:"groupsAttribute,omitempty"`
+}
+
+// GitHubConnector defines GitHub as an identity provider.
+// +k8s:openapi-gen=true
+type GitHubConnector struct {
+	// Orgs restricts login to members of these GitHub organizations. The user's teams in
+	// these organizations are used as groups.
+	// +optional
+	Orgs []string `json:"orgs,omitempty"`
+}
+
+// ManagerFeatures defines the optional features of the Tigera Secure manager GUI.
+// +k8s:openapi-gen=true
+type ManagerFeatures struct {
+	// PolicyRecommendation enables policy recommendations in the manager.
+	// Default: true
+	// +optional
+	PolicyRecommendation *bool `json:"policyRecommendation,omitempty"`
+
+	// ApplicationLayerPolicy enables the application layer policy features in the manager.
+	// Default: true
+	// +optional
+	ApplicationLayerPolicy *bool `json:"applicationLayerPolicy,omitempty"`
+}
+
+// ManagerLogLevels defines the log levels of the containers in the manager pod.
+// +k8s:openapi-gen=true
+type ManagerLogLevels struct {
+	// Manager is the log level of the manager container.
+	// Default: Info
+	// +optional
+	Manager LogLevel `json:"manager,omitempty"`
+
+	// Proxy is the log level of the manager proxy (Voltron) container.
+	// Default: Info
+	// +optional
+	Proxy LogLevel `json:"proxy,omitempty"`
+
+	// ESProxy is the log level of the Elasticsearch proxy container.
+	// Default: Info
+	// +optional
+	ESProxy LogLevel `json:"esProxy,omitempty"`
 }
 
+// LogLevel represents the verbosity of a component's logs. Valid
+// options are: Error, Warning, Info, Debug
+// +kubebuilder:validation:Enum=Error,Warning,Info,Debug
+type LogLevel string
+
+const (
+	LogLevelError   LogLevel = "Error"
+	LogLevelWarning LogLevel = "Warning"
+	LogLevelInfo    LogLevel = "Info"
+	LogLevelDebug   LogLevel = "Debug"
+)
+
 // ManagerStatus defines the observed state of the Tigera Secure manager GUI.
 // +k8s:openapi-gen=true
 type ManagerStatus struct {
 	// The last successfully applied authentication configuration.
 	// +optional
 	Auth *Auth `json:"auth,omitempty"`
+
+	// URL is the external URL of the manager, when it is exposed outside the cluster and the
+	// address is known.
+	// +optional
+	URL string `json:"url,omitempty"`
 }
 
 // Auth defines authentication configuration.
@@ -35,12 +390,52 @@ type Auth struct {
 	// ClientId configures the OAuth2/OIDC client ID to use for OAuth2 or OIDC login.
 	// +optional
 	ClientID string `json:"clientID,omitempty"`
+
+	// Scopes lists the scopes requested from the OIDC issuer in addition to openid. Each scope
+	// must be advertised in the issuer's discovery document.
+	// Default: email, profile
+	// +optional
+	Scopes []string `json:"scopes,omitempty"`
+
+	// UsernameClaim is the ID token claim used as the user's name.
+	// Default: email
+	// +optional
+	UsernameClaim string `json:"usernameClaim,omitempty"`
+
+	// UsernamePrefix is prepended to the username claim to build the Kubernetes username, for example "oidc:".
+	// +optional
+	UsernamePrefix string `json:"usernamePrefix,omitempty"`
+
+	// GroupsClaim is the ID token claim holding the user's groups.
+	// +optional
+	GroupsClaim string `json:"groupsClaim,omitempty"`
+
+	// GroupsPrefix is prepended to each group to build the Kubernetes group names, for example "oidc:".
+	// +optional
+	GroupsPrefix string `json:"groupsPrefix,omitempty"`
+
+	// KubernetesAuthentication configures how OIDC tokens are authenticated against the Kubernetes API.
+	// APIServer means the Kubernetes API server is configured with --oidc-* flags matching this
+	// configuration. Proxy means the manager proxy verifies the token itself and impersonates the user.
+	// Default: APIServer
+	// +kubebuilder:validation:Enum=APIServer,Proxy
+	// +optional
+	KubernetesAuthentication KubernetesAuthenticationType `json:"kubernetesAuthentication,omitempty"`
 }
 
 // AuthType represents the type of authentication to use. Valid
 // options are: Token, Basic, OIDC, OAuth
 type AuthType string
 
+// KubernetesAuthenticationType represents how OIDC users are authenticated against the
+// Kubernetes API. Valid options are: APIServer, Proxy
+type KubernetesAuthenticationType string
+
+const (
+	KubernetesAuthenticationAPIServer KubernetesAuthenticationType = "APIServer"
+	KubernetesAuthenticationProxy     KubernetesAuthenticationType = "Proxy"
+)
+
 const (
 	AuthTypeToken = "Token"
 	AuthTypeBasic = "Basic"
@@ -14,6 +14,20 @@ type TigeraStatusStatus struct {
 	// Conditions represents the latest observed set of conditions for this component. A component may be one or more of
 	// Available, Progressing, or Degraded.
 	Conditions []TigeraStatusCondition `json:"conditions"`
+
+	// Certificates lists the certificates managed for this component and when they expire.
+	// +optional
+	Certificates []CertificateStatus `json:"certificates,omitempty"`
+}
+
+// CertificateStatus reports the expiry of a certificate managed for a component.
+// +k8s:openapi-gen=true
+type CertificateStatus struct {
+	// Name identifies the certificate.
+	Name string `json:"name"`
+
+	// NotAfter is the time at which the certificate expires.
+	NotAfter metav1.Time `json:"notAfter"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
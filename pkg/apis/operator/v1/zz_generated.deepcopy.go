@@ -6,6 +6,8 @@ package v1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	resource "k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -136,6 +138,11 @@ func (in *AdditionalLogStoreSpec) DeepCopyInto(out *AdditionalLogStoreSpec) {
 		*out = new(SyslogStoreSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Splunk != nil {
+		in, out := &in.Splunk, &out.Splunk
+		*out = new(SplunkStoreSpec)
+		**out = **in
+	}
 	return
 }
 
@@ -149,9 +156,132 @@ func (in *AdditionalLogStoreSpec) DeepCopy() *AdditionalLogStoreSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertForwarding) DeepCopyInto(out *AlertForwarding) {
+	*out = *in
+	if in.Webhooks != nil {
+		in, out := &in.Webhooks, &out.Webhooks
+		*out = make([]AlertWebhook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Syslog != nil {
+		in, out := &in.Syslog, &out.Syslog
+		*out = new(SyslogStoreSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertForwarding.
+func (in *AlertForwarding) DeepCopy() *AlertForwarding {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertForwarding)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertWebhook) DeepCopyInto(out *AlertWebhook) {
+	*out = *in
+	if in.Auth != nil {
+		in, out := &in.Auth, &out.Auth
+		*out = new(HTTPHeaderSecret)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertWebhook.
+func (in *AlertWebhook) DeepCopy() *AlertWebhook {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertWebhook)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AnomalyDetectionSpec) DeepCopyInto(out *AnomalyDetectionSpec) {
+	*out = *in
+	if in.Detectors != nil {
+		in, out := &in.Detectors, &out.Detectors
+		*out = make([]AnomalyDetector, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AnomalyDetectionSpec.
+func (in *AnomalyDetectionSpec) DeepCopy() *AnomalyDetectionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AnomalyDetectionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AnomalyDetector) DeepCopyInto(out *AnomalyDetector) {
+	*out = *in
+	if in.TrainingPeriod != nil {
+		in, out := &in.TrainingPeriod, &out.TrainingPeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.DetectionInterval != nil {
+		in, out := &in.DetectionInterval, &out.DetectionInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AnomalyDetector.
+func (in *AnomalyDetector) DeepCopy() *AnomalyDetector {
+	if in == nil {
+		return nil
+	}
+	out := new(AnomalyDetector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AnomalyDetectorStatus) DeepCopyInto(out *AnomalyDetectorStatus) {
+	*out = *in
+	if in.LatestRecordTime != nil {
+		in, out := &in.LatestRecordTime, &out.LatestRecordTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AnomalyDetectorStatus.
+func (in *AnomalyDetectorStatus) DeepCopy() *AnomalyDetectorStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AnomalyDetectorStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Auth) DeepCopyInto(out *Auth) {
 	*out = *in
+	if in.Scopes != nil {
+		in, out := &in.Scopes, &out.Scopes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -201,12 +331,29 @@ func (in *CalicoNetworkSpec) DeepCopy() *CalicoNetworkSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateStatus) DeepCopyInto(out *CertificateStatus) {
+	*out = *in
+	in.NotAfter.DeepCopyInto(&out.NotAfter)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateStatus.
+func (in *CertificateStatus) DeepCopy() *CertificateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Compliance) DeepCopyInto(out *Compliance) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	out.Status = in.Status
 	return
 }
@@ -229,6 +376,29 @@ func (in *Compliance) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComplianceBenchmarker) DeepCopyInto(out *ComplianceBenchmarker) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceBenchmarker.
+func (in *ComplianceBenchmarker) DeepCopy() *ComplianceBenchmarker {
+	if in == nil {
+		return nil
+	}
+	out := new(ComplianceBenchmarker)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ComplianceList) DeepCopyInto(out *ComplianceList) {
 	*out = *in
@@ -262,9 +432,91 @@ func (in *ComplianceList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComplianceReport) DeepCopyInto(out *ComplianceReport) {
+	*out = *in
+	if in.JobNodeSelector != nil {
+		in, out := &in.JobNodeSelector, &out.JobNodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceReport.
+func (in *ComplianceReport) DeepCopy() *ComplianceReport {
+	if in == nil {
+		return nil
+	}
+	out := new(ComplianceReport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComplianceReportType) DeepCopyInto(out *ComplianceReportType) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceReportType.
+func (in *ComplianceReportType) DeepCopy() *ComplianceReportType {
+	if in == nil {
+		return nil
+	}
+	out := new(ComplianceReportType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComplianceSnapshotter) DeepCopyInto(out *ComplianceSnapshotter) {
+	*out = *in
+	if in.Hour != nil {
+		in, out := &in.Hour, &out.Hour
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceSnapshotter.
+func (in *ComplianceSnapshotter) DeepCopy() *ComplianceSnapshotter {
+	if in == nil {
+		return nil
+	}
+	out := new(ComplianceSnapshotter)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ComplianceSpec) DeepCopyInto(out *ComplianceSpec) {
 	*out = *in
+	if in.ReportTypes != nil {
+		in, out := &in.ReportTypes, &out.ReportTypes
+		*out = make([]ComplianceReportType, len(*in))
+		copy(*out, *in)
+	}
+	if in.Reports != nil {
+		in, out := &in.Reports, &out.Reports
+		*out = make([]ComplianceReport, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Snapshotter != nil {
+		in, out := &in.Snapshotter, &out.Snapshotter
+		*out = new(ComplianceSnapshotter)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Benchmarker != nil {
+		in, out := &in.Benchmarker, &out.Benchmarker
+		*out = new(ComplianceBenchmarker)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -311,171 +563,383 @@ func (in *EksCloudwatchLogsSpec) DeepCopy() *EksCloudwatchLogsSpec {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IPPool) DeepCopyInto(out *IPPool) {
+func (in *ElasticsearchUsers) DeepCopyInto(out *ElasticsearchUsers) {
 	*out = *in
+	if in.PasswordRotationDays != nil {
+		in, out := &in.PasswordRotationDays, &out.PasswordRotationDays
+		*out = new(int32)
+		**out = **in
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPPool.
-func (in *IPPool) DeepCopy() *IPPool {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchUsers.
+func (in *ElasticsearchUsers) DeepCopy() *ElasticsearchUsers {
 	if in == nil {
 		return nil
 	}
-	out := new(IPPool)
+	out := new(ElasticsearchUsers)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Indices) DeepCopyInto(out *Indices) {
+func (in *ExternalElasticsearch) DeepCopyInto(out *ExternalElasticsearch) {
 	*out = *in
-	if in.Replicas != nil {
-		in, out := &in.Replicas, &out.Replicas
-		*out = new(int32)
-		**out = **in
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Indices.
-func (in *Indices) DeepCopy() *Indices {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalElasticsearch.
+func (in *ExternalElasticsearch) DeepCopy() *ExternalElasticsearch {
 	if in == nil {
 		return nil
 	}
-	out := new(Indices)
+	out := new(ExternalElasticsearch)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Installation) DeepCopyInto(out *Installation) {
+func (in *ExternalElasticsearchStatus) DeepCopyInto(out *ExternalElasticsearchStatus) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Installation.
-func (in *Installation) DeepCopy() *Installation {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalElasticsearchStatus.
+func (in *ExternalElasticsearchStatus) DeepCopy() *ExternalElasticsearchStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(Installation)
+	out := new(ExternalElasticsearchStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Installation) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *InstallationList) DeepCopyInto(out *InstallationList) {
+func (in *FilesystemSnapshotRepository) DeepCopyInto(out *FilesystemSnapshotRepository) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	out.ListMeta = in.ListMeta
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]Installation, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.StorageSize != nil {
+		in, out := &in.StorageSize, &out.StorageSize
+		x := (*in).DeepCopy()
+		*out = &x
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstallationList.
-func (in *InstallationList) DeepCopy() *InstallationList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FilesystemSnapshotRepository.
+func (in *FilesystemSnapshotRepository) DeepCopy() *FilesystemSnapshotRepository {
 	if in == nil {
 		return nil
 	}
-	out := new(InstallationList)
+	out := new(FilesystemSnapshotRepository)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *InstallationList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *InstallationSpec) DeepCopyInto(out *InstallationSpec) {
+func (in *GitHubConnector) DeepCopyInto(out *GitHubConnector) {
 	*out = *in
-	if in.ImagePullSecrets != nil {
-		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
-		*out = make([]corev1.LocalObjectReference, len(*in))
+	if in.Orgs != nil {
+		in, out := &in.Orgs, &out.Orgs
+		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	if in.CalicoNetwork != nil {
-		in, out := &in.CalicoNetwork, &out.CalicoNetwork
-		*out = new(CalicoNetworkSpec)
-		(*in).DeepCopyInto(*out)
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstallationSpec.
-func (in *InstallationSpec) DeepCopy() *InstallationSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitHubConnector.
+func (in *GitHubConnector) DeepCopy() *GitHubConnector {
 	if in == nil {
 		return nil
 	}
-	out := new(InstallationSpec)
+	out := new(GitHubConnector)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *InstallationStatus) DeepCopyInto(out *InstallationStatus) {
+func (in *HTTPHeaderSecret) DeepCopyInto(out *HTTPHeaderSecret) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstallationStatus.
-func (in *InstallationStatus) DeepCopy() *InstallationStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPHeaderSecret.
+func (in *HTTPHeaderSecret) DeepCopy() *HTTPHeaderSecret {
 	if in == nil {
 		return nil
 	}
-	out := new(InstallationStatus)
+	out := new(HTTPHeaderSecret)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IntrusionDetection) DeepCopyInto(out *IntrusionDetection) {
+func (in *IPPool) DeepCopyInto(out *IPPool) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	out.Status = in.Status
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntrusionDetection.
-func (in *IntrusionDetection) DeepCopy() *IntrusionDetection {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPPool.
+func (in *IPPool) DeepCopy() *IPPool {
 	if in == nil {
 		return nil
 	}
-	out := new(IntrusionDetection)
+	out := new(IPPool)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *IntrusionDetection) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IdentityBroker) DeepCopyInto(out *IdentityBroker) {
+	*out = *in
+	if in.Connectors != nil {
+		in, out := &in.Connectors, &out.Connectors
+		*out = make([]IdentityConnector, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	return nil
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IdentityBroker.
+func (in *IdentityBroker) DeepCopy() *IdentityBroker {
+	if in == nil {
+		return nil
+	}
+	out := new(IdentityBroker)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IdentityConnector) DeepCopyInto(out *IdentityConnector) {
+	*out = *in
+	if in.LDAP != nil {
+		in, out := &in.LDAP, &out.LDAP
+		*out = new(LDAPConnector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SAML != nil {
+		in, out := &in.SAML, &out.SAML
+		*out = new(SAMLConnector)
+		**out = **in
+	}
+	if in.GitHub != nil {
+		in, out := &in.GitHub, &out.GitHub
+		*out = new(GitHubConnector)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IdentityConnector.
+func (in *IdentityConnector) DeepCopy() *IdentityConnector {
+	if in == nil {
+		return nil
+	}
+	out := new(IdentityConnector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IndexLifecycle) DeepCopyInto(out *IndexLifecycle) {
+	*out = *in
+	if in.RolloverSize != nil {
+		in, out := &in.RolloverSize, &out.RolloverSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.MaxTotalStoragePercent != nil {
+		in, out := &in.MaxTotalStoragePercent, &out.MaxTotalStoragePercent
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxLogsStoragePercent != nil {
+		in, out := &in.MaxLogsStoragePercent, &out.MaxLogsStoragePercent
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexLifecycle.
+func (in *IndexLifecycle) DeepCopy() *IndexLifecycle {
+	if in == nil {
+		return nil
+	}
+	out := new(IndexLifecycle)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IndexLifecyclePolicyStatus) DeepCopyInto(out *IndexLifecyclePolicyStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexLifecyclePolicyStatus.
+func (in *IndexLifecyclePolicyStatus) DeepCopy() *IndexLifecyclePolicyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(IndexLifecyclePolicyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Indices) DeepCopyInto(out *Indices) {
+	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Indices.
+func (in *Indices) DeepCopy() *Indices {
+	if in == nil {
+		return nil
+	}
+	out := new(Indices)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Installation) DeepCopyInto(out *Installation) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Installation.
+func (in *Installation) DeepCopy() *Installation {
+	if in == nil {
+		return nil
+	}
+	out := new(Installation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Installation) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstallationList) DeepCopyInto(out *InstallationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Installation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstallationList.
+func (in *InstallationList) DeepCopy() *InstallationList {
+	if in == nil {
+		return nil
+	}
+	out := new(InstallationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *InstallationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstallationSpec) DeepCopyInto(out *InstallationSpec) {
+	*out = *in
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]corev1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.CalicoNetwork != nil {
+		in, out := &in.CalicoNetwork, &out.CalicoNetwork
+		*out = new(CalicoNetworkSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstallationSpec.
+func (in *InstallationSpec) DeepCopy() *InstallationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(InstallationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstallationStatus) DeepCopyInto(out *InstallationStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstallationStatus.
+func (in *InstallationStatus) DeepCopy() *InstallationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(InstallationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IntrusionDetection) DeepCopyInto(out *IntrusionDetection) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntrusionDetection.
+func (in *IntrusionDetection) DeepCopy() *IntrusionDetection {
+	if in == nil {
+		return nil
+	}
+	out := new(IntrusionDetection)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *IntrusionDetection) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -514,6 +978,23 @@ func (in *IntrusionDetectionList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IntrusionDetectionSpec) DeepCopyInto(out *IntrusionDetectionSpec) {
 	*out = *in
+	if in.ThreatFeeds != nil {
+		in, out := &in.ThreatFeeds, &out.ThreatFeeds
+		*out = make([]ThreatFeed, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AlertForwarding != nil {
+		in, out := &in.AlertForwarding, &out.AlertForwarding
+		*out = new(AlertForwarding)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AnomalyDetection != nil {
+		in, out := &in.AnomalyDetection, &out.AnomalyDetection
+		*out = new(AnomalyDetectionSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -530,41 +1011,341 @@ func (in *IntrusionDetectionSpec) DeepCopy() *IntrusionDetectionSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IntrusionDetectionStatus) DeepCopyInto(out *IntrusionDetectionStatus) {
 	*out = *in
+	if in.ThreatFeeds != nil {
+		in, out := &in.ThreatFeeds, &out.ThreatFeeds
+		*out = make([]ThreatFeedStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Detectors != nil {
+		in, out := &in.Detectors, &out.Detectors
+		*out = make([]AnomalyDetectorStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntrusionDetectionStatus.
+func (in *IntrusionDetectionStatus) DeepCopy() *IntrusionDetectionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(IntrusionDetectionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Kibana) DeepCopyInto(out *Kibana) {
+	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SavedObjectConfigMaps != nil {
+		in, out := &in.SavedObjectConfigMaps, &out.SavedObjectConfigMaps
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Kibana.
+func (in *Kibana) DeepCopy() *Kibana {
+	if in == nil {
+		return nil
+	}
+	out := new(Kibana)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KibanaSavedObjectsStatus) DeepCopyInto(out *KibanaSavedObjectsStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KibanaSavedObjectsStatus.
+func (in *KibanaSavedObjectsStatus) DeepCopy() *KibanaSavedObjectsStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KibanaSavedObjectsStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KibanaStatus) DeepCopyInto(out *KibanaStatus) {
+	*out = *in
+	if in.SavedObjects != nil {
+		in, out := &in.SavedObjects, &out.SavedObjects
+		*out = make([]KibanaSavedObjectsStatus, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KibanaStatus.
+func (in *KibanaStatus) DeepCopy() *KibanaStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KibanaStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LDAPConnector) DeepCopyInto(out *LDAPConnector) {
+	*out = *in
+	out.UserSearch = in.UserSearch
+	if in.GroupSearch != nil {
+		in, out := &in.GroupSearch, &out.GroupSearch
+		*out = new(LDAPGroupSearch)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LDAPConnector.
+func (in *LDAPConnector) DeepCopy() *LDAPConnector {
+	if in == nil {
+		return nil
+	}
+	out := new(LDAPConnector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LDAPGroupSearch) DeepCopyInto(out *LDAPGroupSearch) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LDAPGroupSearch.
+func (in *LDAPGroupSearch) DeepCopy() *LDAPGroupSearch {
+	if in == nil {
+		return nil
+	}
+	out := new(LDAPGroupSearch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LDAPUserSearch) DeepCopyInto(out *LDAPUserSearch) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LDAPUserSearch.
+func (in *LDAPUserSearch) DeepCopy() *LDAPUserSearch {
+	if in == nil {
+		return nil
+	}
+	out := new(LDAPUserSearch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogCollector) DeepCopyInto(out *LogCollector) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogCollector.
+func (in *LogCollector) DeepCopy() *LogCollector {
+	if in == nil {
+		return nil
+	}
+	out := new(LogCollector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *LogCollector) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogCollectorList) DeepCopyInto(out *LogCollectorList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]LogCollector, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogCollectorList.
+func (in *LogCollectorList) DeepCopy() *LogCollectorList {
+	if in == nil {
+		return nil
+	}
+	out := new(LogCollectorList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *LogCollectorList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogCollectorSpec) DeepCopyInto(out *LogCollectorSpec) {
+	*out = *in
+	if in.AdditionalStores != nil {
+		in, out := &in.AdditionalStores, &out.AdditionalStores
+		*out = new(AdditionalLogStoreSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AdditionalSources != nil {
+		in, out := &in.AdditionalSources, &out.AdditionalSources
+		*out = new(AdditionalLogSourceSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogCollectorSpec.
+func (in *LogCollectorSpec) DeepCopy() *LogCollectorSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LogCollectorSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogCollectorStatus) DeepCopyInto(out *LogCollectorStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogCollectorStatus.
+func (in *LogCollectorStatus) DeepCopy() *LogCollectorStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LogCollectorStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogStorage) DeepCopyInto(out *LogStorage) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogStorage.
+func (in *LogStorage) DeepCopy() *LogStorage {
+	if in == nil {
+		return nil
+	}
+	out := new(LogStorage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *LogStorage) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogStorageList) DeepCopyInto(out *LogStorageList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]LogStorage, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntrusionDetectionStatus.
-func (in *IntrusionDetectionStatus) DeepCopy() *IntrusionDetectionStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogStorageList.
+func (in *LogStorageList) DeepCopy() *LogStorageList {
 	if in == nil {
 		return nil
 	}
-	out := new(IntrusionDetectionStatus)
+	out := new(LogStorageList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *LogStorageList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LogCollector) DeepCopyInto(out *LogCollector) {
+func (in *LogStorageRestore) DeepCopyInto(out *LogStorageRestore) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogCollector.
-func (in *LogCollector) DeepCopy() *LogCollector {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogStorageRestore.
+func (in *LogStorageRestore) DeepCopy() *LogStorageRestore {
 	if in == nil {
 		return nil
 	}
-	out := new(LogCollector)
+	out := new(LogStorageRestore)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *LogCollector) DeepCopyObject() runtime.Object {
+func (in *LogStorageRestore) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -572,13 +1353,13 @@ func (in *LogCollector) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LogCollectorList) DeepCopyInto(out *LogCollectorList) {
+func (in *LogStorageRestoreList) DeepCopyInto(out *LogStorageRestoreList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	out.ListMeta = in.ListMeta
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]LogCollector, len(*in))
+		*out = make([]LogStorageRestore, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -586,18 +1367,18 @@ func (in *LogCollectorList) DeepCopyInto(out *LogCollectorList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogCollectorList.
-func (in *LogCollectorList) DeepCopy() *LogCollectorList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogStorageRestoreList.
+func (in *LogStorageRestoreList) DeepCopy() *LogStorageRestoreList {
 	if in == nil {
 		return nil
 	}
-	out := new(LogCollectorList)
+	out := new(LogStorageRestoreList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *LogCollectorList) DeepCopyObject() runtime.Object {
+func (in *LogStorageRestoreList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -605,69 +1386,159 @@ func (in *LogCollectorList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LogCollectorSpec) DeepCopyInto(out *LogCollectorSpec) {
+func (in *LogStorageRestoreSpec) DeepCopyInto(out *LogStorageRestoreSpec) {
 	*out = *in
-	if in.AdditionalStores != nil {
-		in, out := &in.AdditionalStores, &out.AdditionalStores
-		*out = new(AdditionalLogStoreSpec)
+	if in.Indices != nil {
+		in, out := &in.Indices, &out.Indices
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogStorageRestoreSpec.
+func (in *LogStorageRestoreSpec) DeepCopy() *LogStorageRestoreSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LogStorageRestoreSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogStorageRestoreStatus) DeepCopyInto(out *LogStorageRestoreStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogStorageRestoreStatus.
+func (in *LogStorageRestoreStatus) DeepCopy() *LogStorageRestoreStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LogStorageRestoreStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogStorageSpec) DeepCopyInto(out *LogStorageSpec) {
+	*out = *in
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = new(Nodes)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.AdditionalSources != nil {
-		in, out := &in.AdditionalSources, &out.AdditionalSources
-		*out = new(AdditionalLogSourceSpec)
+	if in.External != nil {
+		in, out := &in.External, &out.External
+		*out = new(ExternalElasticsearch)
+		**out = **in
+	}
+	if in.Indices != nil {
+		in, out := &in.Indices, &out.Indices
+		*out = new(Indices)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Retention != nil {
+		in, out := &in.Retention, &out.Retention
+		*out = new(Retention)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.IndexLifecycle != nil {
+		in, out := &in.IndexLifecycle, &out.IndexLifecycle
+		*out = new(IndexLifecycle)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Snapshots != nil {
+		in, out := &in.Snapshots, &out.Snapshots
+		*out = new(Snapshots)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = new(ElasticsearchUsers)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Kibana != nil {
+		in, out := &in.Kibana, &out.Kibana
+		*out = new(Kibana)
 		(*in).DeepCopyInto(*out)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogCollectorSpec.
-func (in *LogCollectorSpec) DeepCopy() *LogCollectorSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogStorageSpec.
+func (in *LogStorageSpec) DeepCopy() *LogStorageSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(LogCollectorSpec)
+	out := new(LogStorageSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LogCollectorStatus) DeepCopyInto(out *LogCollectorStatus) {
+func (in *LogStorageStatus) DeepCopyInto(out *LogStorageStatus) {
 	*out = *in
+	if in.IndexLifecyclePolicies != nil {
+		in, out := &in.IndexLifecyclePolicies, &out.IndexLifecyclePolicies
+		*out = make([]IndexLifecyclePolicyStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.External != nil {
+		in, out := &in.External, &out.External
+		*out = new(ExternalElasticsearchStatus)
+		**out = **in
+	}
+	if in.Kibana != nil {
+		in, out := &in.Kibana, &out.Kibana
+		*out = new(KibanaStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogCollectorStatus.
-func (in *LogCollectorStatus) DeepCopy() *LogCollectorStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogStorageStatus.
+func (in *LogStorageStatus) DeepCopy() *LogStorageStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(LogCollectorStatus)
+	out := new(LogStorageStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LogStorage) DeepCopyInto(out *LogStorage) {
+func (in *ManagedCluster) DeepCopyInto(out *ManagedCluster) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogStorage.
-func (in *LogStorage) DeepCopy() *LogStorage {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedCluster.
+func (in *ManagedCluster) DeepCopy() *ManagedCluster {
 	if in == nil {
 		return nil
 	}
-	out := new(LogStorage)
+	out := new(ManagedCluster)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *LogStorage) DeepCopyObject() runtime.Object {
+func (in *ManagedCluster) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -675,13 +1546,13 @@ func (in *LogStorage) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LogStorageList) DeepCopyInto(out *LogStorageList) {
+func (in *ManagedClusterList) DeepCopyInto(out *ManagedClusterList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	out.ListMeta = in.ListMeta
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]LogStorage, len(*in))
+		*out = make([]ManagedCluster, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -689,18 +1560,18 @@ func (in *LogStorageList) DeepCopyInto(out *LogStorageList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogStorageList.
-func (in *LogStorageList) DeepCopy() *LogStorageList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedClusterList.
+func (in *ManagedClusterList) DeepCopy() *ManagedClusterList {
 	if in == nil {
 		return nil
 	}
-	out := new(LogStorageList)
+	out := new(ManagedClusterList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *LogStorageList) DeepCopyObject() runtime.Object {
+func (in *ManagedClusterList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -708,48 +1579,72 @@ func (in *LogStorageList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LogStorageSpec) DeepCopyInto(out *LogStorageSpec) {
+func (in *ManagedClusterLogStorage) DeepCopyInto(out *ManagedClusterLogStorage) {
 	*out = *in
-	if in.Nodes != nil {
-		in, out := &in.Nodes, &out.Nodes
-		*out = new(Nodes)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Indices != nil {
-		in, out := &in.Indices, &out.Indices
-		*out = new(Indices)
-		(*in).DeepCopyInto(*out)
-	}
 	if in.Retention != nil {
 		in, out := &in.Retention, &out.Retention
 		*out = new(Retention)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.IngestionQuota != nil {
+		in, out := &in.IngestionQuota, &out.IngestionQuota
+		x := (*in).DeepCopy()
+		*out = &x
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogStorageSpec.
-func (in *LogStorageSpec) DeepCopy() *LogStorageSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedClusterLogStorage.
+func (in *ManagedClusterLogStorage) DeepCopy() *ManagedClusterLogStorage {
 	if in == nil {
 		return nil
 	}
-	out := new(LogStorageSpec)
+	out := new(ManagedClusterLogStorage)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LogStorageStatus) DeepCopyInto(out *LogStorageStatus) {
+func (in *ManagedClusterSpec) DeepCopyInto(out *ManagedClusterSpec) {
 	*out = *in
+	if in.LogStorage != nil {
+		in, out := &in.LogStorage, &out.LogStorage
+		*out = new(ManagedClusterLogStorage)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogStorageStatus.
-func (in *LogStorageStatus) DeepCopy() *LogStorageStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedClusterSpec.
+func (in *ManagedClusterSpec) DeepCopy() *ManagedClusterSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(LogStorageStatus)
+	out := new(ManagedClusterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedClusterStatus) DeepCopyInto(out *ManagedClusterStatus) {
+	*out = *in
+	if in.CertificateExpiry != nil {
+		in, out := &in.CertificateExpiry, &out.CertificateExpiry
+		*out = (*in).DeepCopy()
+	}
+	if in.LastSeen != nil {
+		in, out := &in.LastSeen, &out.LastSeen
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedClusterStatus.
+func (in *ManagedClusterStatus) DeepCopy() *ManagedClusterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedClusterStatus)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -759,7 +1654,8 @@ func (in *ManagementClusterConnection) DeepCopyInto(out *ManagementClusterConnec
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
@@ -817,6 +1713,11 @@ func (in *ManagementClusterConnectionList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ManagementClusterConnectionSpec) DeepCopyInto(out *ManagementClusterConnectionSpec) {
 	*out = *in
+	if in.Tunnel != nil {
+		in, out := &in.Tunnel, &out.Tunnel
+		*out = new(TunnelConfig)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -830,6 +1731,30 @@ func (in *ManagementClusterConnectionSpec) DeepCopy() *ManagementClusterConnecti
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagementClusterConnectionStatus) DeepCopyInto(out *ManagementClusterConnectionStatus) {
+	*out = *in
+	if in.LastTransitionTime != nil {
+		in, out := &in.LastTransitionTime, &out.LastTransitionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CertificateExpiry != nil {
+		in, out := &in.CertificateExpiry, &out.CertificateExpiry
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagementClusterConnectionStatus.
+func (in *ManagementClusterConnectionStatus) DeepCopy() *ManagementClusterConnectionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagementClusterConnectionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Manager) DeepCopyInto(out *Manager) {
 	*out = *in
@@ -858,6 +1783,86 @@ func (in *Manager) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagerExposure) DeepCopyInto(out *ManagerExposure) {
+	*out = *in
+	if in.Ingress != nil {
+		in, out := &in.Ingress, &out.Ingress
+		*out = new(ManagerIngress)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Route != nil {
+		in, out := &in.Route, &out.Route
+		*out = new(ManagerRoute)
+		**out = **in
+	}
+	if in.NodePort != nil {
+		in, out := &in.NodePort, &out.NodePort
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagerExposure.
+func (in *ManagerExposure) DeepCopy() *ManagerExposure {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagerExposure)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagerFeatures) DeepCopyInto(out *ManagerFeatures) {
+	*out = *in
+	if in.PolicyRecommendation != nil {
+		in, out := &in.PolicyRecommendation, &out.PolicyRecommendation
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ApplicationLayerPolicy != nil {
+		in, out := &in.ApplicationLayerPolicy, &out.ApplicationLayerPolicy
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagerFeatures.
+func (in *ManagerFeatures) DeepCopy() *ManagerFeatures {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagerFeatures)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagerIngress) DeepCopyInto(out *ManagerIngress) {
+	*out = *in
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagerIngress.
+func (in *ManagerIngress) DeepCopy() *ManagerIngress {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagerIngress)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ManagerList) DeepCopyInto(out *ManagerList) {
 	*out = *in
@@ -873,22 +1878,54 @@ func (in *ManagerList) DeepCopyInto(out *ManagerList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagerList.
-func (in *ManagerList) DeepCopy() *ManagerList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagerList.
+func (in *ManagerList) DeepCopy() *ManagerList {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagerList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ManagerList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagerLogLevels) DeepCopyInto(out *ManagerLogLevels) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagerLogLevels.
+func (in *ManagerLogLevels) DeepCopy() *ManagerLogLevels {
 	if in == nil {
 		return nil
 	}
-	out := new(ManagerList)
+	out := new(ManagerLogLevels)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ManagerList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagerRoute) DeepCopyInto(out *ManagerRoute) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagerRoute.
+func (in *ManagerRoute) DeepCopy() *ManagerRoute {
+	if in == nil {
+		return nil
 	}
-	return nil
+	out := new(ManagerRoute)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -897,8 +1934,40 @@ func (in *ManagerSpec) DeepCopyInto(out *ManagerSpec) {
 	if in.Auth != nil {
 		in, out := &in.Auth, &out.Auth
 		*out = new(Auth)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Features != nil {
+		in, out := &in.Features, &out.Features
+		*out = new(ManagerFeatures)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LogLevels != nil {
+		in, out := &in.LogLevels, &out.LogLevels
+		*out = new(ManagerLogLevels)
+		**out = **in
+	}
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
 		**out = **in
 	}
+	if in.IdentityBroker != nil {
+		in, out := &in.IdentityBroker, &out.IdentityBroker
+		*out = new(IdentityBroker)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Exposure != nil {
+		in, out := &in.Exposure, &out.Exposure
+		*out = new(ManagerExposure)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RoleBindings != nil {
+		in, out := &in.RoleBindings, &out.RoleBindings
+		*out = make([]UIRoleBinding, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -918,7 +1987,7 @@ func (in *ManagerStatus) DeepCopyInto(out *ManagerStatus) {
 	if in.Auth != nil {
 		in, out := &in.Auth, &out.Auth
 		*out = new(Auth)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	return
 }
@@ -954,6 +2023,55 @@ func (in *NodeAddressAutodetection) DeepCopy() *NodeAddressAutodetection {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeSet) DeepCopyInto(out *NodeSet) {
+	*out = *in
+	if in.ResourceRequirements != nil {
+		in, out := &in.ResourceRequirements, &out.ResourceRequirements
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StorageSize != nil {
+		in, out := &in.StorageSize, &out.StorageSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeSet.
+func (in *NodeSet) DeepCopy() *NodeSet {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeSet)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeSets) DeepCopyInto(out *NodeSets) {
+	*out = *in
+	in.Master.DeepCopyInto(&out.Master)
+	in.Data.DeepCopyInto(&out.Data)
+	if in.Ingest != nil {
+		in, out := &in.Ingest, &out.Ingest
+		*out = new(NodeSet)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeSets.
+func (in *NodeSets) DeepCopy() *NodeSets {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeSets)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Nodes) DeepCopyInto(out *Nodes) {
 	*out = *in
@@ -962,6 +2080,21 @@ func (in *Nodes) DeepCopyInto(out *Nodes) {
 		*out = new(corev1.ResourceRequirements)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.StorageSize != nil {
+		in, out := &in.StorageSize, &out.StorageSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.NodeSets != nil {
+		in, out := &in.NodeSets, &out.NodeSets
+		*out = new(NodeSets)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ZoneAwareness != nil {
+		in, out := &in.ZoneAwareness, &out.ZoneAwareness
+		*out = new(ZoneAwareness)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -998,6 +2131,26 @@ func (in *Retention) DeepCopyInto(out *Retention) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.DNSLogs != nil {
+		in, out := &in.DNSLogs, &out.DNSLogs
+		*out = new(int32)
+		**out = **in
+	}
+	if in.IDSEvents != nil {
+		in, out := &in.IDSEvents, &out.IDSEvents
+		*out = new(int32)
+		**out = **in
+	}
+	if in.L7Logs != nil {
+		in, out := &in.L7Logs, &out.L7Logs
+		*out = new(int32)
+		**out = **in
+	}
+	if in.BGPLogs != nil {
+		in, out := &in.BGPLogs, &out.BGPLogs
+		*out = new(int32)
+		**out = **in
+	}
 	return
 }
 
@@ -1011,6 +2164,22 @@ func (in *Retention) DeepCopy() *Retention {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *S3SnapshotRepository) DeepCopyInto(out *S3SnapshotRepository) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3SnapshotRepository.
+func (in *S3SnapshotRepository) DeepCopy() *S3SnapshotRepository {
+	if in == nil {
+		return nil
+	}
+	out := new(S3SnapshotRepository)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *S3StoreSpec) DeepCopyInto(out *S3StoreSpec) {
 	*out = *in
@@ -1027,6 +2196,91 @@ func (in *S3StoreSpec) DeepCopy() *S3StoreSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SAMLConnector) DeepCopyInto(out *SAMLConnector) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SAMLConnector.
+func (in *SAMLConnector) DeepCopy() *SAMLConnector {
+	if in == nil {
+		return nil
+	}
+	out := new(SAMLConnector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotRepository) DeepCopyInto(out *SnapshotRepository) {
+	*out = *in
+	if in.Filesystem != nil {
+		in, out := &in.Filesystem, &out.Filesystem
+		*out = new(FilesystemSnapshotRepository)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(S3SnapshotRepository)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotRepository.
+func (in *SnapshotRepository) DeepCopy() *SnapshotRepository {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotRepository)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Snapshots) DeepCopyInto(out *Snapshots) {
+	*out = *in
+	in.Repository.DeepCopyInto(&out.Repository)
+	if in.Retention != nil {
+		in, out := &in.Retention, &out.Retention
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Indices != nil {
+		in, out := &in.Indices, &out.Indices
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Snapshots.
+func (in *Snapshots) DeepCopy() *Snapshots {
+	if in == nil {
+		return nil
+	}
+	out := new(Snapshots)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SplunkStoreSpec) DeepCopyInto(out *SplunkStoreSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SplunkStoreSpec.
+func (in *SplunkStoreSpec) DeepCopy() *SplunkStoreSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SplunkStoreSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SyslogStoreSpec) DeepCopyInto(out *SyslogStoreSpec) {
 	*out = *in
@@ -1048,6 +2302,60 @@ func (in *SyslogStoreSpec) DeepCopy() *SyslogStoreSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ThreatFeed) DeepCopyInto(out *ThreatFeed) {
+	*out = *in
+	if in.PullInterval != nil {
+		in, out := &in.PullInterval, &out.PullInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Auth != nil {
+		in, out := &in.Auth, &out.Auth
+		*out = new(HTTPHeaderSecret)
+		**out = **in
+	}
+	if in.GlobalNetworkSetLabels != nil {
+		in, out := &in.GlobalNetworkSetLabels, &out.GlobalNetworkSetLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ThreatFeed.
+func (in *ThreatFeed) DeepCopy() *ThreatFeed {
+	if in == nil {
+		return nil
+	}
+	out := new(ThreatFeed)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ThreatFeedStatus) DeepCopyInto(out *ThreatFeedStatus) {
+	*out = *in
+	if in.Errors != nil {
+		in, out := &in.Errors, &out.Errors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ThreatFeedStatus.
+func (in *ThreatFeedStatus) DeepCopy() *ThreatFeedStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ThreatFeedStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TigeraStatus) DeepCopyInto(out *TigeraStatus) {
 	*out = *in
@@ -1152,6 +2460,13 @@ func (in *TigeraStatusStatus) DeepCopyInto(out *TigeraStatusStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Certificates != nil {
+		in, out := &in.Certificates, &out.Certificates
+		*out = make([]CertificateStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -1164,3 +2479,96 @@ func (in *TigeraStatusStatus) DeepCopy() *TigeraStatusStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TunnelConfig) DeepCopyInto(out *TunnelConfig) {
+	*out = *in
+	if in.KeepAlive != nil {
+		in, out := &in.KeepAlive, &out.KeepAlive
+		*out = new(bool)
+		**out = **in
+	}
+	if in.KeepAliveInterval != nil {
+		in, out := &in.KeepAliveInterval, &out.KeepAliveInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.DialTimeout != nil {
+		in, out := &in.DialTimeout, &out.DialTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.ReconnectInterval != nil {
+		in, out := &in.ReconnectInterval, &out.ReconnectInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.ReconnectAttempts != nil {
+		in, out := &in.ReconnectAttempts, &out.ReconnectAttempts
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TunnelConfig.
+func (in *TunnelConfig) DeepCopy() *TunnelConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(TunnelConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UIRoleBinding) DeepCopyInto(out *UIRoleBinding) {
+	*out = *in
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Tiers != nil {
+		in, out := &in.Tiers, &out.Tiers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UIRoleBinding.
+func (in *UIRoleBinding) DeepCopy() *UIRoleBinding {
+	if in == nil {
+		return nil
+	}
+	out := new(UIRoleBinding)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ZoneAwareness) DeepCopyInto(out *ZoneAwareness) {
+	*out = *in
+	if in.Zones != nil {
+		in, out := &in.Zones, &out.Zones
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ZoneAwareness.
+func (in *ZoneAwareness) DeepCopy() *ZoneAwareness {
+	if in == nil {
+		return nil
+	}
+	out := new(ZoneAwareness)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -11,34 +11,56 @@ import (
 
 func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenAPIDefinition {
 	return map[string]common.OpenAPIDefinition{
-		"github.com/tigera/operator/pkg/apis/operator/v1.APIServer":                       schema_pkg_apis_operator_v1_APIServer(ref),
-		"github.com/tigera/operator/pkg/apis/operator/v1.APIServerSpec":                   schema_pkg_apis_operator_v1_APIServerSpec(ref),
-		"github.com/tigera/operator/pkg/apis/operator/v1.APIServerStatus":                 schema_pkg_apis_operator_v1_APIServerStatus(ref),
-		"github.com/tigera/operator/pkg/apis/operator/v1.Auth":                            schema_pkg_apis_operator_v1_Auth(ref),
-		"github.com/tigera/operator/pkg/apis/operator/v1.Compliance":                      schema_pkg_apis_operator_v1_Compliance(ref),
-		"github.com/tigera/operator/pkg/apis/operator/v1.ComplianceSpec":                  schema_pkg_apis_operator_v1_ComplianceSpec(ref),
-		"github.com/tigera/operator/pkg/apis/operator/v1.ComplianceStatus":                schema_pkg_apis_operator_v1_ComplianceStatus(ref),
-		"github.com/tigera/operator/pkg/apis/operator/v1.Installation":                    schema_pkg_apis_operator_v1_Installation(ref),
-		"github.com/tigera/operator/pkg/apis/operator/v1.InstallationSpec":                schema_pkg_apis_operator_v1_InstallationSpec(ref),
-		"github.com/tigera/operator/pkg/apis/operator/v1.InstallationStatus":              schema_pkg_apis_operator_v1_InstallationStatus(ref),
-		"github.com/tigera/operator/pkg/apis/operator/v1.IntrusionDetection":              schema_pkg_apis_operator_v1_IntrusionDetection(ref),
-		"github.com/tigera/operator/pkg/apis/operator/v1.IntrusionDetectionSpec":          schema_pkg_apis_operator_v1_IntrusionDetectionSpec(ref),
-		"github.com/tigera/operator/pkg/apis/operator/v1.IntrusionDetectionStatus":        schema_pkg_apis_operator_v1_IntrusionDetectionStatus(ref),
-		"github.com/tigera/operator/pkg/apis/operator/v1.LogCollector":                    schema_pkg_apis_operator_v1_LogCollector(ref),
-		"github.com/tigera/operator/pkg/apis/operator/v1.LogCollectorSpec":                schema_pkg_apis_operator_v1_LogCollectorSpec(ref),
-		"github.com/tigera/operator/pkg/apis/operator/v1.LogCollectorStatus":              schema_pkg_apis_operator_v1_LogCollectorStatus(ref),
-		"github.com/tigera/operator/pkg/apis/operator/v1.LogStorage":                      schema_pkg_apis_operator_v1_LogStorage(ref),
-		"github.com/tigera/operator/pkg/apis/operator/v1.LogStorageSpec":                  schema_pkg_apis_operator_v1_LogStorageSpec(ref),
-		"github.com/tigera/operator/pkg/apis/operator/v1.LogStorageStatus":                schema_pkg_apis_operator_v1_LogStorageStatus(ref),
-		"github.com/tigera/operator/pkg/apis/operator/v1.ManagementClusterConnection":     schema_pkg_apis_operator_v1_ManagementClusterConnection(ref),
-		"github.com/tigera/operator/pkg/apis/operator/v1.ManagementClusterConnectionSpec": schema_pkg_apis_operator_v1_ManagementClusterConnectionSpec(ref),
-		"github.com/tigera/operator/pkg/apis/operator/v1.Manager":                         schema_pkg_apis_operator_v1_Manager(ref),
-		"github.com/tigera/operator/pkg/apis/operator/v1.ManagerSpec":                     schema_pkg_apis_operator_v1_ManagerSpec(ref),
-		"github.com/tigera/operator/pkg/apis/operator/v1.ManagerStatus":                   schema_pkg_apis_operator_v1_ManagerStatus(ref),
-		"github.com/tigera/operator/pkg/apis/operator/v1.S3StoreSpec":                     schema_pkg_apis_operator_v1_S3StoreSpec(ref),
-		"github.com/tigera/operator/pkg/apis/operator/v1.TigeraStatus":                    schema_pkg_apis_operator_v1_TigeraStatus(ref),
-		"github.com/tigera/operator/pkg/apis/operator/v1.TigeraStatusSpec":                schema_pkg_apis_operator_v1_TigeraStatusSpec(ref),
-		"github.com/tigera/operator/pkg/apis/operator/v1.TigeraStatusStatus":              schema_pkg_apis_operator_v1_TigeraStatusStatus(ref),
+		"github.com/tigera/operator/pkg/apis/operator/v1.APIServer":                         schema_pkg_apis_operator_v1_APIServer(ref),
+		"github.com/tigera/operator/pkg/apis/operator/v1.APIServerSpec":                     schema_pkg_apis_operator_v1_APIServerSpec(ref),
+		"github.com/tigera/operator/pkg/apis/operator/v1.APIServerStatus":                   schema_pkg_apis_operator_v1_APIServerStatus(ref),
+		"github.com/tigera/operator/pkg/apis/operator/v1.Auth":                              schema_pkg_apis_operator_v1_Auth(ref),
+		"github.com/tigera/operator/pkg/apis/operator/v1.CertificateStatus":                 schema_pkg_apis_operator_v1_CertificateStatus(ref),
+		"github.com/tigera/operator/pkg/apis/operator/v1.Compliance":                        schema_pkg_apis_operator_v1_Compliance(ref),
+		"github.com/tigera/operator/pkg/apis/operator/v1.ComplianceSpec":                    schema_pkg_apis_operator_v1_ComplianceSpec(ref),
+		"github.com/tigera/operator/pkg/apis/operator/v1.ComplianceStatus":                  schema_pkg_apis_operator_v1_ComplianceStatus(ref),
+		"github.com/tigera/operator/pkg/apis/operator/v1.GitHubConnector":                   schema_pkg_apis_operator_v1_GitHubConnector(ref),
+		"github.com/tigera/operator/pkg/apis/operator/v1.IdentityBroker":                    schema_pkg_apis_operator_v1_IdentityBroker(ref),
+		"github.com/tigera/operator/pkg/apis/operator/v1.IdentityConnector":                 schema_pkg_apis_operator_v1_IdentityConnector(ref),
+		"github.com/tigera/operator/pkg/apis/operator/v1.Installation":                      schema_pkg_apis_operator_v1_Installation(ref),
+		"github.com/tigera/operator/pkg/apis/operator/v1.InstallationSpec":                  schema_pkg_apis_operator_v1_InstallationSpec(ref),
+		"github.com/tigera/operator/pkg/apis/operator/v1.InstallationStatus":                schema_pkg_apis_operator_v1_InstallationStatus(ref),
+		"github.com/tigera/operator/pkg/apis/operator/v1.IntrusionDetection":                schema_pkg_apis_operator_v1_IntrusionDetection(ref),
+		"github.com/tigera/operator/pkg/apis/operator/v1.IntrusionDetectionSpec":            schema_pkg_apis_operator_v1_IntrusionDetectionSpec(ref),
+		"github.com/tigera/operator/pkg/apis/operator/v1.IntrusionDetectionStatus":          schema_pkg_apis_operator_v1_IntrusionDetectionStatus(ref),
+		"github.com/tigera/operator/pkg/apis/operator/v1.LDAPConnector":                     schema_pkg_apis_operator_v1_LDAPConnector(ref),
+		"github.com/tigera/operator/pkg/apis/operator/v1.LDAPGroupSearch":                   schema_pkg_apis_operator_v1_LDAPGroupSearch(ref),
+		"github.com/tigera/operator/pkg/apis/operator/v1.LDAPUserSearch":                    schema_pkg_apis_operator_v1_LDAPUserSearch(ref),
+		"github.com/tigera/operator/pkg/apis/operator/v1.LogCollector":                      schema_pkg_apis_operator_v1_LogCollector(ref),
+		"github.com/tigera/operator/pkg/apis/operator/v1.LogCollectorSpec":                  schema_pkg_apis_operator_v1_LogCollectorSpec(ref),
+		"github.com/tigera/operator/pkg/apis/operator/v1.LogCollectorStatus":                schema_pkg_apis_operator_v1_LogCollectorStatus(ref),
+		"github.com/tigera/operator/pkg/apis/operator/v1.LogStorage":                        schema_pkg_apis_operator_v1_LogStorage(ref),
+		"github.com/tigera/operator/pkg/apis/operator/v1.LogStorageRestore":                 schema_pkg_apis_operator_v1_LogStorageRestore(ref),
+		"github.com/tigera/operator/pkg/apis/operator/v1.LogStorageRestoreSpec":             schema_pkg_apis_operator_v1_LogStorageRestoreSpec(ref),
+		"github.com/tigera/operator/pkg/apis/operator/v1.LogStorageRestoreStatus":           schema_pkg_apis_operator_v1_LogStorageRestoreStatus(ref),
+		"github.com/tigera/operator/pkg/apis/operator/v1.LogStorageSpec":                    schema_pkg_apis_operator_v1_LogStorageSpec(ref),
+		"github.com/tigera/operator/pkg/apis/operator/v1.LogStorageStatus":                  schema_pkg_apis_operator_v1_LogStorageStatus(ref),
+		"github.com/tigera/operator/pkg/apis/operator/v1.ManagedCluster":                    schema_pkg_apis_operator_v1_ManagedCluster(ref),
+		"github.com/tigera/operator/pkg/apis/operator/v1.ManagedClusterSpec":                schema_pkg_apis_operator_v1_ManagedClusterSpec(ref),
+		"github.com/tigera/operator/pkg/apis/operator/v1.ManagedClusterStatus":              schema_pkg_apis_operator_v1_ManagedClusterStatus(ref),
+		"github.com/tigera/operator/pkg/apis/operator/v1.ManagementClusterConnection":       schema_pkg_apis_operator_v1_ManagementClusterConnection(ref),
+		"github.com/tigera/operator/pkg/apis/operator/v1.ManagementClusterConnectionSpec":   schema_pkg_apis_operator_v1_ManagementClusterConnectionSpec(ref),
+		"github.com/tigera/operator/pkg/apis/operator/v1.ManagementClusterConnectionStatus": schema_pkg_apis_operator_v1_ManagementClusterConnectionStatus(ref),
+		"github.com/tigera/operator/pkg/apis/operator/v1.Manager":                           schema_pkg_apis_operator_v1_Manager(ref),
+		"github.com/tigera/operator/pkg/apis/operator/v1.ManagerExposure":                   schema_pkg_apis_operator_v1_ManagerExposure(ref),
+		"github.com/tigera/operator/pkg/apis/operator/v1.ManagerFeatures":                   schema_pkg_apis_operator_v1_ManagerFeatures(ref),
+		"github.com/tigera/operator/pkg/apis/operator/v1.ManagerIngress":                    schema_pkg_apis_operator_v1_ManagerIngress(ref),
+		"github.com/tigera/operator/pkg/apis/operator/v1.ManagerLogLevels":                  schema_pkg_apis_operator_v1_ManagerLogLevels(ref),
+		"github.com/tigera/operator/pkg/apis/operator/v1.ManagerRoute":                      schema_pkg_apis_operator_v1_ManagerRoute(ref),
+		"github.com/tigera/operator/pkg/apis/operator/v1.ManagerSpec":                       schema_pkg_apis_operator_v1_ManagerSpec(ref),
+		"github.com/tigera/operator/pkg/apis/operator/v1.ManagerStatus":                     schema_pkg_apis_operator_v1_ManagerStatus(ref),
+		"github.com/tigera/operator/pkg/apis/operator/v1.S3StoreSpec":                       schema_pkg_apis_operator_v1_S3StoreSpec(ref),
+		"github.com/tigera/operator/pkg/apis/operator/v1.SAMLConnector":                     schema_pkg_apis_operator_v1_SAMLConnector(ref),
+		"github.com/tigera/operator/pkg/apis/operator/v1.TigeraStatus":                      schema_pkg_apis_operator_v1_TigeraStatus(ref),
+		"github.com/tigera/operator/pkg/apis/operator/v1.TigeraStatusSpec":                  schema_pkg_apis_operator_v1_TigeraStatusSpec(ref),
+		"github.com/tigera/operator/pkg/apis/operator/v1.TigeraStatusStatus":                schema_pkg_apis_operator_v1_TigeraStatusStatus(ref),
+		"github.com/tigera/operator/pkg/apis/operator/v1.TunnelConfig":                      schema_pkg_apis_operator_v1_TunnelConfig(ref),
+		"github.com/tigera/operator/pkg/apis/operator/v1.UIRoleBinding":                     schema_pkg_apis_operator_v1_UIRoleBinding(ref),
 	}
 }
 
@@ -146,6 +168,55 @@ func schema_pkg_apis_operator_v1_Auth(ref common.ReferenceCallback) common.OpenA
 							Format:      "",
 						},
 					},
+					"scopes": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Scopes lists the scopes requested from the OIDC issuer in addition to openid. Each scope must be advertised in the issuer's discovery document. Default: email, profile",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"usernameClaim": {
+						SchemaProps: spec.SchemaProps{
+							Description: "UsernameClaim is the ID token claim used as the user's name. Default: email",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"usernamePrefix": {
+						SchemaProps: spec.SchemaProps{
+							Description: "UsernamePrefix is prepended to the username claim to build the Kubernetes username, for example \"oidc:\".",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"groupsClaim": {
+						SchemaProps: spec.SchemaProps{
+							Description: "GroupsClaim is the ID token claim holding the user's groups.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"groupsPrefix": {
+						SchemaProps: spec.SchemaProps{
+							Description: "GroupsPrefix is prepended to each group to build the Kubernetes group names, for example \"oidc:\".",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"kubernetesAuthentication": {
+						SchemaProps: spec.SchemaProps{
+							Description: "KubernetesAuthentication configures how OIDC tokens are authenticated against the Kubernetes API. APIServer means the Kubernetes API server is configured with --oidc-* flags matching this configuration. Proxy means the manager proxy verifies the token itself and impersonates the user. Default: APIServer",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
 			},
 		},
@@ -153,6 +224,34 @@ func schema_pkg_apis_operator_v1_Auth(ref common.ReferenceCallback) common.OpenA
 	}
 }
 
+func schema_pkg_apis_operator_v1_CertificateStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "CertificateStatus reports the expiry of a certificate managed for a component.",
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name identifies the certificate.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"notAfter": {
+						SchemaProps: spec.SchemaProps{
+							Description: "NotAfter is the time at which the certificate expires.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+				},
+				Required: []string{"name", "notAfter"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
 func schema_pkg_apis_operator_v1_Compliance(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -203,10 +302,50 @@ func schema_pkg_apis_operator_v1_ComplianceSpec(ref common.ReferenceCallback) co
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
 				Description: "ComplianceSpec defines the desired state of Tigera compliance reporting capabilities.",
-				Properties:  map[string]spec.Schema{},
+				Properties: map[string]spec.Schema{
+					"reportTypes": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ReportTypes configures the report types that are installed. The built-in report types (inventory, network-access, policy-audit and cis-benchmark) are installed unless disabled here. Additional report types may be added by providing a ConfigMap containing their templates.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/tigera/operator/pkg/apis/operator/v1.ComplianceReportType"),
+									},
+								},
+							},
+						},
+					},
+					"reports": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Reports is a list of reports that are generated on a schedule from the installed report types.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/tigera/operator/pkg/apis/operator/v1.ComplianceReport"),
+									},
+								},
+							},
+						},
+					},
+					"snapshotter": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Snapshotter configures the compliance snapshotter.",
+							Ref:         ref("github.com/tigera/operator/pkg/apis/operator/v1.ComplianceSnapshotter"),
+						},
+					},
+					"benchmarker": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Benchmarker configures the compliance benchmarker.",
+							Ref:         ref("github.com/tigera/operator/pkg/apis/operator/v1.ComplianceBenchmarker"),
+						},
+					},
+				},
 			},
 		},
-		Dependencies: []string{},
+		Dependencies: []string{
+			"github.com/tigera/operator/pkg/apis/operator/v1.ComplianceBenchmarker", "github.com/tigera/operator/pkg/apis/operator/v1.ComplianceReport", "github.com/tigera/operator/pkg/apis/operator/v1.ComplianceReportType", "github.com/tigera/operator/pkg/apis/operator/v1.ComplianceSnapshotter"},
 	}
 }
 
@@ -230,6 +369,122 @@ func schema_pkg_apis_operator_v1_ComplianceStatus(ref common.ReferenceCallback)
 	}
 }
 
+func schema_pkg_apis_operator_v1_GitHubConnector(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "GitHubConnector defines GitHub as an identity provider.",
+				Properties: map[string]spec.Schema{
+					"orgs": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Orgs restricts login to members of these GitHub organizations. The user's teams in these organizations are used as groups.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{},
+	}
+}
+
+func schema_pkg_apis_operator_v1_IdentityBroker(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "IdentityBroker defines the configuration of the identity broker.",
+				Properties: map[string]spec.Schema{
+					"managerDomain": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ManagerDomain is the URL at which users reach the manager, for example https://manager.example.com. The broker is served by the manager at <managerDomain>/dex. It may be omitted when the manager is exposed through an Ingress or a Route with a host, in which case that host is used.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"connectors": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Connectors lists the upstream identity providers users can log in with.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/tigera/operator/pkg/apis/operator/v1.IdentityConnector"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"connectors"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/tigera/operator/pkg/apis/operator/v1.IdentityConnector"},
+	}
+}
+
+func schema_pkg_apis_operator_v1_IdentityConnector(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "IdentityConnector configures an upstream identity provider of the identity broker. Exactly one of ldap, saml or github must be set, matching the type.",
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name identifies the connector and is shown on the login page.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"type": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Type is the type of the identity provider.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"secretName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SecretName is the name of a Secret in the tigera-operator namespace holding the connector's credentials. LDAP uses the keys bindDN, bindPW and optionally ca.crt, SAML uses ca.crt and GitHub uses clientID and clientSecret.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"ldap": {
+						SchemaProps: spec.SchemaProps{
+							Description: "LDAP configures an LDAP connector.",
+							Ref:         ref("github.com/tigera/operator/pkg/apis/operator/v1.LDAPConnector"),
+						},
+					},
+					"saml": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SAML configures a SAML 2.0 connector.",
+							Ref:         ref("github.com/tigera/operator/pkg/apis/operator/v1.SAMLConnector"),
+						},
+					},
+					"github": {
+						SchemaProps: spec.SchemaProps{
+							Description: "GitHub configures a GitHub connector.",
+							Ref:         ref("github.com/tigera/operator/pkg/apis/operator/v1.GitHubConnector"),
+						},
+					},
+				},
+				Required: []string{"name", "type"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/tigera/operator/pkg/apis/operator/v1.GitHubConnector", "github.com/tigera/operator/pkg/apis/operator/v1.LDAPConnector", "github.com/tigera/operator/pkg/apis/operator/v1.SAMLConnector"},
+	}
+}
+
 func schema_pkg_apis_operator_v1_Installation(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -406,10 +661,37 @@ func schema_pkg_apis_operator_v1_IntrusionDetectionSpec(ref common.ReferenceCall
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
 				Description: "IntrusionDetectionSpec defines the desired state of Tigera intrusion detection capabilities.",
-				Properties:  map[string]spec.Schema{},
+				Properties: map[string]spec.Schema{
+					"threatFeeds": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ThreatFeeds is a list of threat feeds that are periodically pulled and used to detect suspicious traffic.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/tigera/operator/pkg/apis/operator/v1.ThreatFeed"),
+									},
+								},
+							},
+						},
+					},
+					"alertForwarding": {
+						SchemaProps: spec.SchemaProps{
+							Description: "AlertForwarding configures destinations that security alerts are forwarded to.",
+							Ref:         ref("github.com/tigera/operator/pkg/apis/operator/v1.AlertForwarding"),
+						},
+					},
+					"anomalyDetection": {
+						SchemaProps: spec.SchemaProps{
+							Description: "AnomalyDetection configures the anomaly detection jobs installed in Elasticsearch.",
+							Ref:         ref("github.com/tigera/operator/pkg/apis/operator/v1.AnomalyDetectionSpec"),
+						},
+					},
+				},
 			},
 		},
-		Dependencies: []string{},
+		Dependencies: []string{
+			"github.com/tigera/operator/pkg/apis/operator/v1.AlertForwarding", "github.com/tigera/operator/pkg/apis/operator/v1.AnomalyDetectionSpec", "github.com/tigera/operator/pkg/apis/operator/v1.ThreatFeed"},
 	}
 }
 
@@ -426,90 +708,256 @@ func schema_pkg_apis_operator_v1_IntrusionDetectionStatus(ref common.ReferenceCa
 							Format:      "",
 						},
 					},
+					"threatFeeds": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ThreatFeeds reports the threat feeds that are failing to be pulled.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/tigera/operator/pkg/apis/operator/v1.ThreatFeedStatus"),
+									},
+								},
+							},
+						},
+					},
+					"detectors": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Detectors reports the state of each anomaly detector.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/tigera/operator/pkg/apis/operator/v1.AnomalyDetectorStatus"),
+									},
+								},
+							},
+						},
+					},
 				},
 			},
 		},
-		Dependencies: []string{},
+		Dependencies: []string{
+			"github.com/tigera/operator/pkg/apis/operator/v1.AnomalyDetectorStatus", "github.com/tigera/operator/pkg/apis/operator/v1.ThreatFeedStatus"},
 	}
 }
 
-func schema_pkg_apis_operator_v1_LogCollector(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_operator_v1_LDAPConnector(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "LogCollector installs the components required for Tigera flow and DNS log collection. At most one instance of this resource is supported. It must be named \"tigera-secure\". When created, this installs fluentd on all nodes configured to collect Tigera log data and export it to Tigera's Elasticsearch cluster as well as any additionally configured destinations.",
+				Description: "LDAPConnector defines an LDAP identity provider.",
 				Properties: map[string]spec.Schema{
-					"kind": {
+					"host": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#types-kinds",
+							Description: "Host is the host and port of the LDAP server, for example ldap.example.com:636.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"apiVersion": {
+					"startTLS": {
 						SchemaProps: spec.SchemaProps{
-							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#resources",
-							Type:        []string{"string"},
+							Description: "StartTLS connects without TLS and upgrades the connection with StartTLS.",
+							Type:        []string{"boolean"},
 							Format:      "",
 						},
 					},
-					"metadata": {
-						SchemaProps: spec.SchemaProps{
-							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
-						},
-					},
-					"spec": {
+					"userSearch": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Specification of the desired state for Tigera log collection.",
-							Ref:         ref("github.com/tigera/operator/pkg/apis/operator/v1.LogCollectorSpec"),
+							Description: "UserSearch configures how users are looked up.",
+							Ref:         ref("github.com/tigera/operator/pkg/apis/operator/v1.LDAPUserSearch"),
 						},
 					},
-					"status": {
+					"groupSearch": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Most recently observed state for Tigera log collection.",
-							Ref:         ref("github.com/tigera/operator/pkg/apis/operator/v1.LogCollectorStatus"),
+							Description: "GroupSearch configures how the groups of a user are looked up.",
+							Ref:         ref("github.com/tigera/operator/pkg/apis/operator/v1.LDAPGroupSearch"),
 						},
 					},
 				},
+				Required: []string{"host", "userSearch"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/tigera/operator/pkg/apis/operator/v1.LogCollectorSpec", "github.com/tigera/operator/pkg/apis/operator/v1.LogCollectorStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+			"github.com/tigera/operator/pkg/apis/operator/v1.LDAPGroupSearch", "github.com/tigera/operator/pkg/apis/operator/v1.LDAPUserSearch"},
 	}
 }
 
-func schema_pkg_apis_operator_v1_LogCollectorSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_operator_v1_LDAPGroupSearch(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "LogCollectorSpec defines the desired state of Tigera flow, audit, and DNS log collection.",
+				Description: "LDAPGroupSearch defines how the groups of a user are looked up in LDAP.",
 				Properties: map[string]spec.Schema{
-					"additionalStores": {
+					"baseDN": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Configuration for exporting flow, audit, and DNS logs to external storage.",
-							Ref:         ref("github.com/tigera/operator/pkg/apis/operator/v1.AdditionalLogStoreSpec"),
+							Description: "BaseDN is the directory to search groups in, for example ou=groups,dc=example,dc=com.",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"additionalSources": {
+					"filter": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Configuration for importing audit logs from managed kubernetes cluster log sources.",
-							Ref:         ref("github.com/tigera/operator/pkg/apis/operator/v1.AdditionalLogSourceSpec"),
+							Description: "Filter is an optional filter applied when searching groups, for example (objectClass=groupOfNames).",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-				},
-			},
-		},
-		Dependencies: []string{
-			"github.com/tigera/operator/pkg/apis/operator/v1.AdditionalLogSourceSpec", "github.com/tigera/operator/pkg/apis/operator/v1.AdditionalLogStoreSpec"},
-	}
-}
-
-func schema_pkg_apis_operator_v1_LogCollectorStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
-	return common.OpenAPIDefinition{
-		Schema: spec.Schema{
-			SchemaProps: spec.SchemaProps{
-				Description: "LogCollectorStatus defines the observed state of Tigera flow and DNS log collection",
-				Properties: map[string]spec.Schema{
+					"userAttribute": {
+						SchemaProps: spec.SchemaProps{
+							Description: "UserAttribute is the user attribute whose value is stored in the group's member attribute. Default: DN",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"groupAttribute": {
+						SchemaProps: spec.SchemaProps{
+							Description: "GroupAttribute is the group attribute listing its members. Default: member",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"nameAttribute": {
+						SchemaProps: spec.SchemaProps{
+							Description: "NameAttribute is the attribute holding the group name. Default: cn",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"baseDN"},
+			},
+		},
+		Dependencies: []string{},
+	}
+}
+
+func schema_pkg_apis_operator_v1_LDAPUserSearch(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "LDAPUserSearch defines how users are looked up in LDAP.",
+				Properties: map[string]spec.Schema{
+					"baseDN": {
+						SchemaProps: spec.SchemaProps{
+							Description: "BaseDN is the directory to search users in, for example ou=people,dc=example,dc=com.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"filter": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Filter is an optional filter applied when searching users, for example (objectClass=person).",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"usernameAttribute": {
+						SchemaProps: spec.SchemaProps{
+							Description: "UsernameAttribute is the attribute matched against the username entered by the user. Default: uid",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"emailAttribute": {
+						SchemaProps: spec.SchemaProps{
+							Description: "EmailAttribute is the attribute holding the user's email address. Default: mail",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"nameAttribute": {
+						SchemaProps: spec.SchemaProps{
+							Description: "NameAttribute is the attribute holding the user's display name. Default: cn",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"baseDN"},
+			},
+		},
+		Dependencies: []string{},
+	}
+}
+
+func schema_pkg_apis_operator_v1_LogCollector(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "LogCollector installs the components required for Tigera flow and DNS log collection. At most one instance of this resource is supported. It must be named \"tigera-secure\". When created, this installs fluentd on all nodes configured to collect Tigera log data and export it to Tigera's Elasticsearch cluster as well as any additionally configured destinations.",
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Specification of the desired state for Tigera log collection.",
+							Ref:         ref("github.com/tigera/operator/pkg/apis/operator/v1.LogCollectorSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Most recently observed state for Tigera log collection.",
+							Ref:         ref("github.com/tigera/operator/pkg/apis/operator/v1.LogCollectorStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/tigera/operator/pkg/apis/operator/v1.LogCollectorSpec", "github.com/tigera/operator/pkg/apis/operator/v1.LogCollectorStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_operator_v1_LogCollectorSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "LogCollectorSpec defines the desired state of Tigera flow, audit, and DNS log collection.",
+				Properties: map[string]spec.Schema{
+					"additionalStores": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Configuration for exporting flow, audit, and DNS logs to external storage.",
+							Ref:         ref("github.com/tigera/operator/pkg/apis/operator/v1.AdditionalLogStoreSpec"),
+						},
+					},
+					"additionalSources": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Configuration for importing audit logs from managed kubernetes cluster log sources.",
+							Ref:         ref("github.com/tigera/operator/pkg/apis/operator/v1.AdditionalLogSourceSpec"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/tigera/operator/pkg/apis/operator/v1.AdditionalLogSourceSpec", "github.com/tigera/operator/pkg/apis/operator/v1.AdditionalLogStoreSpec"},
+	}
+}
+
+func schema_pkg_apis_operator_v1_LogCollectorStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "LogCollectorStatus defines the observed state of Tigera flow and DNS log collection",
+				Properties: map[string]spec.Schema{
 					"state": {
 						SchemaProps: spec.SchemaProps{
 							Description: "State provides user-readable status.",
@@ -524,164 +972,781 @@ func schema_pkg_apis_operator_v1_LogCollectorStatus(ref common.ReferenceCallback
 	}
 }
 
-func schema_pkg_apis_operator_v1_LogStorage(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_operator_v1_LogStorage(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "LogStorage installs the components required for Tigera flow and DNS log storage. At most one instance of this resource is supported. It must be named \"tigera-secure\". When created, this installs an Elasticsearch cluster for use by Tigera Secure.",
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Specification of the desired state for Tigera log storage.",
+							Ref:         ref("github.com/tigera/operator/pkg/apis/operator/v1.LogStorageSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Most recently observed state for Tigera log storage.",
+							Ref:         ref("github.com/tigera/operator/pkg/apis/operator/v1.LogStorageStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/tigera/operator/pkg/apis/operator/v1.LogStorageSpec", "github.com/tigera/operator/pkg/apis/operator/v1.LogStorageStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_operator_v1_LogStorageRestore(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "LogStorageRestore restores indices of the LogStorage Elasticsearch cluster from a snapshot taken as configured in the snapshots section of the LogStorage. Restores are run one at a time, in the order they are created. A restore is run once; create a new LogStorageRestore to restore again.",
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/tigera/operator/pkg/apis/operator/v1.LogStorageRestoreSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/tigera/operator/pkg/apis/operator/v1.LogStorageRestoreStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/tigera/operator/pkg/apis/operator/v1.LogStorageRestoreSpec", "github.com/tigera/operator/pkg/apis/operator/v1.LogStorageRestoreStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_operator_v1_LogStorageRestoreSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "LogStorageRestoreSpec defines the indices to restore from a snapshot.",
+				Properties: map[string]spec.Schema{
+					"snapshot": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Snapshot is the name of the snapshot to restore from. Default: the most recent successful snapshot",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"indices": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Indices lists the indices or index patterns to restore.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"renamePattern": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RenamePattern is a regular expression matching the names of the restored indices. Together with RenameReplacement it restores indices under a different name, which is needed while an open index with the same name exists.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"renameReplacement": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RenameReplacement is the name the indices matching RenamePattern are restored under. It can refer to the groups of the pattern, as in restored_$1.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"indices"},
+			},
+		},
+		Dependencies: []string{},
+	}
+}
+
+func schema_pkg_apis_operator_v1_LogStorageRestoreStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "LogStorageRestoreStatus defines the observed state of a restore.",
+				Properties: map[string]spec.Schema{
+					"state": {
+						SchemaProps: spec.SchemaProps{
+							Description: "State is Pending, Running, Completed or Failed.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"snapshot": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Snapshot is the name of the snapshot the indices are restored from.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Message describes why the restore failed.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"startTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "StartTime is the time at which the restore started.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"completionTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CompletionTime is the time at which the restore completed or failed.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
+func schema_pkg_apis_operator_v1_LogStorageSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "LogStorageSpec defines the desired state of Tigera flow and DNS log storage.",
+				Properties: map[string]spec.Schema{
+					"nodes": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Nodes defines the configuration for a set of identical Elasticsearch cluster nodes, each of type master, data, and ingest. It must be set unless External is.",
+							Ref:         ref("github.com/tigera/operator/pkg/apis/operator/v1.Nodes"),
+						},
+					},
+					"external": {
+						SchemaProps: spec.SchemaProps{
+							Description: "External stores logs in an existing Elasticsearch cluster instead of one run by the operator. Neither Elasticsearch nor Kibana is deployed, and the Elasticsearch users of the components are created in the external cluster.",
+							Ref:         ref("github.com/tigera/operator/pkg/apis/operator/v1.ExternalElasticsearch"),
+						},
+					},
+					"indices": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Index defines the configuration for the indices in the Elasticsearch cluster.",
+							Ref:         ref("github.com/tigera/operator/pkg/apis/operator/v1.Indices"),
+						},
+					},
+					"retention": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Retention defines how long data is retained in the Elasticsearch cluster before it is cleared.",
+							Ref:         ref("github.com/tigera/operator/pkg/apis/operator/v1.Retention"),
+						},
+					},
+					"storageClassName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "StorageClassName is the name of the StorageClass the volumes of the Elasticsearch nodes are provisioned with. The volumes can only be grown later on if the StorageClass allows volume expansion. Default: tigera-elasticsearch",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"indexLifecycle": {
+						SchemaProps: spec.SchemaProps{
+							Description: "IndexLifecycle configures when log indices are rolled over and how much of the Elasticsearch storage logs may take up. How long logs are kept is configured by Retention.",
+							Ref:         ref("github.com/tigera/operator/pkg/apis/operator/v1.IndexLifecycle"),
+						},
+					},
+					"snapshots": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Snapshots configures periodic snapshots of the Elasticsearch indices to a snapshot repository, from which indices can be restored with a LogStorageRestore.",
+							Ref:         ref("github.com/tigera/operator/pkg/apis/operator/v1.Snapshots"),
+						},
+					},
+					"users": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Users configures the Elasticsearch users that the components access Elasticsearch as. The operator creates a user with only the privileges it needs for each component.",
+							Ref:         ref("github.com/tigera/operator/pkg/apis/operator/v1.ElasticsearchUsers"),
+						},
+					},
+					"kibana": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kibana configures the Kibana instance that is deployed with the Elasticsearch cluster run by the operator.",
+							Ref:         ref("github.com/tigera/operator/pkg/apis/operator/v1.Kibana"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/tigera/operator/pkg/apis/operator/v1.ElasticsearchUsers", "github.com/tigera/operator/pkg/apis/operator/v1.ExternalElasticsearch", "github.com/tigera/operator/pkg/apis/operator/v1.IndexLifecycle", "github.com/tigera/operator/pkg/apis/operator/v1.Indices", "github.com/tigera/operator/pkg/apis/operator/v1.Kibana", "github.com/tigera/operator/pkg/apis/operator/v1.Nodes", "github.com/tigera/operator/pkg/apis/operator/v1.Retention", "github.com/tigera/operator/pkg/apis/operator/v1.Snapshots"},
+	}
+}
+
+func schema_pkg_apis_operator_v1_LogStorageStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "LogStorageStatus defines the observed state of Tigera flow and DNS log storage.",
+				Properties: map[string]spec.Schema{
+					"state": {
+						SchemaProps: spec.SchemaProps{
+							Description: "State provides user-readable status.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"elasticsearchHash": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ElasticsearchHash represents the current revision and configuration of the installed Elasticsearch cluster. This is an opaque string which can be monitored for changes to perform actions when Elasticsearch is modified.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"kibanaHash": {
+						SchemaProps: spec.SchemaProps{
+							Description: "KibanaHash represents the current revision and configuration of the installed Kibana dashboard. This is an opaque string which can be monitored for changes to perform actions when Kibana is modified.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"indexLifecyclePolicies": {
+						SchemaProps: spec.SchemaProps{
+							Description: "IndexLifecyclePolicies reports whether the index lifecycle policy of each log type was applied to the Elasticsearch cluster.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/tigera/operator/pkg/apis/operator/v1.IndexLifecyclePolicyStatus"),
+									},
+								},
+							},
+						},
+					},
+					"external": {
+						SchemaProps: spec.SchemaProps{
+							Description: "External reports whether the operator can reach the external Elasticsearch cluster, if one is configured.",
+							Ref:         ref("github.com/tigera/operator/pkg/apis/operator/v1.ExternalElasticsearchStatus"),
+						},
+					},
+					"kibana": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kibana reports the health of Kibana and the import of its saved objects.",
+							Ref:         ref("github.com/tigera/operator/pkg/apis/operator/v1.KibanaStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/tigera/operator/pkg/apis/operator/v1.ExternalElasticsearchStatus", "github.com/tigera/operator/pkg/apis/operator/v1.IndexLifecyclePolicyStatus", "github.com/tigera/operator/pkg/apis/operator/v1.KibanaStatus"},
+	}
+}
+
+func schema_pkg_apis_operator_v1_ManagedCluster(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ManagedCluster registers a managed cluster with a management cluster. The operator issues the managed cluster a tunnel certificate and generates the manifest that connects it to this management cluster. ManagedClusters are only reconciled when the cluster management type is Management.",
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/tigera/operator/pkg/apis/operator/v1.ManagedClusterSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/tigera/operator/pkg/apis/operator/v1.ManagedClusterStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/tigera/operator/pkg/apis/operator/v1.ManagedClusterSpec", "github.com/tigera/operator/pkg/apis/operator/v1.ManagedClusterStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_operator_v1_ManagedClusterSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ManagedClusterSpec defines the desired state of ManagedCluster",
+				Properties: map[string]spec.Schema{
+					"managementClusterAddr": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ManagementClusterAddr is the address at which the managed cluster reaches the tunnel endpoint of this management cluster. Ex.: \"10.128.0.10:30449\". It is written into the generated manifest.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"logStorage": {
+						SchemaProps: spec.SchemaProps{
+							Description: "LogStorage overrides the LogStorage settings of the management cluster for the logs of this managed cluster.",
+							Ref:         ref("github.com/tigera/operator/pkg/apis/operator/v1.ManagedClusterLogStorage"),
+						},
+					},
+				},
+				Required: []string{"managementClusterAddr"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/tigera/operator/pkg/apis/operator/v1.ManagedClusterLogStorage"},
+	}
+}
+
+func schema_pkg_apis_operator_v1_ManagedClusterStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ManagedClusterStatus defines the observed state of ManagedCluster",
+				Properties: map[string]spec.Schema{
+					"manifestSecretName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ManifestSecretName is the name of the Secret in the tigera-operator namespace holding the manifest to apply on the managed cluster, under the key manifest.yaml. The manifest contains the ManagementClusterConnection and the tunnel certificate of the managed cluster.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"certificateFingerprint": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CertificateFingerprint is the fingerprint of the tunnel certificate issued to the managed cluster.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"certificateExpiry": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CertificateExpiry is the time at which the tunnel certificate of the managed cluster expires. A new certificate is issued ahead of expiry, after which the manifest must be applied on the managed cluster again.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"renewalPending": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RenewalPending is true while the manifest holds a renewed tunnel certificate that the managed cluster has not switched to yet. Apply the manifest on the managed cluster, then annotate this ManagedCluster with certs.tigera.io/acknowledged-fingerprint set to the PendingCertificateFingerprint to complete the renewal. The managed cluster reports the fingerprint of the certificate it uses in its ManagementClusterConnection.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"pendingCertificateFingerprint": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PendingCertificateFingerprint is the fingerprint of the renewed tunnel certificate while RenewalPending.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"connected": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Connected is true while the managed cluster has an open tunnel to this management cluster.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"lastSeen": {
+						SchemaProps: spec.SchemaProps{
+							Description: "LastSeen is the last time the managed cluster was observed to be connected. While the managed cluster stays connected it is refreshed every 10 minutes.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
+func schema_pkg_apis_operator_v1_ManagementClusterConnection(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ManagementClusterConnection represents a link between a managed cluster and a management cluster. At most one instance of this resource is supported. It must be named \"tigera-secure\".",
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/tigera/operator/pkg/apis/operator/v1.ManagementClusterConnectionSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/tigera/operator/pkg/apis/operator/v1.ManagementClusterConnectionStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/tigera/operator/pkg/apis/operator/v1.ManagementClusterConnectionSpec", "github.com/tigera/operator/pkg/apis/operator/v1.ManagementClusterConnectionStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_operator_v1_ManagementClusterConnectionSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ManagementClusterConnectionSpec defines the desired state of ManagementClusterConnection",
+				Properties: map[string]spec.Schema{
+					"managementClusterAddr": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Specify where the managed cluster can reach the management cluster. Ex.: \"10.128.0.10:30449\". A managed cluster should be able to access this address. This field is used by managed clusters only.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"proxyURL": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ProxyURL is the URL of the HTTP proxy through which the managed cluster reaches the management cluster, e.g. \"http://proxy.example.com:3128\". Traffic to in-cluster services does not use the proxy.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"tunnel": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Tunnel tunes how the tunnel to the management cluster is kept alive and re-established.",
+							Ref:         ref("github.com/tigera/operator/pkg/apis/operator/v1.TunnelConfig"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/tigera/operator/pkg/apis/operator/v1.TunnelConfig"},
+	}
+}
+
+func schema_pkg_apis_operator_v1_ManagementClusterConnectionStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ManagementClusterConnectionStatus defines the observed state of ManagementClusterConnection",
+				Properties: map[string]spec.Schema{
+					"connected": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Connected is true when the tunnel agent is running and the management cluster is reachable.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Message describes why the connection is down.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"lastTransitionTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "LastTransitionTime is the last time Connected changed. The status is only written when the connection changes, not on every probe.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"certificateFingerprint": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CertificateFingerprint is the fingerprint of the tunnel certificate of this cluster. After applying a manifest with a renewed certificate, the ManagedCluster in the management cluster is annotated with this fingerprint to acknowledge the renewal.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"certificateExpiry": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CertificateExpiry is the time at which the tunnel certificate of this cluster expires. The management cluster issues a new certificate ahead of expiry; apply its regenerated manifest to rotate the certificate.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
+func schema_pkg_apis_operator_v1_Manager(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Manager installs the Tigera Secure manager graphical user interface. At most one instance of this resource is supported. It must be named \"tigera-secure\".",
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Specification of the desired state for the Tigera Secure EE manager.",
+							Ref:         ref("github.com/tigera/operator/pkg/apis/operator/v1.ManagerSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Most recently observed state for the Tigera Secure EE manager.",
+							Ref:         ref("github.com/tigera/operator/pkg/apis/operator/v1.ManagerStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/tigera/operator/pkg/apis/operator/v1.ManagerSpec", "github.com/tigera/operator/pkg/apis/operator/v1.ManagerStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_operator_v1_ManagerExposure(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "LogStorage installs the components required for Tigera flow and DNS log storage. At most one instance of this resource is supported. It must be named \"tigera-secure\". When created, this installs an Elasticsearch cluster for use by Tigera Secure.",
+				Description: "ManagerExposure defines how the manager is exposed outside the cluster.",
 				Properties: map[string]spec.Schema{
-					"kind": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#types-kinds",
-							Type:        []string{"string"},
-							Format:      "",
-						},
-					},
-					"apiVersion": {
+					"type": {
 						SchemaProps: spec.SchemaProps{
-							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#resources",
+							Description: "Type is the kind of exposure. Route is only supported on OpenShift.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"metadata": {
+					"ingress": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+							Description: "Ingress configures the Ingress when type is Ingress.",
+							Ref:         ref("github.com/tigera/operator/pkg/apis/operator/v1.ManagerIngress"),
 						},
 					},
-					"spec": {
+					"route": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Specification of the desired state for Tigera log storage.",
-							Ref:         ref("github.com/tigera/operator/pkg/apis/operator/v1.LogStorageSpec"),
+							Description: "Route configures the OpenShift Route when type is Route.",
+							Ref:         ref("github.com/tigera/operator/pkg/apis/operator/v1.ManagerRoute"),
 						},
 					},
-					"status": {
+					"nodePort": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Most recently observed state for Tigera log storage.",
-							Ref:         ref("github.com/tigera/operator/pkg/apis/operator/v1.LogStorageStatus"),
+							Description: "NodePort is the node port the manager service listens on when type is NodePort or LoadBalancer. If not set, a port is allocated by Kubernetes.",
+							Type:        []string{"integer"},
+							Format:      "int32",
 						},
 					},
 				},
+				Required: []string{"type"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/tigera/operator/pkg/apis/operator/v1.LogStorageSpec", "github.com/tigera/operator/pkg/apis/operator/v1.LogStorageStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+			"github.com/tigera/operator/pkg/apis/operator/v1.ManagerIngress", "github.com/tigera/operator/pkg/apis/operator/v1.ManagerRoute"},
 	}
 }
 
-func schema_pkg_apis_operator_v1_LogStorageSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_operator_v1_ManagerFeatures(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "LogStorageSpec defines the desired state of Tigera flow and DNS log storage.",
+				Description: "ManagerFeatures defines the optional features of the Tigera Secure manager GUI.",
 				Properties: map[string]spec.Schema{
-					"nodes": {
+					"policyRecommendation": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Nodes defines the configuration for a set of identical Elasticsearch cluster nodes, each of type master, data, and ingest.",
-							Ref:         ref("github.com/tigera/operator/pkg/apis/operator/v1.Nodes"),
-						},
-					},
-					"indices": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Index defines the configuration for the indices in the Elasticsearch cluster.",
-							Ref:         ref("github.com/tigera/operator/pkg/apis/operator/v1.Indices"),
+							Description: "PolicyRecommendation enables policy recommendations in the manager. Default: true",
+							Type:        []string{"boolean"},
+							Format:      "",
 						},
 					},
-					"retention": {
+					"applicationLayerPolicy": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Retention defines how long data is retained in the Elasticsearch cluster before it is cleared.",
-							Ref:         ref("github.com/tigera/operator/pkg/apis/operator/v1.Retention"),
+							Description: "ApplicationLayerPolicy enables the application layer policy features in the manager. Default: true",
+							Type:        []string{"boolean"},
+							Format:      "",
 						},
 					},
 				},
 			},
 		},
-		Dependencies: []string{
-			"github.com/tigera/operator/pkg/apis/operator/v1.Indices", "github.com/tigera/operator/pkg/apis/operator/v1.Nodes", "github.com/tigera/operator/pkg/apis/operator/v1.Retention"},
+		Dependencies: []string{},
 	}
 }
 
-func schema_pkg_apis_operator_v1_LogStorageStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_operator_v1_ManagerIngress(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "LogStorageStatus defines the observed state of Tigera flow and DNS log storage.",
+				Description: "ManagerIngress defines the Ingress exposing the manager. The Ingress controller must connect to the manager over HTTPS.",
 				Properties: map[string]spec.Schema{
-					"state": {
+					"host": {
 						SchemaProps: spec.SchemaProps{
-							Description: "State provides user-readable status.",
+							Description: "Host is the host name users reach the manager at.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"elasticsearchHash": {
+					"class": {
 						SchemaProps: spec.SchemaProps{
-							Description: "ElasticsearchHash represents the current revision and configuration of the installed Elasticsearch cluster. This is an opaque string which can be monitored for changes to perform actions when Elasticsearch is modified.",
+							Description: "Class is the Ingress class, set as the kubernetes.io/ingress.class annotation.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"kibanaHash": {
+					"tlsSecretName": {
 						SchemaProps: spec.SchemaProps{
-							Description: "KibanaHash represents the current revision and configuration of the installed Kibana dashboard. This is an opaque string which can be monitored for changes to perform actions when Kibana is modified.",
+							Description: "TLSSecretName is the name of a Secret in the tigera-manager namespace holding the certificate served by the Ingress. If not set, the Ingress controller's default certificate is used.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
+					"annotations": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Annotations are added to the Ingress, for example to configure the Ingress controller.",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
 				},
+				Required: []string{"host"},
 			},
 		},
 		Dependencies: []string{},
 	}
 }
 
-func schema_pkg_apis_operator_v1_ManagementClusterConnection(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_operator_v1_ManagerLogLevels(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "ManagementClusterConnection represents a link between a managed cluster and a management cluster. At most one instance of this resource is supported. It must be named \"tigera-secure\".",
+				Description: "ManagerLogLevels defines the log levels of the containers in the manager pod.",
 				Properties: map[string]spec.Schema{
-					"kind": {
+					"manager": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#types-kinds",
+							Description: "Manager is the log level of the manager container. Default: Info",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"apiVersion": {
+					"proxy": {
 						SchemaProps: spec.SchemaProps{
-							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#resources",
+							Description: "Proxy is the log level of the manager proxy (Voltron) container. Default: Info",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"metadata": {
-						SchemaProps: spec.SchemaProps{
-							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
-						},
-					},
-					"spec": {
+					"esProxy": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("github.com/tigera/operator/pkg/apis/operator/v1.ManagementClusterConnectionSpec"),
+							Description: "ESProxy is the log level of the Elasticsearch proxy container. Default: Info",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
 				},
 			},
 		},
-		Dependencies: []string{
-			"github.com/tigera/operator/pkg/apis/operator/v1.ManagementClusterConnectionSpec", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+		Dependencies: []string{},
 	}
 }
 
-func schema_pkg_apis_operator_v1_ManagementClusterConnectionSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_operator_v1_ManagerRoute(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "ManagementClusterConnectionSpec defines the desired state of ManagementClusterConnection",
+				Description: "ManagerRoute defines the OpenShift Route exposing the manager. TLS is passed through to the manager.",
 				Properties: map[string]spec.Schema{
-					"managementClusterAddr": {
+					"host": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Specify where the managed cluster can reach the management cluster. Ex.: \"10.128.0.10:30449\". A managed cluster should be able to access this address. This field is used by managed clusters only.",
+							Description: "Host is the host name users reach the manager at. If not set, OpenShift generates one.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
@@ -693,68 +1758,74 @@ func schema_pkg_apis_operator_v1_ManagementClusterConnectionSpec(ref common.Refe
 	}
 }
 
-func schema_pkg_apis_operator_v1_Manager(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_operator_v1_ManagerSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "Manager installs the Tigera Secure manager graphical user interface. At most one instance of this resource is supported. It must be named \"tigera-secure\".",
+				Description: "ManagerSpec defines configuration for the Tigera Secure manager GUI.",
 				Properties: map[string]spec.Schema{
-					"kind": {
+					"auth": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#types-kinds",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "Auth defines the authentication strategy for the Tigera Secure manager GUI.",
+							Ref:         ref("github.com/tigera/operator/pkg/apis/operator/v1.Auth"),
 						},
 					},
-					"apiVersion": {
+					"clusterName": {
 						SchemaProps: spec.SchemaProps{
-							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#resources",
+							Description: "ClusterName is the name displayed for this cluster in the Tigera Secure manager GUI. Default: cluster",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"metadata": {
+					"features": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+							Description: "Features enables or disables optional features of the Tigera Secure manager GUI.",
+							Ref:         ref("github.com/tigera/operator/pkg/apis/operator/v1.ManagerFeatures"),
 						},
 					},
-					"spec": {
+					"logLevels": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Specification of the desired state for the Tigera Secure EE manager.",
-							Ref:         ref("github.com/tigera/operator/pkg/apis/operator/v1.ManagerSpec"),
+							Description: "LogLevels configures the log level of the manager containers.",
+							Ref:         ref("github.com/tigera/operator/pkg/apis/operator/v1.ManagerLogLevels"),
 						},
 					},
-					"status": {
+					"replicas": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Most recently observed state for the Tigera Secure EE manager.",
-							Ref:         ref("github.com/tigera/operator/pkg/apis/operator/v1.ManagerStatus"),
+							Description: "Replicas is the number of manager pods to run. When more than one replica is requested the deployment is rolled one pod at a time so that the manager remains available during node drains. Default: 1",
+							Type:        []string{"integer"},
+							Format:      "int32",
 						},
 					},
-				},
-			},
-		},
-		Dependencies: []string{
-			"github.com/tigera/operator/pkg/apis/operator/v1.ManagerSpec", "github.com/tigera/operator/pkg/apis/operator/v1.ManagerStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
-	}
-}
-
-func schema_pkg_apis_operator_v1_ManagerSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
-	return common.OpenAPIDefinition{
-		Schema: spec.Schema{
-			SchemaProps: spec.SchemaProps{
-				Description: "ManagerSpec defines configuration for the Tigera Secure manager GUI.",
-				Properties: map[string]spec.Schema{
-					"auth": {
+					"identityBroker": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Auth defines the authentication strategy for the Tigera Secure manager GUI.",
-							Ref:         ref("github.com/tigera/operator/pkg/apis/operator/v1.Auth"),
+							Description: "IdentityBroker deploys an identity broker (Dex) that lets users log in to the manager with LDAP, SAML or GitHub accounts. When set, auth type must be OIDC and the manager's OIDC settings are configured to use the broker.",
+							Ref:         ref("github.com/tigera/operator/pkg/apis/operator/v1.IdentityBroker"),
+						},
+					},
+					"exposure": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Exposure configures how the manager is reachable from outside the cluster. By default the manager is only exposed through a ClusterIP service.",
+							Ref:         ref("github.com/tigera/operator/pkg/apis/operator/v1.ManagerExposure"),
+						},
+					},
+					"roleBindings": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RoleBindings grant manager users and identity provider groups one of the predefined manager profiles. The operator renders the ClusterRoles and ClusterRoleBindings for each binding and removes them when the binding is removed.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/tigera/operator/pkg/apis/operator/v1.UIRoleBinding"),
+									},
+								},
+							},
 						},
 					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/tigera/operator/pkg/apis/operator/v1.Auth"},
+			"github.com/tigera/operator/pkg/apis/operator/v1.Auth", "github.com/tigera/operator/pkg/apis/operator/v1.IdentityBroker", "github.com/tigera/operator/pkg/apis/operator/v1.ManagerExposure", "github.com/tigera/operator/pkg/apis/operator/v1.ManagerFeatures", "github.com/tigera/operator/pkg/apis/operator/v1.ManagerLogLevels", "github.com/tigera/operator/pkg/apis/operator/v1.UIRoleBinding"},
 	}
 }
 
@@ -770,6 +1841,13 @@ func schema_pkg_apis_operator_v1_ManagerStatus(ref common.ReferenceCallback) com
 							Ref:         ref("github.com/tigera/operator/pkg/apis/operator/v1.Auth"),
 						},
 					},
+					"url": {
+						SchemaProps: spec.SchemaProps{
+							Description: "URL is the external URL of the manager, when it is exposed outside the cluster and the address is known.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
 			},
 		},
@@ -813,6 +1891,55 @@ func schema_pkg_apis_operator_v1_S3StoreSpec(ref common.ReferenceCallback) commo
 	}
 }
 
+func schema_pkg_apis_operator_v1_SAMLConnector(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "SAMLConnector defines a SAML 2.0 identity provider.",
+				Properties: map[string]spec.Schema{
+					"ssoURL": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SSOURL is the URL of the identity provider's single sign-on endpoint.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"entityIssuer": {
+						SchemaProps: spec.SchemaProps{
+							Description: "EntityIssuer is the issuer value sent in authentication requests.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"usernameAttribute": {
+						SchemaProps: spec.SchemaProps{
+							Description: "UsernameAttribute is the assertion attribute holding the username. Default: name",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"emailAttribute": {
+						SchemaProps: spec.SchemaProps{
+							Description: "EmailAttribute is the assertion attribute holding the user's email address. Default: email",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"groupsAttribute": {
+						SchemaProps: spec.SchemaProps{
+							Description: "GroupsAttribute is the assertion attribute holding the user's groups.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"ssoURL"},
+			},
+		},
+		Dependencies: []string{},
+	}
+}
+
 func schema_pkg_apis_operator_v1_TigeraStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -886,11 +2013,140 @@ func schema_pkg_apis_operator_v1_TigeraStatusStatus(ref common.ReferenceCallback
 							},
 						},
 					},
+					"certificates": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Certificates lists the certificates managed for this component and when they expire.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/tigera/operator/pkg/apis/operator/v1.CertificateStatus"),
+									},
+								},
+							},
+						},
+					},
 				},
 				Required: []string{"conditions"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/tigera/operator/pkg/apis/operator/v1.TigeraStatusCondition"},
+			"github.com/tigera/operator/pkg/apis/operator/v1.CertificateStatus", "github.com/tigera/operator/pkg/apis/operator/v1.TigeraStatusCondition"},
+	}
+}
+
+func schema_pkg_apis_operator_v1_TunnelConfig(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "TunnelConfig defines the keepalive and reconnect behavior of the tunnel to the management cluster. By default the certificate of the management cluster is verified using the management cluster CA from the tigera-managed-cluster-connection Secret. To verify it with another CA, create a ConfigMap named tigera-management-cluster-ca-bundle in the tigera-operator namespace holding the CA bundle under the key ca.crt.",
+				Properties: map[string]spec.Schema{
+					"keepAlive": {
+						SchemaProps: spec.SchemaProps{
+							Description: "KeepAlive enables keepalive probes on the tunnel connection, which detect a broken tunnel and keep idle connections open through proxies and load balancers. Default: true",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"keepAliveInterval": {
+						SchemaProps: spec.SchemaProps{
+							Description: "KeepAliveInterval is the time between keepalive probes.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+					"dialTimeout": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DialTimeout is how long a single attempt to connect to the management cluster may take.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+					"reconnectInterval": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ReconnectInterval is the time between attempts to re-establish the tunnel.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+					"reconnectAttempts": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ReconnectAttempts is the number of consecutive failed attempts after which the tunnel agent exits and is restarted.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Duration"},
+	}
+}
+
+func schema_pkg_apis_operator_v1_UIRoleBinding(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "UIRoleBinding grants a set of users and groups a predefined manager profile.",
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name identifies the binding. It is used in the names of the generated RBAC resources and must be a valid DNS label.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"profile": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Profile is the set of permissions granted.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"groups": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Groups are the identity provider groups granted the profile. Groups are matched as seen by Kubernetes, so when Auth.GroupsPrefix is set it is prepended to each group.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"users": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Users are the users granted the profile. When Auth.UsernamePrefix is set it is prepended to each user.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"tiers": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Tiers are the Calico policy tiers the binding may edit. Required, and only valid, for the TierEditor profile.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"name", "profile"},
+			},
+		},
+		Dependencies: []string{},
 	}
 }
@@ -39,11 +39,14 @@ const (
 	VersionManagerProxy   = "v2.7.0-0.dev-30-g75b3524"
 	VersionManagerEsProxy = "v2.7.0-0.dev-22-g2e2f167"
 
+	// Identity broker image.
+	VersionDex = "v2.22.0"
+
 	// ECK Elasticsearch images
 	VersionECKOperator      = "0.9.0"
+	VersionECKOperatorV1    = "1.0.1"
 	VersionECKElasticsearch = "7.3.2"
 	VersionECKKibana        = "7.3.2"
-	VersionEsCurator        = "v2.6.0-0.dev-25-gb04da05"
 
 	VersionKibana = "7.3"
 
@@ -69,6 +69,10 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		return fmt.Errorf("apiserver-controller failed to watch the Secret resource: %v", err)
 	}
 
+	if err = utils.AddAPIServiceWatch(c); err != nil {
+		return fmt.Errorf("apiserver-controller failed to watch APIService resource: %v", err)
+	}
+
 	// TODO: Watch for dependent objects.
 
 	log.V(5).Info("Controller created and Watches setup")
@@ -176,6 +180,25 @@ func (r *ReconcileAPIServer) Reconcile(request reconcile.Request) (reconcile.Res
 		return reconcile.Result{RequeueAfter: 30 * time.Second}, nil
 	}
 
+	// The API server pods may be running before the aggregated API is being served. Other controllers
+	// depend on the Tigera API, so don't report ready until the APIService is available.
+	available, msg, err := utils.IsAPIServiceAvailable(ctx, r.client)
+	if err != nil {
+		r.status.SetDegraded("Error querying Tigera APIService", err.Error())
+		return reconcile.Result{}, err
+	}
+	if !available {
+		reqLogger.Info("Tigera API is not yet available", "reason", msg)
+		r.status.SetDegraded("Tigera API is not available", msg)
+		if instance.Status.State == operatorv1.APIServerStatusReady {
+			instance.Status.State = ""
+			if err = r.client.Status().Update(ctx, instance); err != nil {
+				return reconcile.Result{}, err
+			}
+		}
+		return reconcile.Result{RequeueAfter: 10 * time.Second}, nil
+	}
+
 	// Everything is available - update the CRD status.
 	instance.Status.State = operatorv1.APIServerStatusReady
 	if err = r.client.Status().Update(ctx, instance); err != nil {
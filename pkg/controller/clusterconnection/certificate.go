@@ -0,0 +1,66 @@
+// Copyright (c) 2020 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterconnection
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/tigera/operator/pkg/render"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// The management cluster renews the tunnel certificate well ahead of expiry. Degrade once the renewed certificate
+// has not been applied this close to expiry.
+const tunnelCertificateExpiryWarning = 7 * 24 * time.Hour
+
+// tunnelCertificateExpiry returns the expiry of the tunnel certificate in the Guardian secret, or nil if the secret
+// holds no certificate in the format generated by the management cluster.
+func tunnelCertificateExpiry(tunnelSecret *corev1.Secret) *metav1.Time {
+	expiry, err := render.CertificateExpiry(tunnelSecret.Data[render.ManagedClusterCertKey])
+	if err != nil {
+		return nil
+	}
+	t := metav1.NewTime(expiry)
+	return &t
+}
+
+// tunnelCertificateFingerprint returns the fingerprint of the tunnel certificate in the Guardian secret, which is
+// used to acknowledge a renewed certificate on the management cluster, or "" if the secret holds no certificate.
+func tunnelCertificateFingerprint(tunnelSecret *corev1.Secret) string {
+	fingerprint, err := render.ManagedClusterCertificateFingerprint(tunnelSecret.Data[render.ManagedClusterCertKey])
+	if err != nil {
+		return ""
+	}
+	return fingerprint
+}
+
+// checkTunnelCertificateExpiry returns an error if the tunnel certificate has expired or is about to.
+func checkTunnelCertificateExpiry(expiry *metav1.Time, now time.Time) error {
+	if expiry == nil {
+		return nil
+	}
+	if now.After(expiry.Time) {
+		return fmt.Errorf("the tunnel certificate in secret %s expired at %s, apply the manifest regenerated by the management cluster",
+			render.GuardianSecretName, expiry.UTC().Format(time.RFC3339))
+	}
+	if now.Add(tunnelCertificateExpiryWarning).After(expiry.Time) {
+		return fmt.Errorf("the tunnel certificate in secret %s expires at %s, apply the manifest regenerated by the management cluster",
+			render.GuardianSecretName, expiry.UTC().Format(time.RFC3339))
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package clusterconnection
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/tigera/operator/pkg/controller/installation"
 	"github.com/tigera/operator/pkg/controller/status"
@@ -12,6 +13,7 @@ import (
 	operatorv1 "github.com/tigera/operator/pkg/apis/operator/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -27,6 +29,14 @@ var log = logf.Log.WithName("clusterconnection_controller")
 
 const controllerName = "clusterconnection-controller"
 
+const (
+	// probeInterval is the interval at which the connection to the management cluster is probed.
+	probeInterval = 30 * time.Second
+	// persistentFailureThreshold is how long the connection may be down before the component is degraded, which
+	// leaves Guardian time to reconnect after a restart or a transient network failure.
+	persistentFailureThreshold = 5 * time.Minute
+)
+
 // Add creates a new ManagementClusterConnection Controller and adds it to the Manager. The Manager will set fields on the Controller
 // and start it when the Manager is started. This controller is meant only for enterprise users.
 func Add(mgr manager.Manager, p operatorv1.Provider, enterpriseEnabled bool) error {
@@ -39,11 +49,15 @@ func Add(mgr manager.Manager, p operatorv1.Provider, enterpriseEnabled bool) err
 
 // newReconciler returns a new reconcile.Reconciler
 func newReconciler(mgr manager.Manager, p operatorv1.Provider) reconcile.Reconciler {
-	return &ReconcileConnection{
+	r := &ReconcileConnection{
 		Client:   mgr.GetClient(),
 		Scheme:   mgr.GetScheme(),
 		Provider: p,
+		Status:   status.New(mgr.GetClient(), "management-cluster-connection"),
+		Probe:    dialManagementCluster,
 	}
+	r.Status.Run()
+	return r
 }
 
 // add adds a new controller to mgr with r as the reconcile.Reconciler
@@ -54,8 +68,9 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		return fmt.Errorf("failed to create %s: %v", controllerName, err)
 	}
 
-	// Watch for changes to primary resource ManagementClusterConnection
-	err = c.Watch(&source.Kind{Type: &operatorv1.ManagementClusterConnection{}}, &handler.EnqueueRequestForObject{})
+	// Watch for changes to primary resource ManagementClusterConnection. The controller writes the connection status
+	// itself and probes the connection on a timer, so only spec changes trigger a reconcile.
+	err = c.Watch(&source.Kind{Type: &operatorv1.ManagementClusterConnection{}}, &handler.EnqueueRequestForObject{}, utils.GenerationChangedPredicate)
 	if err != nil {
 		return fmt.Errorf("%s failed to watch primary resource: %v", controllerName, err)
 	}
@@ -65,6 +80,10 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		return fmt.Errorf("%s failed to watch Secret resource %s: %v", controllerName, render.GuardianSecretName, err)
 	}
 
+	if err = utils.AddConfigMapWatch(c, render.GuardianCABundleConfigMapName, render.OperatorNamespace()); err != nil {
+		return fmt.Errorf("%s failed to watch ConfigMap resource %s: %v", controllerName, render.GuardianCABundleConfigMapName, err)
+	}
+
 	if err = utils.AddNetworkWatch(c); err != nil {
 		return fmt.Errorf("%s failed to watch Network resource: %v", controllerName, err)
 	}
@@ -80,6 +99,9 @@ type ReconcileConnection struct {
 	Client   client.Client
 	Scheme   *runtime.Scheme
 	Provider operatorv1.Provider
+	Status   *status.StatusManager
+	// Probe checks that the management cluster is reachable.
+	Probe Prober
 }
 
 // Reconcile reads that state of the cluster for a ManagementClusterConnection object and makes changes based on the
@@ -108,19 +130,28 @@ func (r *ReconcileConnection) Reconcile(request reconcile.Request) (reconcile.Re
 			if instl.Spec.ClusterManagementType == operatorv1.ClusterManagementTypeManaged {
 				log.Error(err, "ManagementClusterConnection is a necessary resource for Managed clusters")
 			}
+			r.Status.OnCRNotFound()
 			return result, nil
 		}
 		return result, err
 	}
+	r.Status.OnCRFound()
 
 	if instl.Spec.ClusterManagementType != operatorv1.ClusterManagementTypeManaged {
 		log.Info(fmt.Sprintf("Setting up management cluster connection, even though clusterType != %v",
 			operatorv1.ClusterManagementTypeManaged))
 	}
 
+	if err = validateProxyURL(mcc.Spec.ProxyURL); err != nil {
+		reqLogger.Error(err, "Invalid ManagementClusterConnection configuration")
+		r.Status.SetDegraded("Invalid ManagementClusterConnection configuration", err.Error())
+		return result, nil
+	}
+
 	pullSecrets, err := utils.GetNetworkingPullSecrets(instl, r.Client)
 	if err != nil {
 		log.Error(err, "Error with Pull secrets")
+		r.Status.SetDegraded("Error retrieving pull secrets", err.Error())
 		return result, err
 	}
 
@@ -128,25 +159,104 @@ func (r *ReconcileConnection) Reconcile(request reconcile.Request) (reconcile.Re
 	tunnelSecret := &corev1.Secret{}
 	err = r.Client.Get(ctx, types.NamespacedName{Name: render.GuardianSecretName, Namespace: render.OperatorNamespace()}, tunnelSecret)
 	if err != nil {
-		if !errors.IsNotFound(err) {
+		if errors.IsNotFound(err) {
+			r.Status.SetDegraded(fmt.Sprintf("Waiting for secret %s to be created", render.GuardianSecretName), "")
 			return result, nil
 		}
+		r.Status.SetDegraded(fmt.Sprintf("Error reading secret %s", render.GuardianSecretName), err.Error())
 		return result, err
 	}
 
+	caBundle := &corev1.ConfigMap{}
+	err = r.Client.Get(ctx, types.NamespacedName{Name: render.GuardianCABundleConfigMapName, Namespace: render.OperatorNamespace()}, caBundle)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			r.Status.SetDegraded(fmt.Sprintf("Error reading configmap %s", render.GuardianCABundleConfigMapName), err.Error())
+			return result, err
+		}
+		caBundle = nil
+	}
+
 	ch := utils.NewComponentHandler(log, r.Client, r.Scheme, mcc)
 	component := render.Guardian(
-		mcc.Spec.ManagementClusterAddr,
+		mcc,
 		pullSecrets,
 		r.Provider == operatorv1.ProviderOpenShift,
 		instl.Spec.Registry,
 		tunnelSecret,
+		caBundle,
 	)
 
-	if err := ch.CreateOrUpdate(ctx, component, &status.StatusManager{}); err != nil {
+	if err := ch.CreateOrUpdate(ctx, component, r.Status); err != nil {
+		r.Status.SetDegraded("Error creating / updating resource", err.Error())
 		return result, err
 	}
 
-	//We should create the Guardian deployment.
-	return result, nil
+	// Report the expiry of the tunnel certificate. When the management cluster renews it, the renewed certificate is
+	// not fetched from the management cluster, since there is no channel to fetch it over besides the tunnel it
+	// authenticates. Applying the regenerated manifest updates the Guardian secret, which rolls out Guardian with the
+	// new certificate, and the expiry is reported until then.
+	certExpiry := tunnelCertificateExpiry(tunnelSecret)
+	if certExpiry != nil {
+		r.Status.SetCertificates([]operatorv1.CertificateStatus{{Name: render.GuardianSecretName, NotAfter: *certExpiry}})
+	} else {
+		r.Status.SetCertificates(nil)
+	}
+
+	// Probe the connection and degrade if it has been down for longer than Guardian needs to reconnect.
+	now := metav1.Now()
+	connStatus := operatorv1.ManagementClusterConnectionStatus{
+		Connected:              true,
+		CertificateFingerprint: tunnelCertificateFingerprint(tunnelSecret),
+		CertificateExpiry:      certExpiry,
+		LastTransitionTime:     mcc.Status.LastTransitionTime,
+	}
+	var degradedReason, degradedMsg string
+	if err := probeConnection(ctx, r.Client, mcc, r.Probe); err != nil {
+		reqLogger.Info("Management cluster connection is down", "reason", err.Error())
+		connStatus.Connected = false
+		connStatus.Message = err.Error()
+
+		// Before the status is first written, measure the outage from the creation of the resource.
+		downSince := mcc.CreationTimestamp.Time
+		if mcc.Status.LastTransitionTime != nil {
+			downSince = mcc.Status.LastTransitionTime.Time
+			if mcc.Status.Connected {
+				downSince = now.Time
+			}
+		}
+		if now.Sub(downSince) > persistentFailureThreshold {
+			degradedReason, degradedMsg = "Management cluster connection is down", err.Error()
+		}
+	}
+	// An expiring certificate is reported over a connection failure since it is likely the cause.
+	if err := checkTunnelCertificateExpiry(certExpiry, now.Time); err != nil {
+		reqLogger.Info("Tunnel certificate is expiring", "reason", err.Error())
+		degradedReason, degradedMsg = "Tunnel certificate is expiring", err.Error()
+	}
+	if degradedReason != "" {
+		r.Status.SetDegraded(degradedReason, degradedMsg)
+	} else {
+		r.Status.ClearDegraded()
+	}
+
+	if err := updateStatus(ctx, r.Client, mcc, connStatus, now); err != nil {
+		return result, err
+	}
+
+	return reconcile.Result{RequeueAfter: probeInterval}, nil
+}
+
+// updateStatus writes the connection status when it has changed. Probes run every probeInterval, so the status is
+// left alone while the connection stays the same to avoid rewriting it on every probe.
+func updateStatus(ctx context.Context, cli client.Client, mcc *operatorv1.ManagementClusterConnection, status operatorv1.ManagementClusterConnectionStatus, now metav1.Time) error {
+	if mcc.Status.LastTransitionTime == nil || mcc.Status.Connected != status.Connected {
+		status.LastTransitionTime = &now
+	} else if mcc.Status.Message == status.Message &&
+		mcc.Status.CertificateFingerprint == status.CertificateFingerprint &&
+		mcc.Status.CertificateExpiry.Equal(status.CertificateExpiry) {
+		return nil
+	}
+	mcc.Status = status
+	return cli.Status().Update(ctx, mcc)
 }
@@ -1,9 +1,18 @@
 package clusterconnection_test
 
 import (
+	"bytes"
 	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"time"
 
 	"github.com/tigera/operator/pkg/controller/clusterconnection"
+	"github.com/tigera/operator/pkg/controller/status"
 	"github.com/tigera/operator/pkg/render"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -29,6 +38,7 @@ var _ = Describe("ManagementClusterConnection controller tests", func() {
 	var r clusterconnection.ReconcileConnection
 	var scheme *runtime.Scheme
 	var dpl *appsv1.Deployment
+	var probedAddr, probedProxy string
 
 	BeforeSuite(func() {
 		// Create a Kubernetes client.
@@ -46,6 +56,12 @@ var _ = Describe("ManagementClusterConnection controller tests", func() {
 			Client:   c,
 			Scheme:   scheme,
 			Provider: operatorv1.ProviderNone,
+			Status:   status.New(c, "management-cluster-connection"),
+			Probe: func(addr, proxyURL string) error {
+				probedAddr = addr
+				probedProxy = proxyURL
+				return nil
+			},
 		}
 		dpl = &appsv1.Deployment{
 			TypeMeta: metav1.TypeMeta{Kind: "Deployment", APIVersion: "apps/v1"},
@@ -101,4 +117,85 @@ var _ = Describe("ManagementClusterConnection controller tests", func() {
 		Expect(dpl.Labels["k8s-app"]).To(Equal(render.GuardianName))
 
 	})
+
+	It("should report the connection status", func() {
+		By("reporting the connection as down while guardian is unavailable")
+		_, err := r.Reconcile(reconcile.Request{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.Get(ctx, client.ObjectKey{Name: "tigera-secure"}, cfg)).To(Succeed())
+		Expect(cfg.Status.Connected).To(BeFalse())
+		Expect(cfg.Status.Message).To(ContainSubstring("no available replicas"))
+		Expect(cfg.Status.LastTransitionTime).NotTo(BeNil())
+
+		By("probing the management cluster through the proxy once guardian is available")
+		Expect(c.Get(ctx, client.ObjectKey{Name: render.GuardianDeploymentName, Namespace: render.GuardianNamespace}, dpl)).To(Succeed())
+		dpl.Status.AvailableReplicas = 1
+		Expect(c.Update(ctx, dpl)).To(Succeed())
+		cfg.Spec.ProxyURL = "http://proxy.example.com:3128"
+		Expect(c.Update(ctx, cfg)).To(Succeed())
+
+		_, err = r.Reconcile(reconcile.Request{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(probedAddr).To(Equal("127.0.0.1:12345"))
+		Expect(probedProxy).To(Equal("http://proxy.example.com:3128"))
+		Expect(c.Get(ctx, client.ObjectKey{Name: "tigera-secure"}, cfg)).To(Succeed())
+		Expect(cfg.Status.Connected).To(BeTrue())
+		Expect(cfg.Status.Message).To(BeEmpty())
+		Expect(cfg.Status.LastTransitionTime).NotTo(BeNil())
+
+		By("leaving the status alone while the connection does not change")
+		resourceVersion := cfg.ResourceVersion
+		_, err = r.Reconcile(reconcile.Request{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.Get(ctx, client.ObjectKey{Name: "tigera-secure"}, cfg)).To(Succeed())
+		Expect(cfg.ResourceVersion).To(Equal(resourceVersion))
+
+		By("bypassing the proxy for addresses Guardian reaches directly")
+		cfg.Spec.ManagementClusterAddr = "tigera-voltron.tigera-manager.svc:9449"
+		Expect(c.Update(ctx, cfg)).To(Succeed())
+		_, err = r.Reconcile(reconcile.Request{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(probedAddr).To(Equal("tigera-voltron.tigera-manager.svc:9449"))
+		Expect(probedProxy).To(BeEmpty())
+
+		cfg.Spec.ManagementClusterAddr = "127.0.0.1:12345"
+		Expect(c.Update(ctx, cfg)).To(Succeed())
+		_, err = r.Reconcile(reconcile.Request{})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should report the tunnel certificate expiry", func() {
+		notAfter := time.Now().Add(72 * time.Hour).Truncate(time.Second)
+		secret := &corev1.Secret{}
+		Expect(c.Get(ctx, client.ObjectKey{Name: render.GuardianSecretName, Namespace: render.OperatorNamespace()}, secret)).To(Succeed())
+		secret.Data[render.ManagedClusterCertKey] = createCertificate(notAfter)
+		Expect(c.Update(ctx, secret)).To(Succeed())
+
+		_, err := r.Reconcile(reconcile.Request{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.Get(ctx, client.ObjectKey{Name: "tigera-secure"}, cfg)).To(Succeed())
+		Expect(cfg.Status.CertificateExpiry).NotTo(BeNil())
+		Expect(cfg.Status.CertificateExpiry.Time).To(BeTemporally("==", notAfter))
+		Expect(cfg.Status.CertificateFingerprint).NotTo(BeEmpty())
+
+		By("degrading while the certificate is about to expire")
+		Expect(r.Status.IsDegraded()).To(BeTrue())
+	})
 })
+
+// createCertificate returns a PEM encoded self-signed certificate that expires at notAfter.
+func createCertificate(notAfter time.Time) []byte {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	Expect(err).NotTo(HaveOccurred())
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "test"},
+		NotBefore:    time.Now(),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	Expect(err).NotTo(HaveOccurred())
+	var certPem bytes.Buffer
+	Expect(pem.Encode(&certPem, &pem.Block{Type: "CERTIFICATE", Bytes: der})).To(Succeed())
+	return certPem.Bytes()
+}
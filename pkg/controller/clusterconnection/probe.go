@@ -0,0 +1,178 @@
+// Copyright (c) 2020 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterconnection
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	operatorv1 "github.com/tigera/operator/pkg/apis/operator/v1"
+	"github.com/tigera/operator/pkg/render"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const probeTimeout = 10 * time.Second
+
+// Prober checks whether the management cluster address accepts connections, through the proxy if one is given.
+type Prober func(addr, proxyURL string) error
+
+// validateProxyURL validates the proxy URL of the ManagementClusterConnection.
+func validateProxyURL(proxyURL string) error {
+	if proxyURL == "" {
+		return nil
+	}
+	u, err := url.Parse(proxyURL)
+	if err != nil {
+		return fmt.Errorf("proxyURL %q is not a valid URL: %v", proxyURL, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("proxyURL %q must be an http or https URL", proxyURL)
+	}
+	return nil
+}
+
+// probeConnection returns nil if the tunnel to the management cluster is up: Guardian is running and the
+// management cluster is reachable from this cluster. The tunnel itself is not opened by the probe since the
+// management cluster only accepts a single tunnel per managed cluster.
+func probeConnection(ctx context.Context, cli client.Client, mcc *operatorv1.ManagementClusterConnection, probe Prober) error {
+	d := &appsv1.Deployment{}
+	err := cli.Get(ctx, types.NamespacedName{Name: render.GuardianDeploymentName, Namespace: render.GuardianNamespace}, d)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return fmt.Errorf("the %s deployment does not exist", render.GuardianDeploymentName)
+		}
+		return err
+	}
+	if d.Status.AvailableReplicas == 0 {
+		return fmt.Errorf("the %s deployment has no available replicas", render.GuardianDeploymentName)
+	}
+	if err := probe(mcc.Spec.ManagementClusterAddr, guardianProxyURL(d, mcc.Spec.ManagementClusterAddr)); err != nil {
+		return fmt.Errorf("the management cluster at %s is not reachable: %v", mcc.Spec.ManagementClusterAddr, err)
+	}
+	return nil
+}
+
+// guardianProxyURL returns the proxy Guardian uses to reach addr, so that the probe takes the same path as the
+// tunnel. It is read from the environment of the deployed Guardian rather than the spec, since the deployment may
+// still be rolling out a change, and it honours the NO_PROXY exclusions Guardian is configured with.
+func guardianProxyURL(d *appsv1.Deployment, addr string) string {
+	var proxyURL, noProxy string
+	for _, c := range d.Spec.Template.Spec.Containers {
+		if c.Name != render.GuardianDeploymentName {
+			continue
+		}
+		for _, env := range c.Env {
+			switch env.Name {
+			case "HTTPS_PROXY":
+				proxyURL = env.Value
+			case "NO_PROXY":
+				noProxy = env.Value
+			}
+		}
+	}
+	if proxyURL == "" {
+		return ""
+	}
+
+	host := addr
+	if h, _, err := net.SplitHostPort(addr); err == nil {
+		host = h
+	}
+	for _, exclusion := range strings.Split(noProxy, ",") {
+		exclusion = strings.TrimSpace(exclusion)
+		if exclusion == "" {
+			continue
+		}
+		if exclusion == "*" || host == strings.TrimPrefix(exclusion, ".") || strings.HasSuffix(host, "."+strings.TrimPrefix(exclusion, ".")) {
+			return ""
+		}
+	}
+	return proxyURL
+}
+
+// dialManagementCluster is the default Prober. It opens a TCP connection to the management cluster, using an
+// HTTP CONNECT request when a proxy is given.
+func dialManagementCluster(addr, proxyURL string) error {
+	if proxyURL == "" {
+		conn, err := net.DialTimeout("tcp", addr, probeTimeout)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}
+
+	proxy, err := url.Parse(proxyURL)
+	if err != nil {
+		return err
+	}
+	proxyAddr := proxy.Host
+	if proxy.Port() == "" {
+		port := "80"
+		if proxy.Scheme == "https" {
+			port = "443"
+		}
+		proxyAddr = net.JoinHostPort(proxy.Hostname(), port)
+	}
+	dialer := &net.Dialer{Timeout: probeTimeout}
+	var conn net.Conn
+	if proxy.Scheme == "https" {
+		conn, err = tls.DialWithDialer(dialer, "tcp", proxyAddr, &tls.Config{ServerName: proxy.Hostname()})
+	} else {
+		conn, err = dialer.Dial("tcp", proxyAddr)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to connect to proxy: %v", err)
+	}
+	defer conn.Close()
+	if err := conn.SetDeadline(time.Now().Add(probeTimeout)); err != nil {
+		return err
+	}
+
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: addr},
+		Host:   addr,
+		Header: http.Header{},
+	}
+	if proxy.User != nil {
+		password, _ := proxy.User.Password()
+		credentials := base64.StdEncoding.EncodeToString([]byte(proxy.User.Username() + ":" + password))
+		req.Header.Set("Proxy-Authorization", "Basic "+credentials)
+	}
+	if err := req.Write(conn); err != nil {
+		return fmt.Errorf("failed to send CONNECT request to proxy: %v", err)
+	}
+	resp, err := http.ReadResponse(bufio.NewReader(conn), req)
+	if err != nil {
+		return fmt.Errorf("failed to read CONNECT response from proxy: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("proxy refused the connection: %s", resp.Status)
+	}
+	return nil
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/tigera/operator/pkg/controller/utils"
 	"github.com/tigera/operator/pkg/render"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -68,6 +69,10 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		return fmt.Errorf("compliance-controller failed to watch APIServer resource: %v", err)
 	}
 
+	if err = utils.AddAPIServiceWatch(c); err != nil {
+		return fmt.Errorf("compliance-controller failed to watch APIService resource: %v", err)
+	}
+
 	for _, secretName := range []string{
 		render.ElasticsearchPublicCertSecret, render.ElasticsearchComplianceBenchmarkerUserSecret,
 		render.ElasticsearchComplianceControllerUserSecret, render.ElasticsearchComplianceReporterUserSecret,
@@ -77,7 +82,9 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		}
 	}
 
-	if err = utils.AddConfigMapWatch(c, render.ElasticsearchConfigMapName, render.OperatorNamespace()); err != nil {
+	// Watch all ConfigMaps in the operator namespace. This covers the Elasticsearch configuration as well as the report
+	// templates referenced by the Compliance resource, whose names are only known at reconcile time.
+	if err = utils.AddConfigMapWatch(c, "", render.OperatorNamespace()); err != nil {
 		return fmt.Errorf("compliance-controller failed to watch the ConfigMap resource: %v", err)
 	}
 
@@ -106,6 +113,26 @@ func GetCompliance(ctx context.Context, cli client.Client) (*operatorv1.Complian
 	return instance, nil
 }
 
+// getReportTemplates retrieves the ConfigMaps holding the templates of the report types in the Compliance resource,
+// keyed by report type name.
+func getReportTemplates(ctx context.Context, cli client.Client, instance *operatorv1.Compliance) (map[operatorv1.ComplianceReportTypeName]*corev1.ConfigMap, error) {
+	templates := map[operatorv1.ComplianceReportTypeName]*corev1.ConfigMap{}
+	for _, rt := range instance.Spec.ReportTypes {
+		if rt.Disabled || rt.TemplatesConfigMap == "" {
+			continue
+		}
+		cm := &corev1.ConfigMap{}
+		if err := cli.Get(ctx, client.ObjectKey{Name: rt.TemplatesConfigMap, Namespace: render.OperatorNamespace()}, cm); err != nil {
+			return nil, err
+		}
+		if _, ok := cm.Data[operatorv1.ComplianceReportTemplateUISummaryKey]; !ok {
+			return nil, fmt.Errorf("ConfigMap %s for report type %s is missing the %s key", rt.TemplatesConfigMap, rt.Name, operatorv1.ComplianceReportTemplateUISummaryKey)
+		}
+		templates[rt.Name] = cm
+	}
+	return templates, nil
+}
+
 // Reconcile reads that state of the cluster for a Compliance object and makes changes based on the state read
 // and what is in the Compliance.Spec
 // Note:
@@ -134,9 +161,16 @@ func (r *ReconcileCompliance) Reconcile(request reconcile.Request) (reconcile.Re
 	r.status.OnCRFound()
 	reqLogger.V(2).Info("Loaded config", "config", instance)
 
+	if err = validateCustomResource(instance); err != nil {
+		reqLogger.Error(err, "Invalid Compliance configuration")
+		r.status.SetDegraded("Invalid Compliance configuration", err.Error())
+		return reconcile.Result{}, nil
+	}
+
 	if !utils.IsAPIServerReady(r.client, reqLogger) {
 		r.status.SetDegraded("Waiting for Tigera API server to be ready", "")
-		return reconcile.Result{}, err
+		// Requeue through the rate limiter so that we back off while the aggregated API starts.
+		return reconcile.Result{Requeue: true}, nil
 	}
 
 	if err = utils.CheckLicenseKey(ctx, r.client); err != nil {
@@ -195,18 +229,34 @@ func (r *ReconcileCompliance) Reconcile(request reconcile.Request) (reconcile.Re
 		return reconcile.Result{}, err
 	}
 
+	reportTemplates, err := getReportTemplates(ctx, r.client, instance)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			log.Info("Compliance report templates are not available yet, waiting until they become available")
+			r.status.SetDegraded("Compliance report templates are not available yet, waiting until they become available", err.Error())
+			return reconcile.Result{}, nil
+		}
+		r.status.SetDegraded("Failed to get compliance report templates", err.Error())
+		return reconcile.Result{}, err
+	}
+
 	// Create a component handler to manage the rendered component.
 	handler := utils.NewComponentHandler(log, r.client, r.scheme, instance)
 
 	reqLogger.V(3).Info("rendering components")
 	openshift := r.provider == operatorv1.ProviderOpenShift
 	// Render the desired objects from the CRD and create or update them.
-	component := render.Compliance(esSecrets, network, esClusterConfig, pullSecrets, openshift)
+	component := render.Compliance(esSecrets, network, instance, reportTemplates, esClusterConfig, pullSecrets, openshift)
 	if err := handler.CreateOrUpdate(context.Background(), component, r.status); err != nil {
 		r.status.SetDegraded("Error creating / updating resource", err.Error())
 		return reconcile.Result{}, err
 	}
 
+	if err := removeStaleReports(ctx, r.client, instance); err != nil {
+		r.status.SetDegraded("Error removing stale compliance reports", err.Error())
+		return reconcile.Result{}, err
+	}
+
 	// Clear the degraded bit if we've reached this far.
 	r.status.ClearDegraded()
 
@@ -0,0 +1,72 @@
+// Copyright (c) 2020 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compliance
+
+import (
+	"context"
+
+	v3 "github.com/tigera/api/pkg/apis/projectcalico/v3"
+	operatorv1 "github.com/tigera/operator/pkg/apis/operator/v1"
+	"github.com/tigera/operator/pkg/render"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// removeStaleReports deletes the GlobalReportTypes and GlobalReports that were rendered for report types and reports
+// that have since been disabled or removed from the Compliance CR. Built-in report types are always rendered by the
+// operator, the others are recognised by their label so that reports created by users are left alone.
+func removeStaleReports(ctx context.Context, cli client.Client, instance *operatorv1.Compliance) error {
+	builtIn := map[string]bool{}
+	for _, name := range render.ComplianceBuiltInReportTypes {
+		builtIn[string(name)] = true
+	}
+
+	reportTypes := v3.GlobalReportTypeList{}
+	if err := cli.List(ctx, &reportTypes); err != nil {
+		return err
+	}
+	desiredTypes := render.ComplianceReportTypeNames(instance)
+	for i := range reportTypes.Items {
+		grt := &reportTypes.Items[i]
+		if _, ok := grt.Labels[render.ComplianceReportTypeLabel]; !ok && !builtIn[grt.Name] {
+			continue
+		}
+		if desiredTypes[grt.Name] {
+			continue
+		}
+		log.Info("Removing compliance report type", "globalreporttype", grt.Name)
+		if err := cli.Delete(ctx, grt); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	reports := v3.GlobalReportList{}
+	if err := cli.List(ctx, &reports); err != nil {
+		return err
+	}
+	desiredReports := render.ComplianceReportNames(instance)
+	for i := range reports.Items {
+		gr := &reports.Items[i]
+		if _, ok := gr.Labels[render.ComplianceReportLabel]; !ok || desiredReports[gr.Name] {
+			continue
+		}
+		log.Info("Removing compliance report", "globalreport", gr.Name)
+		if err := cli.Delete(ctx, gr); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
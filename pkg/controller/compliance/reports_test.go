@@ -0,0 +1,104 @@
+// Copyright (c) 2020 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compliance
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	v3 "github.com/tigera/api/pkg/apis/projectcalico/v3"
+	"github.com/tigera/operator/pkg/apis"
+	operatorv1 "github.com/tigera/operator/pkg/apis/operator/v1"
+	"github.com/tigera/operator/pkg/render"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Compliance report pruning", func() {
+	var c client.Client
+	var instance *operatorv1.Compliance
+	ctx := context.Background()
+
+	reportTypeNames := func() []string {
+		list := v3.GlobalReportTypeList{}
+		Expect(c.List(ctx, &list)).To(Succeed())
+		var names []string
+		for _, grt := range list.Items {
+			names = append(names, grt.Name)
+		}
+		return names
+	}
+
+	reportNames := func() []string {
+		list := v3.GlobalReportList{}
+		Expect(c.List(ctx, &list)).To(Succeed())
+		var names []string
+		for _, gr := range list.Items {
+			names = append(names, gr.Name)
+		}
+		return names
+	}
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(apis.AddToScheme(scheme)).NotTo(HaveOccurred())
+		c = fake.NewFakeClientWithScheme(scheme)
+
+		instance = &operatorv1.Compliance{
+			ObjectMeta: metav1.ObjectMeta{Name: "tigera-secure"},
+			Spec: operatorv1.ComplianceSpec{
+				ReportTypes: []operatorv1.ComplianceReportType{{Name: "custom", TemplatesConfigMap: "custom-templates"}},
+				Reports:     []operatorv1.ComplianceReport{{Name: "daily-inventory", ReportType: "inventory", Schedule: "0 0 * * *"}},
+			},
+		}
+
+		for _, name := range render.ComplianceBuiltInReportTypes {
+			Expect(c.Create(ctx, &v3.GlobalReportType{ObjectMeta: metav1.ObjectMeta{Name: string(name)}})).To(Succeed())
+		}
+		Expect(c.Create(ctx, &v3.GlobalReportType{ObjectMeta: metav1.ObjectMeta{
+			Name:   "custom",
+			Labels: map[string]string{render.ComplianceReportTypeLabel: "custom"},
+		}})).To(Succeed())
+		Expect(c.Create(ctx, &v3.GlobalReportType{ObjectMeta: metav1.ObjectMeta{Name: "users-own"}})).To(Succeed())
+		Expect(c.Create(ctx, &v3.GlobalReport{ObjectMeta: metav1.ObjectMeta{
+			Name:   "daily-inventory",
+			Labels: map[string]string{render.ComplianceReportLabel: "daily-inventory"},
+		}})).To(Succeed())
+		Expect(c.Create(ctx, &v3.GlobalReport{ObjectMeta: metav1.ObjectMeta{Name: "users-own"}})).To(Succeed())
+	})
+
+	It("should keep the report types and reports in the Compliance CR", func() {
+		Expect(removeStaleReports(ctx, c, instance)).To(Succeed())
+		Expect(reportTypeNames()).To(ConsistOf("inventory", "network-access", "policy-audit", "cis-benchmark", "custom", "users-own"))
+		Expect(reportNames()).To(ConsistOf("daily-inventory", "users-own"))
+	})
+
+	It("should remove report types and reports toggled off in the Compliance CR", func() {
+		instance.Spec.ReportTypes = []operatorv1.ComplianceReportType{
+			{Name: operatorv1.ComplianceReportTypeNetworkAccess, Disabled: true},
+			{Name: "custom", TemplatesConfigMap: "custom-templates", Disabled: true},
+		}
+		instance.Spec.Reports = nil
+
+		Expect(removeStaleReports(ctx, c, instance)).To(Succeed())
+		Expect(reportTypeNames()).To(ConsistOf("inventory", "policy-audit", "cis-benchmark", "users-own"))
+		Expect(reportNames()).To(ConsistOf("users-own"))
+	})
+})
@@ -0,0 +1,62 @@
+// Copyright (c) 2020 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compliance
+
+import (
+	"fmt"
+
+	operatorv1 "github.com/tigera/operator/pkg/apis/operator/v1"
+	"github.com/tigera/operator/pkg/render"
+)
+
+// validateCustomResource validates that the given custom resource is correct. This
+// should be called before rendering objects.
+func validateCustomResource(instance *operatorv1.Compliance) error {
+	enabled := map[operatorv1.ComplianceReportTypeName]bool{}
+	for _, name := range render.ComplianceBuiltInReportTypes {
+		enabled[name] = true
+	}
+
+	seen := map[operatorv1.ComplianceReportTypeName]bool{}
+	for _, rt := range instance.Spec.ReportTypes {
+		if seen[rt.Name] {
+			return fmt.Errorf("report type %s is configured more than once", rt.Name)
+		}
+		seen[rt.Name] = true
+
+		_, builtIn := enabled[rt.Name]
+		if !builtIn && rt.TemplatesConfigMap == "" {
+			return fmt.Errorf("report type %s is not a built-in report type and must specify templatesConfigMap", rt.Name)
+		}
+		enabled[rt.Name] = !rt.Disabled
+	}
+
+	reports := map[string]bool{}
+	for _, r := range instance.Spec.Reports {
+		if reports[r.Name] {
+			return fmt.Errorf("report %s is configured more than once", r.Name)
+		}
+		reports[r.Name] = true
+
+		if !enabled[r.ReportType] {
+			return fmt.Errorf("report %s uses report type %s which is not installed", r.Name, r.ReportType)
+		}
+	}
+
+	if s := instance.Spec.Snapshotter; s != nil && s.Hour != nil && (*s.Hour < 0 || *s.Hour > 23) {
+		return fmt.Errorf("snapshotter hour must be between 0 and 23")
+	}
+	return nil
+}
@@ -0,0 +1,295 @@
+// Copyright (c) 2020 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package intrusiondetection
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	operatorv1 "github.com/tigera/operator/pkg/apis/operator/v1"
+	"github.com/tigera/operator/pkg/elasticsearch"
+	"github.com/tigera/operator/pkg/render"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// managedJobSetting is the custom setting that marks the jobs run as anomaly detectors, so that they are stopped
+	// once they are removed from the IntrusionDetection CR.
+	managedJobSetting = "tigera_operator_managed"
+
+	defaultTrainingPeriod = 7 * 24 * time.Hour
+)
+
+// anomalyDetectionClient is the part of the Elasticsearch client that anomaly detectors are run with.
+type anomalyDetectionClient interface {
+	Jobs(ctx context.Context) ([]elasticsearch.Job, error)
+	JobStats(ctx context.Context, jobID string) (elasticsearch.JobStats, error)
+	UpdateJob(ctx context.Context, jobID string, update elasticsearch.JobUpdate) error
+	OpenJob(ctx context.Context, jobID string) error
+	CloseJob(ctx context.Context, jobID string) error
+	Datafeeds(ctx context.Context) ([]elasticsearch.Datafeed, error)
+	DatafeedStats(ctx context.Context, datafeedID string) (elasticsearch.DatafeedStats, error)
+	UpdateDatafeed(ctx context.Context, datafeedID string, update elasticsearch.DatafeedUpdate) error
+	StartDatafeed(ctx context.Context, datafeedID string, start time.Time) error
+	StopDatafeed(ctx context.Context, datafeedID string) error
+}
+
+// newAnomalyDetectionClient returns a client that calls Elasticsearch as the intrusion detection job user, which is
+// allowed to manage the machine learning jobs.
+func newAnomalyDetectionClient(esClusterConfig *render.ElasticsearchClusterConfig, esSecrets []*corev1.Secret) (*elasticsearch.Client, error) {
+	var user, cert *corev1.Secret
+	for _, s := range esSecrets {
+		switch s.Name {
+		case render.ElasticsearchIntrusionDetectionJobUserSecret:
+			user = s
+		case render.ElasticsearchPublicCertSecret:
+			cert = s
+		}
+	}
+	if user == nil || cert == nil {
+		return nil, fmt.Errorf("the intrusion detection job user and the Elasticsearch certificate are required")
+	}
+	return elasticsearch.NewClient(esClusterConfig.URL(), string(user.Data["username"]), string(user.Data["password"]), cert.Data["tls.crt"])
+}
+
+// hasAnomalyDetectors returns true if anomaly detectors are configured, or were configured the last time the status
+// was reported and may have to be stopped.
+func hasAnomalyDetectors(instance *operatorv1.IntrusionDetection) bool {
+	return (instance.Spec.AnomalyDetection != nil && len(instance.Spec.AnomalyDetection.Detectors) > 0) ||
+		len(instance.Status.Detectors) > 0
+}
+
+// reconcileAnomalyDetectors runs the jobs of the anomaly detectors in the IntrusionDetection CR with their configured
+// limits and schedule, stops the detectors that have been removed, and returns the state of each detector. Requests
+// that Elasticsearch rejects are reported in the status of the detector, any other error is returned.
+func reconcileAnomalyDetectors(ctx context.Context, es anomalyDetectionClient, instance *operatorv1.IntrusionDetection) ([]operatorv1.AnomalyDetectorStatus, error) {
+	jobs, err := es.Jobs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	datafeeds, err := es.Datafeeds(ctx)
+	if err != nil {
+		return nil, err
+	}
+	jobsByID := map[string]elasticsearch.Job{}
+	for _, job := range jobs {
+		jobsByID[job.ID] = job
+	}
+	datafeedsByJob := map[string]elasticsearch.Datafeed{}
+	for _, df := range datafeeds {
+		datafeedsByJob[df.JobID] = df
+	}
+
+	var detectors []operatorv1.AnomalyDetector
+	if instance.Spec.AnomalyDetection != nil {
+		detectors = instance.Spec.AnomalyDetection.Detectors
+	}
+
+	enabled := map[string]bool{}
+	var statuses []operatorv1.AnomalyDetectorStatus
+	for _, d := range detectors {
+		enabled[d.Name] = true
+		job, ok := jobsByID[d.Name]
+		if !ok {
+			statuses = append(statuses, operatorv1.AnomalyDetectorStatus{Name: d.Name, Error: "the job does not exist in Elasticsearch"})
+			continue
+		}
+		df, ok := datafeedsByJob[d.Name]
+		if !ok {
+			statuses = append(statuses, operatorv1.AnomalyDetectorStatus{Name: d.Name, Error: "the job has no datafeed"})
+			continue
+		}
+
+		runErr := runAnomalyDetector(ctx, es, d, job, df)
+		if runErr != nil && !isRejected(runErr) {
+			return nil, runErr
+		}
+		status, err := anomalyDetectorStatus(ctx, es, job.ID, df.ID)
+		if err != nil {
+			return nil, err
+		}
+		if runErr != nil {
+			status.Error = runErr.Error()
+		}
+		statuses = append(statuses, status)
+	}
+
+	for _, job := range jobs {
+		if enabled[job.ID] || job.CustomSettings[managedJobSetting] != true {
+			continue
+		}
+		log.Info("Stopping anomaly detector", "job", job.ID)
+		if err := stopAnomalyDetector(ctx, es, job, datafeedsByJob[job.ID]); err != nil {
+			return nil, err
+		}
+	}
+	return statuses, nil
+}
+
+// runAnomalyDetector applies the limits and schedule of the detector to its job and datafeed, restarting them if
+// needed, and makes sure they are running.
+func runAnomalyDetector(ctx context.Context, es anomalyDetectionClient, d operatorv1.AnomalyDetector, job elasticsearch.Job, df elasticsearch.Datafeed) error {
+	jobStats, err := es.JobStats(ctx, job.ID)
+	if err != nil {
+		return err
+	}
+	if jobStats.State == elasticsearch.JobStateFailed {
+		// A failed job has to be force closed by an administrator, its status reports why it failed.
+		return nil
+	}
+	dfStats, err := es.DatafeedStats(ctx, df.ID)
+	if err != nil {
+		return err
+	}
+	jobOpen := jobStats.State == elasticsearch.JobStateOpened || jobStats.State == elasticsearch.JobStateOpening
+	dfStarted := dfStats.State == elasticsearch.DatafeedStateStarted || dfStats.State == elasticsearch.DatafeedStateStarting
+
+	limitChanged := false
+	if d.ModelMemoryLimit != "" {
+		want, err := elasticsearch.ParseByteSize(d.ModelMemoryLimit)
+		if err != nil {
+			return err
+		}
+		have, err := elasticsearch.ParseByteSize(job.AnalysisLimits.ModelMemoryLimit)
+		limitChanged = err != nil || have != want
+	}
+	intervalChanged := false
+	if d.DetectionInterval != nil {
+		have, err := elasticsearch.ParseTimeValue(df.Frequency)
+		intervalChanged = err != nil || have != d.DetectionInterval.Duration
+	}
+
+	// The datafeed has to be restarted to pick up its new schedule, and the job has to be closed to change its limits.
+	if dfStarted && (limitChanged || intervalChanged) {
+		if err := es.StopDatafeed(ctx, df.ID); err != nil {
+			return err
+		}
+		dfStarted = false
+	}
+	if jobOpen && limitChanged {
+		if err := es.CloseJob(ctx, job.ID); err != nil {
+			return err
+		}
+		jobOpen = false
+	}
+
+	if limitChanged || job.CustomSettings[managedJobSetting] != true {
+		update := elasticsearch.JobUpdate{CustomSettings: map[string]interface{}{managedJobSetting: true}}
+		for k, v := range job.CustomSettings {
+			if k != managedJobSetting {
+				update.CustomSettings[k] = v
+			}
+		}
+		if limitChanged {
+			update.AnalysisLimits = &elasticsearch.AnalysisLimits{ModelMemoryLimit: d.ModelMemoryLimit}
+		}
+		if err := es.UpdateJob(ctx, job.ID, update); err != nil {
+			return err
+		}
+	}
+	if intervalChanged {
+		frequency := fmt.Sprintf("%ds", int64(d.DetectionInterval.Duration/time.Second))
+		if err := es.UpdateDatafeed(ctx, df.ID, elasticsearch.DatafeedUpdate{Frequency: frequency}); err != nil {
+			return err
+		}
+	}
+
+	if !jobOpen {
+		log.Info("Opening anomaly detector", "job", job.ID)
+		if err := es.OpenJob(ctx, job.ID); err != nil {
+			return err
+		}
+	}
+	if !dfStarted {
+		// A new model is trained on the logs of the training period, a model that has processed logs before
+		// continues where it stopped.
+		var start time.Time
+		if jobStats.DataCounts.LatestRecordTimestamp == 0 {
+			period := defaultTrainingPeriod
+			if d.TrainingPeriod != nil {
+				period = d.TrainingPeriod.Duration
+			}
+			start = time.Now().Add(-period)
+		}
+		if err := es.StartDatafeed(ctx, df.ID, start); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// stopAnomalyDetector stops the datafeed and closes the job of a detector that was removed, and removes the mark
+// that it is run as a detector.
+func stopAnomalyDetector(ctx context.Context, es anomalyDetectionClient, job elasticsearch.Job, df elasticsearch.Datafeed) error {
+	if df.ID != "" {
+		if err := es.StopDatafeed(ctx, df.ID); err != nil {
+			return err
+		}
+	}
+	if err := es.CloseJob(ctx, job.ID); err != nil {
+		return err
+	}
+	update := elasticsearch.JobUpdate{CustomSettings: map[string]interface{}{}}
+	for k, v := range job.CustomSettings {
+		if k != managedJobSetting {
+			update.CustomSettings[k] = v
+		}
+	}
+	return es.UpdateJob(ctx, job.ID, update)
+}
+
+// anomalyDetectorStatus reports the state of the job and datafeed of a detector.
+func anomalyDetectorStatus(ctx context.Context, es anomalyDetectionClient, jobID, datafeedID string) (operatorv1.AnomalyDetectorStatus, error) {
+	status := operatorv1.AnomalyDetectorStatus{Name: jobID}
+	jobStats, err := es.JobStats(ctx, jobID)
+	if err != nil {
+		if !isRejected(err) {
+			return status, err
+		}
+		status.Error = err.Error()
+		return status, nil
+	}
+	dfStats, err := es.DatafeedStats(ctx, datafeedID)
+	if err != nil {
+		if !isRejected(err) {
+			return status, err
+		}
+		status.Error = err.Error()
+		return status, nil
+	}
+
+	status.JobState = jobStats.State
+	status.DatafeedState = dfStats.State
+	if ts := jobStats.DataCounts.LatestRecordTimestamp; ts > 0 {
+		t := metav1.NewTime(time.Unix(0, ts*int64(time.Millisecond)))
+		status.LatestRecordTime = &t
+	}
+	switch {
+	case jobStats.State == elasticsearch.JobStateFailed:
+		status.Error = fmt.Sprintf("the job failed: %s", jobStats.AssignmentExplanation)
+	case jobStats.ModelSizeStats.MemoryStatus == "hard_limit":
+		status.Error = "the model reached its memory limit and no longer learns from new logs"
+	}
+	return status, nil
+}
+
+// isRejected returns true if Elasticsearch rejected the request, rather than the request failing to reach it.
+func isRejected(err error) bool {
+	_, ok := err.(*elasticsearch.Error)
+	return ok
+}
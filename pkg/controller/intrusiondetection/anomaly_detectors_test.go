@@ -0,0 +1,262 @@
+// Copyright (c) 2020 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package intrusiondetection
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	operatorv1 "github.com/tigera/operator/pkg/apis/operator/v1"
+	"github.com/tigera/operator/pkg/elasticsearch"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// fakeMLClient keeps the machine learning jobs and datafeeds in memory and records the calls that change them.
+type fakeMLClient struct {
+	jobs      map[string]*elasticsearch.Job
+	jobStats  map[string]*elasticsearch.JobStats
+	datafeeds map[string]*elasticsearch.Datafeed
+	dfStats   map[string]*elasticsearch.DatafeedStats
+	calls     []string
+	starts    map[string]time.Time
+	openErr   error
+}
+
+func newFakeMLClient() *fakeMLClient {
+	return &fakeMLClient{
+		jobs:      map[string]*elasticsearch.Job{},
+		jobStats:  map[string]*elasticsearch.JobStats{},
+		datafeeds: map[string]*elasticsearch.Datafeed{},
+		dfStats:   map[string]*elasticsearch.DatafeedStats{},
+		starts:    map[string]time.Time{},
+	}
+}
+
+// addJob adds a closed job with a stopped datafeed.
+func (f *fakeMLClient) addJob(id, memoryLimit, frequency string) {
+	f.jobs[id] = &elasticsearch.Job{ID: id, AnalysisLimits: elasticsearch.AnalysisLimits{ModelMemoryLimit: memoryLimit}}
+	f.jobStats[id] = &elasticsearch.JobStats{ID: id, State: "closed"}
+	dfID := "datafeed-" + id
+	f.datafeeds[dfID] = &elasticsearch.Datafeed{ID: dfID, JobID: id, Frequency: frequency}
+	f.dfStats[dfID] = &elasticsearch.DatafeedStats{ID: dfID, State: "stopped"}
+}
+
+func (f *fakeMLClient) Jobs(ctx context.Context) ([]elasticsearch.Job, error) {
+	var jobs []elasticsearch.Job
+	for _, j := range f.jobs {
+		jobs = append(jobs, *j)
+	}
+	return jobs, nil
+}
+
+func (f *fakeMLClient) JobStats(ctx context.Context, jobID string) (elasticsearch.JobStats, error) {
+	return *f.jobStats[jobID], nil
+}
+
+func (f *fakeMLClient) UpdateJob(ctx context.Context, jobID string, update elasticsearch.JobUpdate) error {
+	f.calls = append(f.calls, "update job "+jobID)
+	if update.AnalysisLimits != nil {
+		if f.jobStats[jobID].State != "closed" {
+			return &elasticsearch.Error{StatusCode: http.StatusBadRequest, Body: "job must be closed"}
+		}
+		f.jobs[jobID].AnalysisLimits = *update.AnalysisLimits
+	}
+	f.jobs[jobID].CustomSettings = update.CustomSettings
+	return nil
+}
+
+func (f *fakeMLClient) OpenJob(ctx context.Context, jobID string) error {
+	f.calls = append(f.calls, "open job "+jobID)
+	if f.openErr != nil {
+		return f.openErr
+	}
+	f.jobStats[jobID].State = elasticsearch.JobStateOpened
+	return nil
+}
+
+func (f *fakeMLClient) CloseJob(ctx context.Context, jobID string) error {
+	f.calls = append(f.calls, "close job "+jobID)
+	f.jobStats[jobID].State = "closed"
+	return nil
+}
+
+func (f *fakeMLClient) Datafeeds(ctx context.Context) ([]elasticsearch.Datafeed, error) {
+	var datafeeds []elasticsearch.Datafeed
+	for _, df := range f.datafeeds {
+		datafeeds = append(datafeeds, *df)
+	}
+	return datafeeds, nil
+}
+
+func (f *fakeMLClient) DatafeedStats(ctx context.Context, datafeedID string) (elasticsearch.DatafeedStats, error) {
+	return *f.dfStats[datafeedID], nil
+}
+
+func (f *fakeMLClient) UpdateDatafeed(ctx context.Context, datafeedID string, update elasticsearch.DatafeedUpdate) error {
+	f.calls = append(f.calls, "update datafeed "+datafeedID)
+	f.datafeeds[datafeedID].Frequency = update.Frequency
+	return nil
+}
+
+func (f *fakeMLClient) StartDatafeed(ctx context.Context, datafeedID string, start time.Time) error {
+	f.calls = append(f.calls, "start datafeed "+datafeedID)
+	f.dfStats[datafeedID].State = elasticsearch.DatafeedStateStarted
+	f.starts[datafeedID] = start
+	return nil
+}
+
+func (f *fakeMLClient) StopDatafeed(ctx context.Context, datafeedID string) error {
+	f.calls = append(f.calls, "stop datafeed "+datafeedID)
+	f.dfStats[datafeedID].State = "stopped"
+	return nil
+}
+
+var _ = Describe("Anomaly detectors", func() {
+	var es *fakeMLClient
+	var instance *operatorv1.IntrusionDetection
+	ctx := context.Background()
+
+	BeforeEach(func() {
+		es = newFakeMLClient()
+		es.addJob("port_scan_pods", "1024mb", "150s")
+		es.addJob("ip_sweep_pods", "1024mb", "150s")
+		instance = &operatorv1.IntrusionDetection{
+			Spec: operatorv1.IntrusionDetectionSpec{
+				AnomalyDetection: &operatorv1.AnomalyDetectionSpec{
+					Detectors: []operatorv1.AnomalyDetector{{Name: "port_scan_pods"}},
+				},
+			},
+		}
+	})
+
+	It("should start a detector and train it on the training period", func() {
+		statuses, err := reconcileAnomalyDetectors(ctx, es, instance)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(es.calls).To(Equal([]string{
+			"update job port_scan_pods",
+			"open job port_scan_pods",
+			"start datafeed datafeed-port_scan_pods",
+		}))
+		Expect(es.jobs["port_scan_pods"].CustomSettings).To(HaveKeyWithValue(managedJobSetting, true))
+		Expect(es.starts["datafeed-port_scan_pods"]).To(BeTemporally("~", time.Now().Add(-defaultTrainingPeriod), time.Minute))
+		Expect(statuses).To(Equal([]operatorv1.AnomalyDetectorStatus{{
+			Name:          "port_scan_pods",
+			JobState:      elasticsearch.JobStateOpened,
+			DatafeedState: elasticsearch.DatafeedStateStarted,
+		}}))
+
+		By("leaving a running detector as it is")
+		es.calls = nil
+		_, err = reconcileAnomalyDetectors(ctx, es, instance)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(es.calls).To(BeEmpty())
+	})
+
+	It("should continue a model that has processed logs before", func() {
+		es.jobStats["port_scan_pods"].DataCounts.LatestRecordTimestamp = 1577836800000
+		statuses, err := reconcileAnomalyDetectors(ctx, es, instance)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(es.starts["datafeed-port_scan_pods"].IsZero()).To(BeTrue())
+		Expect(statuses[0].LatestRecordTime).To(Equal(&metav1.Time{Time: time.Unix(1577836800, 0)}))
+	})
+
+	It("should restart a detector to apply a new schedule and memory limit", func() {
+		_, err := reconcileAnomalyDetectors(ctx, es, instance)
+		Expect(err).NotTo(HaveOccurred())
+
+		By("keeping the detector running when the limit is the same")
+		es.calls = nil
+		instance.Spec.AnomalyDetection.Detectors[0].ModelMemoryLimit = "1gb"
+		instance.Spec.AnomalyDetection.Detectors[0].DetectionInterval = &metav1.Duration{Duration: 150 * time.Second}
+		_, err = reconcileAnomalyDetectors(ctx, es, instance)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(es.calls).To(BeEmpty())
+
+		By("restarting the datafeed for a new detection interval")
+		instance.Spec.AnomalyDetection.Detectors[0].DetectionInterval.Duration = 5 * time.Minute
+		_, err = reconcileAnomalyDetectors(ctx, es, instance)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(es.calls).To(Equal([]string{
+			"stop datafeed datafeed-port_scan_pods",
+			"update datafeed datafeed-port_scan_pods",
+			"start datafeed datafeed-port_scan_pods",
+		}))
+		Expect(es.datafeeds["datafeed-port_scan_pods"].Frequency).To(Equal("300s"))
+
+		By("closing the job for a new memory limit")
+		es.calls = nil
+		instance.Spec.AnomalyDetection.Detectors[0].ModelMemoryLimit = "512mb"
+		_, err = reconcileAnomalyDetectors(ctx, es, instance)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(es.calls).To(Equal([]string{
+			"stop datafeed datafeed-port_scan_pods",
+			"close job port_scan_pods",
+			"update job port_scan_pods",
+			"open job port_scan_pods",
+			"start datafeed datafeed-port_scan_pods",
+		}))
+		Expect(es.jobs["port_scan_pods"].AnalysisLimits.ModelMemoryLimit).To(Equal("512mb"))
+		Expect(es.jobs["port_scan_pods"].CustomSettings).To(HaveKeyWithValue(managedJobSetting, true))
+	})
+
+	It("should stop detectors that are removed and leave other jobs alone", func() {
+		es.jobs["ip_sweep_pods"].CustomSettings = map[string]interface{}{"custom_urls": "kept"}
+		es.jobStats["ip_sweep_pods"].State = elasticsearch.JobStateOpened
+		_, err := reconcileAnomalyDetectors(ctx, es, instance)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(es.jobStats["ip_sweep_pods"].State).To(Equal(elasticsearch.JobStateOpened))
+
+		es.jobs["port_scan_pods"].CustomSettings["custom_urls"] = "kept"
+		es.calls = nil
+		instance.Spec.AnomalyDetection.Detectors = nil
+		statuses, err := reconcileAnomalyDetectors(ctx, es, instance)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(statuses).To(BeEmpty())
+		Expect(es.calls).To(Equal([]string{
+			"stop datafeed datafeed-port_scan_pods",
+			"close job port_scan_pods",
+			"update job port_scan_pods",
+		}))
+		Expect(es.jobs["port_scan_pods"].CustomSettings).To(Equal(map[string]interface{}{"custom_urls": "kept"}))
+		Expect(es.jobStats["ip_sweep_pods"].State).To(Equal(elasticsearch.JobStateOpened))
+	})
+
+	It("should report detectors that cannot run", func() {
+		instance.Spec.AnomalyDetection.Detectors = append(instance.Spec.AnomalyDetection.Detectors,
+			operatorv1.AnomalyDetector{Name: "missing"}, operatorv1.AnomalyDetector{Name: "ip_sweep_pods"})
+		es.openErr = &elasticsearch.Error{StatusCode: http.StatusTooManyRequests, Body: "not enough memory"}
+		es.jobStats["ip_sweep_pods"].State = elasticsearch.JobStateFailed
+		es.jobStats["ip_sweep_pods"].AssignmentExplanation = "out of memory"
+
+		statuses, err := reconcileAnomalyDetectors(ctx, es, instance)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(statuses).To(HaveLen(3))
+		Expect(statuses[0].Error).To(ContainSubstring("not enough memory"))
+		Expect(statuses[1]).To(Equal(operatorv1.AnomalyDetectorStatus{Name: "missing", Error: "the job does not exist in Elasticsearch"}))
+		Expect(statuses[2].JobState).To(Equal(elasticsearch.JobStateFailed))
+		Expect(statuses[2].Error).To(Equal("the job failed: out of memory"))
+
+		By("returning errors that did not come from Elasticsearch")
+		es.openErr = fmt.Errorf("connection refused")
+		_, err = reconcileAnomalyDetectors(ctx, es, instance)
+		Expect(err).To(HaveOccurred())
+	})
+})
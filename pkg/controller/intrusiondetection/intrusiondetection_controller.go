@@ -7,6 +7,8 @@ import (
 
 	"k8s.io/apimachinery/pkg/types"
 
+	"github.com/go-logr/logr"
+	v3 "github.com/tigera/api/pkg/apis/projectcalico/v3"
 	operatorv1 "github.com/tigera/operator/pkg/apis/operator/v1"
 	"github.com/tigera/operator/pkg/controller/installation"
 	"github.com/tigera/operator/pkg/controller/status"
@@ -28,6 +30,9 @@ import (
 
 var log = logf.Log.WithName("controller_intrusiondetection")
 
+// statusInterval is how often the status of the GlobalThreatFeeds and anomaly detectors is checked.
+const statusInterval = 5 * time.Minute
+
 // Add creates a new IntrusionDetection Controller and adds it to the Manager. The Manager will set fields on the Controller
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager, p operatorv1.Provider, tsee bool) error {
@@ -80,13 +85,14 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		return fmt.Errorf("intrusiondetection-controller failed to watch APIServer resource: %v", err)
 	}
 
-	for _, secretName := range []string{
-		render.ElasticsearchPublicCertSecret, render.ElasticsearchIntrusionDetectionUserSecret,
-		render.ElasticsearchIntrusionDetectionJobUserSecret, render.KibanaPublicCertSecret,
-	} {
-		if err = utils.AddSecretsWatch(c, secretName, render.OperatorNamespace()); err != nil {
-			return fmt.Errorf("intrusiondetection-controller failed to watch the Secret resource: %v", err)
-		}
+	if err = utils.AddAPIServiceWatch(c); err != nil {
+		return fmt.Errorf("intrusiondetection-controller failed to watch APIService resource: %v", err)
+	}
+
+	// Watch all Secrets in the operator namespace, since the names of the Secrets referenced by threat feeds and alert
+	// webhooks are only known at reconcile time. This includes the Elasticsearch and Kibana Secrets.
+	if err = utils.AddSecretsWatch(c, "", render.OperatorNamespace()); err != nil {
+		return fmt.Errorf("intrusiondetection-controller failed to watch the Secret resource: %v", err)
 	}
 
 	if err = utils.AddConfigMapWatch(c, render.ElasticsearchConfigMapName, render.OperatorNamespace()); err != nil {
@@ -140,9 +146,16 @@ func (r *ReconcileIntrusionDetection) Reconcile(request reconcile.Request) (reco
 	r.status.OnCRFound()
 	reqLogger.V(2).Info("Loaded config", "config", instance)
 
+	if err = validateCustomResource(instance); err != nil {
+		reqLogger.Error(err, "Invalid IntrusionDetection configuration")
+		r.status.SetDegraded("Invalid IntrusionDetection configuration", err.Error())
+		return reconcile.Result{}, nil
+	}
+
 	if !utils.IsAPIServerReady(r.client, reqLogger) {
 		r.status.SetDegraded("Waiting for Tigera API server to be ready", "")
-		return reconcile.Result{}, err
+		// Requeue through the rate limiter so that we back off while the aggregated API starts.
+		return reconcile.Result{Requeue: true}, nil
 	}
 
 	if err = utils.CheckLicenseKey(ctx, r.client); err != nil {
@@ -195,10 +208,25 @@ func (r *ReconcileIntrusionDetection) Reconcile(request reconcile.Request) (reco
 		return reconcile.Result{}, err
 	}
 
-	kibanaPublicCertSecret := &corev1.Secret{}
-	if err := r.client.Get(ctx, types.NamespacedName{Name: render.KibanaPublicCertSecret, Namespace: render.OperatorNamespace()}, kibanaPublicCertSecret); err != nil {
-		reqLogger.Error(err, "Failed to read Kibana public cert secret")
-		r.status.SetDegraded("Failed to read Kibana public cert secret", err.Error())
+	// The dashboards are only installed when Kibana is deployed with Elasticsearch.
+	var kibanaPublicCertSecret *corev1.Secret
+	if esClusterConfig.KibanaEnabled() {
+		kibanaPublicCertSecret = &corev1.Secret{}
+		if err := r.client.Get(ctx, types.NamespacedName{Name: render.KibanaPublicCertSecret, Namespace: render.OperatorNamespace()}, kibanaPublicCertSecret); err != nil {
+			reqLogger.Error(err, "Failed to read Kibana public cert secret")
+			r.status.SetDegraded("Failed to read Kibana public cert secret", err.Error())
+			return reconcile.Result{}, err
+		}
+	}
+
+	authSecrets, err := getAuthSecrets(ctx, r.client, instance)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			log.Info("Threat feed or alert webhook Secret is not available yet, waiting until it becomes available")
+			r.status.SetDegraded("Threat feed or alert webhook Secret is not available yet, waiting until it becomes available", err.Error())
+			return reconcile.Result{}, nil
+		}
+		r.status.SetDegraded("Failed to get threat feed or alert webhook Secret", err.Error())
 		return reconcile.Result{}, err
 	}
 
@@ -208,8 +236,10 @@ func (r *ReconcileIntrusionDetection) Reconcile(request reconcile.Request) (reco
 	reqLogger.V(3).Info("rendering components")
 	// Render the desired objects from the CRD and create or update them.
 	component := render.IntrusionDetection(
+		instance,
 		esSecrets,
 		kibanaPublicCertSecret,
+		authSecrets,
 		network.Spec.Registry,
 		esClusterConfig,
 		pullSecrets,
@@ -220,6 +250,24 @@ func (r *ReconcileIntrusionDetection) Reconcile(request reconcile.Request) (reco
 		return reconcile.Result{}, err
 	}
 
+	if err := removeStaleThreatFeeds(ctx, r.client, instance, authSecrets); err != nil {
+		r.status.SetDegraded("Error removing stale threat feeds", err.Error())
+		return reconcile.Result{}, err
+	}
+
+	var detectorStatus []operatorv1.AnomalyDetectorStatus
+	if hasAnomalyDetectors(instance) {
+		es, err := newAnomalyDetectionClient(esClusterConfig, esSecrets)
+		if err != nil {
+			r.status.SetDegraded("Failed to create the Elasticsearch client", err.Error())
+			return reconcile.Result{}, err
+		}
+		if detectorStatus, err = reconcileAnomalyDetectors(ctx, es, instance); err != nil {
+			r.status.SetDegraded("Error running anomaly detectors", err.Error())
+			return reconcile.Result{}, err
+		}
+	}
+
 	// Clear the degraded bit if we've reached this far.
 	r.status.ClearDegraded()
 
@@ -231,8 +279,76 @@ func (r *ReconcileIntrusionDetection) Reconcile(request reconcile.Request) (reco
 
 	// Everything is available - update the CRD status.
 	instance.Status.State = operatorv1.IntrusionDetectionStatusReady
+	instance.Status.ThreatFeeds = r.threatFeedStatus(ctx, instance, reqLogger)
+	instance.Status.Detectors = detectorStatus
 	if err = r.client.Status().Update(ctx, instance); err != nil {
 		return reconcile.Result{}, err
 	}
+
+	if len(instance.Spec.ThreatFeeds) > 0 || len(instance.Status.Detectors) > 0 {
+		// Feed pull failures are reported on the GlobalThreatFeeds, which live in the aggregated API, and detectors
+		// run in Elasticsearch. Neither is watched, so check back periodically to keep the reported status current.
+		return reconcile.Result{RequeueAfter: statusInterval}, nil
+	}
 	return reconcile.Result{}, nil
 }
+
+// getAuthSecrets retrieves the Secrets referenced by the threat feeds and alert webhooks in the IntrusionDetection
+// resource, checking that each contains the referenced key.
+func getAuthSecrets(ctx context.Context, cli client.Client, instance *operatorv1.IntrusionDetection) ([]*corev1.Secret, error) {
+	var auths []*operatorv1.HTTPHeaderSecret
+	for _, feed := range instance.Spec.ThreatFeeds {
+		if feed.Auth != nil {
+			auths = append(auths, feed.Auth)
+		}
+	}
+	if instance.Spec.AlertForwarding != nil {
+		for _, wh := range instance.Spec.AlertForwarding.Webhooks {
+			if wh.Auth != nil {
+				auths = append(auths, wh.Auth)
+			}
+		}
+	}
+
+	var secrets []*corev1.Secret
+	seen := map[string]*corev1.Secret{}
+	for _, auth := range auths {
+		secret, ok := seen[auth.SecretName]
+		if !ok {
+			secret = &corev1.Secret{}
+			if err := cli.Get(ctx, types.NamespacedName{Name: auth.SecretName, Namespace: render.OperatorNamespace()}, secret); err != nil {
+				return nil, err
+			}
+			seen[auth.SecretName] = secret
+			secrets = append(secrets, secret)
+		}
+		if len(secret.Data[auth.SecretKey]) == 0 {
+			return nil, fmt.Errorf("Expected secret %q to have a field named %q", auth.SecretName, auth.SecretKey)
+		}
+	}
+	return secrets, nil
+}
+
+// threatFeedStatus returns the status of the threat feeds that are reporting errors. Failures to read a feed are
+// logged rather than returned, since they should not block reconciliation.
+func (r *ReconcileIntrusionDetection) threatFeedStatus(ctx context.Context, instance *operatorv1.IntrusionDetection, reqLogger logr.Logger) []operatorv1.ThreatFeedStatus {
+	var statuses []operatorv1.ThreatFeedStatus
+	for _, feed := range instance.Spec.ThreatFeeds {
+		gtf := &v3.GlobalThreatFeed{}
+		if err := r.client.Get(ctx, types.NamespacedName{Name: feed.Name}, gtf); err != nil {
+			reqLogger.V(2).Info("Failed to read GlobalThreatFeed status", "name", feed.Name, "err", err)
+			continue
+		}
+		if len(gtf.Status.ErrorConditions) == 0 {
+			continue
+		}
+
+		status := operatorv1.ThreatFeedStatus{Name: feed.Name}
+		for _, ec := range gtf.Status.ErrorConditions {
+			status.Errors = append(status.Errors, fmt.Sprintf("%s: %s", ec.Type, ec.Message))
+		}
+		reqLogger.Info("Threat feed is failing", "name", feed.Name, "errors", status.Errors)
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
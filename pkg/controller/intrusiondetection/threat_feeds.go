@@ -0,0 +1,71 @@
+// Copyright (c) 2020 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package intrusiondetection
+
+import (
+	"context"
+
+	v3 "github.com/tigera/api/pkg/apis/projectcalico/v3"
+	operatorv1 "github.com/tigera/operator/pkg/apis/operator/v1"
+	"github.com/tigera/operator/pkg/render"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// removeStaleThreatFeeds deletes the GlobalThreatFeeds of threat feeds that have been removed from the
+// IntrusionDetection CR, along with the copies of the Secrets that no threat feed or alert webhook references anymore.
+// Only resources labelled by the operator are removed, so threat feeds created by users are left alone.
+func removeStaleThreatFeeds(ctx context.Context, cli client.Client, instance *operatorv1.IntrusionDetection, authSecrets []*corev1.Secret) error {
+	desiredFeeds := map[string]bool{}
+	for _, feed := range instance.Spec.ThreatFeeds {
+		desiredFeeds[feed.Name] = true
+	}
+	feeds := v3.GlobalThreatFeedList{}
+	if err := cli.List(ctx, &feeds); err != nil {
+		return err
+	}
+	for i := range feeds.Items {
+		gtf := &feeds.Items[i]
+		if _, ok := gtf.Labels[render.ThreatFeedLabel]; !ok || desiredFeeds[gtf.Name] {
+			continue
+		}
+		log.Info("Removing threat feed", "globalthreatfeed", gtf.Name)
+		if err := cli.Delete(ctx, gtf); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	desiredSecrets := map[string]bool{}
+	for _, s := range authSecrets {
+		desiredSecrets[s.Name] = true
+	}
+	secrets := corev1.SecretList{}
+	if err := cli.List(ctx, &secrets, client.InNamespace(render.IntrusionDetectionNamespace)); err != nil {
+		return err
+	}
+	for i := range secrets.Items {
+		secret := &secrets.Items[i]
+		if _, ok := secret.Labels[render.IntrusionDetectionAuthSecretLabel]; !ok || desiredSecrets[secret.Name] {
+			continue
+		}
+		log.Info("Removing threat feed or alert webhook Secret", "secret", secret.Name)
+		if err := cli.Delete(ctx, secret); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
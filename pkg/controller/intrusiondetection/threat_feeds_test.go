@@ -0,0 +1,110 @@
+// Copyright (c) 2020 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package intrusiondetection
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	v3 "github.com/tigera/api/pkg/apis/projectcalico/v3"
+	"github.com/tigera/operator/pkg/apis"
+	operatorv1 "github.com/tigera/operator/pkg/apis/operator/v1"
+	"github.com/tigera/operator/pkg/render"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Threat feed pruning", func() {
+	var c client.Client
+	var instance *operatorv1.IntrusionDetection
+	ctx := context.Background()
+
+	feedNames := func() []string {
+		list := v3.GlobalThreatFeedList{}
+		Expect(c.List(ctx, &list)).To(Succeed())
+		var names []string
+		for _, gtf := range list.Items {
+			names = append(names, gtf.Name)
+		}
+		return names
+	}
+
+	secretNames := func() []string {
+		list := corev1.SecretList{}
+		Expect(c.List(ctx, &list, client.InNamespace(render.IntrusionDetectionNamespace))).To(Succeed())
+		var names []string
+		for _, s := range list.Items {
+			names = append(names, s.Name)
+		}
+		return names
+	}
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(apis.AddToScheme(scheme)).NotTo(HaveOccurred())
+		Expect(corev1.AddToScheme(scheme)).NotTo(HaveOccurred())
+		c = fake.NewFakeClientWithScheme(scheme)
+
+		instance = &operatorv1.IntrusionDetection{
+			ObjectMeta: metav1.ObjectMeta{Name: "tigera-secure"},
+			Spec: operatorv1.IntrusionDetectionSpec{
+				ThreatFeeds: []operatorv1.ThreatFeed{{
+					Name: "feodo",
+					URL:  "https://feodotracker.example.com/blocklist.txt",
+					Auth: &operatorv1.HTTPHeaderSecret{SecretName: "feodo-token", SecretKey: "token"},
+				}},
+			},
+		}
+
+		for _, name := range []string{"feodo", "dropped"} {
+			Expect(c.Create(ctx, &v3.GlobalThreatFeed{ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{render.ThreatFeedLabel: name},
+			}})).To(Succeed())
+			Expect(c.Create(ctx, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+				Name:      name + "-token",
+				Namespace: render.IntrusionDetectionNamespace,
+				Labels:    map[string]string{render.IntrusionDetectionAuthSecretLabel: "true"},
+			}})).To(Succeed())
+		}
+		Expect(c.Create(ctx, &v3.GlobalThreatFeed{ObjectMeta: metav1.ObjectMeta{Name: "users-own"}})).To(Succeed())
+		Expect(c.Create(ctx, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name:      render.ElasticsearchIntrusionDetectionUserSecret,
+			Namespace: render.IntrusionDetectionNamespace,
+		}})).To(Succeed())
+	})
+
+	It("should delete the threat feeds and Secrets dropped from the IntrusionDetection CR", func() {
+		authSecrets := []*corev1.Secret{{ObjectMeta: metav1.ObjectMeta{Name: "feodo-token", Namespace: render.OperatorNamespace()}}}
+		Expect(removeStaleThreatFeeds(ctx, c, instance, authSecrets)).To(Succeed())
+
+		Expect(feedNames()).To(ConsistOf("feodo", "users-own"))
+		Expect(secretNames()).To(ConsistOf("feodo-token", render.ElasticsearchIntrusionDetectionUserSecret))
+	})
+
+	It("should delete every labelled threat feed and Secret once no threat feeds are configured", func() {
+		instance.Spec.ThreatFeeds = nil
+		Expect(removeStaleThreatFeeds(ctx, c, instance, nil)).To(Succeed())
+
+		Expect(feedNames()).To(ConsistOf("users-own"))
+		Expect(secretNames()).To(ConsistOf(render.ElasticsearchIntrusionDetectionUserSecret))
+	})
+})
@@ -0,0 +1,109 @@
+// Copyright (c) 2020 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package intrusiondetection
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	operatorv1 "github.com/tigera/operator/pkg/apis/operator/v1"
+	"github.com/tigera/operator/pkg/elasticsearch"
+)
+
+// minThreatFeedPullInterval is the shortest pull interval allowed, to avoid hammering feed providers.
+const minThreatFeedPullInterval = 5 * time.Minute
+
+// validateCustomResource validates that the given custom resource is correct. This
+// should be called before rendering objects.
+func validateCustomResource(instance *operatorv1.IntrusionDetection) error {
+	feeds := map[string]bool{}
+	for _, feed := range instance.Spec.ThreatFeeds {
+		if feeds[feed.Name] {
+			return fmt.Errorf("threat feed %s is configured more than once", feed.Name)
+		}
+		feeds[feed.Name] = true
+
+		if err := validateHTTPURL(feed.URL); err != nil {
+			return fmt.Errorf("threat feed %s has an invalid url: %v", feed.Name, err)
+		}
+		if feed.PullInterval != nil && feed.PullInterval.Duration < minThreatFeedPullInterval {
+			return fmt.Errorf("threat feed %s pullInterval must be at least %s", feed.Name, minThreatFeedPullInterval)
+		}
+	}
+
+	if af := instance.Spec.AlertForwarding; af != nil {
+		webhooks := map[string]bool{}
+		for _, wh := range af.Webhooks {
+			if webhooks[wh.Name] {
+				return fmt.Errorf("alert webhook %s is configured more than once", wh.Name)
+			}
+			webhooks[wh.Name] = true
+
+			if err := validateHTTPURL(wh.URL); err != nil {
+				return fmt.Errorf("alert webhook %s has an invalid url: %v", wh.Name, err)
+			}
+		}
+		if af.Syslog != nil {
+			u, err := url.Parse(af.Syslog.Endpoint)
+			if err != nil {
+				return fmt.Errorf("alert syslog endpoint is invalid: %v", err)
+			}
+			if u.Scheme != "tcp" && u.Scheme != "udp" {
+				return fmt.Errorf("alert syslog endpoint must use the tcp or udp scheme")
+			}
+			if u.Host == "" {
+				return fmt.Errorf("alert syslog endpoint must specify a host")
+			}
+		}
+	}
+
+	if ad := instance.Spec.AnomalyDetection; ad != nil {
+		detectors := map[string]bool{}
+		for _, d := range ad.Detectors {
+			if detectors[d.Name] {
+				return fmt.Errorf("anomaly detector %s is configured more than once", d.Name)
+			}
+			detectors[d.Name] = true
+
+			if d.TrainingPeriod != nil && d.TrainingPeriod.Duration <= 0 {
+				return fmt.Errorf("anomaly detector %s trainingPeriod must be positive", d.Name)
+			}
+			if i := d.DetectionInterval; i != nil && (i.Duration < time.Second || i.Duration%time.Second != 0) {
+				return fmt.Errorf("anomaly detector %s detectionInterval must be a whole number of seconds, e.g. 5m", d.Name)
+			}
+			if d.ModelMemoryLimit != "" {
+				if size, err := elasticsearch.ParseByteSize(d.ModelMemoryLimit); err != nil || size < 1<<20 {
+					return fmt.Errorf("anomaly detector %s modelMemoryLimit must be a byte size of at least 1mb, e.g. 512mb", d.Name)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func validateHTTPURL(s string) error {
+	u, err := url.Parse(s)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("scheme must be http or https")
+	}
+	if u.Host == "" {
+		return fmt.Errorf("host must be specified")
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+// Copyright (c) 2020 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package intrusiondetection
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/tigera/operator/pkg/apis"
+	operatorv1 "github.com/tigera/operator/pkg/apis/operator/v1"
+	"github.com/tigera/operator/pkg/render"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("IntrusionDetection validation", func() {
+	var instance *operatorv1.IntrusionDetection
+
+	BeforeEach(func() {
+		instance = &operatorv1.IntrusionDetection{
+			Spec: operatorv1.IntrusionDetectionSpec{
+				AlertForwarding: &operatorv1.AlertForwarding{
+					Webhooks: []operatorv1.AlertWebhook{{Name: "soc", URL: "https://soc.example.com/alerts"}},
+					Syslog:   &operatorv1.SyslogStoreSpec{Endpoint: "tcp://1.2.3.4:601"},
+				},
+				AnomalyDetection: &operatorv1.AnomalyDetectionSpec{DisableDefaultJobs: true},
+			},
+		}
+	})
+
+	It("should accept valid alert destinations", func() {
+		Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+	})
+
+	It("should reject webhooks configured more than once", func() {
+		af := instance.Spec.AlertForwarding
+		af.Webhooks = append(af.Webhooks, af.Webhooks[0])
+		Expect(validateCustomResource(instance)).To(HaveOccurred())
+	})
+
+	It("should reject webhooks that are not http or https URLs", func() {
+		instance.Spec.AlertForwarding.Webhooks[0].URL = "ftp://soc.example.com/alerts"
+		Expect(validateCustomResource(instance)).To(HaveOccurred())
+
+		instance.Spec.AlertForwarding.Webhooks[0].URL = "https:///alerts"
+		Expect(validateCustomResource(instance)).To(HaveOccurred())
+	})
+
+	It("should only accept tcp and udp syslog endpoints", func() {
+		instance.Spec.AlertForwarding.Syslog.Endpoint = "udp://1.2.3.4:514"
+		Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+
+		instance.Spec.AlertForwarding.Syslog.Endpoint = "https://1.2.3.4:601"
+		Expect(validateCustomResource(instance)).To(HaveOccurred())
+
+		instance.Spec.AlertForwarding.Syslog.Endpoint = "1.2.3.4:601"
+		Expect(validateCustomResource(instance)).To(HaveOccurred())
+	})
+
+	It("should validate anomaly detectors", func() {
+		instance.Spec.AnomalyDetection.Detectors = []operatorv1.AnomalyDetector{{
+			Name:              "port_scan_pods",
+			TrainingPeriod:    &metav1.Duration{Duration: 24 * time.Hour},
+			DetectionInterval: &metav1.Duration{Duration: 5 * time.Minute},
+			ModelMemoryLimit:  "512mb",
+		}}
+		Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+
+		By("rejecting detectors configured more than once")
+		ad := instance.Spec.AnomalyDetection
+		ad.Detectors = append(ad.Detectors, operatorv1.AnomalyDetector{Name: "port_scan_pods"})
+		Expect(validateCustomResource(instance)).To(HaveOccurred())
+		ad.Detectors = ad.Detectors[:1]
+
+		By("rejecting an invalid model memory limit")
+		ad.Detectors[0].ModelMemoryLimit = "lots"
+		Expect(validateCustomResource(instance)).To(HaveOccurred())
+		ad.Detectors[0].ModelMemoryLimit = "512kb"
+		Expect(validateCustomResource(instance)).To(HaveOccurred())
+		ad.Detectors[0].ModelMemoryLimit = "1gb"
+		Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+
+		By("rejecting a training period or detection interval that is too short")
+		ad.Detectors[0].TrainingPeriod.Duration = 0
+		Expect(validateCustomResource(instance)).To(HaveOccurred())
+		ad.Detectors[0].TrainingPeriod = nil
+		ad.Detectors[0].DetectionInterval.Duration = time.Millisecond
+		Expect(validateCustomResource(instance)).To(HaveOccurred())
+		ad.Detectors[0].DetectionInterval.Duration = 1500 * time.Millisecond
+		Expect(validateCustomResource(instance)).To(HaveOccurred())
+	})
+
+	It("should read the Secrets that alert webhooks authenticate with", func() {
+		scheme := runtime.NewScheme()
+		Expect(apis.AddToScheme(scheme)).To(Succeed())
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		c := fake.NewFakeClientWithScheme(scheme)
+		ctx := context.Background()
+
+		instance.Spec.AlertForwarding.Webhooks[0].Auth = &operatorv1.HTTPHeaderSecret{SecretName: "soc-token", SecretKey: "token"}
+		_, err := getAuthSecrets(ctx, c, instance)
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+
+		Expect(c.Create(ctx, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "soc-token", Namespace: render.OperatorNamespace()},
+			Data:       map[string][]byte{"token": []byte("abc")},
+		})).To(Succeed())
+		secrets, err := getAuthSecrets(ctx, c, instance)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(secrets).To(HaveLen(1))
+		Expect(secrets[0].Name).To(Equal("soc-token"))
+	})
+})
@@ -69,7 +69,8 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 
 	for _, secretName := range []string{
 		render.ElasticsearchLogCollectorUserSecret, render.ElasticsearchEksLogForwarderUserSecret,
-		render.ElasticsearchPublicCertSecret, render.S3FluentdSecretName, render.EksLogForwarderSecret} {
+		render.ElasticsearchPublicCertSecret, render.S3FluentdSecretName, render.EksLogForwarderSecret,
+		render.SplunkFluentdSecretName} {
 		if err = utils.AddSecretsWatch(c, secretName, render.OperatorNamespace()); err != nil {
 			return fmt.Errorf("log-collector-controller failed to watch the Secret resource(%s): %v", secretName, err)
 		}
@@ -113,6 +114,12 @@ func GetLogCollector(ctx context.Context, cli client.Client) (*operatorv1.LogCol
 				return nil, fmt.Errorf("Syslog config has invalid Endpoint: %s", err)
 			}
 		}
+		if instance.Spec.AdditionalStores.Splunk != nil {
+			_, _, _, err := render.ParseEndpoint(instance.Spec.AdditionalStores.Splunk.Endpoint)
+			if err != nil {
+				return nil, fmt.Errorf("Splunk config has invalid Endpoint: %s", err)
+			}
+		}
 	}
 
 	return instance, nil
@@ -196,6 +203,7 @@ func (r *ReconcileLogCollector) Reconcile(request reconcile.Request) (reconcile.
 	}
 
 	var s3Credential *render.S3Credential
+	var splunkCredential *render.SplunkCredential
 	if instance.Spec.AdditionalStores != nil {
 		if instance.Spec.AdditionalStores.S3 != nil {
 			s3Credential, err = getS3Credential(r.client)
@@ -210,6 +218,19 @@ func (r *ReconcileLogCollector) Reconcile(request reconcile.Request) (reconcile.
 				return reconcile.Result{}, nil
 			}
 		}
+		if instance.Spec.AdditionalStores.Splunk != nil {
+			splunkCredential, err = getSplunkCredential(r.client)
+			if err != nil {
+				log.Error(err, "Error with Splunk credential secret")
+				r.status.SetDegraded("Error with Splunk credential secret", err.Error())
+				return reconcile.Result{}, err
+			}
+			if splunkCredential == nil {
+				log.Info("Splunk credential secret does not exist")
+				r.status.SetDegraded("Splunk credential secret does not exist", "")
+				return reconcile.Result{}, nil
+			}
+		}
 	}
 
 	filters, err := getFluentdFilters(r.client)
@@ -247,6 +268,7 @@ func (r *ReconcileLogCollector) Reconcile(request reconcile.Request) (reconcile.
 		esSecrets,
 		esClusterConfig,
 		s3Credential,
+		splunkCredential,
 		filters,
 		eksConfig,
 		pullSecrets,
@@ -308,6 +330,31 @@ func getS3Credential(client client.Client) (*render.S3Credential, error) {
 	}, nil
 }
 
+func getSplunkCredential(client client.Client) (*render.SplunkCredential, error) {
+	secret := &corev1.Secret{}
+	secretNamespacedName := types.NamespacedName{
+		Name:      render.SplunkFluentdSecretName,
+		Namespace: render.OperatorNamespace(),
+	}
+	if err := client.Get(context.Background(), secretNamespacedName, secret); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("Failed to read secret %q: %s", render.SplunkFluentdSecretName, err)
+	}
+
+	token, ok := secret.Data[render.SplunkTokenName]
+	if !ok || len(token) == 0 {
+		return nil, fmt.Errorf(
+			"Expected secret %q to have a field named %q",
+			render.SplunkFluentdSecretName, render.SplunkTokenName)
+	}
+
+	return &render.SplunkCredential{
+		Token: token,
+	}, nil
+}
+
 func getFluentdFilters(client client.Client) (*render.FluentdFilters, error) {
 	cm := &corev1.ConfigMap{}
 	cmNamespacedName := types.NamespacedName{
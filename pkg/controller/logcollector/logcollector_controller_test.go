@@ -0,0 +1,74 @@
+// Copyright (c) 2020 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logcollector
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/tigera/operator/pkg/apis"
+	"github.com/tigera/operator/pkg/render"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("LogCollector Splunk credential", func() {
+	var cli client.Client
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(apis.AddToScheme(scheme)).NotTo(HaveOccurred())
+		Expect(corev1.AddToScheme(scheme)).NotTo(HaveOccurred())
+		cli = fake.NewFakeClientWithScheme(scheme)
+	})
+
+	createSecret := func(data map[string][]byte) {
+		Expect(cli.Create(context.Background(), &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: render.SplunkFluentdSecretName, Namespace: render.OperatorNamespace()},
+			Data:       data,
+		})).To(Succeed())
+	}
+
+	It("should return no credential if the secret does not exist", func() {
+		credential, err := getSplunkCredential(cli)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(credential).To(BeNil())
+	})
+
+	It("should read the token of the collector", func() {
+		createSecret(map[string][]byte{render.SplunkTokenName: []byte("TokenForHEC")})
+		credential, err := getSplunkCredential(cli)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(credential).To(Equal(&render.SplunkCredential{Token: []byte("TokenForHEC")}))
+	})
+
+	It("should reject a secret without a token", func() {
+		createSecret(map[string][]byte{"hec-token": []byte("TokenForHEC")})
+		_, err := getSplunkCredential(cli)
+		Expect(err).To(MatchError(`Expected secret "log-collector-splunk-credentials" to have a field named "token"`))
+	})
+
+	It("should reject a secret with an empty token", func() {
+		createSecret(map[string][]byte{render.SplunkTokenName: {}})
+		_, err := getSplunkCredential(cli)
+		Expect(err).To(HaveOccurred())
+	})
+})
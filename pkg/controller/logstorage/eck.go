@@ -0,0 +1,127 @@
+// Copyright (c) 2020 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logstorage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	esalpha1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1alpha1"
+	kibanaalpha1 "github.com/elastic/cloud-on-k8s/pkg/apis/kibana/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/stringsutil"
+	"github.com/tigera/operator/pkg/controller/utils"
+	"github.com/tigera/operator/pkg/render"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	eckElasticsearchGroup = "elasticsearch.k8s.elastic.co"
+	eckKibanaGroup        = "kibana.k8s.elastic.co"
+)
+
+// servedECKVersions returns the versions of the ECK Elasticsearch and Kibana APIs that the cluster serves.
+func servedECKVersions(cfg *rest.Config) (map[string][]string, error) {
+	served := map[string][]string{}
+	for _, group := range []string{eckElasticsearchGroup, eckKibanaGroup} {
+		versions, err := utils.ServedVersions(cfg, group)
+		if err != nil {
+			return nil, fmt.Errorf("failed to discover the versions of the %s API: %v", group, err)
+		}
+		served[group] = versions
+	}
+	return served, nil
+}
+
+// eckAPIVersion returns the version of the ECK APIs to manage Elasticsearch and Kibana through, which is v1 once the
+// cluster serves it for both. If neither version is served for both, it returns why Elasticsearch and Kibana cannot
+// be managed instead.
+func eckAPIVersion(served map[string][]string) (string, string) {
+	for _, version := range []string{render.ECKAPIVersionV1, render.ECKAPIVersionV1alpha1} {
+		if stringsutil.StringInSlice(version, served[eckElasticsearchGroup]) && stringsutil.StringInSlice(version, served[eckKibanaGroup]) {
+			return version, ""
+		}
+	}
+	for _, group := range []string{eckElasticsearchGroup, eckKibanaGroup} {
+		if len(served[group]) == 0 {
+			return "", fmt.Sprintf("the %s API is not installed", group)
+		}
+	}
+	return "", fmt.Sprintf("the cluster serves the %s API at version %s and the %s API at version %s, but Elasticsearch and Kibana are managed through version %s or %s of both",
+		eckElasticsearchGroup, strings.Join(served[eckElasticsearchGroup], ", "),
+		eckKibanaGroup, strings.Join(served[eckKibanaGroup], ", "),
+		render.ECKAPIVersionV1, render.ECKAPIVersionV1alpha1)
+}
+
+// newElasticsearch returns an empty Elasticsearch of the given version of the ECK API.
+func newElasticsearch(version string) runtime.Object {
+	if version == render.ECKAPIVersionV1 {
+		es := &unstructured.Unstructured{}
+		es.SetGroupVersionKind(render.ElasticsearchV1GVK)
+		return es
+	}
+	return &esalpha1.Elasticsearch{}
+}
+
+// newKibana returns an empty Kibana of the given version of the ECK API.
+func newKibana(version string) runtime.Object {
+	if version == render.ECKAPIVersionV1 {
+		kb := &unstructured.Unstructured{}
+		kb.SetGroupVersionKind(render.KibanaV1GVK)
+		return kb
+	}
+	return &kibanaalpha1.Kibana{}
+}
+
+// elasticsearchV1alpha1 returns the Elasticsearch as a v1alpha1 Elasticsearch, which is how the controller reads
+// its node specs and status whichever version of the ECK API it is managed through.
+func elasticsearchV1alpha1(obj runtime.Object) (*esalpha1.Elasticsearch, error) {
+	switch es := obj.(type) {
+	case *esalpha1.Elasticsearch:
+		return es, nil
+	case *unstructured.Unstructured:
+		return render.ElasticsearchV1alpha1(es)
+	}
+	return nil, fmt.Errorf("unexpected Elasticsearch type %T", obj)
+}
+
+// kibanaV1alpha1 returns the Kibana as a v1alpha1 Kibana, which is how the controller reads its status whichever
+// version of the ECK API it is managed through.
+func kibanaV1alpha1(obj runtime.Object) (*kibanaalpha1.Kibana, error) {
+	switch kb := obj.(type) {
+	case *kibanaalpha1.Kibana:
+		return kb, nil
+	case *unstructured.Unstructured:
+		return render.KibanaV1alpha1(kb)
+	}
+	return nil, fmt.Errorf("unexpected Kibana type %T", obj)
+}
+
+// getElasticsearchObject returns the Elasticsearch in the version of the ECK API it is managed through. Once the
+// cluster serves the v1 API, an Elasticsearch created through the v1alpha1 API is read through the v1 API too, and
+// is migrated by rendering it over.
+func (r *ReconcileLogStorage) getElasticsearchObject(ctx context.Context) (runtime.Object, error) {
+	es := newElasticsearch(r.eckAPIVersion)
+	return es, r.client.Get(ctx, client.ObjectKey{Name: render.ElasticsearchName, Namespace: render.ElasticsearchNamespace}, es)
+}
+
+// getKibanaObject returns the Kibana in the version of the ECK API it is managed through.
+func (r *ReconcileLogStorage) getKibanaObject(ctx context.Context) (runtime.Object, error) {
+	kb := newKibana(r.eckAPIVersion)
+	return kb, r.client.Get(ctx, client.ObjectKey{Name: render.KibanaName, Namespace: render.KibanaNamespace}, kb)
+}
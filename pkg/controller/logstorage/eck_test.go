@@ -0,0 +1,109 @@
+// Copyright (c) 2020 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logstorage
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	operatorv1 "github.com/tigera/operator/pkg/apis/operator/v1"
+	"github.com/tigera/operator/pkg/render"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var _ = Describe("LogStorage ECK API versions", func() {
+	It("should manage Elasticsearch and Kibana through v1 once the cluster serves it", func() {
+		version, reason := eckAPIVersion(map[string][]string{
+			eckElasticsearchGroup: {"v1", "v1beta1", "v1alpha1"},
+			eckKibanaGroup:        {"v1", "v1beta1", "v1alpha1"},
+		})
+		Expect(reason).To(BeEmpty())
+		Expect(version).To(Equal(render.ECKAPIVersionV1))
+
+		version, reason = eckAPIVersion(map[string][]string{
+			eckElasticsearchGroup: {"v1alpha1"},
+			eckKibanaGroup:        {"v1alpha1"},
+		})
+		Expect(reason).To(BeEmpty())
+		Expect(version).To(Equal(render.ECKAPIVersionV1alpha1))
+	})
+
+	It("should only use a version that both APIs serve", func() {
+		version, reason := eckAPIVersion(map[string][]string{
+			eckElasticsearchGroup: {"v1", "v1alpha1"},
+			eckKibanaGroup:        {"v1alpha1"},
+		})
+		Expect(reason).To(BeEmpty())
+		Expect(version).To(Equal(render.ECKAPIVersionV1alpha1))
+	})
+
+	It("should explain why the served ECK APIs cannot be used", func() {
+		version, reason := eckAPIVersion(map[string][]string{
+			eckKibanaGroup: {"v1alpha1"},
+		})
+		Expect(version).To(BeEmpty())
+		Expect(reason).To(Equal("the elasticsearch.k8s.elastic.co API is not installed"))
+
+		version, reason = eckAPIVersion(map[string][]string{
+			eckElasticsearchGroup: {"v1beta1"},
+			eckKibanaGroup:        {"v1beta1"},
+		})
+		Expect(version).To(BeEmpty())
+		Expect(reason).To(Equal("the cluster serves the elasticsearch.k8s.elastic.co API at version v1beta1 and the kibana.k8s.elastic.co API at version v1beta1, " +
+			"but Elasticsearch and Kibana are managed through version v1 or v1alpha1 of both"))
+	})
+
+	It("should keep the names of the node sets when migrating to the v1 API", func() {
+		ls := &operatorv1.LogStorage{Spec: operatorv1.LogStorageSpec{
+			Nodes: &operatorv1.Nodes{
+				NodeSets: &operatorv1.NodeSets{
+					Master: operatorv1.NodeSet{Count: 1},
+					Data:   operatorv1.NodeSet{Count: 2},
+				},
+			},
+		}}
+		fillDefaults(ls)
+		statefulSets := func(version string) []string {
+			component, err := render.Elasticsearch(ls, render.NewElasticsearchClusterConfig("cluster", 1, 5), nil, nil, false, nil, operatorv1.ProviderNone, version, "")
+			Expect(err).NotTo(HaveOccurred())
+			es, err := elasticsearchFromObjects(component.Objects())
+			Expect(err).NotTo(HaveOccurred())
+			var names []string
+			for _, spec := range es.Spec.Nodes {
+				names = append(names, render.ElasticsearchStatefulSetName(spec.Name))
+			}
+			return names
+		}
+
+		Expect(statefulSets(render.ECKAPIVersionV1)).To(Equal(statefulSets(render.ECKAPIVersionV1alpha1)))
+	})
+
+	It("should read the Elasticsearch of either version of the ECK API", func() {
+		es := &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"nodeSets": []interface{}{map[string]interface{}{"name": "data", "count": int64(2)}},
+			},
+		}}
+		es.SetGroupVersionKind(render.ElasticsearchV1GVK)
+		alpha, err := elasticsearchV1alpha1(es)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(alpha.Spec.Nodes).To(HaveLen(1))
+		Expect(alpha.Spec.Nodes[0].Name).To(Equal("data"))
+		Expect(alpha.Spec.Nodes[0].NodeCount).To(Equal(int32(2)))
+
+		typed := newElasticsearch(render.ECKAPIVersionV1alpha1)
+		Expect(elasticsearchV1alpha1(typed)).To(BeIdenticalTo(typed))
+	})
+})
@@ -0,0 +1,180 @@
+// Copyright (c) 2020 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// The code in this file connects the components to an Elasticsearch cluster that is not run by the operator.
+package logstorage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	operatorv1 "github.com/tigera/operator/pkg/apis/operator/v1"
+	"github.com/tigera/operator/pkg/controller/utils"
+	"github.com/tigera/operator/pkg/elasticsearch"
+	"github.com/tigera/operator/pkg/render"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// reconcileExternal checks that the external Elasticsearch cluster of the LogStorage can be reached, creates the
+// users of the components in it and points the components at it. Neither Elasticsearch nor Kibana is deployed.
+func (r *ReconcileLogStorage) reconcileExternal(ctx context.Context, network *operatorv1.Installation, ls *operatorv1.LogStorage, reqLogger logr.Logger) (reconcile.Result, error) {
+	if err := validateExternal(&ls.Spec); err != nil {
+		r.setDegraded(ctx, reqLogger, ls, "Invalid LogStorage external Elasticsearch configuration", err)
+		return reconcile.Result{}, nil
+	}
+
+	// The components cannot be moved between clusters without losing their logs. Without the ECK APIs, the operator
+	// cannot have created Elasticsearch.
+	if r.unsupportedECK == "" {
+		if _, err := r.getElasticsearchObject(ctx); err == nil {
+			r.setDegraded(ctx, reqLogger, ls, "The Elasticsearch cluster run by the operator must be removed, by deleting the LogStorage, before an external cluster is used", nil)
+			return reconcile.Result{}, nil
+		} else if !errors.IsNotFound(err) {
+			r.setDegraded(ctx, reqLogger, ls, "Failed to read Elasticsearch", err)
+			return reconcile.Result{}, err
+		}
+	}
+
+	caSecret, err := r.getOperatorSecret(ctx, ls.Spec.External.CASecretName)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			r.setDegraded(ctx, reqLogger, ls, fmt.Sprintf("Waiting for the external Elasticsearch CA Secret %s", ls.Spec.External.CASecretName), nil)
+			return reconcile.Result{RequeueAfter: 30 * time.Second}, nil
+		}
+		r.setDegraded(ctx, reqLogger, ls, "Failed to read the external Elasticsearch CA Secret", err)
+		return reconcile.Result{}, err
+	}
+	credentials, err := r.getOperatorSecret(ctx, ls.Spec.External.CredentialsSecretName)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			r.setDegraded(ctx, reqLogger, ls, fmt.Sprintf("Waiting for the external Elasticsearch credentials Secret %s", ls.Spec.External.CredentialsSecretName), nil)
+			return reconcile.Result{RequeueAfter: 30 * time.Second}, nil
+		}
+		r.setDegraded(ctx, reqLogger, ls, "Failed to read the external Elasticsearch credentials Secret", err)
+		return reconcile.Result{}, err
+	}
+
+	// Write back the LogStorage object to update any defaults that were set
+	if err := r.client.Update(ctx, ls); err != nil {
+		r.status.SetDegraded("Failed to update LogStorage with defaults", err.Error())
+		return reconcile.Result{}, err
+	}
+
+	es, err := elasticsearch.NewClient(ls.Spec.External.Endpoint, string(credentials.Data["username"]), string(credentials.Data["password"]), caSecret.Data[render.ExternalElasticsearchCAKey])
+	if err != nil {
+		r.setDegraded(ctx, reqLogger, ls, "Invalid external Elasticsearch CA", err)
+		return reconcile.Result{}, nil
+	}
+
+	// Kibana is not deployed for an external cluster.
+	ls.Status.Kibana = &operatorv1.KibanaStatus{Enabled: false}
+	ls.Status.External = checkExternal(ctx, es)
+	if !ls.Status.External.Reachable {
+		r.setDegraded(ctx, reqLogger, ls, fmt.Sprintf("Cannot reach the external Elasticsearch cluster: %s", ls.Status.External.Message), nil)
+		return reconcile.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+
+	userSecrets, err := r.reconcileUsers(ctx, es, passwordRotation(ls))
+	if err != nil {
+		r.setDegraded(ctx, reqLogger, ls, "Failed to create the Elasticsearch users of the components", err)
+		return reconcile.Result{}, err
+	}
+
+	clusterConfig := render.NewExternalElasticsearchClusterConfig(render.DefaultElasticsearchClusterName, ls.Spec.External.Endpoint, ls.Replicas(), defaultElasticsearchShards)
+	hdler := utils.NewComponentHandler(log, r.client, r.scheme, ls)
+	if err := hdler.CreateOrUpdate(ctx, render.ElasticsearchExternal(clusterConfig, caSecret, userSecrets), r.status); err != nil {
+		r.setDegraded(ctx, reqLogger, ls, "Error creating / updating resource", err)
+		return reconcile.Result{}, err
+	}
+
+	managedClusters, err := r.listManagedClusters(ctx, network)
+	if err != nil {
+		r.status.SetDegraded("Failed to list managed clusters", err.Error())
+		return reconcile.Result{}, err
+	}
+	if err := validateManagedClusters(managedClusters); err != nil {
+		r.setDegraded(ctx, reqLogger, ls, "Invalid managed cluster log storage settings", err)
+		return reconcile.Result{}, nil
+	}
+
+	if policy, err := reconcileIndices(ctx, es, ls, managedClusters); err != nil {
+		r.setDegraded(ctx, reqLogger, ls, "Failed to manage the log indices", err)
+		return reconcile.Result{}, err
+	} else if policy != nil {
+		r.setDegraded(ctx, reqLogger, ls, fmt.Sprintf("Index lifecycle policy %s is not applied: %s", policy.Name, policy.Message), nil)
+		return reconcile.Result{RequeueAfter: time.Minute}, nil
+	}
+
+	r.status.ClearDegraded()
+	if err := r.updateStatus(ctx, reqLogger, ls, operatorv1.LogStorageStatusReady); err != nil {
+		return reconcile.Result{}, err
+	}
+	// The connectivity of the cluster is checked along with the storage of the logs.
+	return reconcile.Result{RequeueAfter: storageCheckInterval}, nil
+}
+
+// clusterInfoClient is the part of the Elasticsearch client that the connectivity of a cluster is checked with.
+type clusterInfoClient interface {
+	Info(ctx context.Context) (elasticsearch.ClusterInfo, error)
+}
+
+// checkExternal reports whether the operator can connect and authenticate to the external cluster.
+func checkExternal(ctx context.Context, es clusterInfoClient) *operatorv1.ExternalElasticsearchStatus {
+	info, err := es.Info(ctx)
+	if err != nil {
+		return &operatorv1.ExternalElasticsearchStatus{Message: err.Error()}
+	}
+	return &operatorv1.ExternalElasticsearchStatus{Reachable: true, Version: info.Version}
+}
+
+func (r *ReconcileLogStorage) getOperatorSecret(ctx context.Context, name string) (*corev1.Secret, error) {
+	secret := &corev1.Secret{}
+	return secret, r.client.Get(ctx, types.NamespacedName{Name: name, Namespace: render.OperatorNamespace()}, secret)
+}
+
+// externalSecretPredicate passes the events of the Secrets in the operator namespace that the LogStorage references
+// for its external Elasticsearch cluster.
+func externalSecretPredicate(cli client.Client) predicate.Funcs {
+	referenced := func(meta metav1.Object) bool {
+		if meta.GetNamespace() != render.OperatorNamespace() {
+			return false
+		}
+		ls, err := GetLogStorage(context.Background(), cli)
+		if err != nil || ls.Spec.External == nil {
+			return false
+		}
+		return meta.GetName() == ls.Spec.External.CASecretName || meta.GetName() == ls.Spec.External.CredentialsSecretName
+	}
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return referenced(e.Meta)
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return referenced(e.MetaNew)
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return referenced(e.Meta)
+		},
+	}
+}
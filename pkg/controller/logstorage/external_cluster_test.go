@@ -0,0 +1,67 @@
+// Copyright (c) 2020 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logstorage
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/tigera/operator/pkg/apis"
+	operatorv1 "github.com/tigera/operator/pkg/apis/operator/v1"
+	"github.com/tigera/operator/pkg/render"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+var _ = Describe("LogStorage external Elasticsearch Secrets", func() {
+	var cli client.Client
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(apis.AddToScheme(scheme)).NotTo(HaveOccurred())
+		cli = fake.NewFakeClientWithScheme(scheme)
+	})
+
+	secretEvent := func(name, namespace string) event.CreateEvent {
+		s := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+		return event.CreateEvent{Meta: s, Object: s}
+	}
+
+	It("should only pass the events of the Secrets the LogStorage references", func() {
+		pred := externalSecretPredicate(cli)
+		Expect(pred.Create(secretEvent("external-es-ca", render.OperatorNamespace()))).To(BeFalse())
+
+		Expect(cli.Create(context.Background(), &operatorv1.LogStorage{
+			ObjectMeta: metav1.ObjectMeta{Name: "tigera-secure"},
+			Spec: operatorv1.LogStorageSpec{External: &operatorv1.ExternalElasticsearch{
+				Endpoint:              "https://logs.example.com:9200",
+				CASecretName:          "external-es-ca",
+				CredentialsSecretName: "external-es-admin",
+			}},
+		})).To(Succeed())
+
+		Expect(pred.Create(secretEvent("external-es-ca", render.OperatorNamespace()))).To(BeTrue())
+		Expect(pred.Create(secretEvent("external-es-admin", render.OperatorNamespace()))).To(BeTrue())
+		Expect(pred.Create(secretEvent("external-es-ca", "default"))).To(BeFalse())
+		Expect(pred.Create(secretEvent("other", render.OperatorNamespace()))).To(BeFalse())
+	})
+})
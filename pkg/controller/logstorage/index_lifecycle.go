@@ -0,0 +1,382 @@
+// Copyright (c) 2020 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logstorage
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	operatorv1 "github.com/tigera/operator/pkg/apis/operator/v1"
+	"github.com/tigera/operator/pkg/elasticsearch"
+	"github.com/tigera/operator/pkg/render"
+
+	batch "k8s.io/api/batch/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// logIndexPattern matches the indices of all log types.
+	logIndexPattern = "tigera_secure_ee_*"
+
+	// storageCheckInterval is how often the storage taken up by logs is checked against its limits.
+	storageCheckInterval = 15 * time.Minute
+
+	// The curators that removed old indices before index lifecycle policies did.
+	curatorName                = "elastic-curator"
+	managedClusterCuratorLabel = "operator.tigera.io/managed-cluster"
+)
+
+// indexLifecycleClient is the part of the Elasticsearch client that index lifecycles are managed with.
+type indexLifecycleClient interface {
+	PutLifecyclePolicy(ctx context.Context, name string, policy elasticsearch.LifecyclePolicy) error
+	PutIndexTemplate(ctx context.Context, name string, template elasticsearch.IndexTemplate) error
+	AliasExists(ctx context.Context, alias string) (bool, error)
+	CreateWriteIndex(ctx context.Context, index, alias string) error
+	IndicesOutsideAlias(ctx context.Context, pattern, alias string) ([]string, error)
+	AdoptIndices(ctx context.Context, alias, policy string, indices []string) error
+	LifecycleErrors(ctx context.Context, pattern string) ([]elasticsearch.LifecycleError, error)
+	Indices(ctx context.Context, pattern string) ([]elasticsearch.Index, error)
+	WriteIndices(ctx context.Context, pattern string) (map[string]bool, error)
+	StorageCapacity(ctx context.Context) (int64, error)
+	DeleteIndex(ctx context.Context, index string) error
+}
+
+// logType is a type of log stored in Elasticsearch.
+type logType struct {
+	// index is the name of the indices of the log type, up to the cluster name.
+	index string
+	// retention returns the number of days logs of the type are kept.
+	retention func(r *operatorv1.Retention) int32
+	// flowOrDNS is true for the log types that count against MaxLogsStoragePercent.
+	flowOrDNS bool
+}
+
+var logTypes = []logType{
+	{index: "tigera_secure_ee_flows", retention: func(r *operatorv1.Retention) int32 { return *r.Flows }, flowOrDNS: true},
+	{index: "tigera_secure_ee_dns", retention: func(r *operatorv1.Retention) int32 { return *r.DNSLogs }, flowOrDNS: true},
+	{index: "tigera_secure_ee_audit_ee", retention: func(r *operatorv1.Retention) int32 { return *r.AuditReports }},
+	{index: "tigera_secure_ee_audit_kube", retention: func(r *operatorv1.Retention) int32 { return *r.AuditReports }},
+	{index: "tigera_secure_ee_snapshots", retention: func(r *operatorv1.Retention) int32 { return *r.Snapshots }},
+	{index: "tigera_secure_ee_compliance_reports", retention: func(r *operatorv1.Retention) int32 { return *r.ComplianceReports }},
+	{index: "tigera_secure_ee_events", retention: func(r *operatorv1.Retention) int32 { return *r.IDSEvents }},
+	{index: "tigera_secure_ee_l7", retention: func(r *operatorv1.Retention) int32 { return *r.L7Logs }},
+	{index: "tigera_secure_ee_bgp", retention: func(r *operatorv1.Retention) int32 { return *r.BGPLogs }},
+}
+
+// indexLifecycle is the lifecycle of the indices of one log type of one cluster. The policy, the index template
+// and the write alias that logs are written to all share the same name.
+type indexLifecycle struct {
+	name     string
+	policy   elasticsearch.LifecyclePolicy
+	template elasticsearch.IndexTemplate
+}
+
+// indexLifecycles returns the lifecycle of every log type of this cluster and of each managed cluster. The logs of
+// managed clusters are kept for the retention periods of the LogStorage, unless the managed cluster overrides them.
+func indexLifecycles(ls *operatorv1.LogStorage, clusters []operatorv1.ManagedCluster) []indexLifecycle {
+	var lifecycles []indexLifecycle
+	for _, lt := range logTypes {
+		lifecycles = append(lifecycles, newIndexLifecycle(lt, render.DefaultElasticsearchClusterName, ls.Spec.Retention, ls.Spec.IndexLifecycle, ls.Replicas()))
+	}
+	for i := range clusters {
+		mc := &clusters[i]
+		retention := ls.Spec.Retention
+		if mc.Spec.LogStorage != nil {
+			retention = mergeRetention(retention, mc.Spec.LogStorage.Retention)
+		}
+		for _, lt := range logTypes {
+			lifecycles = append(lifecycles, newIndexLifecycle(lt, render.ManagedClusterIndexPrefix(mc), retention, ls.Spec.IndexLifecycle, ls.Replicas()))
+		}
+	}
+	return lifecycles
+}
+
+func newIndexLifecycle(lt logType, cluster string, retention *operatorv1.Retention, il *operatorv1.IndexLifecycle, replicas int) indexLifecycle {
+	name := fmt.Sprintf("%s.%s", lt.index, cluster)
+	return indexLifecycle{
+		name: name,
+		policy: elasticsearch.LifecyclePolicy{Phases: map[string]elasticsearch.LifecyclePhase{
+			"hot": {Actions: map[string]map[string]interface{}{
+				"rollover": {"max_size": fmt.Sprintf("%db", il.RolloverSize.Value()), "max_age": il.RolloverAge},
+			}},
+			"delete": {
+				MinAge:  fmt.Sprintf("%dd", lt.retention(retention)),
+				Actions: map[string]map[string]interface{}{"delete": {}},
+			},
+		}},
+		template: elasticsearch.IndexTemplate{
+			// Only matches the indices created behind the write alias, named <name>.<date>-<generation>, since
+			// the rollover settings do not apply to other indices of the log type, see adoptIndices.
+			IndexPatterns: []string{name + ".*-*"},
+			// Applied over the templates the log writers create for the mappings of their indices.
+			Order: 1,
+			Settings: map[string]interface{}{
+				"index.lifecycle.name":           name,
+				"index.lifecycle.rollover_alias": name,
+				// New indices get the replicas of the LogStorage before the log writers pick up a change.
+				"index.number_of_replicas": replicas,
+			},
+		},
+	}
+}
+
+// mergeRetention returns the retention periods of base, overridden by those set in override.
+func mergeRetention(base, override *operatorv1.Retention) *operatorv1.Retention {
+	merged := base.DeepCopy()
+	if override == nil {
+		return merged
+	}
+	if override.Flows != nil {
+		merged.Flows = override.Flows
+	}
+	if override.AuditReports != nil {
+		merged.AuditReports = override.AuditReports
+	}
+	if override.Snapshots != nil {
+		merged.Snapshots = override.Snapshots
+	}
+	if override.ComplianceReports != nil {
+		merged.ComplianceReports = override.ComplianceReports
+	}
+	if override.DNSLogs != nil {
+		merged.DNSLogs = override.DNSLogs
+	}
+	if override.IDSEvents != nil {
+		merged.IDSEvents = override.IDSEvents
+	}
+	if override.L7Logs != nil {
+		merged.L7Logs = override.L7Logs
+	}
+	if override.BGPLogs != nil {
+		merged.BGPLogs = override.BGPLogs
+	}
+	return merged
+}
+
+// applyIndexLifecycles applies the policy and index template of each lifecycle, creates the first index behind
+// its write alias if there is none yet and moves the other indices of its log type behind the alias. A lifecycle is not applied if Elasticsearch rejects it or if an index it
+// manages failed a lifecycle step. An error is only returned if Elasticsearch cannot be reached.
+func applyIndexLifecycles(ctx context.Context, es indexLifecycleClient, lifecycles []indexLifecycle) ([]operatorv1.IndexLifecyclePolicyStatus, error) {
+	stepErrors, err := es.LifecycleErrors(ctx, logIndexPattern)
+	if err != nil {
+		return nil, err
+	}
+	failedSteps := map[string]string{}
+	for _, e := range stepErrors {
+		if _, ok := failedSteps[e.Policy]; !ok {
+			failedSteps[e.Policy] = fmt.Sprintf("index %s failed a lifecycle step: %s", e.Index, e.Reason)
+		}
+	}
+
+	var statuses []operatorv1.IndexLifecyclePolicyStatus
+	for _, lc := range lifecycles {
+		status := operatorv1.IndexLifecyclePolicyStatus{Name: lc.name, Applied: true}
+		if err := applyIndexLifecycle(ctx, es, lc); err != nil {
+			if !isRejected(err) {
+				return nil, err
+			}
+			status.Applied = false
+			status.Message = err.Error()
+		} else if msg, ok := failedSteps[lc.name]; ok {
+			status.Applied = false
+			status.Message = msg
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+func applyIndexLifecycle(ctx context.Context, es indexLifecycleClient, lc indexLifecycle) error {
+	if err := es.PutLifecyclePolicy(ctx, lc.name, lc.policy); err != nil {
+		return err
+	}
+	if err := es.PutIndexTemplate(ctx, lc.name, lc.template); err != nil {
+		return err
+	}
+	exists, err := es.AliasExists(ctx, lc.name)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		log.Info("Creating the write index of a log type", "alias", lc.name)
+		if err := es.CreateWriteIndex(ctx, fmt.Sprintf("<%s.{now/d}-000001>", lc.name), lc.name); err != nil {
+			return err
+		}
+	}
+	return adoptIndices(ctx, es, lc)
+}
+
+// adoptIndices moves the indices of the log type that are not behind the write alias, such as the daily indices
+// written before index lifecycles were managed by the operator, behind the alias. This keeps their logs visible
+// through the alias and removes them once they are older than the retention period of the log type.
+func adoptIndices(ctx context.Context, es indexLifecycleClient, lc indexLifecycle) error {
+	indices, err := es.IndicesOutsideAlias(ctx, lc.name+".*", lc.name)
+	if err != nil || len(indices) == 0 {
+		return err
+	}
+	log.Info("Moving existing indices behind the write alias", "alias", lc.name, "indices", indices)
+	return es.AdoptIndices(ctx, lc.name, lc.name, indices)
+}
+
+// storageLimits are the number of bytes that logs may take up.
+type storageLimits struct {
+	total     int64
+	flowOrDNS int64
+	// clusters holds the ingestion quotas of the managed clusters, by index prefix.
+	clusters map[string]int64
+}
+
+func newStorageLimits(il *operatorv1.IndexLifecycle, capacity int64, clusters []operatorv1.ManagedCluster) storageLimits {
+	limits := storageLimits{
+		total:     capacity * int64(*il.MaxTotalStoragePercent) / 100,
+		flowOrDNS: capacity * int64(*il.MaxLogsStoragePercent) / 100,
+		clusters:  map[string]int64{},
+	}
+	for i := range clusters {
+		mc := &clusters[i]
+		if mc.Spec.LogStorage != nil && mc.Spec.LogStorage.IngestionQuota != nil {
+			limits.clusters[render.ManagedClusterIndexPrefix(mc)] = mc.Spec.LogStorage.IngestionQuota.Value()
+		}
+	}
+	return limits
+}
+
+// indicesOverLimits returns the indices to remove, oldest first, for the logs to fit within the limits. The ingestion
+// quota of each managed cluster is applied first, then the limit of flow and DNS logs, then the limit of all logs.
+// Write indices are never removed, since logs are still written to them.
+func indicesOverLimits(indices []elasticsearch.Index, writeIndices map[string]bool, limits storageLimits) []string {
+	removed := map[string]bool{}
+	// trim removes the oldest of the matching indices until they take up no more than the limit.
+	trim := func(limit int64, match func(index string) bool) {
+		var used int64
+		for _, idx := range indices {
+			if !removed[idx.Name] && match(idx.Name) {
+				used += idx.Size
+			}
+		}
+		for _, idx := range indices {
+			if used <= limit {
+				return
+			}
+			if removed[idx.Name] || writeIndices[idx.Name] || !match(idx.Name) {
+				continue
+			}
+			removed[idx.Name] = true
+			used -= idx.Size
+		}
+	}
+
+	var clusters []string
+	for cluster := range limits.clusters {
+		clusters = append(clusters, cluster)
+	}
+	sort.Strings(clusters)
+	for _, cluster := range clusters {
+		trim(limits.clusters[cluster], func(index string) bool { return indexCluster(index) == cluster })
+	}
+	trim(limits.flowOrDNS, isFlowOrDNSIndex)
+	trim(limits.total, func(string) bool { return true })
+
+	var toRemove []string
+	for _, idx := range indices {
+		if removed[idx.Name] {
+			toRemove = append(toRemove, idx.Name)
+		}
+	}
+	return toRemove
+}
+
+// indexCluster returns the name of the cluster whose logs are stored in the index.
+func indexCluster(index string) string {
+	parts := strings.SplitN(index, ".", 3)
+	if len(parts) < 2 {
+		return ""
+	}
+	return parts[1]
+}
+
+func isFlowOrDNSIndex(index string) bool {
+	for _, lt := range logTypes {
+		if lt.flowOrDNS && strings.HasPrefix(index, lt.index+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// enforceStorageLimits removes the oldest log indices while logs take up more storage than the LogStorage and the
+// ingestion quotas of the managed clusters allow.
+func enforceStorageLimits(ctx context.Context, es indexLifecycleClient, il *operatorv1.IndexLifecycle, clusters []operatorv1.ManagedCluster) error {
+	capacity, err := es.StorageCapacity(ctx)
+	if err != nil {
+		return err
+	}
+	indices, err := es.Indices(ctx, logIndexPattern)
+	if err != nil {
+		return err
+	}
+	writeIndices, err := es.WriteIndices(ctx, logIndexPattern)
+	if err != nil {
+		return err
+	}
+	for _, index := range indicesOverLimits(indices, writeIndices, newStorageLimits(il, capacity, clusters)) {
+		log.Info("Removing index to stay within the log storage limits", "index", index)
+		if err := es.DeleteIndex(ctx, index); err != nil && !elasticsearch.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// reconcileIndices applies the index lifecycles of the log types of this cluster and of the managed clusters, and
+// keeps the logs within their storage limits. The storage limits are only enforced once all policies are applied,
+// otherwise the first policy that is not applied is returned.
+func reconcileIndices(ctx context.Context, es indexLifecycleClient, ls *operatorv1.LogStorage, clusters []operatorv1.ManagedCluster) (*operatorv1.IndexLifecyclePolicyStatus, error) {
+	policies, err := applyIndexLifecycles(ctx, es, indexLifecycles(ls, clusters))
+	if err != nil {
+		return nil, err
+	}
+	ls.Status.IndexLifecyclePolicies = policies
+	for i := range policies {
+		if !policies[i].Applied {
+			return &policies[i], nil
+		}
+	}
+	return nil, enforceStorageLimits(ctx, es, ls.Spec.IndexLifecycle, clusters)
+}
+
+// removeCurators removes the CronJobs that curated the log indices before index lifecycle policies replaced them.
+func (r *ReconcileLogStorage) removeCurators(ctx context.Context) error {
+	cronJobs := batch.CronJobList{}
+	if err := r.client.List(ctx, &cronJobs, client.InNamespace(render.ElasticsearchNamespace)); err != nil {
+		return err
+	}
+	for i := range cronJobs.Items {
+		cj := &cronJobs.Items[i]
+		if _, ok := cj.Labels[managedClusterCuratorLabel]; !ok && cj.Name != curatorName {
+			continue
+		}
+		log.Info("Removing curator", "name", cj.Name)
+		if err := r.client.Delete(ctx, cj, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
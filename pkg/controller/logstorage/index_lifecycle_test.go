@@ -0,0 +1,294 @@
+// Copyright (c) 2020 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logstorage
+
+import (
+	"context"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	operatorv1 "github.com/tigera/operator/pkg/apis/operator/v1"
+	"github.com/tigera/operator/pkg/elasticsearch"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// fakeIndexLifecycleClient stands in for Elasticsearch when applying index lifecycles.
+type fakeIndexLifecycleClient struct {
+	policies     map[string]elasticsearch.LifecyclePolicy
+	templates    map[string]elasticsearch.IndexTemplate
+	aliases      map[string]string
+	indices      []string
+	adopted      map[string][]string
+	stepErrors   []elasticsearch.LifecycleError
+	rejectPolicy string
+}
+
+func (f *fakeIndexLifecycleClient) PutLifecyclePolicy(ctx context.Context, name string, policy elasticsearch.LifecyclePolicy) error {
+	if name == f.rejectPolicy {
+		return &elasticsearch.Error{StatusCode: 400, Body: "invalid policy"}
+	}
+	f.policies[name] = policy
+	return nil
+}
+
+func (f *fakeIndexLifecycleClient) PutIndexTemplate(ctx context.Context, name string, template elasticsearch.IndexTemplate) error {
+	f.templates[name] = template
+	return nil
+}
+
+func (f *fakeIndexLifecycleClient) AliasExists(ctx context.Context, alias string) (bool, error) {
+	_, ok := f.aliases[alias]
+	return ok, nil
+}
+
+func (f *fakeIndexLifecycleClient) CreateWriteIndex(ctx context.Context, index, alias string) error {
+	f.aliases[alias] = index
+	return nil
+}
+
+func (f *fakeIndexLifecycleClient) IndicesOutsideAlias(ctx context.Context, pattern, alias string) ([]string, error) {
+	var indices []string
+	for _, index := range f.indices {
+		if !strings.HasPrefix(index, strings.TrimSuffix(pattern, "*")) || index == f.aliases[alias] {
+			continue
+		}
+		adopted := false
+		for _, a := range f.adopted[alias] {
+			adopted = adopted || a == index
+		}
+		if !adopted {
+			indices = append(indices, index)
+		}
+	}
+	return indices, nil
+}
+
+func (f *fakeIndexLifecycleClient) AdoptIndices(ctx context.Context, alias, policy string, indices []string) error {
+	f.adopted[alias] = append(f.adopted[alias], indices...)
+	return nil
+}
+
+func (f *fakeIndexLifecycleClient) LifecycleErrors(ctx context.Context, pattern string) ([]elasticsearch.LifecycleError, error) {
+	return f.stepErrors, nil
+}
+
+func (f *fakeIndexLifecycleClient) Indices(ctx context.Context, pattern string) ([]elasticsearch.Index, error) {
+	return nil, nil
+}
+
+func (f *fakeIndexLifecycleClient) WriteIndices(ctx context.Context, pattern string) (map[string]bool, error) {
+	return nil, nil
+}
+
+func (f *fakeIndexLifecycleClient) StorageCapacity(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+
+func (f *fakeIndexLifecycleClient) DeleteIndex(ctx context.Context, index string) error {
+	return nil
+}
+
+var _ = Describe("LogStorage index lifecycles", func() {
+	var ls *operatorv1.LogStorage
+	var es *fakeIndexLifecycleClient
+	ctx := context.Background()
+
+	BeforeEach(func() {
+		ls = &operatorv1.LogStorage{}
+		fillDefaults(ls)
+		es = &fakeIndexLifecycleClient{
+			policies:  map[string]elasticsearch.LifecyclePolicy{},
+			templates: map[string]elasticsearch.IndexTemplate{},
+			aliases:   map[string]string{},
+			adopted:   map[string][]string{},
+		}
+	})
+
+	It("should roll over and remove the indices of each log type", func() {
+		lifecycles := indexLifecycles(ls, nil)
+		Expect(lifecycles).To(HaveLen(len(logTypes)))
+
+		flows := lifecycles[0]
+		Expect(flows.name).To(Equal("tigera_secure_ee_flows.cluster"))
+		Expect(flows.policy.Phases["hot"].Actions["rollover"]).To(Equal(map[string]interface{}{
+			"max_size": "32212254720b",
+			"max_age":  "1d",
+		}))
+		Expect(flows.policy.Phases["delete"].MinAge).To(Equal("8d"))
+		Expect(flows.template.IndexPatterns).To(Equal([]string{"tigera_secure_ee_flows.cluster.*-*"}))
+		Expect(flows.template.Settings).To(Equal(map[string]interface{}{
+			"index.lifecycle.name":           "tigera_secure_ee_flows.cluster",
+			"index.lifecycle.rollover_alias": "tigera_secure_ee_flows.cluster",
+			"index.number_of_replicas":       0,
+		}))
+	})
+
+	It("should keep each log type for its own retention period", func() {
+		dns, events := int32(3), int32(90)
+		ls.Spec.Retention.DNSLogs = &dns
+		ls.Spec.Retention.IDSEvents = &events
+		retention := map[string]string{}
+		for _, lc := range indexLifecycles(ls, nil) {
+			retention[lc.name] = lc.policy.Phases["delete"].MinAge
+		}
+		Expect(retention).To(Equal(map[string]string{
+			"tigera_secure_ee_flows.cluster":              "8d",
+			"tigera_secure_ee_dns.cluster":                "3d",
+			"tigera_secure_ee_audit_ee.cluster":           "365d",
+			"tigera_secure_ee_audit_kube.cluster":         "365d",
+			"tigera_secure_ee_snapshots.cluster":          "365d",
+			"tigera_secure_ee_compliance_reports.cluster": "365d",
+			"tigera_secure_ee_events.cluster":             "90d",
+			"tigera_secure_ee_l7.cluster":                 "8d",
+			"tigera_secure_ee_bgp.cluster":                "8d",
+		}))
+
+		By("rejecting retention periods out of bounds")
+		Expect(validateRetention(ls.Spec.Retention)).To(Succeed())
+		dns = 0
+		Expect(validateRetention(ls.Spec.Retention)).To(MatchError("retention.dnsLogs must be between 1 and 3650 days"))
+		dns, events = 3, 4000
+		Expect(validateRetention(ls.Spec.Retention)).To(MatchError("retention.idsEvents must be between 1 and 3650 days"))
+	})
+
+	It("should apply the retention of managed clusters to their logs", func() {
+		flows := int32(2)
+		clusters := []operatorv1.ManagedCluster{{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-a"},
+			Spec: operatorv1.ManagedClusterSpec{LogStorage: &operatorv1.ManagedClusterLogStorage{
+				IndexPrefix: "team-a",
+				Retention:   &operatorv1.Retention{Flows: &flows},
+			}},
+		}}
+		lifecycles := indexLifecycles(ls, clusters)
+		Expect(lifecycles).To(HaveLen(2 * len(logTypes)))
+
+		managed := map[string]string{}
+		for _, lc := range lifecycles[len(logTypes):] {
+			managed[lc.name] = lc.policy.Phases["delete"].MinAge
+		}
+		Expect(managed).To(HaveKeyWithValue("tigera_secure_ee_flows.team-a", "2d"))
+		Expect(managed).To(HaveKeyWithValue("tigera_secure_ee_audit_kube.team-a", "365d"))
+
+		By("leaving the retention of the LogStorage unchanged")
+		Expect(*ls.Spec.Retention.Flows).To(Equal(int32(8)))
+	})
+
+	It("should create the write index of each log type once", func() {
+		es.aliases["tigera_secure_ee_dns.cluster"] = "tigera_secure_ee_dns.cluster.2020.01.01-000001"
+		statuses, err := applyIndexLifecycles(ctx, es, indexLifecycles(ls, nil))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(statuses).To(HaveLen(len(logTypes)))
+		for _, status := range statuses {
+			Expect(status.Applied).To(BeTrue())
+		}
+		Expect(es.policies).To(HaveLen(len(logTypes)))
+		Expect(es.templates).To(HaveLen(len(logTypes)))
+		Expect(es.aliases).To(HaveKeyWithValue("tigera_secure_ee_flows.cluster", "<tigera_secure_ee_flows.cluster.{now/d}-000001>"))
+		Expect(es.aliases).To(HaveKeyWithValue("tigera_secure_ee_dns.cluster", "tigera_secure_ee_dns.cluster.2020.01.01-000001"))
+	})
+
+	It("should move existing indices of a log type behind its write alias", func() {
+		es.indices = []string{
+			"tigera_secure_ee_flows.cluster.20200101",
+			"tigera_secure_ee_flows.cluster.20200102",
+			"tigera_secure_ee_flows.team-a.20200101",
+		}
+		_, err := applyIndexLifecycles(ctx, es, indexLifecycles(ls, nil))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(es.aliases).To(HaveKeyWithValue("tigera_secure_ee_flows.cluster", "<tigera_secure_ee_flows.cluster.{now/d}-000001>"))
+		Expect(es.adopted).To(Equal(map[string][]string{
+			"tigera_secure_ee_flows.cluster": {"tigera_secure_ee_flows.cluster.20200101", "tigera_secure_ee_flows.cluster.20200102"},
+		}))
+
+		By("leaving the indices alone once they are behind the alias")
+		_, err = applyIndexLifecycles(ctx, es, indexLifecycles(ls, nil))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(es.adopted["tigera_secure_ee_flows.cluster"]).To(HaveLen(2))
+	})
+
+	It("should report the policies that are not applied", func() {
+		es.rejectPolicy = "tigera_secure_ee_flows.cluster"
+		es.stepErrors = []elasticsearch.LifecycleError{{
+			Index:  "tigera_secure_ee_dns.cluster.2020.01.01-000001",
+			Policy: "tigera_secure_ee_dns.cluster",
+			Reason: "no alias",
+		}}
+		statuses, err := applyIndexLifecycles(ctx, es, indexLifecycles(ls, nil))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(statuses[0].Name).To(Equal("tigera_secure_ee_flows.cluster"))
+		Expect(statuses[0].Applied).To(BeFalse())
+		Expect(statuses[0].Message).To(ContainSubstring("invalid policy"))
+		Expect(statuses[1]).To(Equal(operatorv1.IndexLifecyclePolicyStatus{
+			Name:    "tigera_secure_ee_dns.cluster",
+			Message: "index tigera_secure_ee_dns.cluster.2020.01.01-000001 failed a lifecycle step: no alias",
+		}))
+		Expect(statuses[2].Applied).To(BeTrue())
+	})
+
+	Context("storage limits", func() {
+		indices := []elasticsearch.Index{
+			{Name: "tigera_secure_ee_flows.cluster.2020.01.01-000001", Size: 300},
+			{Name: "tigera_secure_ee_audit_kube.cluster.2020.01.01-000001", Size: 100},
+			{Name: "tigera_secure_ee_flows.team-a.2020.01.01-000001", Size: 200},
+			{Name: "tigera_secure_ee_flows.cluster.2020.01.02-000002", Size: 300},
+			{Name: "tigera_secure_ee_flows.team-a.2020.01.02-000002", Size: 200},
+			{Name: "tigera_secure_ee_audit_kube.cluster.2020.01.02-000002", Size: 100},
+		}
+		writeIndices := map[string]bool{
+			"tigera_secure_ee_flows.cluster.2020.01.02-000002":      true,
+			"tigera_secure_ee_flows.team-a.2020.01.02-000002":       true,
+			"tigera_secure_ee_audit_kube.cluster.2020.01.02-000002": true,
+		}
+
+		It("should keep indices within the limits", func() {
+			Expect(indicesOverLimits(indices, writeIndices, storageLimits{total: 1200, flowOrDNS: 1000})).To(BeEmpty())
+		})
+
+		It("should remove the oldest flow and DNS log indices over their limit", func() {
+			Expect(indicesOverLimits(indices, writeIndices, storageLimits{total: 1200, flowOrDNS: 700})).To(Equal([]string{
+				"tigera_secure_ee_flows.cluster.2020.01.01-000001",
+			}))
+		})
+
+		It("should remove the oldest indices over the total limit, but not write indices", func() {
+			Expect(indicesOverLimits(indices, writeIndices, storageLimits{total: 100, flowOrDNS: 100})).To(Equal([]string{
+				"tigera_secure_ee_flows.cluster.2020.01.01-000001",
+				"tigera_secure_ee_audit_kube.cluster.2020.01.01-000001",
+				"tigera_secure_ee_flows.team-a.2020.01.01-000001",
+			}))
+		})
+
+		It("should apply the ingestion quotas of managed clusters", func() {
+			quota := resource.MustParse("300")
+			il := ls.Spec.IndexLifecycle
+			limits := newStorageLimits(il, 10000, []operatorv1.ManagedCluster{{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster-a"},
+				Spec: operatorv1.ManagedClusterSpec{LogStorage: &operatorv1.ManagedClusterLogStorage{
+					IndexPrefix:    "team-a",
+					IngestionQuota: &quota,
+				}},
+			}})
+			Expect(limits).To(Equal(storageLimits{total: 8000, flowOrDNS: 7000, clusters: map[string]int64{"team-a": 300}}))
+			Expect(indicesOverLimits(indices, writeIndices, limits)).To(Equal([]string{
+				"tigera_secure_ee_flows.team-a.2020.01.01-000001",
+			}))
+		})
+	})
+})
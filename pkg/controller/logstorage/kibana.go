@@ -0,0 +1,142 @@
+// Copyright (c) 2020 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logstorage
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	kibanaalpha1 "github.com/elastic/cloud-on-k8s/pkg/apis/kibana/v1alpha1"
+	operatorv1 "github.com/tigera/operator/pkg/apis/operator/v1"
+	"github.com/tigera/operator/pkg/elasticsearch"
+	"github.com/tigera/operator/pkg/render"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// savedObjectsClient is the part of the Kibana client that saved objects are imported with.
+type savedObjectsClient interface {
+	ImportSavedObjects(ctx context.Context, filename string, ndjson []byte) error
+}
+
+// newKibanaClient returns a client that calls Kibana, under its base path, as the elastic superuser.
+func newKibanaClient(ctx context.Context, cli client.Client) (*elasticsearch.Client, error) {
+	userSecret := &corev1.Secret{}
+	if err := cli.Get(ctx, types.NamespacedName{Name: render.ElasticsearchElasticUserSecret, Namespace: render.ElasticsearchNamespace}, userSecret); err != nil {
+		return nil, err
+	}
+	certSecret := &corev1.Secret{}
+	if err := cli.Get(ctx, types.NamespacedName{Name: render.KibanaPublicCertSecret, Namespace: render.KibanaNamespace}, certSecret); err != nil {
+		return nil, err
+	}
+	kibanaURL := fmt.Sprintf("%s/%s", render.KibanaHTTPSEndpoint, render.KibanaBasePath)
+	return elasticsearch.NewClient(kibanaURL, "elastic", string(userSecret.Data["elastic"]), certSecret.Data["tls.crt"])
+}
+
+// kibanaStatus returns the status of a deployed Kibana, keeping the saved object imports of the previous status.
+func kibanaStatus(kb *kibanaalpha1.Kibana, previous *operatorv1.KibanaStatus) *operatorv1.KibanaStatus {
+	status := &operatorv1.KibanaStatus{
+		Enabled:           true,
+		Health:            string(kb.Status.Health),
+		AvailableReplicas: int32(kb.Status.AvailableNodes),
+	}
+	if previous != nil {
+		status.SavedObjects = previous.SavedObjects
+	}
+	return status
+}
+
+// reconcileSavedObjects imports the saved objects of each ConfigMap of the LogStorage into Kibana, unless the
+// contents of the ConfigMap were imported already, and records the imports in the status of the LogStorage. It
+// returns the first import that failed, if any.
+func (r *ReconcileLogStorage) reconcileSavedObjects(ctx context.Context, kb savedObjectsClient, ls *operatorv1.LogStorage) (*operatorv1.KibanaSavedObjectsStatus, error) {
+	imported := map[string]string{}
+	for _, s := range ls.Status.Kibana.SavedObjects {
+		imported[s.ConfigMapName] = s.Hash
+	}
+
+	var statuses []operatorv1.KibanaSavedObjectsStatus
+	for _, name := range ls.Spec.Kibana.SavedObjectConfigMaps {
+		status := operatorv1.KibanaSavedObjectsStatus{ConfigMapName: name, Hash: imported[name]}
+		cm := &corev1.ConfigMap{}
+		if err := r.client.Get(ctx, types.NamespacedName{Name: name, Namespace: render.OperatorNamespace()}, cm); err != nil {
+			if !errors.IsNotFound(err) {
+				return nil, err
+			}
+			status.Message = "ConfigMap not found"
+		} else if hash := render.AnnotationHash(cm.Data); hash != status.Hash {
+			if err := importSavedObjects(ctx, kb, cm); err != nil {
+				status.Message = err.Error()
+			} else {
+				status.Hash = hash
+			}
+		}
+		statuses = append(statuses, status)
+	}
+	ls.Status.Kibana.SavedObjects = statuses
+
+	for i := range statuses {
+		if statuses[i].Message != "" {
+			return &statuses[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// importSavedObjects imports each key of the ConfigMap as a file of saved objects, in the order of the keys.
+func importSavedObjects(ctx context.Context, kb savedObjectsClient, cm *corev1.ConfigMap) error {
+	var keys []string
+	for key := range cm.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if err := kb.ImportSavedObjects(ctx, key, []byte(cm.Data[key])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeKibana removes Kibana once it is disabled, along with its namespace and the copy of its certificate that
+// the manager and intrusion detection controllers read. Objects that are gone already are skipped, so that a
+// disabled Kibana does not cost a delete request per object on every reconcile.
+func (r *ReconcileLogStorage) removeKibana(ctx context.Context) error {
+	objs := []struct {
+		key types.NamespacedName
+		obj runtime.Object
+	}{
+		{types.NamespacedName{Name: render.KibanaName, Namespace: render.KibanaNamespace}, newKibana(r.eckAPIVersion)},
+		{types.NamespacedName{Name: render.KibanaNamespace}, &corev1.Namespace{}},
+		{types.NamespacedName{Name: render.KibanaPublicCertSecret, Namespace: render.OperatorNamespace()}, &corev1.Secret{}},
+	}
+	for _, o := range objs {
+		if err := r.client.Get(ctx, o.key, o.obj); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		if err := r.client.Delete(ctx, o.obj); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+// Copyright (c) 2020 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logstorage
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/tigera/operator/pkg/apis"
+	operatorv1 "github.com/tigera/operator/pkg/apis/operator/v1"
+	"github.com/tigera/operator/pkg/controller/status"
+	"github.com/tigera/operator/pkg/render"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// fakeSavedObjectsClient stands in for Kibana when importing saved objects.
+type fakeSavedObjectsClient struct {
+	imported []string
+	reject   string
+}
+
+func (f *fakeSavedObjectsClient) ImportSavedObjects(ctx context.Context, filename string, ndjson []byte) error {
+	if filename == f.reject {
+		return fmt.Errorf("Kibana did not import %s", filename)
+	}
+	f.imported = append(f.imported, filename)
+	return nil
+}
+
+// deleteCountingClient counts the delete requests made through it.
+type deleteCountingClient struct {
+	client.Client
+	deletes int
+}
+
+func (d *deleteCountingClient) Delete(ctx context.Context, obj runtime.Object, opts ...client.DeleteOption) error {
+	d.deletes++
+	return d.Client.Delete(ctx, obj, opts...)
+}
+
+var _ = Describe("LogStorage Kibana", func() {
+	var cli client.Client
+	var r *ReconcileLogStorage
+	var kb *fakeSavedObjectsClient
+	var ls *operatorv1.LogStorage
+	ctx := context.Background()
+
+	createConfigMap := func(name string, data map[string]string) {
+		Expect(cli.Create(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: render.OperatorNamespace()},
+			Data:       data,
+		})).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(apis.AddToScheme(scheme)).To(Succeed())
+		cli = fake.NewFakeClientWithScheme(scheme)
+		r = &ReconcileLogStorage{client: cli, scheme: scheme, status: status.New(cli, "log-storage")}
+		kb = &fakeSavedObjectsClient{}
+		ls = &operatorv1.LogStorage{}
+		fillDefaults(ls)
+		ls.Status.Kibana = &operatorv1.KibanaStatus{Enabled: true}
+	})
+
+	It("should import each ConfigMap once until it changes", func() {
+		createConfigMap("dashboards", map[string]string{"network.ndjson": "{}", "dns.ndjson": "{}"})
+		ls.Spec.Kibana.SavedObjectConfigMaps = []string{"dashboards"}
+
+		failed, err := r.reconcileSavedObjects(ctx, kb, ls)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(failed).To(BeNil())
+		Expect(kb.imported).To(Equal([]string{"dns.ndjson", "network.ndjson"}))
+		Expect(ls.Status.Kibana.SavedObjects).To(HaveLen(1))
+		Expect(ls.Status.Kibana.SavedObjects[0].Hash).NotTo(BeEmpty())
+
+		By("skipping contents that were imported already")
+		kb.imported = nil
+		_, err = r.reconcileSavedObjects(ctx, kb, ls)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(kb.imported).To(BeEmpty())
+
+		By("importing the ConfigMap again once it changes")
+		cm := &corev1.ConfigMap{}
+		Expect(cli.Get(ctx, client.ObjectKey{Name: "dashboards", Namespace: render.OperatorNamespace()}, cm)).To(Succeed())
+		cm.Data["dns.ndjson"] = `{"type":"dashboard"}`
+		Expect(cli.Update(ctx, cm)).To(Succeed())
+		_, err = r.reconcileSavedObjects(ctx, kb, ls)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(kb.imported).To(Equal([]string{"dns.ndjson", "network.ndjson"}))
+	})
+
+	It("should report ConfigMaps that are missing or fail to import", func() {
+		createConfigMap("broken", map[string]string{"broken.ndjson": "{}"})
+		ls.Spec.Kibana.SavedObjectConfigMaps = []string{"missing", "broken"}
+		kb.reject = "broken.ndjson"
+
+		failed, err := r.reconcileSavedObjects(ctx, kb, ls)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(failed).To(Equal(&operatorv1.KibanaSavedObjectsStatus{ConfigMapName: "missing", Message: "ConfigMap not found"}))
+		Expect(ls.Status.Kibana.SavedObjects).To(Equal([]operatorv1.KibanaSavedObjectsStatus{
+			{ConfigMapName: "missing", Message: "ConfigMap not found"},
+			{ConfigMapName: "broken", Message: "Kibana did not import broken.ndjson"},
+		}))
+	})
+
+	It("should only delete the Kibana objects that remain once Kibana is disabled", func() {
+		Expect(cli.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: render.KibanaNamespace}})).To(Succeed())
+		Expect(cli.Create(ctx, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: render.KibanaPublicCertSecret, Namespace: render.OperatorNamespace()},
+		})).To(Succeed())
+		counting := &deleteCountingClient{Client: cli}
+		r.client = counting
+
+		Expect(r.removeKibana(ctx)).To(Succeed())
+		Expect(counting.deletes).To(Equal(2))
+		Expect(cli.Get(ctx, client.ObjectKey{Name: render.KibanaNamespace}, &corev1.Namespace{})).NotTo(Succeed())
+
+		By("not deleting anything once Kibana is gone")
+		counting.deletes = 0
+		Expect(r.removeKibana(ctx)).To(Succeed())
+		Expect(counting.deletes).To(BeZero())
+	})
+})
@@ -10,8 +10,6 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
-	esalpha1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1alpha1"
-	kibanaalpha1 "github.com/elastic/cloud-on-k8s/pkg/apis/kibana/v1alpha1"
 	"github.com/go-logr/logr"
 	operatorv1 "github.com/tigera/operator/pkg/apis/operator/v1"
 	"github.com/tigera/operator/pkg/controller/installation"
@@ -20,6 +18,7 @@ import (
 	"github.com/tigera/operator/pkg/render"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -50,7 +49,18 @@ func Add(mgr manager.Manager, provider operatorv1.Provider, tsee bool) error {
 		return err
 	}
 
-	return add(mgr, r)
+	// Elasticsearch and Kibana are managed through the newest version of the ECK APIs that the cluster serves.
+	served, err := servedECKVersions(mgr.GetConfig())
+	if err != nil {
+		return err
+	}
+	if r.eckAPIVersion, r.unsupportedECK = eckAPIVersion(served); r.unsupportedECK != "" {
+		log.Info("Elasticsearch and Kibana cannot be managed", "reason", r.unsupportedECK)
+	} else {
+		log.Info("Managing Elasticsearch and Kibana", "eckAPIVersion", r.eckAPIVersion)
+	}
+
+	return add(mgr, r, r.eckAPIVersion)
 }
 
 // newReconciler returns a new reconcile.Reconciler
@@ -103,8 +113,9 @@ func getLocalDNSName(resolvConfPath string) (string, error) {
 	return localDNSName, nil
 }
 
-// add adds a new Controller to mgr with r as the reconcile.Reconciler
-func add(mgr manager.Manager, r reconcile.Reconciler) error {
+// add adds a new Controller to mgr with r as the reconcile.Reconciler. Elasticsearch and Kibana are watched in the
+// given version of the ECK APIs, unless it is empty.
+func add(mgr manager.Manager, r reconcile.Reconciler, eckAPIVersion string) error {
 	c, err := controller.New("log-storage-controller", mgr, controller.Options{Reconciler: r})
 	if err != nil {
 		return err
@@ -120,18 +131,29 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		return fmt.Errorf("log-storage-controller failed to watch Network resource: %v", err)
 	}
 
-	if err = c.Watch(&source.Kind{Type: &esalpha1.Elasticsearch{}}, &handler.EnqueueRequestForOwner{
-		IsController: true,
-		OwnerType:    &operatorv1.LogStorage{},
-	}); err != nil {
-		return fmt.Errorf("log-storage-controller failed to watch Elasticsearch resource: %v", err)
+	// In a management cluster, the logs of each managed cluster are curated with its own settings.
+	if err = c.Watch(&source.Kind{Type: &operatorv1.ManagedCluster{}}, &handler.EnqueueRequestForObject{}); err != nil {
+		return fmt.Errorf("log-storage-controller failed to watch ManagedCluster resource: %v", err)
 	}
 
-	if err = c.Watch(&source.Kind{Type: &kibanaalpha1.Kibana{}}, &handler.EnqueueRequestForOwner{
-		IsController: true,
-		OwnerType:    &operatorv1.LogStorage{},
-	}); err != nil {
-		return fmt.Errorf("log-storage-controller failed to watch Kibana resource: %v", err)
+	if err = c.Watch(&source.Kind{Type: &operatorv1.LogStorageRestore{}}, &handler.EnqueueRequestForObject{}); err != nil {
+		return fmt.Errorf("log-storage-controller failed to watch LogStorageRestore resource: %v", err)
+	}
+
+	if eckAPIVersion != "" {
+		if err = c.Watch(&source.Kind{Type: newElasticsearch(eckAPIVersion)}, &handler.EnqueueRequestForOwner{
+			IsController: true,
+			OwnerType:    &operatorv1.LogStorage{},
+		}); err != nil {
+			return fmt.Errorf("log-storage-controller failed to watch Elasticsearch resource: %v", err)
+		}
+
+		if err = c.Watch(&source.Kind{Type: newKibana(eckAPIVersion)}, &handler.EnqueueRequestForOwner{
+			IsController: true,
+			OwnerType:    &operatorv1.LogStorage{},
+		}); err != nil {
+			return fmt.Errorf("log-storage-controller failed to watch Kibana resource: %v", err)
+		}
 	}
 
 	// Watch all the elasticsearch user secrets in the operator namespace. In the future, we may want put this logic in
@@ -154,6 +176,28 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		return err
 	}
 
+	// Watch the Secrets that the LogStorage references for an external Elasticsearch cluster. Their names are chosen
+	// by the user, so they are matched against the LogStorage rather than by label.
+	if err = c.Watch(&source.Kind{Type: &corev1.Secret{}}, &handler.EnqueueRequestForObject{}, externalSecretPredicate(mgr.GetClient())); err != nil {
+		return fmt.Errorf("log-storage-controller failed to watch the external Elasticsearch Secrets: %v", err)
+	}
+
+	// Watch the ConfigMaps in the operator namespace, so that changed Kibana saved objects are imported.
+	err = c.Watch(&source.Kind{Type: &corev1.ConfigMap{}}, &handler.EnqueueRequestForObject{}, &predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return e.Meta.GetNamespace() == render.OperatorNamespace()
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return e.MetaNew.GetNamespace() == render.OperatorNamespace()
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return e.Meta.GetNamespace() == render.OperatorNamespace()
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("log-storage-controller failed to watch the ConfigMap resource: %v", err)
+	}
+
 	// Watch all the secrets created by this controller so we can regenerate any that are deleted
 	for _, secretName := range []string{render.TigeraElasticsearchCertSecret, render.TigeraKibanaCertSecret, render.ECKWebhookSecretName} {
 		if err = utils.AddSecretsWatch(c, secretName, render.OperatorNamespace()); err != nil {
@@ -180,6 +224,11 @@ type ReconcileLogStorage struct {
 	status   *status.StatusManager
 	provider operatorv1.Provider
 	localDNS string
+
+	// eckAPIVersion is the version of the ECK APIs that Elasticsearch and Kibana are managed through.
+	eckAPIVersion string
+	// unsupportedECK is why Elasticsearch and Kibana cannot be managed through the ECK APIs that the cluster serves.
+	unsupportedECK string
 }
 
 func GetLogStorage(ctx context.Context, cli client.Client) (*operatorv1.LogStorage, error) {
@@ -215,6 +264,22 @@ func fillDefaults(opr *operatorv1.LogStorage) {
 		var crr int32 = 365
 		opr.Spec.Retention.ComplianceReports = &crr
 	}
+	if opr.Spec.Retention.DNSLogs == nil {
+		var dr int32 = 8
+		opr.Spec.Retention.DNSLogs = &dr
+	}
+	if opr.Spec.Retention.IDSEvents == nil {
+		var ir int32 = 365
+		opr.Spec.Retention.IDSEvents = &ir
+	}
+	if opr.Spec.Retention.L7Logs == nil {
+		var lr int32 = 8
+		opr.Spec.Retention.L7Logs = &lr
+	}
+	if opr.Spec.Retention.BGPLogs == nil {
+		var br int32 = 8
+		opr.Spec.Retention.BGPLogs = &br
+	}
 
 	if opr.Spec.Indices == nil {
 		opr.Spec.Indices = &operatorv1.Indices{}
@@ -224,6 +289,46 @@ func fillDefaults(opr *operatorv1.LogStorage) {
 		var replicas int32 = render.DefaultElasticsearchReplicas
 		opr.Spec.Indices.Replicas = &replicas
 	}
+
+	if opr.Spec.StorageClassName == "" {
+		opr.Spec.StorageClassName = render.ElasticsearchStorageClass
+	}
+
+	if opr.Spec.IndexLifecycle == nil {
+		opr.Spec.IndexLifecycle = &operatorv1.IndexLifecycle{}
+	}
+	il := opr.Spec.IndexLifecycle
+	if il.RolloverSize == nil {
+		size := resource.MustParse("30Gi")
+		il.RolloverSize = &size
+	}
+	if il.RolloverAge == "" {
+		il.RolloverAge = "1d"
+	}
+	if il.MaxTotalStoragePercent == nil {
+		var total int32 = 80
+		il.MaxTotalStoragePercent = &total
+	}
+	if il.MaxLogsStoragePercent == nil {
+		var logs int32 = 70
+		il.MaxLogsStoragePercent = &logs
+	}
+
+	if opr.Spec.Users == nil {
+		opr.Spec.Users = &operatorv1.ElasticsearchUsers{}
+	}
+	if opr.Spec.Users.PasswordRotationDays == nil {
+		var days int32 = 90
+		opr.Spec.Users.PasswordRotationDays = &days
+	}
+
+	if opr.Spec.Kibana == nil {
+		opr.Spec.Kibana = &operatorv1.Kibana{}
+	}
+	if opr.Spec.Kibana.Replicas == nil {
+		var replicas int32 = 1
+		opr.Spec.Kibana.Replicas = &replicas
+	}
 }
 
 // Reconcile reads that state of the cluster for a LogStorage object and makes changes based on the state read
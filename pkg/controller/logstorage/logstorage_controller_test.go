@@ -2,7 +2,11 @@ package logstorage_test
 
 import (
 	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
 
 	esalpha1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1alpha1"
 	kibanav1alpha1 "github.com/elastic/cloud-on-k8s/pkg/apis/kibana/v1alpha1"
@@ -18,6 +22,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -156,5 +161,174 @@ var _ = Describe("Log storage controller", func() {
 				cli.Get(ctx, client.ObjectKey{Name: render.KibanaName, Namespace: render.KibanaNamespace}, &kibanav1alpha1.Kibana{}),
 			).ShouldNot(HaveOccurred())
 		})
+
+		It("does not create Elasticsearch for an invalid node configuration", func() {
+			ctx := context.Background()
+			ls := &operatorv1.LogStorage{}
+			Expect(cli.Get(ctx, client.ObjectKey{Name: "tigera-secure"}, ls)).ShouldNot(HaveOccurred())
+			ls.Spec.Nodes.ZoneAwareness = &operatorv1.ZoneAwareness{Zones: []string{"zone-a", "zone-b"}}
+			Expect(cli.Update(ctx, ls)).ShouldNot(HaveOccurred())
+
+			r, err := logstorage.NewReconcilerWithShims(cli, scheme, status.New(cli, "log-storage"), operatorv1.ProviderNone, resolvConfPath)
+			Expect(err).ShouldNot(HaveOccurred())
+			_, err = r.Reconcile(reconcile.Request{})
+			Expect(err).ShouldNot(HaveOccurred())
+
+			Expect(
+				cli.Get(ctx, client.ObjectKey{Name: render.ElasticsearchName, Namespace: render.ElasticsearchNamespace}, &esalpha1.Elasticsearch{}),
+			).Should(HaveOccurred())
+			Expect(cli.Get(ctx, client.ObjectKey{Name: "tigera-secure"}, ls)).ShouldNot(HaveOccurred())
+			Expect(ls.Status.State).To(Equal(operatorv1.LogStorageStatusDegraded))
+		})
+
+		Context("with existing Elasticsearch volumes", func() {
+			var pvc *corev1.PersistentVolumeClaim
+			BeforeEach(func() {
+				pvc = &corev1.PersistentVolumeClaim{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "elasticsearch-data-tigera-secure-es-0",
+						Namespace: render.ElasticsearchNamespace,
+						Labels:    map[string]string{"elasticsearch.k8s.elastic.co/statefulset-name": render.ElasticsearchStatefulSetName("")},
+					},
+					Spec: corev1.PersistentVolumeClaimSpec{
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{"storage": resource.MustParse("5Gi")},
+						},
+					},
+				}
+				Expect(cli.Create(context.Background(), pvc)).ShouldNot(HaveOccurred())
+			})
+
+			It("does not grow the volumes if the storage class does not allow volume expansion", func() {
+				ctx := context.Background()
+				r, err := logstorage.NewReconcilerWithShims(cli, scheme, status.New(cli, "log-storage"), operatorv1.ProviderNone, resolvConfPath)
+				Expect(err).ShouldNot(HaveOccurred())
+				_, err = r.Reconcile(reconcile.Request{})
+				Expect(err).ShouldNot(HaveOccurred())
+
+				Expect(
+					cli.Get(ctx, client.ObjectKey{Name: render.ElasticsearchName, Namespace: render.ElasticsearchNamespace}, &esalpha1.Elasticsearch{}),
+				).Should(HaveOccurred())
+				Expect(cli.Get(ctx, client.ObjectKey{Name: pvc.Name, Namespace: pvc.Namespace}, pvc)).ShouldNot(HaveOccurred())
+				Expect(pvc.Spec.Resources.Requests.Storage().String()).To(Equal("5Gi"))
+			})
+
+			It("grows the volumes if the storage class allows volume expansion", func() {
+				ctx := context.Background()
+				sc := &storagev1.StorageClass{}
+				Expect(cli.Get(ctx, client.ObjectKey{Name: render.ElasticsearchStorageClass}, sc)).ShouldNot(HaveOccurred())
+				allowExpansion := true
+				sc.AllowVolumeExpansion = &allowExpansion
+				Expect(cli.Update(ctx, sc)).ShouldNot(HaveOccurred())
+
+				r, err := logstorage.NewReconcilerWithShims(cli, scheme, status.New(cli, "log-storage"), operatorv1.ProviderNone, resolvConfPath)
+				Expect(err).ShouldNot(HaveOccurred())
+				_, err = r.Reconcile(reconcile.Request{})
+				Expect(err).ShouldNot(HaveOccurred())
+
+				Expect(
+					cli.Get(ctx, client.ObjectKey{Name: render.ElasticsearchName, Namespace: render.ElasticsearchNamespace}, &esalpha1.Elasticsearch{}),
+				).ShouldNot(HaveOccurred())
+				Expect(cli.Get(ctx, client.ObjectKey{Name: pvc.Name, Namespace: pvc.Namespace}, pvc)).ShouldNot(HaveOccurred())
+				Expect(pvc.Spec.Resources.Requests.Storage().String()).To(Equal("10Gi"))
+			})
+		})
+
+		Context("with an external Elasticsearch", func() {
+			var server *httptest.Server
+			BeforeEach(func() {
+				// The stand-in accepts every change and holds no indices.
+				server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					switch {
+					case r.URL.Path == "/":
+						_, _ = w.Write([]byte(`{"cluster_name":"logs","version":{"number":"7.6.2"}}`))
+					case strings.HasPrefix(r.URL.Path, "/_cat/"):
+						_, _ = w.Write([]byte(`[]`))
+					case strings.HasSuffix(r.URL.Path, "/_ilm/explain"):
+						_, _ = w.Write([]byte(`{"indices":{}}`))
+					default:
+						_, _ = w.Write([]byte(`{}`))
+					}
+				}))
+
+				ctx := context.Background()
+				ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+				Expect(cli.Create(ctx, &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "external-es-ca", Namespace: render.OperatorNamespace()},
+					Data:       map[string][]byte{"ca.crt": ca},
+				})).ShouldNot(HaveOccurred())
+				Expect(cli.Create(ctx, &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "external-es-admin", Namespace: render.OperatorNamespace()},
+					Data:       map[string][]byte{"username": []byte("admin"), "password": []byte("password")},
+				})).ShouldNot(HaveOccurred())
+
+				ls := &operatorv1.LogStorage{}
+				Expect(cli.Get(ctx, client.ObjectKey{Name: "tigera-secure"}, ls)).ShouldNot(HaveOccurred())
+				ls.Spec.External = &operatorv1.ExternalElasticsearch{
+					Endpoint:              server.URL,
+					CASecretName:          "external-es-ca",
+					CredentialsSecretName: "external-es-admin",
+				}
+				Expect(cli.Update(ctx, ls)).ShouldNot(HaveOccurred())
+			})
+
+			AfterEach(func() {
+				server.Close()
+			})
+
+			It("connects the components to the external cluster", func() {
+				ctx := context.Background()
+				r, err := logstorage.NewReconcilerWithShims(cli, scheme, status.New(cli, "log-storage"), operatorv1.ProviderNone, resolvConfPath)
+				Expect(err).ShouldNot(HaveOccurred())
+				_, err = r.Reconcile(reconcile.Request{})
+				Expect(err).ShouldNot(HaveOccurred())
+
+				Expect(
+					cli.Get(ctx, client.ObjectKey{Name: render.ElasticsearchName, Namespace: render.ElasticsearchNamespace}, &esalpha1.Elasticsearch{}),
+				).Should(HaveOccurred())
+				Expect(
+					cli.Get(ctx, client.ObjectKey{Name: render.KibanaName, Namespace: render.KibanaNamespace}, &kibanav1alpha1.Kibana{}),
+				).Should(HaveOccurred())
+
+				ls := &operatorv1.LogStorage{}
+				Expect(cli.Get(ctx, client.ObjectKey{Name: "tigera-secure"}, ls)).ShouldNot(HaveOccurred())
+				Expect(ls.Status.State).To(Equal(operatorv1.LogStorageStatusReady))
+				Expect(ls.Status.External).To(Equal(&operatorv1.ExternalElasticsearchStatus{Reachable: true, Version: "7.6.2"}))
+
+				configMap := &corev1.ConfigMap{}
+				Expect(cli.Get(ctx, client.ObjectKey{Name: render.ElasticsearchConfigMapName, Namespace: render.OperatorNamespace()}, configMap)).ShouldNot(HaveOccurred())
+				Expect(configMap.Data).To(HaveKeyWithValue("url", server.URL))
+
+				By("creating the users of the components once")
+				userSecret := &corev1.Secret{}
+				Expect(cli.Get(ctx, client.ObjectKey{Name: render.ElasticsearchManagerUserSecret, Namespace: render.OperatorNamespace()}, userSecret)).ShouldNot(HaveOccurred())
+				Expect(string(userSecret.Data["username"])).To(Equal("tigera-ee-manager"))
+				password := userSecret.Data["password"]
+				Expect(password).NotTo(BeEmpty())
+
+				_, err = r.Reconcile(reconcile.Request{})
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(cli.Get(ctx, client.ObjectKey{Name: render.ElasticsearchManagerUserSecret, Namespace: render.OperatorNamespace()}, userSecret)).ShouldNot(HaveOccurred())
+				Expect(userSecret.Data["password"]).To(Equal(password))
+			})
+
+			It("reports an unreachable external cluster", func() {
+				server.Close()
+				ctx := context.Background()
+				r, err := logstorage.NewReconcilerWithShims(cli, scheme, status.New(cli, "log-storage"), operatorv1.ProviderNone, resolvConfPath)
+				Expect(err).ShouldNot(HaveOccurred())
+				_, err = r.Reconcile(reconcile.Request{})
+				Expect(err).ShouldNot(HaveOccurred())
+
+				ls := &operatorv1.LogStorage{}
+				Expect(cli.Get(ctx, client.ObjectKey{Name: "tigera-secure"}, ls)).ShouldNot(HaveOccurred())
+				Expect(ls.Status.State).To(Equal(operatorv1.LogStorageStatusDegraded))
+				Expect(ls.Status.External.Reachable).To(BeFalse())
+				Expect(ls.Status.External.Message).NotTo(BeEmpty())
+				Expect(
+					cli.Get(ctx, client.ObjectKey{Name: render.ElasticsearchManagerUserSecret, Namespace: render.OperatorNamespace()}, &corev1.Secret{}),
+				).Should(HaveOccurred())
+			})
+		})
 	})
 })
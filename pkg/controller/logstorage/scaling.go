@@ -0,0 +1,200 @@
+// Copyright (c) 2020 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logstorage
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	esalpha1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1alpha1"
+	"github.com/tigera/operator/pkg/elasticsearch"
+	"github.com/tigera/operator/pkg/render"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	// logIndexPattern matches the indices of every log type of this cluster and the managed clusters.
+	logIndexPattern = "tigera_secure_ee_*"
+
+	// maxDiskUsagePercent is the disk usage of the remaining nodes that a scale down may lead to. Elasticsearch
+	// does not allocate shards to nodes past its low disk watermark of 85%.
+	maxDiskUsagePercent = 85
+)
+
+// scalingClient is the part of the Elasticsearch client that scale downs are checked and replicas are changed with.
+// Moving the shards off the nodes that are removed is left to ECK, which excludes the nodes from shard allocation
+// and only deletes them once their shards have moved.
+type scalingClient interface {
+	Allocations(ctx context.Context) ([]elasticsearch.NodeAllocation, error)
+	IndexReplicas(ctx context.Context, pattern string) (map[string]int, error)
+	SetIndexReplicas(ctx context.Context, pattern string, replicas int) error
+	ClusterHealth(ctx context.Context) (elasticsearch.ClusterHealth, error)
+}
+
+// nodeNames returns the names of the Elasticsearch nodes of the node specs, which ECK names after their pods.
+func nodeNames(specs []esalpha1.NodeSpec) map[string]bool {
+	names := map[string]bool{}
+	for _, spec := range specs {
+		for i := int32(0); i < spec.NodeCount; i++ {
+			names[fmt.Sprintf("%s-%d", render.ElasticsearchStatefulSetName(spec.Name), i)] = true
+		}
+	}
+	return names
+}
+
+// departingNodes returns the nodes of the current Elasticsearch cluster that the desired cluster does not have.
+func departingNodes(current, desired *esalpha1.Elasticsearch) []string {
+	desiredNodes := nodeNames(desired.Spec.Nodes)
+	var departing []string
+	for name := range nodeNames(current.Spec.Nodes) {
+		if !desiredNodes[name] {
+			departing = append(departing, name)
+		}
+	}
+	sort.Strings(departing)
+	return departing
+}
+
+// isDataNodeSpec returns whether the nodes of the node spec hold data, which they do unless configured otherwise.
+func isDataNodeSpec(spec esalpha1.NodeSpec) bool {
+	if spec.Config == nil {
+		return true
+	}
+	data, ok := spec.Config.Data["node.data"]
+	return !ok || fmt.Sprint(data) != "false"
+}
+
+// nodeSpecStorage returns the size of the data volume requested for each node of the node spec, in bytes.
+func nodeSpecStorage(spec esalpha1.NodeSpec) int64 {
+	for _, pvc := range spec.VolumeClaimTemplates {
+		if pvc.Name == "elasticsearch-data" {
+			storage := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+			return storage.Value()
+		}
+	}
+	return 0
+}
+
+// checkScaleDown returns an error if handing the desired cluster to ECK could lose data. Nodes are not removed
+// while shards are missing from the cluster, the data nodes of the desired cluster must be able to hold every
+// replica of an index on a node of its own, and they must have the disk space for the data of the cluster. The data
+// nodes of the desired cluster are the nodes that remain and the nodes that are added, which may be all of them when
+// the node specs are renamed, such as when zone awareness or dedicated node sets are turned on. Nodes that are added
+// are counted with the size of the volume they request.
+func checkScaleDown(ctx context.Context, es scalingClient, desired *esalpha1.Elasticsearch, replicas int) error {
+	health, err := es.ClusterHealth(ctx)
+	if err != nil {
+		return err
+	}
+	if health.Status == "red" {
+		return fmt.Errorf("the Elasticsearch cluster is red, nodes are not removed while shards are unassigned")
+	}
+	nodes, err := es.Allocations(ctx)
+	if err != nil {
+		return err
+	}
+
+	var used int64
+	diskTotal := map[string]int64{}
+	for _, node := range nodes {
+		used += node.DiskIndices
+		diskTotal[node.Name] = node.DiskTotal
+	}
+
+	var capacity int64
+	dataNodes := 0
+	for _, spec := range desired.Spec.Nodes {
+		if !isDataNodeSpec(spec) {
+			continue
+		}
+		for name := range nodeNames([]esalpha1.NodeSpec{spec}) {
+			dataNodes++
+			if total, ok := diskTotal[name]; ok {
+				capacity += total
+			} else {
+				capacity += nodeSpecStorage(spec)
+			}
+		}
+	}
+
+	if replicas >= dataNodes {
+		return fmt.Errorf("the indices have %d replicas, which need at least %d Elasticsearch data nodes but %d would remain",
+			replicas, replicas+1, dataNodes)
+	}
+	if used*100 > capacity*maxDiskUsagePercent {
+		return fmt.Errorf("the remaining Elasticsearch nodes do not have the disk space for the %s of logs in the cluster",
+			resource.NewQuantity(used, resource.BinarySI).String())
+	}
+	return nil
+}
+
+// prepareScaleDown checks that the departing nodes can be removed without losing data. ECK only removes a node once
+// its shards have moved, so once the scale down is accepted the existing indices are given the replicas of the
+// LogStorage, in case they have more than the remaining nodes can hold. It returns false with the reason if the scale
+// down is rejected, in which case the replicas are left as they are.
+func prepareScaleDown(ctx context.Context, es scalingClient, desired *esalpha1.Elasticsearch, replicas int) (bool, error) {
+	if err := checkScaleDown(ctx, es, desired, replicas); err != nil {
+		return false, err
+	}
+	return true, migrateReplicas(ctx, es, replicas)
+}
+
+// drainingNodes returns the nodes that are still in the cluster and hold shards, although the desired cluster
+// does not have them. ECK moves their shards to the remaining nodes before it removes them.
+func drainingNodes(ctx context.Context, es scalingClient, desired *esalpha1.Elasticsearch) ([]string, error) {
+	nodes, err := es.Allocations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	desiredNodes := nodeNames(desired.Spec.Nodes)
+	var draining []string
+	for _, node := range nodes {
+		if !desiredNodes[node.Name] && node.Shards > 0 {
+			draining = append(draining, node.Name)
+		}
+	}
+	return draining, nil
+}
+
+// migrateReplicas sets the replicas of the existing log indices to the replicas of the LogStorage.
+func migrateReplicas(ctx context.Context, es scalingClient, replicas int) error {
+	current, err := es.IndexReplicas(ctx, logIndexPattern)
+	if err != nil {
+		return err
+	}
+	changed := 0
+	for _, n := range current {
+		if n != replicas {
+			changed++
+		}
+	}
+	if changed == 0 {
+		return nil
+	}
+	log.Info("Changing the replicas of the log indices", "indices", changed, "replicas", replicas)
+	return es.SetIndexReplicas(ctx, logIndexPattern, replicas)
+}
+
+// dataMigrationMessage returns a description of the shards that Elasticsearch is still moving or creating, if any.
+func dataMigrationMessage(health elasticsearch.ClusterHealth) string {
+	if health.RelocatingShards == 0 && health.InitializingShards == 0 && health.UnassignedShards == 0 {
+		return ""
+	}
+	return fmt.Sprintf("Elasticsearch is %s with %d relocating, %d initializing and %d unassigned shards",
+		health.Status, health.RelocatingShards, health.InitializingShards, health.UnassignedShards)
+}
@@ -0,0 +1,192 @@
+// Copyright (c) 2020 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logstorage
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	cmneckalpha1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1alpha1"
+	esalpha1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1alpha1"
+	"github.com/tigera/operator/pkg/elasticsearch"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// fakeScalingClient stands in for Elasticsearch when scale downs are checked and replicas are changed.
+type fakeScalingClient struct {
+	health      elasticsearch.ClusterHealth
+	nodes       []elasticsearch.NodeAllocation
+	replicas    map[string]int
+	setReplicas *int
+}
+
+func (f *fakeScalingClient) Allocations(ctx context.Context) ([]elasticsearch.NodeAllocation, error) {
+	return f.nodes, nil
+}
+
+func (f *fakeScalingClient) IndexReplicas(ctx context.Context, pattern string) (map[string]int, error) {
+	return f.replicas, nil
+}
+
+func (f *fakeScalingClient) SetIndexReplicas(ctx context.Context, pattern string, replicas int) error {
+	f.setReplicas = &replicas
+	return nil
+}
+
+func (f *fakeScalingClient) ClusterHealth(ctx context.Context) (elasticsearch.ClusterHealth, error) {
+	return f.health, nil
+}
+
+var _ = Describe("LogStorage Elasticsearch scaling", func() {
+	var es *fakeScalingClient
+	ctx := context.Background()
+
+	cluster := func(specs ...esalpha1.NodeSpec) *esalpha1.Elasticsearch {
+		return &esalpha1.Elasticsearch{Spec: esalpha1.ElasticsearchSpec{Nodes: specs}}
+	}
+
+	// nodeSpec returns a node spec like the ones rendered for the LogStorage, with 10Gi data volumes.
+	nodeSpec := func(name string, count int32, data bool) esalpha1.NodeSpec {
+		return esalpha1.NodeSpec{
+			Name:      name,
+			NodeCount: count,
+			Config:    &cmneckalpha1.Config{Data: map[string]interface{}{"node.data": fmt.Sprint(data)}},
+			VolumeClaimTemplates: []corev1.PersistentVolumeClaim{{
+				ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch-data"},
+				Spec: corev1.PersistentVolumeClaimSpec{
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")},
+					},
+				},
+			}},
+		}
+	}
+
+	BeforeEach(func() {
+		es = &fakeScalingClient{
+			health: elasticsearch.ClusterHealth{Status: "green"},
+			nodes: []elasticsearch.NodeAllocation{
+				{Name: "tigera-secure-es-data-0", Shards: 4, DiskIndices: 2 << 30, DiskTotal: 10 << 30},
+				{Name: "tigera-secure-es-data-1", Shards: 4, DiskIndices: 2 << 30, DiskTotal: 10 << 30},
+				{Name: "tigera-secure-es-data-2", Shards: 4, DiskIndices: 2 << 30, DiskTotal: 10 << 30},
+			},
+		}
+	})
+
+	It("should report the departing nodes until ECK has moved their shards", func() {
+		current := cluster(esalpha1.NodeSpec{Name: "master", NodeCount: 1}, esalpha1.NodeSpec{Name: "data", NodeCount: 3})
+		desired := cluster(esalpha1.NodeSpec{Name: "data", NodeCount: 2})
+
+		departing := departingNodes(current, desired)
+		Expect(departing).To(Equal([]string{"tigera-secure-es-data-2", "tigera-secure-es-master-0"}))
+		Expect(checkScaleDown(ctx, es, desired, 1)).To(Succeed())
+
+		draining, err := drainingNodes(ctx, es, desired)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(draining).To(Equal([]string{"tigera-secure-es-data-2"}))
+
+		By("no longer reporting the nodes once their shards have moved")
+		es.nodes[2].Shards = 0
+		draining, err = drainingNodes(ctx, es, desired)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(draining).To(BeEmpty())
+	})
+
+	It("should not remove nodes that the remaining nodes cannot take the data of", func() {
+		es.nodes[0].DiskIndices = 14 << 30
+		Expect(checkScaleDown(ctx, es, cluster(nodeSpec("data", 2, true)), 1)).To(
+			MatchError("the remaining Elasticsearch nodes do not have the disk space for the 18Gi of logs in the cluster"))
+	})
+
+	It("should not remove nodes while shards are missing", func() {
+		es.health.Status = "red"
+		Expect(checkScaleDown(ctx, es, cluster(nodeSpec("data", 2, true)), 1)).NotTo(Succeed())
+	})
+
+	It("should not leave fewer data nodes than the replicas need", func() {
+		Expect(checkScaleDown(ctx, es, cluster(nodeSpec("data", 2, true)), 2)).To(
+			MatchError("the indices have 2 replicas, which need at least 3 Elasticsearch data nodes but 2 would remain"))
+
+		By("not counting the nodes that hold no data")
+		Expect(checkScaleDown(ctx, es, cluster(nodeSpec("master", 3, false), nodeSpec("data", 1, true)), 1)).NotTo(Succeed())
+	})
+
+	It("should allow moving from a single node set to dedicated node sets", func() {
+		es.nodes = []elasticsearch.NodeAllocation{
+			{Name: "tigera-secure-es--0", Shards: 4, DiskIndices: 2 << 30, DiskTotal: 10 << 30},
+			{Name: "tigera-secure-es--1", Shards: 4, DiskIndices: 2 << 30, DiskTotal: 10 << 30},
+			{Name: "tigera-secure-es--2", Shards: 4, DiskIndices: 2 << 30, DiskTotal: 10 << 30},
+		}
+		current := cluster(nodeSpec("", 3, true))
+		desired := cluster(nodeSpec("master", 3, false), nodeSpec("data", 3, true))
+
+		Expect(departingNodes(current, desired)).To(HaveLen(3))
+		Expect(checkScaleDown(ctx, es, desired, 1)).To(Succeed())
+
+		By("still refusing dedicated data nodes that cannot take the data")
+		desired = cluster(nodeSpec("master", 3, false), nodeSpec("data", 2, true))
+		es.nodes[0].DiskIndices = 14 << 30
+		Expect(checkScaleDown(ctx, es, desired, 1)).NotTo(Succeed())
+	})
+
+	It("should allow turning on zone awareness", func() {
+		current := cluster(nodeSpec("data", 3, true))
+		desired := cluster(nodeSpec("data-z0", 2, true), nodeSpec("data-z1", 1, true))
+
+		Expect(departingNodes(current, desired)).To(HaveLen(3))
+		Expect(checkScaleDown(ctx, es, desired, 1)).To(Succeed())
+	})
+
+	It("should change the replicas of the existing indices", func() {
+		es.replicas = map[string]int{"tigera_secure_ee_flows.cluster.000001": 0, "tigera_secure_ee_dns.cluster.000001": 1}
+		Expect(migrateReplicas(ctx, es, 1)).To(Succeed())
+		Expect(es.setReplicas).NotTo(BeNil())
+		Expect(*es.setReplicas).To(Equal(1))
+
+		By("leaving indices that have the replicas already")
+		es.setReplicas = nil
+		es.replicas = map[string]int{"tigera_secure_ee_flows.cluster.000001": 1}
+		Expect(migrateReplicas(ctx, es, 1)).To(Succeed())
+		Expect(es.setReplicas).To(BeNil())
+	})
+
+	It("should only change the replicas once the scale down is accepted", func() {
+		es.replicas = map[string]int{"tigera_secure_ee_flows.cluster.000001": 2}
+		desired := cluster(nodeSpec("data", 2, true))
+
+		accepted, err := prepareScaleDown(ctx, es, desired, 2)
+		Expect(accepted).To(BeFalse())
+		Expect(err).To(HaveOccurred())
+		Expect(es.setReplicas).To(BeNil())
+
+		accepted, err = prepareScaleDown(ctx, es, desired, 1)
+		Expect(accepted).To(BeTrue())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(es.setReplicas).NotTo(BeNil())
+		Expect(*es.setReplicas).To(Equal(1))
+	})
+
+	It("should describe the shards that are still moving", func() {
+		Expect(dataMigrationMessage(elasticsearch.ClusterHealth{Status: "green"})).To(BeEmpty())
+		Expect(dataMigrationMessage(elasticsearch.ClusterHealth{Status: "yellow", RelocatingShards: 1, UnassignedShards: 2})).To(
+			Equal("Elasticsearch is yellow with 1 relocating, 0 initializing and 2 unassigned shards"))
+	})
+})
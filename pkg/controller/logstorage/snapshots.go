@@ -0,0 +1,178 @@
+// Copyright (c) 2020 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logstorage
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	operatorv1 "github.com/tigera/operator/pkg/apis/operator/v1"
+	"github.com/tigera/operator/pkg/elasticsearch"
+	"github.com/tigera/operator/pkg/render"
+
+	batch "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// snapshotClient is the part of the Elasticsearch client that restores are run with.
+type snapshotClient interface {
+	LatestSnapshot(ctx context.Context, repo string) (string, error)
+	Restore(ctx context.Context, repo, snapshot string, req elasticsearch.RestoreRequest) error
+	ActiveSnapshotRecoveries(ctx context.Context) (int, error)
+}
+
+// newElasticsearchClient returns a client that calls Elasticsearch as the elastic superuser.
+func newElasticsearchClient(ctx context.Context, cli client.Client) (*elasticsearch.Client, error) {
+	userSecret := &corev1.Secret{}
+	if err := cli.Get(ctx, types.NamespacedName{Name: render.ElasticsearchElasticUserSecret, Namespace: render.ElasticsearchNamespace}, userSecret); err != nil {
+		return nil, err
+	}
+	certSecret := &corev1.Secret{}
+	if err := cli.Get(ctx, types.NamespacedName{Name: render.ElasticsearchPublicCertSecret, Namespace: render.ElasticsearchNamespace}, certSecret); err != nil {
+		return nil, err
+	}
+	return elasticsearch.NewClient(render.ElasticsearchHTTPSEndpoint, "elastic", string(userSecret.Data["elastic"]), certSecret.Data["tls.crt"])
+}
+
+// getSnapshotCredentials returns the object store credentials of an S3 snapshot repository, or nil for other
+// repositories.
+func (r *ReconcileLogStorage) getSnapshotCredentials(ctx context.Context, snapshots *operatorv1.Snapshots) (*corev1.Secret, error) {
+	if snapshots.Repository.Type != operatorv1.SnapshotRepositoryS3 {
+		return nil, nil
+	}
+	secret := &corev1.Secret{}
+	key := types.NamespacedName{Name: snapshots.Repository.S3.CredentialsSecretName, Namespace: render.OperatorNamespace()}
+	if err := r.client.Get(ctx, key, secret); err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
+// removeSnapshotCronJob stops taking snapshots once they are no longer configured. The snapshot volume is kept,
+// so that the snapshots on it are not lost.
+func (r *ReconcileLogStorage) removeSnapshotCronJob(ctx context.Context) error {
+	cj := &batch.CronJob{ObjectMeta: metav1.ObjectMeta{Name: render.ElasticsearchSnapshotCronJobName, Namespace: render.ElasticsearchNamespace}}
+	if err := r.client.Delete(ctx, cj, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// reconcileRestores runs the LogStorageRestores one at a time, in the order they were created. It returns true
+// while a restore is running, so that its progress is checked again.
+func (r *ReconcileLogStorage) reconcileRestores(ctx context.Context, es snapshotClient) (bool, error) {
+	restores := operatorv1.LogStorageRestoreList{}
+	if err := r.client.List(ctx, &restores); err != nil {
+		return false, err
+	}
+	items := restores.Items
+	sort.Slice(items, func(i, j int) bool {
+		ti, tj := items[i].CreationTimestamp, items[j].CreationTimestamp
+		if !ti.Equal(&tj) {
+			return ti.Before(&tj)
+		}
+		return items[i].Name < items[j].Name
+	})
+
+	var pending []*operatorv1.LogStorageRestore
+	for i := range items {
+		restore := &items[i]
+		switch restore.Status.State {
+		case operatorv1.LogStorageRestoreRunning:
+			// Elasticsearch accepts the restore right away and then recovers the shards from the snapshot.
+			active, err := es.ActiveSnapshotRecoveries(ctx)
+			if err != nil {
+				return false, err
+			}
+			if active > 0 {
+				return true, nil
+			}
+			if err := r.finishRestore(ctx, restore, operatorv1.LogStorageRestoreCompleted, ""); err != nil {
+				return false, err
+			}
+		case "", operatorv1.LogStorageRestorePending:
+			pending = append(pending, restore)
+		}
+	}
+	if len(pending) == 0 {
+		return false, nil
+	}
+
+	for _, restore := range pending[1:] {
+		if restore.Status.State == "" {
+			restore.Status.State = operatorv1.LogStorageRestorePending
+			if err := r.client.Status().Update(ctx, restore); err != nil {
+				return false, err
+			}
+		}
+	}
+	return r.startRestore(ctx, es, pending[0])
+}
+
+// startRestore starts restoring the indices of the restore. Requests that Elasticsearch rejects fail the restore,
+// any other error is returned so that the restore is retried.
+func (r *ReconcileLogStorage) startRestore(ctx context.Context, es snapshotClient, restore *operatorv1.LogStorageRestore) (bool, error) {
+	snapshot := restore.Spec.Snapshot
+	if snapshot == "" {
+		var err error
+		if snapshot, err = es.LatestSnapshot(ctx, render.ElasticsearchSnapshotRepository); err != nil {
+			if !isRejected(err) {
+				return false, err
+			}
+			return false, r.finishRestore(ctx, restore, operatorv1.LogStorageRestoreFailed, err.Error())
+		}
+	}
+
+	log.Info("Restoring indices from snapshot", "restore", restore.Name, "snapshot", snapshot)
+	err := es.Restore(ctx, render.ElasticsearchSnapshotRepository, snapshot, elasticsearch.RestoreRequest{
+		Indices:           strings.Join(restore.Spec.Indices, ","),
+		RenamePattern:     restore.Spec.RenamePattern,
+		RenameReplacement: restore.Spec.RenameReplacement,
+	})
+	restore.Status.Snapshot = snapshot
+	if err != nil {
+		if !isRejected(err) {
+			return false, err
+		}
+		return false, r.finishRestore(ctx, restore, operatorv1.LogStorageRestoreFailed, err.Error())
+	}
+
+	now := metav1.Now()
+	restore.Status.State = operatorv1.LogStorageRestoreRunning
+	restore.Status.StartTime = &now
+	return true, r.client.Status().Update(ctx, restore)
+}
+
+func (r *ReconcileLogStorage) finishRestore(ctx context.Context, restore *operatorv1.LogStorageRestore, state, message string) error {
+	now := metav1.Now()
+	restore.Status.State = state
+	restore.Status.Message = message
+	restore.Status.CompletionTime = &now
+	if restore.Status.StartTime == nil {
+		restore.Status.StartTime = &now
+	}
+	return r.client.Status().Update(ctx, restore)
+}
+
+// isRejected returns true if Elasticsearch rejected the request, rather than the request failing to reach it.
+func isRejected(err error) bool {
+	_, ok := err.(*elasticsearch.Error)
+	return ok || err == elasticsearch.ErrNoSnapshot
+}
@@ -0,0 +1,141 @@
+// Copyright (c) 2020 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logstorage
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/tigera/operator/pkg/apis"
+	operatorv1 "github.com/tigera/operator/pkg/apis/operator/v1"
+	"github.com/tigera/operator/pkg/controller/status"
+	"github.com/tigera/operator/pkg/elasticsearch"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// fakeSnapshotClient stands in for Elasticsearch when running restores.
+type fakeSnapshotClient struct {
+	latest     string
+	restoreErr error
+	active     int
+	restored   []elasticsearch.RestoreRequest
+}
+
+func (f *fakeSnapshotClient) LatestSnapshot(ctx context.Context, repo string) (string, error) {
+	if f.latest == "" {
+		return "", elasticsearch.ErrNoSnapshot
+	}
+	return f.latest, nil
+}
+
+func (f *fakeSnapshotClient) Restore(ctx context.Context, repo, snapshot string, req elasticsearch.RestoreRequest) error {
+	if f.restoreErr != nil {
+		return f.restoreErr
+	}
+	f.restored = append(f.restored, req)
+	return nil
+}
+
+func (f *fakeSnapshotClient) ActiveSnapshotRecoveries(ctx context.Context) (int, error) {
+	return f.active, nil
+}
+
+var _ = Describe("LogStorage restores", func() {
+	var cli client.Client
+	var r *ReconcileLogStorage
+	var es *fakeSnapshotClient
+	ctx := context.Background()
+
+	createRestore := func(name string, created time.Time, indices ...string) {
+		Expect(cli.Create(ctx, &operatorv1.LogStorageRestore{
+			ObjectMeta: metav1.ObjectMeta{Name: name, CreationTimestamp: metav1.NewTime(created)},
+			Spec:       operatorv1.LogStorageRestoreSpec{Indices: indices},
+		})).To(Succeed())
+	}
+	getRestore := func(name string) *operatorv1.LogStorageRestore {
+		restore := &operatorv1.LogStorageRestore{}
+		Expect(cli.Get(ctx, client.ObjectKey{Name: name}, restore)).To(Succeed())
+		return restore
+	}
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(apis.AddToScheme(scheme)).To(Succeed())
+		cli = fake.NewFakeClientWithScheme(scheme)
+		r = &ReconcileLogStorage{client: cli, scheme: scheme, status: status.New(cli, "log-storage")}
+		es = &fakeSnapshotClient{latest: "snapshot-2020.01.02-01.00"}
+	})
+
+	It("should run restores one at a time in the order they were created", func() {
+		now := time.Now()
+		createRestore("second", now, "tigera_secure_ee_flows*")
+		createRestore("first", now.Add(-time.Minute), "tigera_secure_ee_audit_*", "tigera_secure_ee_snapshots*")
+
+		es.active = 2
+		running, err := r.reconcileRestores(ctx, es)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(running).To(BeTrue())
+		Expect(es.restored).To(Equal([]elasticsearch.RestoreRequest{{Indices: "tigera_secure_ee_audit_*,tigera_secure_ee_snapshots*"}}))
+		first := getRestore("first")
+		Expect(first.Status.State).To(Equal(operatorv1.LogStorageRestoreRunning))
+		Expect(first.Status.Snapshot).To(Equal("snapshot-2020.01.02-01.00"))
+		Expect(first.Status.StartTime).NotTo(BeNil())
+		Expect(getRestore("second").Status.State).To(Equal(operatorv1.LogStorageRestorePending))
+
+		By("waiting while shards are recovered from the snapshot")
+		running, err = r.reconcileRestores(ctx, es)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(running).To(BeTrue())
+		Expect(es.restored).To(HaveLen(1))
+
+		By("starting the next restore once the first one completed")
+		es.active = 0
+		running, err = r.reconcileRestores(ctx, es)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(running).To(BeTrue())
+		Expect(getRestore("first").Status.State).To(Equal(operatorv1.LogStorageRestoreCompleted))
+		Expect(getRestore("first").Status.CompletionTime).NotTo(BeNil())
+		Expect(getRestore("second").Status.State).To(Equal(operatorv1.LogStorageRestoreRunning))
+		Expect(es.restored).To(HaveLen(2))
+	})
+
+	It("should fail restores that Elasticsearch rejects", func() {
+		createRestore("restore", time.Now(), "tigera_secure_ee_flows*")
+		es.restoreErr = &elasticsearch.Error{StatusCode: 500, Body: "cannot restore index because an open index with same name already exists"}
+
+		running, err := r.reconcileRestores(ctx, es)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(running).To(BeFalse())
+		restore := getRestore("restore")
+		Expect(restore.Status.State).To(Equal(operatorv1.LogStorageRestoreFailed))
+		Expect(restore.Status.Message).To(ContainSubstring("open index with same name"))
+	})
+
+	It("should fail restores when there is no snapshot to restore from", func() {
+		createRestore("restore", time.Now(), "tigera_secure_ee_flows*")
+		es.latest = ""
+
+		_, err := r.reconcileRestores(ctx, es)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(getRestore("restore").Status.State).To(Equal(operatorv1.LogStorageRestoreFailed))
+	})
+})
@@ -5,6 +5,7 @@ package logstorage
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	cmneckalpha1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1alpha1"
@@ -48,6 +49,46 @@ func (r *ReconcileLogStorage) reconcileUnmanaged(ctx context.Context, network *o
 		return r.finalizeDeletion(ctx, ls)
 	}
 
+	if err := validateRetention(ls.Spec.Retention); err != nil {
+		r.setDegraded(ctx, reqLogger, ls, "Invalid LogStorage retention", err)
+		return reconcile.Result{}, nil
+	}
+
+	if err := validateIndexLifecycle(ls.Spec.IndexLifecycle); err != nil {
+		r.setDegraded(ctx, reqLogger, ls, "Invalid LogStorage index lifecycle configuration", err)
+		return reconcile.Result{}, nil
+	}
+
+	if err := validateUsers(ls.Spec.Users); err != nil {
+		r.setDegraded(ctx, reqLogger, ls, "Invalid LogStorage users configuration", err)
+		return reconcile.Result{}, nil
+	}
+
+	if ls.Spec.External != nil {
+		return r.reconcileExternal(ctx, network, ls, reqLogger)
+	}
+	ls.Status.External = nil
+
+	if r.unsupportedECK != "" {
+		r.setDegraded(ctx, reqLogger, ls, "Elasticsearch cannot be managed through the installed ECK APIs", fmt.Errorf("%s", r.unsupportedECK))
+		return reconcile.Result{}, nil
+	}
+
+	if err := validateNodes(ls.Spec.Nodes); err != nil {
+		r.setDegraded(ctx, reqLogger, ls, "Invalid LogStorage node configuration", err)
+		return reconcile.Result{}, nil
+	}
+
+	if err := validateReplicas(ls); err != nil {
+		r.setDegraded(ctx, reqLogger, ls, "Invalid LogStorage index replicas", err)
+		return reconcile.Result{}, nil
+	}
+
+	if err := validateSnapshots(ls.Spec.Snapshots); err != nil {
+		r.setDegraded(ctx, reqLogger, ls, "Invalid LogStorage snapshot configuration", err)
+		return reconcile.Result{}, nil
+	}
+
 	if svc, err := r.getElasticsearchService(ctx); err == nil {
 		// if the Elasticsearch service is an ExternalName service, then this was previous a "Managed" cluster and
 		// the service needs to be removed before creating the Elasticsearch resource
@@ -78,8 +119,9 @@ func (r *ReconcileLogStorage) reconcileUnmanaged(ctx context.Context, network *o
 		return reconcile.Result{}, err
 	}
 
-	if err := r.client.Get(ctx, client.ObjectKey{Name: render.ElasticsearchStorageClass}, &storagev1.StorageClass{}); err != nil {
-		r.setDegraded(ctx, reqLogger, ls, fmt.Sprintf("Couldn't find storage class %s, this must be provided", render.ElasticsearchStorageClass), err)
+	storageClass := &storagev1.StorageClass{}
+	if err := r.client.Get(ctx, client.ObjectKey{Name: ls.Spec.StorageClassName}, storageClass); err != nil {
+		r.setDegraded(ctx, reqLogger, ls, fmt.Sprintf("Couldn't find storage class %s, this must be provided", ls.Spec.StorageClassName), err)
 		return reconcile.Result{RequeueAfter: 10 * time.Second}, nil
 	}
 
@@ -116,6 +158,9 @@ func (r *ReconcileLogStorage) reconcileUnmanaged(ctx context.Context, network *o
 	}
 
 	esClusterConfig := render.NewElasticsearchClusterConfig("cluster", ls.Replicas(), defaultElasticsearchShards)
+	if !ls.KibanaEnabled() {
+		esClusterConfig.DisableKibana()
+	}
 
 	reqLogger.V(2).Info("Creating Elasticsearch components")
 	hdler := utils.NewComponentHandler(log, r.client, r.scheme, ls)
@@ -127,6 +172,7 @@ func (r *ReconcileLogStorage) reconcileUnmanaged(ctx context.Context, network *o
 		createWebhookSecret,
 		pullSecrets,
 		r.provider,
+		r.eckAPIVersion,
 		network.Spec.Registry,
 	)
 	if err != nil {
@@ -134,11 +180,80 @@ func (r *ReconcileLogStorage) reconcileUnmanaged(ctx context.Context, network *o
 		return reconcile.Result{}, err
 	}
 
+	// Check that the volumes can be resized before the new sizes are handed to ECK.
+	desiredES, err := elasticsearchFromObjects(component.Objects())
+	if err != nil {
+		r.setDegraded(ctx, reqLogger, ls, "Failed to read the rendered Elasticsearch", err)
+		return reconcile.Result{}, err
+	}
+	resizes, err := r.volumeResizes(ctx, desiredES)
+	if err != nil {
+		r.setDegraded(ctx, reqLogger, ls, "Failed to read the Elasticsearch volumes", err)
+		return reconcile.Result{}, err
+	}
+	if err := checkVolumeResizes(resizes, storageClass); err != nil {
+		r.setDegraded(ctx, reqLogger, ls, "Cannot resize the Elasticsearch volumes", err)
+		return reconcile.Result{}, nil
+	}
+
+	// ECK moves the shards off the nodes that are removed before it deletes them, so a scale down is only checked
+	// for data loss before it is handed to ECK.
+	currentES, err := r.getElasticsearch(ctx)
+	if err != nil && !errors.IsNotFound(err) {
+		r.setDegraded(ctx, reqLogger, ls, "Failed to read Elasticsearch", err)
+		return reconcile.Result{}, err
+	}
+	if err == nil {
+		if departing := departingNodes(currentES, desiredES); len(departing) > 0 {
+			es, err := newElasticsearchClient(ctx, r.client)
+			if err != nil {
+				r.setDegraded(ctx, reqLogger, ls, "Failed to create the Elasticsearch client", err)
+				return reconcile.Result{}, err
+			}
+			if accepted, err := prepareScaleDown(ctx, es, desiredES, ls.Replicas()); !accepted {
+				r.setDegraded(ctx, reqLogger, ls, "Cannot remove Elasticsearch nodes", err)
+				return reconcile.Result{RequeueAfter: time.Minute}, nil
+			} else if err != nil {
+				r.setDegraded(ctx, reqLogger, ls, "Failed to change the replicas of the log indices", err)
+				return reconcile.Result{RequeueAfter: 30 * time.Second}, nil
+			}
+		}
+	}
+
 	if err := hdler.CreateOrUpdate(ctx, component, r.status); err != nil {
 		r.setDegraded(ctx, reqLogger, ls, "Error creating / updating resource", err)
 		return reconcile.Result{}, err
 	}
 
+	if err := r.expandVolumes(ctx, resizes); err != nil {
+		r.setDegraded(ctx, reqLogger, ls, "Failed to expand the Elasticsearch volumes", err)
+		return reconcile.Result{}, err
+	}
+
+	if !ls.KibanaEnabled() {
+		if err := r.removeKibana(ctx); err != nil {
+			r.setDegraded(ctx, reqLogger, ls, "Failed to remove Kibana", err)
+			return reconcile.Result{}, err
+		}
+		ls.Status.Kibana = &operatorv1.KibanaStatus{Enabled: false}
+	}
+
+	// The snapshot volume must exist for the Elasticsearch nodes to start.
+	if snapshots := ls.Spec.Snapshots; snapshots != nil {
+		s3Credentials, err := r.getSnapshotCredentials(ctx, snapshots)
+		if err != nil {
+			r.setDegraded(ctx, reqLogger, ls, "Failed to read the snapshot repository credentials", err)
+			return reconcile.Result{}, err
+		}
+		if err := hdler.CreateOrUpdate(ctx, render.ElasticsearchSnapshots(ls, s3Credentials, pullSecrets, network.Spec.Registry), r.status); err != nil {
+			r.setDegraded(ctx, reqLogger, ls, "Error creating / updating the snapshot resources", err)
+			return reconcile.Result{}, err
+		}
+	} else if err := r.removeSnapshotCronJob(ctx); err != nil {
+		r.setDegraded(ctx, reqLogger, ls, "Failed to remove the snapshot CronJob", err)
+		return reconcile.Result{}, err
+	}
+
 	reqLogger.V(2).Info("Checking if Elasticsearch is operational")
 	if isReady, err := r.isElasticsearchReady(ctx); err != nil {
 		r.setDegraded(ctx, reqLogger, ls, "Error figuring out if Elasticsearch is operational", err)
@@ -148,13 +263,15 @@ func (r *ReconcileLogStorage) reconcileUnmanaged(ctx context.Context, network *o
 		return reconcile.Result{RequeueAfter: 5 * time.Second}, nil
 	}
 
-	reqLogger.V(2).Info("Checking if Kibana is operational")
-	if isReady, err := r.isKibanaReady(ctx); err != nil {
-		r.setDegraded(ctx, reqLogger, ls, "Failed to figure out if Kibana is operational", err)
-		return reconcile.Result{}, err
-	} else if !isReady {
-		r.setDegraded(ctx, reqLogger, ls, "Waiting for Kibana to be operational", nil)
-		return reconcile.Result{RequeueAfter: 5 * time.Second}, nil
+	if ls.KibanaEnabled() {
+		reqLogger.V(2).Info("Checking if Kibana is operational")
+		if isReady, err := r.isKibanaReady(ctx, ls); err != nil {
+			r.setDegraded(ctx, reqLogger, ls, "Failed to figure out if Kibana is operational", err)
+			return reconcile.Result{}, err
+		} else if !isReady {
+			r.setDegraded(ctx, reqLogger, ls, "Waiting for Kibana to be operational", nil)
+			return reconcile.Result{RequeueAfter: 5 * time.Second}, nil
+		}
 	}
 
 	reqLogger.V(2).Info("Elasticsearch and Kibana are operational")
@@ -164,35 +281,115 @@ func (r *ReconcileLogStorage) reconcileUnmanaged(ctx context.Context, network *o
 		return reconcile.Result{}, err
 	}
 
-	kibanaPublicCertSecret := &corev1.Secret{}
-	if err := r.client.Get(ctx, types.NamespacedName{Name: render.KibanaPublicCertSecret, Namespace: render.KibanaNamespace}, kibanaPublicCertSecret); err != nil {
-		r.setDegraded(ctx, reqLogger, ls, "Failed to read Kibana public cert secret", err)
+	var kibanaPublicCertSecret *corev1.Secret
+	if ls.KibanaEnabled() {
+		kibanaPublicCertSecret = &corev1.Secret{}
+		if err := r.client.Get(ctx, types.NamespacedName{Name: render.KibanaPublicCertSecret, Namespace: render.KibanaNamespace}, kibanaPublicCertSecret); err != nil {
+			r.setDegraded(ctx, reqLogger, ls, "Failed to read Kibana public cert secret", err)
+			return reconcile.Result{}, err
+		}
+	}
+
+	es, err := newElasticsearchClient(ctx, r.client)
+	if err != nil {
+		r.setDegraded(ctx, reqLogger, ls, "Failed to create the Elasticsearch client", err)
 		return reconcile.Result{}, err
 	}
 
-	if err := hdler.CreateOrUpdate(ctx, render.ElasticsearchSecrets(esPublicCertSecret, kibanaPublicCertSecret), r.status); err != nil {
+	userSecrets, err := r.reconcileUsers(ctx, es, passwordRotation(ls))
+	if err != nil {
+		r.setDegraded(ctx, reqLogger, ls, "Failed to create the Elasticsearch users of the components", err)
+		return reconcile.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+
+	if err := hdler.CreateOrUpdate(ctx, render.ElasticsearchSecrets(esPublicCertSecret, kibanaPublicCertSecret, userSecrets), r.status); err != nil {
 		r.setDegraded(ctx, reqLogger, ls, "Error creating / update resource", err)
 		return reconcile.Result{}, err
 	}
 
-	esSecrets, err := utils.ElasticsearchSecrets(context.Background(), []string{render.ElasticsearchCuratorUserSecret}, r.client)
+	// Existing indices keep their replicas unless they are changed along with the LogStorage.
+	if err := migrateReplicas(ctx, es, ls.Replicas()); err != nil {
+		r.setDegraded(ctx, reqLogger, ls, "Failed to change the replicas of the log indices", err)
+		return reconcile.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+	draining, err := drainingNodes(ctx, es, desiredES)
 	if err != nil {
-		if errors.IsNotFound(err) {
-			log.Info("Elasticsearch secrets are not available yet, waiting until they become available")
-			r.status.SetDegraded("Elasticsearch secrets are not available yet, waiting until they become available", err.Error())
-			return reconcile.Result{}, nil
-		}
-		r.status.SetDegraded("Failed to get Elasticsearch credentials", err.Error())
+		r.setDegraded(ctx, reqLogger, ls, "Failed to read the shards of the Elasticsearch nodes", err)
+		return reconcile.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+	health, err := es.ClusterHealth(ctx)
+	if err != nil {
+		r.setDegraded(ctx, reqLogger, ls, "Failed to read the Elasticsearch cluster health", err)
+		return reconcile.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+	migrating := true
+	if len(draining) > 0 {
+		r.status.SetProgressing("Draining Elasticsearch nodes", fmt.Sprintf("Moving shards off Elasticsearch nodes %s before they are removed", strings.Join(draining, ", ")))
+	} else if msg := dataMigrationMessage(health); msg != "" {
+		r.status.SetProgressing("Migrating Elasticsearch data", msg)
+	} else {
+		r.status.ClearProgressing()
+		migrating = false
+	}
+
+	// The logs of managed clusters have index lifecycles of their own.
+	managedClusters, err := r.listManagedClusters(ctx, network)
+	if err != nil {
+		r.status.SetDegraded("Failed to list managed clusters", err.Error())
 		return reconcile.Result{}, err
 	}
+	if err := validateManagedClusters(managedClusters); err != nil {
+		r.setDegraded(ctx, reqLogger, ls, "Invalid managed cluster log storage settings", err)
+		return reconcile.Result{}, nil
+	}
 
-	curatorComponent := render.ElasticCurator(*ls, esSecrets, pullSecrets, network.Spec.Registry, render.DefaultElasticsearchClusterName)
-	if err := hdler.CreateOrUpdate(ctx, curatorComponent, r.status); err != nil {
-		r.status.SetDegraded("Error creating / updating resource", err.Error())
+	if err := r.removeCurators(ctx); err != nil {
+		r.setDegraded(ctx, reqLogger, ls, "Failed to remove the curators", err)
 		return reconcile.Result{}, err
 	}
 
-	r.status.SetCronJobs([]types.NamespacedName{{Name: render.EsCuratorName, Namespace: render.ElasticsearchNamespace}})
+	var cronJobs []types.NamespacedName
+	if ls.Spec.Snapshots != nil {
+		cronJobs = append(cronJobs, types.NamespacedName{Name: render.ElasticsearchSnapshotCronJobName, Namespace: render.ElasticsearchNamespace})
+	}
+	r.status.SetCronJobs(cronJobs)
+
+	restoreRunning := false
+	if ls.Spec.Snapshots != nil {
+		if err := es.PutSnapshotRepository(ctx, render.ElasticsearchSnapshotRepository, render.SnapshotRepository(ls.Spec.Snapshots)); err != nil {
+			r.setDegraded(ctx, reqLogger, ls, "Failed to register the snapshot repository", err)
+			return reconcile.Result{RequeueAfter: 30 * time.Second}, nil
+		}
+		if restoreRunning, err = r.reconcileRestores(ctx, es); err != nil {
+			r.setDegraded(ctx, reqLogger, ls, "Failed to restore from snapshot", err)
+			return reconcile.Result{}, err
+		}
+	}
+
+	if policy, err := reconcileIndices(ctx, es, ls, managedClusters); err != nil {
+		r.setDegraded(ctx, reqLogger, ls, "Failed to manage the log indices", err)
+		return reconcile.Result{}, err
+	} else if policy != nil {
+		r.setDegraded(ctx, reqLogger, ls, fmt.Sprintf("Index lifecycle policy %s is not applied: %s", policy.Name, policy.Message), nil)
+		return reconcile.Result{RequeueAfter: time.Minute}, nil
+	}
+
+	if ls.KibanaEnabled() && len(ls.Spec.Kibana.SavedObjectConfigMaps) > 0 {
+		kb, err := newKibanaClient(ctx, r.client)
+		if err != nil {
+			r.setDegraded(ctx, reqLogger, ls, "Failed to create the Kibana client", err)
+			return reconcile.Result{}, err
+		}
+		if savedObjects, err := r.reconcileSavedObjects(ctx, kb, ls); err != nil {
+			r.setDegraded(ctx, reqLogger, ls, "Failed to import the Kibana saved objects", err)
+			return reconcile.Result{}, err
+		} else if savedObjects != nil {
+			r.setDegraded(ctx, reqLogger, ls, fmt.Sprintf("Kibana saved objects of ConfigMap %s are not imported: %s", savedObjects.ConfigMapName, savedObjects.Message), nil)
+			return reconcile.Result{RequeueAfter: time.Minute}, nil
+		}
+	} else if ls.Status.Kibana != nil {
+		ls.Status.Kibana.SavedObjects = nil
+	}
 
 	// Clear the degraded bit if we've reached this far.
 	r.status.ClearDegraded()
@@ -201,12 +398,37 @@ func (r *ReconcileLogStorage) reconcileUnmanaged(ctx context.Context, network *o
 		return reconcile.Result{}, err
 	}
 
-	return reconcile.Result{}, nil
+	if restoreRunning {
+		return reconcile.Result{RequeueAfter: 10 * time.Second}, nil
+	}
+	if migrating {
+		return reconcile.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+	// Logs keep growing, so their storage is checked against its limits periodically.
+	return reconcile.Result{RequeueAfter: storageCheckInterval}, nil
 }
 
+// getElasticsearch returns the Elasticsearch as a v1alpha1 Elasticsearch, whichever version of the ECK API it is
+// managed through.
 func (r *ReconcileLogStorage) getElasticsearch(ctx context.Context) (*esalpha1.Elasticsearch, error) {
-	es := esalpha1.Elasticsearch{}
-	return &es, r.client.Get(ctx, client.ObjectKey{Name: render.ElasticsearchName, Namespace: render.ElasticsearchNamespace}, &es)
+	es, err := r.getElasticsearchObject(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return elasticsearchV1alpha1(es)
+}
+
+// listManagedClusters returns the managed clusters of a management cluster, whose logs are stored alongside those of
+// the management cluster.
+func (r *ReconcileLogStorage) listManagedClusters(ctx context.Context, network *operatorv1.Installation) ([]operatorv1.ManagedCluster, error) {
+	if network.Spec.ClusterManagementType != operatorv1.ClusterManagementTypeManagement {
+		return nil, nil
+	}
+	clusters := operatorv1.ManagedClusterList{}
+	if err := r.client.List(ctx, &clusters); err != nil {
+		return nil, err
+	}
+	return clusters.Items, nil
 }
 
 func (r *ReconcileLogStorage) getElasticsearchService(ctx context.Context) (*corev1.Service, error) {
@@ -214,9 +436,13 @@ func (r *ReconcileLogStorage) getElasticsearchService(ctx context.Context) (*cor
 	return &svc, r.client.Get(ctx, client.ObjectKey{Name: render.ElasticsearchServiceName, Namespace: render.ElasticsearchNamespace}, &svc)
 }
 
+// getKibana returns the Kibana as a v1alpha1 Kibana, whichever version of the ECK API it is managed through.
 func (r *ReconcileLogStorage) getKibana(ctx context.Context) (*kibanaalpha1.Kibana, error) {
-	kb := kibanaalpha1.Kibana{}
-	return &kb, r.client.Get(ctx, client.ObjectKey{Name: render.KibanaName, Namespace: render.KibanaNamespace}, &kb)
+	kb, err := r.getKibanaObject(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return kibanaV1alpha1(kb)
 }
 
 func (r *ReconcileLogStorage) isElasticsearchReady(ctx context.Context) (bool, error) {
@@ -229,38 +455,42 @@ func (r *ReconcileLogStorage) isElasticsearchReady(ctx context.Context) (bool, e
 	return false, nil
 }
 
-func (r *ReconcileLogStorage) isKibanaReady(ctx context.Context) (bool, error) {
-	if kb, err := r.getKibana(ctx); err != nil {
+// isKibanaReady reports the health of Kibana in the status of the LogStorage and returns true once Kibana is
+// connected to Elasticsearch.
+func (r *ReconcileLogStorage) isKibanaReady(ctx context.Context, ls *operatorv1.LogStorage) (bool, error) {
+	kb, err := r.getKibana(ctx)
+	if err != nil {
 		return false, err
-	} else if kb.Status.AssociationStatus == cmneckalpha1.AssociationEstablished {
-		return true, nil
 	}
-
-	return false, nil
+	ls.Status.Kibana = kibanaStatus(kb, ls.Status.Kibana)
+	return kb.Status.AssociationStatus == cmneckalpha1.AssociationEstablished, nil
 }
 
 // finalizeDeletion makes sure that both Kibana and Elasticsearch are deleted before removing the finalizers on the LogStorage
 // resource. This needs to happen because the eck operator will be deleted when the LogStorage resource is deleted, but
 // the eck operator is needed to delete Elasticsearch and Kibana
 func (r *ReconcileLogStorage) finalizeDeletion(ctx context.Context, ls *operatorv1.LogStorage) (reconcile.Result, error) {
-	// remove Elasticsearch
-	if es, err := r.getElasticsearch(ctx); err == nil {
-		if err := r.client.Delete(ctx, es); err != nil {
-			r.status.SetDegraded("Failed to delete Elasticsearch", err.Error())
+	// Without the ECK APIs it manages them through, the operator cannot have created Elasticsearch and Kibana.
+	if r.unsupportedECK == "" {
+		// remove Elasticsearch
+		if es, err := r.getElasticsearchObject(ctx); err == nil {
+			if err := r.client.Delete(ctx, es); err != nil {
+				r.status.SetDegraded("Failed to delete Elasticsearch", err.Error())
+				return reconcile.Result{}, err
+			}
+		} else if !errors.IsNotFound(err) {
 			return reconcile.Result{}, err
 		}
-	} else if !errors.IsNotFound(err) {
-		return reconcile.Result{}, err
-	}
 
-	// remove kibana
-	if kb, err := r.getKibana(ctx); err == nil {
-		if err := r.client.Delete(ctx, kb); err != nil {
-			r.status.SetDegraded("Failed to delete kibana", err.Error())
+		// remove kibana
+		if kb, err := r.getKibanaObject(ctx); err == nil {
+			if err := r.client.Delete(ctx, kb); err != nil {
+				r.status.SetDegraded("Failed to delete kibana", err.Error())
+				return reconcile.Result{}, err
+			}
+		} else if !errors.IsNotFound(err) {
 			return reconcile.Result{}, err
 		}
-	} else if !errors.IsNotFound(err) {
-		return reconcile.Result{}, err
 	}
 
 	// remove the finalizer now that Elasticsearch and Kibana have been deleted
@@ -0,0 +1,258 @@
+// Copyright (c) 2020 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logstorage
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"time"
+
+	operatorv1 "github.com/tigera/operator/pkg/apis/operator/v1"
+	"github.com/tigera/operator/pkg/controller/utils"
+	"github.com/tigera/operator/pkg/elasticsearch"
+	"github.com/tigera/operator/pkg/render"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	passwordLength = 24
+
+	// passwordRotatedAnnotation is set on the Secret of a user to the time its password was set.
+	passwordRotatedAnnotation = "operator.tigera.io/password-rotated-at"
+)
+
+// userClient is the part of the Elasticsearch client that the users of the components are managed with.
+type userClient interface {
+	PutRole(ctx context.Context, name string, role elasticsearch.Role) error
+	PutUser(ctx context.Context, username, password string, roles []string) error
+}
+
+// componentUser is the Elasticsearch user a component accesses Elasticsearch as. The user is granted a role of the
+// same name, with only the privileges the component needs.
+type componentUser struct {
+	// secret is the name of the Secret in the operator namespace holding the credentials of the user.
+	secret string
+	role   elasticsearch.Role
+}
+
+// username returns the name of the user, which is the name of its Secret without the common suffix.
+func (u componentUser) username() string {
+	return strings.TrimSuffix(u.secret, "-elasticsearch-access")
+}
+
+var (
+	readPrivileges  = []string{"read", "view_index_metadata"}
+	writePrivileges = []string{"create_index", "write", "view_index_metadata"}
+)
+
+var componentUsers = []componentUser{
+	{
+		secret: render.ElasticsearchLogCollectorUserSecret,
+		role: elasticsearch.Role{
+			Cluster: []string{"monitor", "manage_index_templates"},
+			Indices: []elasticsearch.IndexPrivileges{{
+				Names:      []string{"tigera_secure_ee_flows.*", "tigera_secure_ee_dns.*", "tigera_secure_ee_audit_*", "tigera_secure_ee_l7.*", "tigera_secure_ee_bgp.*"},
+				Privileges: writePrivileges,
+			}},
+		},
+	},
+	{
+		secret: render.ElasticsearchEksLogForwarderUserSecret,
+		role: elasticsearch.Role{
+			Cluster: []string{"monitor", "manage_index_templates"},
+			Indices: []elasticsearch.IndexPrivileges{{Names: []string{"tigera_secure_ee_audit_kube.*"}, Privileges: writePrivileges}},
+		},
+	},
+	{
+		secret: render.ElasticsearchManagerUserSecret,
+		role: elasticsearch.Role{
+			Cluster: []string{"monitor"},
+			Indices: []elasticsearch.IndexPrivileges{
+				{Names: []string{"tigera_secure_ee_*"}, Privileges: readPrivileges},
+				// Events are dismissed and deleted from the manager.
+				{Names: []string{"tigera_secure_ee_events.*"}, Privileges: []string{"write"}},
+			},
+		},
+	},
+	{
+		secret: render.ElasticsearchComplianceBenchmarkerUserSecret,
+		role: elasticsearch.Role{
+			Cluster: []string{"monitor", "manage_index_templates"},
+			Indices: []elasticsearch.IndexPrivileges{{Names: []string{"tigera_secure_ee_benchmark_results.*"}, Privileges: writePrivileges}},
+		},
+	},
+	{
+		secret: render.ElasticsearchComplianceControllerUserSecret,
+		role: elasticsearch.Role{
+			Cluster: []string{"monitor"},
+			Indices: []elasticsearch.IndexPrivileges{{Names: []string{"tigera_secure_ee_compliance_reports.*"}, Privileges: readPrivileges}},
+		},
+	},
+	{
+		secret: render.ElasticsearchComplianceReporterUserSecret,
+		role: elasticsearch.Role{
+			Cluster: []string{"monitor", "manage_index_templates"},
+			Indices: []elasticsearch.IndexPrivileges{
+				{Names: []string{"tigera_secure_ee_audit_*", "tigera_secure_ee_snapshots.*", "tigera_secure_ee_benchmark_results.*", "tigera_secure_ee_flows.*"}, Privileges: readPrivileges},
+				{Names: []string{"tigera_secure_ee_compliance_reports.*"}, Privileges: writePrivileges},
+			},
+		},
+	},
+	{
+		secret: render.ElasticsearchComplianceSnapshotterUserSecret,
+		role: elasticsearch.Role{
+			Cluster: []string{"monitor", "manage_index_templates"},
+			Indices: []elasticsearch.IndexPrivileges{{Names: []string{"tigera_secure_ee_snapshots.*"}, Privileges: writePrivileges}},
+		},
+	},
+	{
+		secret: render.ElasticsearchComplianceServerUserSecret,
+		role: elasticsearch.Role{
+			Cluster: []string{"monitor"},
+			Indices: []elasticsearch.IndexPrivileges{{Names: []string{"tigera_secure_ee_compliance_reports.*", "tigera_secure_ee_benchmark_results.*"}, Privileges: readPrivileges}},
+		},
+	},
+	{
+		secret: render.ElasticsearchIntrusionDetectionUserSecret,
+		role: elasticsearch.Role{
+			Cluster: []string{"monitor", "manage_ml", "manage_index_templates"},
+			Indices: []elasticsearch.IndexPrivileges{
+				{Names: []string{"tigera_secure_ee_*"}, Privileges: readPrivileges},
+				// Threat feeds are kept in the .tigera indices.
+				{Names: []string{"tigera_secure_ee_events.*", ".tigera.*"}, Privileges: append([]string{"delete"}, writePrivileges...)},
+			},
+		},
+	},
+	{
+		secret: render.ElasticsearchIntrusionDetectionJobUserSecret,
+		role: elasticsearch.Role{
+			Cluster: []string{"monitor", "manage_ml"},
+			Indices: []elasticsearch.IndexPrivileges{{Names: []string{"tigera_secure_ee_*"}, Privileges: readPrivileges}},
+		},
+	},
+}
+
+// reconcileUsers creates the role and user of each component in Elasticsearch and returns the Secrets holding
+// their credentials. The password of a user is kept in its Secret and replaced once it is older than the rotation
+// period. A new password is written to the Secret before it is set in Elasticsearch, so that it is not lost if the
+// Secret cannot be written. The components hash the data of their Secrets into the annotations of their pods, so
+// they are restarted with a new password once it is rotated.
+func (r *ReconcileLogStorage) reconcileUsers(ctx context.Context, es userClient, rotation time.Duration) ([]*corev1.Secret, error) {
+	now := time.Now()
+	var secrets []*corev1.Secret
+	for _, u := range componentUsers {
+		username := u.username()
+		current, err := r.getUserSecret(ctx, u.secret)
+		if err != nil {
+			return nil, err
+		}
+		password, rotatedAt, err := userPassword(current, rotation, now)
+		if err != nil {
+			return nil, err
+		}
+		secret := &corev1.Secret{
+			TypeMeta: metav1.TypeMeta{Kind: "Secret", APIVersion: "v1"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        u.secret,
+				Namespace:   render.OperatorNamespace(),
+				Labels:      map[string]string{tigeraElasticsearchUserSecretLabel: "true"},
+				Annotations: map[string]string{passwordRotatedAnnotation: rotatedAt.Format(time.RFC3339)},
+			},
+			Data: map[string][]byte{
+				"username": []byte(username),
+				"password": []byte(password),
+			},
+		}
+		if err := r.storeUserSecret(ctx, current, secret); err != nil {
+			return nil, err
+		}
+
+		if err := es.PutRole(ctx, username, u.role); err != nil {
+			return nil, err
+		}
+		if err := es.PutUser(ctx, username, password, []string{username}); err != nil {
+			return nil, err
+		}
+		secrets = append(secrets, secret)
+	}
+	return secrets, nil
+}
+
+// getUserSecret returns the Secret of a user, or nil if there is none yet.
+func (r *ReconcileLogStorage) getUserSecret(ctx context.Context, name string) (*corev1.Secret, error) {
+	secret := &corev1.Secret{}
+	if err := r.client.Get(ctx, types.NamespacedName{Name: name, Namespace: render.OperatorNamespace()}, secret); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return secret, nil
+}
+
+// userPassword returns the password kept in the Secret of a user and when it was set, or a new password if there
+// is none yet or the kept one is due to be rotated. A Secret without a valid rotation time was not created by the
+// operator, its password is kept and rotated once the rotation period has passed from now.
+func userPassword(current *corev1.Secret, rotation time.Duration, now time.Time) (string, time.Time, error) {
+	if current != nil {
+		if password := current.Data["password"]; len(password) > 0 {
+			rotatedAt, err := time.Parse(time.RFC3339, current.Annotations[passwordRotatedAnnotation])
+			if err != nil {
+				return string(password), now, nil
+			}
+			if now.Before(rotatedAt.Add(rotation)) {
+				return string(password), rotatedAt, nil
+			}
+		}
+	}
+	password, err := utils.RandomPassword(passwordLength)
+	return password, now, err
+}
+
+// storeUserSecret writes the credentials and rotation time of a user to its Secret, unless they are stored already.
+func (r *ReconcileLogStorage) storeUserSecret(ctx context.Context, current, desired *corev1.Secret) error {
+	if current == nil {
+		return r.client.Create(ctx, desired.DeepCopy())
+	}
+	if bytes.Equal(current.Data["password"], desired.Data["password"]) &&
+		current.Annotations[passwordRotatedAnnotation] == desired.Annotations[passwordRotatedAnnotation] {
+		return nil
+	}
+	updated := current.DeepCopy()
+	if updated.Labels == nil {
+		updated.Labels = map[string]string{}
+	}
+	if updated.Annotations == nil {
+		updated.Annotations = map[string]string{}
+	}
+	for k, v := range desired.Labels {
+		updated.Labels[k] = v
+	}
+	for k, v := range desired.Annotations {
+		updated.Annotations[k] = v
+	}
+	updated.Data = desired.Data
+	return r.client.Update(ctx, updated)
+}
+
+// passwordRotation returns how long the passwords of the component users are kept.
+func passwordRotation(ls *operatorv1.LogStorage) time.Duration {
+	return time.Duration(*ls.Spec.Users.PasswordRotationDays) * 24 * time.Hour
+}
@@ -0,0 +1,182 @@
+// Copyright (c) 2020 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logstorage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/tigera/operator/pkg/apis"
+	"github.com/tigera/operator/pkg/controller/status"
+	"github.com/tigera/operator/pkg/elasticsearch"
+	"github.com/tigera/operator/pkg/render"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// fakeUserClient stands in for Elasticsearch when managing the users of the components.
+type fakeUserClient struct {
+	roles     map[string]elasticsearch.Role
+	passwords map[string]string
+}
+
+func (f *fakeUserClient) PutRole(ctx context.Context, name string, role elasticsearch.Role) error {
+	f.roles[name] = role
+	return nil
+}
+
+func (f *fakeUserClient) PutUser(ctx context.Context, username, password string, roles []string) error {
+	f.passwords[username] = password
+	return nil
+}
+
+// failingWriteClient fails every write, as when the operator may not update its Secrets.
+type failingWriteClient struct {
+	client.Client
+}
+
+func (f *failingWriteClient) Create(ctx context.Context, obj runtime.Object, opts ...client.CreateOption) error {
+	return fmt.Errorf("create refused")
+}
+
+func (f *failingWriteClient) Update(ctx context.Context, obj runtime.Object, opts ...client.UpdateOption) error {
+	return fmt.Errorf("update refused")
+}
+
+var _ = Describe("LogStorage component users", func() {
+	var cli client.Client
+	var r *ReconcileLogStorage
+	var es *fakeUserClient
+	ctx := context.Background()
+	rotation := 90 * 24 * time.Hour
+
+	createUserSecret := func(name, password string, rotatedAt time.Time) {
+		Expect(cli.Create(ctx, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   render.OperatorNamespace(),
+				Annotations: map[string]string{passwordRotatedAnnotation: rotatedAt.Format(time.RFC3339)},
+			},
+			Data: map[string][]byte{"username": []byte("tigera-ee-manager"), "password": []byte(password)},
+		})).To(Succeed())
+	}
+	userSecret := func(secrets []*corev1.Secret, name string) *corev1.Secret {
+		for _, s := range secrets {
+			if s.Name == name {
+				return s
+			}
+		}
+		Fail("no secret " + name)
+		return nil
+	}
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(apis.AddToScheme(scheme)).To(Succeed())
+		cli = fake.NewFakeClientWithScheme(scheme)
+		r = &ReconcileLogStorage{client: cli, scheme: scheme, status: status.New(cli, "log-storage")}
+		es = &fakeUserClient{roles: map[string]elasticsearch.Role{}, passwords: map[string]string{}}
+	})
+
+	It("should create a user with a role of its own for each component", func() {
+		secrets, err := r.reconcileUsers(ctx, es, rotation)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(secrets).To(HaveLen(len(componentUsers)))
+		Expect(es.roles).To(HaveLen(len(componentUsers)))
+
+		secret := userSecret(secrets, render.ElasticsearchManagerUserSecret)
+		Expect(secret.Labels).To(HaveKeyWithValue(tigeraElasticsearchUserSecretLabel, "true"))
+		Expect(string(secret.Data["username"])).To(Equal("tigera-ee-manager"))
+		Expect(string(secret.Data["password"])).To(HaveLen(passwordLength))
+		Expect(es.passwords).To(HaveKeyWithValue("tigera-ee-manager", string(secret.Data["password"])))
+		Expect(es.roles["tigera-ee-manager"].Indices[0].Privileges).To(Equal([]string{"read", "view_index_metadata"}))
+	})
+
+	It("should keep a password until it is due to be rotated", func() {
+		rotatedAt := time.Now().Add(-24 * time.Hour).Truncate(time.Second)
+		createUserSecret(render.ElasticsearchManagerUserSecret, "kept-password", rotatedAt)
+
+		secrets, err := r.reconcileUsers(ctx, es, rotation)
+		Expect(err).NotTo(HaveOccurred())
+		secret := userSecret(secrets, render.ElasticsearchManagerUserSecret)
+		Expect(string(secret.Data["password"])).To(Equal("kept-password"))
+		Expect(secret.Annotations).To(HaveKeyWithValue(passwordRotatedAnnotation, rotatedAt.Format(time.RFC3339)))
+		Expect(es.passwords).To(HaveKeyWithValue("tigera-ee-manager", "kept-password"))
+	})
+
+	It("should rotate a password once the rotation period has passed", func() {
+		createUserSecret(render.ElasticsearchManagerUserSecret, "old-password", time.Now().Add(-91*24*time.Hour))
+
+		secrets, err := r.reconcileUsers(ctx, es, rotation)
+		Expect(err).NotTo(HaveOccurred())
+		secret := userSecret(secrets, render.ElasticsearchManagerUserSecret)
+		Expect(string(secret.Data["password"])).NotTo(Equal("old-password"))
+		Expect(es.passwords).To(HaveKeyWithValue("tigera-ee-manager", string(secret.Data["password"])))
+
+		rotatedAt, err := time.Parse(time.RFC3339, secret.Annotations[passwordRotatedAnnotation])
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rotatedAt).To(BeTemporally("~", time.Now(), time.Minute))
+	})
+
+	It("should keep the password of a Secret the operator did not create and rotate it later", func() {
+		Expect(cli.Create(ctx, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: render.ElasticsearchManagerUserSecret, Namespace: render.OperatorNamespace()},
+			Data:       map[string][]byte{"username": []byte("tigera-ee-manager"), "password": []byte("provisioned")},
+		})).To(Succeed())
+
+		secrets, err := r.reconcileUsers(ctx, es, rotation)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(userSecret(secrets, render.ElasticsearchManagerUserSecret).Data["password"])).To(Equal("provisioned"))
+		Expect(es.passwords).To(HaveKeyWithValue("tigera-ee-manager", "provisioned"))
+
+		stored := &corev1.Secret{}
+		Expect(cli.Get(ctx, client.ObjectKey{Name: render.ElasticsearchManagerUserSecret, Namespace: render.OperatorNamespace()}, stored)).To(Succeed())
+		rotatedAt, err := time.Parse(time.RFC3339, stored.Annotations[passwordRotatedAnnotation])
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rotatedAt).To(BeTemporally("~", time.Now(), time.Minute))
+	})
+
+	It("should store a new password before setting it in Elasticsearch", func() {
+		createUserSecret(render.ElasticsearchManagerUserSecret, "old-password", time.Now().Add(-91*24*time.Hour))
+
+		_, err := r.reconcileUsers(ctx, es, rotation)
+		Expect(err).NotTo(HaveOccurred())
+		stored := &corev1.Secret{}
+		Expect(cli.Get(ctx, client.ObjectKey{Name: render.ElasticsearchManagerUserSecret, Namespace: render.OperatorNamespace()}, stored)).To(Succeed())
+		Expect(string(stored.Data["password"])).NotTo(Equal("old-password"))
+		Expect(es.passwords).To(HaveKeyWithValue("tigera-ee-manager", string(stored.Data["password"])))
+	})
+
+	It("should not change a password in Elasticsearch if its Secret cannot be written", func() {
+		createUserSecret(render.ElasticsearchManagerUserSecret, "old-password", time.Now().Add(-91*24*time.Hour))
+		r.client = &failingWriteClient{Client: cli}
+
+		_, err := r.reconcileUsers(ctx, es, rotation)
+		Expect(err).To(HaveOccurred())
+		Expect(es.passwords).NotTo(HaveKey("tigera-ee-manager"))
+
+		stored := &corev1.Secret{}
+		Expect(cli.Get(ctx, client.ObjectKey{Name: render.ElasticsearchManagerUserSecret, Namespace: render.OperatorNamespace()}, stored)).To(Succeed())
+		Expect(string(stored.Data["password"])).To(Equal("old-password"))
+	})
+})
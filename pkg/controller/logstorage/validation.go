@@ -0,0 +1,195 @@
+// Copyright (c) 2020 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logstorage
+
+import (
+	"fmt"
+	"net/url"
+
+	operatorv1 "github.com/tigera/operator/pkg/apis/operator/v1"
+	"github.com/tigera/operator/pkg/controller/utils"
+	"github.com/tigera/operator/pkg/render"
+)
+
+// validateNodes validates the Elasticsearch node configuration of the LogStorage.
+func validateNodes(nodes *operatorv1.Nodes) error {
+	if nodes == nil {
+		return fmt.Errorf("nodes must be set")
+	}
+
+	if nodes.NodeSets == nil {
+		if nodes.Count < 1 {
+			return fmt.Errorf("nodes.count must be at least 1")
+		}
+	} else {
+		sets := map[string]*operatorv1.NodeSet{
+			"master": &nodes.NodeSets.Master,
+			"data":   &nodes.NodeSets.Data,
+			"ingest": nodes.NodeSets.Ingest,
+		}
+		for _, role := range []string{"master", "data", "ingest"} {
+			if set := sets[role]; set != nil && set.Count < 1 {
+				return fmt.Errorf("nodes.nodeSets.%s.count must be at least 1", role)
+			}
+		}
+	}
+
+	if za := nodes.ZoneAwareness; za != nil {
+		if len(za.Zones) < 2 {
+			return fmt.Errorf("nodes.zoneAwareness.zones must list at least 2 zones")
+		}
+		seen := map[string]bool{}
+		for _, zone := range za.Zones {
+			if zone == "" {
+				return fmt.Errorf("nodes.zoneAwareness.zones must not contain empty zones")
+			}
+			if seen[zone] {
+				return fmt.Errorf("nodes.zoneAwareness.zones lists zone %q more than once", zone)
+			}
+			seen[zone] = true
+		}
+		// Shard replicas are only allocated to a different zone than their primary, so every zone needs data nodes.
+		if dataNodeCount(nodes) < len(za.Zones) {
+			return fmt.Errorf("there must be at least one data node for each of the %d zones", len(za.Zones))
+		}
+	}
+	return nil
+}
+
+// validateReplicas validates that every replica of an index can be allocated to a data node of its own, once the
+// node configuration is validated.
+func validateReplicas(ls *operatorv1.LogStorage) error {
+	if replicas := ls.Replicas(); replicas < 0 {
+		return fmt.Errorf("indices.replicas must not be negative")
+	} else if data := dataNodeCount(ls.Spec.Nodes); replicas >= data {
+		return fmt.Errorf("indices.replicas is %d, which needs at least %d Elasticsearch data nodes but there are %d", replicas, replicas+1, data)
+	}
+	return nil
+}
+
+// dataNodeCount returns the number of Elasticsearch nodes that hold data.
+func dataNodeCount(nodes *operatorv1.Nodes) int {
+	if nodes.NodeSets != nil {
+		return int(nodes.NodeSets.Data.Count)
+	}
+	return int(nodes.Count)
+}
+
+// validateSnapshots validates the snapshot configuration of the LogStorage.
+func validateSnapshots(snapshots *operatorv1.Snapshots) error {
+	if snapshots == nil {
+		return nil
+	}
+	repo := snapshots.Repository
+	switch repo.Type {
+	case operatorv1.SnapshotRepositoryFilesystem:
+		if repo.Filesystem == nil || repo.Filesystem.StorageClassName == "" {
+			return fmt.Errorf("snapshots.repository.filesystem.storageClassName must be set for a %s repository", repo.Type)
+		}
+	case operatorv1.SnapshotRepositoryS3:
+		if repo.S3 == nil || repo.S3.Bucket == "" || repo.S3.CredentialsSecretName == "" {
+			return fmt.Errorf("snapshots.repository.s3.bucket and credentialsSecretName must be set for an %s repository", repo.Type)
+		}
+	default:
+		return fmt.Errorf("snapshots.repository.type %q is not supported", repo.Type)
+	}
+	return nil
+}
+
+// validateIndexLifecycle validates the rollover and storage limits of the LogStorage, once defaults are filled in.
+func validateIndexLifecycle(il *operatorv1.IndexLifecycle) error {
+	if il.RolloverSize.Sign() <= 0 {
+		return fmt.Errorf("indexLifecycle.rolloverSize must be positive")
+	}
+	if *il.MaxLogsStoragePercent > *il.MaxTotalStoragePercent {
+		return fmt.Errorf("indexLifecycle.maxLogsStoragePercent must not exceed indexLifecycle.maxTotalStoragePercent")
+	}
+	return nil
+}
+
+// validateUsers validates the management of the component users, once defaults are filled in.
+func validateUsers(users *operatorv1.ElasticsearchUsers) error {
+	if *users.PasswordRotationDays < 1 {
+		return fmt.Errorf("users.passwordRotationDays must be at least 1")
+	}
+	return nil
+}
+
+// maxRetention is the longest retention period, in days.
+const maxRetention = 3650
+
+// validateRetention validates the retention periods that are set.
+func validateRetention(r *operatorv1.Retention) error {
+	if r == nil {
+		return nil
+	}
+	periods := []struct {
+		field string
+		days  *int32
+	}{
+		{"flows", r.Flows},
+		{"auditReports", r.AuditReports},
+		{"snapshots", r.Snapshots},
+		{"complianceReports", r.ComplianceReports},
+		{"dnsLogs", r.DNSLogs},
+		{"idsEvents", r.IDSEvents},
+		{"l7Logs", r.L7Logs},
+		{"bgpLogs", r.BGPLogs},
+	}
+	for _, p := range periods {
+		if p.days != nil && (*p.days < 1 || *p.days > maxRetention) {
+			return fmt.Errorf("retention.%s must be between 1 and %d days", p.field, maxRetention)
+		}
+	}
+	return nil
+}
+
+// validateManagedClusters validates the log storage settings of the managed clusters.
+func validateManagedClusters(clusters []operatorv1.ManagedCluster) error {
+	if err := utils.ValidateManagedClusterIndexPrefixes(clusters, render.DefaultElasticsearchClusterName); err != nil {
+		return err
+	}
+	for _, mc := range clusters {
+		if mc.Spec.LogStorage == nil {
+			continue
+		}
+		if err := validateRetention(mc.Spec.LogStorage.Retention); err != nil {
+			return fmt.Errorf("managed cluster %s: %v", mc.Name, err)
+		}
+	}
+	return nil
+}
+
+// validateExternal validates the external Elasticsearch cluster of the LogStorage.
+func validateExternal(spec *operatorv1.LogStorageSpec) error {
+	ext := spec.External
+	u, err := url.Parse(ext.Endpoint)
+	if err != nil || u.Scheme != "https" || (u.Path != "" && u.Path != "/") {
+		return fmt.Errorf("external.endpoint must be of the form https://host:port")
+	}
+	if _, _, _, err := render.ParseEndpoint(ext.Endpoint); err != nil {
+		return fmt.Errorf("external.endpoint must be of the form https://host:port")
+	}
+	if ext.CASecretName == "" {
+		return fmt.Errorf("external.caSecretName must be set")
+	}
+	if ext.CredentialsSecretName == "" {
+		return fmt.Errorf("external.credentialsSecretName must be set")
+	}
+	if spec.Snapshots != nil {
+		return fmt.Errorf("snapshots are only supported for the Elasticsearch cluster run by the operator")
+	}
+	return nil
+}
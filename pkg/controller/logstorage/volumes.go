@@ -0,0 +1,129 @@
+// Copyright (c) 2020 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logstorage
+
+import (
+	"context"
+	"fmt"
+
+	esalpha1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1alpha1"
+	"github.com/tigera/operator/pkg/render"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// esStatefulSetLabel is the label ECK sets on the pods and volumes of the StatefulSet of a node spec.
+const esStatefulSetLabel = "elasticsearch.k8s.elastic.co/statefulset-name"
+
+// volumeResize is a volume of an Elasticsearch node whose size differs from the size configured in the LogStorage.
+type volumeResize struct {
+	pvc         *corev1.PersistentVolumeClaim
+	statefulSet string
+	size        resource.Quantity
+}
+
+// elasticsearchFromObjects returns the Elasticsearch CR among the rendered objects, as a v1alpha1 Elasticsearch.
+func elasticsearchFromObjects(objs []runtime.Object) (*esalpha1.Elasticsearch, error) {
+	for _, obj := range objs {
+		switch es := obj.(type) {
+		case *esalpha1.Elasticsearch:
+			return es, nil
+		case *unstructured.Unstructured:
+			if es.GroupVersionKind() == render.ElasticsearchV1GVK {
+				return render.ElasticsearchV1alpha1(es)
+			}
+		}
+	}
+	return nil, fmt.Errorf("no Elasticsearch was rendered")
+}
+
+// volumeResizes returns the volumes of the Elasticsearch nodes whose size differs from the size in the volume
+// claim template of their node spec.
+func (r *ReconcileLogStorage) volumeResizes(ctx context.Context, es *esalpha1.Elasticsearch) ([]volumeResize, error) {
+	var resizes []volumeResize
+	for _, spec := range es.Spec.Nodes {
+		if len(spec.VolumeClaimTemplates) == 0 {
+			continue
+		}
+		size, ok := spec.VolumeClaimTemplates[0].Spec.Resources.Requests[corev1.ResourceStorage]
+		if !ok {
+			continue
+		}
+
+		statefulSet := render.ElasticsearchStatefulSetName(spec.Name)
+		pvcs := corev1.PersistentVolumeClaimList{}
+		if err := r.client.List(ctx, &pvcs,
+			client.InNamespace(render.ElasticsearchNamespace),
+			client.MatchingLabels(map[string]string{esStatefulSetLabel: statefulSet}),
+		); err != nil {
+			return nil, err
+		}
+		for i := range pvcs.Items {
+			pvc := &pvcs.Items[i]
+			current, ok := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+			if !ok || current.Cmp(size) == 0 {
+				continue
+			}
+			resizes = append(resizes, volumeResize{pvc: pvc, statefulSet: statefulSet, size: size})
+		}
+	}
+	return resizes, nil
+}
+
+// checkVolumeResizes returns an error if the volumes cannot be resized: volumes cannot shrink, and can only grow
+// if the StorageClass allows volume expansion.
+func checkVolumeResizes(resizes []volumeResize, sc *storagev1.StorageClass) error {
+	for _, resize := range resizes {
+		current := resize.pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+		if resize.size.Cmp(current) < 0 {
+			return fmt.Errorf("the size of volume %s cannot be decreased from %s to %s", resize.pvc.Name, current.String(), resize.size.String())
+		}
+	}
+	if len(resizes) > 0 && (sc.AllowVolumeExpansion == nil || !*sc.AllowVolumeExpansion) {
+		return fmt.Errorf("storage class %s does not allow volume expansion, the Elasticsearch volumes cannot grow to the configured size", sc.Name)
+	}
+	return nil
+}
+
+// expandVolumes grows the volumes of the Elasticsearch nodes online. The volume claim templates of a StatefulSet
+// cannot be changed, so the StatefulSets of the grown volumes are removed without removing their pods and ECK
+// recreates them with the new volume claim templates.
+func (r *ReconcileLogStorage) expandVolumes(ctx context.Context, resizes []volumeResize) error {
+	statefulSets := map[string]bool{}
+	for _, resize := range resizes {
+		log.Info("Expanding Elasticsearch volume", "name", resize.pvc.Name, "size", resize.size.String())
+		resize.pvc.Spec.Resources.Requests[corev1.ResourceStorage] = resize.size
+		if err := r.client.Update(ctx, resize.pvc); err != nil {
+			return err
+		}
+		statefulSets[resize.statefulSet] = true
+	}
+
+	for name := range statefulSets {
+		sts := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: render.ElasticsearchNamespace}}
+		if err := r.client.Delete(ctx, sts, client.PropagationPolicy(metav1.DeletePropagationOrphan)); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
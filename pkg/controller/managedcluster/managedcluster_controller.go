@@ -0,0 +1,392 @@
+// Copyright (c) 2020 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package managedcluster
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	v3 "github.com/tigera/api/pkg/apis/projectcalico/v3"
+	operatorv1 "github.com/tigera/operator/pkg/apis/operator/v1"
+	"github.com/tigera/operator/pkg/controller/installation"
+	"github.com/tigera/operator/pkg/controller/status"
+	"github.com/tigera/operator/pkg/controller/utils"
+	"github.com/tigera/operator/pkg/render"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+var log = logf.Log.WithName("managedcluster_controller")
+
+const controllerName = "managedcluster-controller"
+
+// The connection status of managed clusters is reported by Voltron on the Calico ManagedCluster, which is served
+// by the aggregated API and therefore not watched. Poll it at this interval while managed clusters exist.
+const connectionStatusInterval = 1 * time.Minute
+
+// LastSeen is refreshed at this interval while a managed cluster stays connected, rather than on every poll, so
+// that the status is not rewritten every connectionStatusInterval.
+const lastSeenInterval = 10 * time.Minute
+
+// Add creates a new ManagedCluster Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and start it when the Manager is started. This controller is meant only for enterprise users.
+func Add(mgr manager.Manager, p operatorv1.Provider, enterpriseEnabled bool) error {
+	if !enterpriseEnabled {
+		// No need to start this controller.
+		return nil
+	}
+	return add(mgr, newReconciler(mgr, p))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager, p operatorv1.Provider) reconcile.Reconciler {
+	r := &ReconcileManagedCluster{
+		client:   mgr.GetClient(),
+		scheme:   mgr.GetScheme(),
+		provider: p,
+		status:   status.New(mgr.GetClient(), "managed-clusters"),
+	}
+	r.status.Run()
+	return r
+}
+
+// add adds a new controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %v", controllerName, err)
+	}
+
+	// Watch for changes to primary resource ManagedCluster. The controller updates the status of the ManagedClusters
+	// itself, so only spec changes and the acknowledgement of a renewed certificate trigger a reconcile.
+	err = c.Watch(&source.Kind{Type: &operatorv1.ManagedCluster{}}, &handler.EnqueueRequestForObject{}, predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			ack := render.ManagedClusterAcknowledgedFingerprintAnnotation
+			return e.MetaOld.GetGeneration() != e.MetaNew.GetGeneration() ||
+				e.MetaOld.GetAnnotations()[ack] != e.MetaNew.GetAnnotations()[ack]
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("%s failed to watch primary resource: %v", controllerName, err)
+	}
+
+	// Watch the Secrets in the operator namespace. This covers the Voltron tunnel secret holding the CA the
+	// managed cluster certificates are signed with, as well as the Secrets generated for each managed cluster.
+	if err = utils.AddSecretsWatch(c, "", render.OperatorNamespace()); err != nil {
+		return fmt.Errorf("%s failed to watch Secret resources: %v", controllerName, err)
+	}
+
+	// The Elasticsearch configuration is written into the manifests of the managed clusters.
+	if err = utils.AddConfigMapWatch(c, render.ElasticsearchConfigMapName, render.OperatorNamespace()); err != nil {
+		return fmt.Errorf("%s failed to watch ConfigMap resource: %v", controllerName, err)
+	}
+
+	if err = utils.AddNetworkWatch(c); err != nil {
+		return fmt.Errorf("%s failed to watch Network resource: %v", controllerName, err)
+	}
+
+	if err = utils.AddAPIServerWatch(c); err != nil {
+		return fmt.Errorf("%s failed to watch APIServer resource: %v", controllerName, err)
+	}
+
+	return nil
+}
+
+// blank assignment to verify that ReconcileManagedCluster implements reconcile.Reconciler
+var _ reconcile.Reconciler = &ReconcileManagedCluster{}
+
+// ReconcileManagedCluster reconciles the ManagedCluster objects of a management cluster
+type ReconcileManagedCluster struct {
+	client   client.Client
+	scheme   *runtime.Scheme
+	provider operatorv1.Provider
+	status   *status.StatusManager
+}
+
+// Reconcile registers every ManagedCluster with the management cluster. Since the Secrets and the Installation
+// that trigger a reconcile affect all managed clusters, all of them are reconciled regardless of the request.
+func (r *ReconcileManagedCluster) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	reqLogger.V(1).Info("Reconciling managed clusters")
+	ctx := context.Background()
+
+	clusters := operatorv1.ManagedClusterList{}
+	if err := r.client.List(ctx, &clusters); err != nil {
+		return reconcile.Result{}, err
+	}
+	if len(clusters.Items) == 0 {
+		r.status.OnCRNotFound()
+		return reconcile.Result{}, nil
+	}
+	r.status.OnCRFound()
+
+	instl, err := installation.GetInstallation(ctx, r.client, r.provider)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			reqLogger.Info("Installation not found")
+			r.status.SetDegraded("Installation not found", err.Error())
+			return reconcile.Result{}, nil
+		}
+		r.status.SetDegraded("Error querying installation", err.Error())
+		return reconcile.Result{}, err
+	}
+	if instl.Spec.ClusterManagementType != operatorv1.ClusterManagementTypeManagement {
+		msg := fmt.Sprintf("ManagedClusters are only reconciled when the cluster management type is %s",
+			operatorv1.ClusterManagementTypeManagement)
+		reqLogger.Info(msg)
+		r.status.SetDegraded("Not a management cluster", msg)
+		return reconcile.Result{}, nil
+	}
+
+	if !utils.IsAPIServerReady(r.client, reqLogger) {
+		reqLogger.Info("Waiting for Tigera API server to be ready")
+		r.status.SetDegraded("Waiting for Tigera API server to be ready", "")
+		// Requeue through the rate limiter so that we back off while the aggregated API starts.
+		return reconcile.Result{Requeue: true}, nil
+	}
+
+	// The Voltron tunnel secret is created by the manager controller.
+	voltronSecret := &corev1.Secret{}
+	err = r.client.Get(ctx, types.NamespacedName{Name: render.VoltronTunnelSecretName, Namespace: render.OperatorNamespace()}, voltronSecret)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			reqLogger.Info("Waiting for the Voltron tunnel secret to be created", "secret", render.VoltronTunnelSecretName)
+			r.status.SetDegraded(fmt.Sprintf("Waiting for secret %s to be created", render.VoltronTunnelSecretName), "")
+			return reconcile.Result{}, nil
+		}
+		r.status.SetDegraded(fmt.Sprintf("Error reading secret %s", render.VoltronTunnelSecretName), err.Error())
+		return reconcile.Result{}, err
+	}
+
+	if err := utils.ValidateManagedClusterIndexPrefixes(clusters.Items, render.DefaultElasticsearchClusterName); err != nil {
+		reqLogger.Error(err, "Invalid managed cluster log storage settings")
+		r.status.SetDegraded("Invalid managed cluster log storage settings", err.Error())
+		return reconcile.Result{}, nil
+	}
+
+	// The Elasticsearch configuration is created along with the LogStorage, which may not exist yet.
+	esClusterConfig, err := utils.GetElasticsearchClusterConfig(ctx, r.client)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			r.status.SetDegraded("Error reading the Elasticsearch configuration", err.Error())
+			return reconcile.Result{}, err
+		}
+		esClusterConfig = nil
+	}
+
+	var certs []operatorv1.CertificateStatus
+	for i := range clusters.Items {
+		mc := &clusters.Items[i]
+		if err := r.reconcileManagedCluster(ctx, mc, voltronSecret, esClusterConfig); err != nil {
+			reqLogger.Error(err, "Error reconciling managed cluster", "cluster", mc.Name)
+			r.status.SetDegraded(fmt.Sprintf("Error reconciling managed cluster %s", mc.Name), err.Error())
+			return reconcile.Result{}, err
+		}
+		if mc.Status.CertificateExpiry != nil {
+			certs = append(certs, operatorv1.CertificateStatus{Name: mc.Name, NotAfter: *mc.Status.CertificateExpiry})
+		}
+	}
+	r.status.SetCertificates(certs)
+	r.status.ClearDegraded()
+
+	return reconcile.Result{RequeueAfter: connectionStatusInterval}, nil
+}
+
+// reconcileManagedCluster issues the managed cluster a tunnel certificate, renders its manifest and registration,
+// and updates its status.
+func (r *ReconcileManagedCluster) reconcileManagedCluster(ctx context.Context, mc *operatorv1.ManagedCluster, voltronSecret *corev1.Secret, esClusterConfig *render.ElasticsearchClusterConfig) error {
+	clusterLog := log.WithValues("cluster", mc.Name)
+	connected, err := isConnected(ctx, r.client, mc.Name)
+	if err != nil {
+		return err
+	}
+
+	certs, err := r.getOrCreateCertificates(ctx, clusterLog, mc, voltronSecret)
+	if err != nil {
+		return err
+	}
+
+	component, err := render.ManagedCluster(mc, certs.key, certs.cert, certs.pendingKey, certs.pendingCert, voltronSecret, esClusterConfig)
+	if err != nil {
+		return err
+	}
+	if err := utils.NewComponentHandler(clusterLog, r.client, r.scheme, mc).CreateOrUpdate(ctx, component, nil); err != nil {
+		return err
+	}
+
+	fingerprint, err := render.ManagedClusterCertificateFingerprint(certs.cert)
+	if err != nil {
+		return err
+	}
+	expiry, err := render.CertificateExpiry(certs.cert)
+	if err != nil {
+		return err
+	}
+	notAfter := metav1.NewTime(expiry)
+
+	status := operatorv1.ManagedClusterStatus{
+		ManifestSecretName:     render.ManagedClusterSecretName(mc.Name),
+		CertificateFingerprint: fingerprint,
+		CertificateExpiry:      &notAfter,
+		RenewalPending:         certs.pendingCert != nil,
+		Connected:              connected,
+		LastSeen:               mc.Status.LastSeen,
+	}
+	if connected && (!mc.Status.Connected || mc.Status.LastSeen == nil || time.Since(mc.Status.LastSeen.Time) >= lastSeenInterval) {
+		now := metav1.Now()
+		status.LastSeen = &now
+	}
+	if certs.pendingCert != nil {
+		if status.PendingCertificateFingerprint, err = render.ManagedClusterCertificateFingerprint(certs.pendingCert); err != nil {
+			return err
+		}
+	}
+	return updateStatus(ctx, r.client, clusterLog, mc, status)
+}
+
+// tunnelCertificates holds the tunnel certificate Voltron accepts from a managed cluster and, while a renewal is
+// in progress, the renewed certificate handed out in its manifest.
+type tunnelCertificates struct {
+	key, cert               []byte
+	pendingKey, pendingCert []byte
+}
+
+// getOrCreateCertificates returns the tunnel certificates of the managed cluster. The certificates are rotated in
+// the following steps:
+//
+//  1. Once the accepted certificate is due for renewal, or was not issued by the Voltron CA that is taking over,
+//     a pending certificate is issued and written into the manifest, while Voltron keeps accepting the current
+//     certificate.
+//  2. The managed cluster applies the regenerated manifest, and the ManagedCluster is annotated with the
+//     fingerprint of the pending certificate to acknowledge it, see
+//     render.ManagedClusterAcknowledgedFingerprintAnnotation.
+//  3. Once acknowledged, the pending certificate becomes the accepted certificate. A managed cluster that does
+//     not acknowledge the renewal switches over once the accepted certificate expires.
+//
+// A certificate that is no longer valid for the Voltron CAs is replaced right away, since the managed cluster
+// cannot connect with it anyway.
+func (r *ReconcileManagedCluster) getOrCreateCertificates(ctx context.Context, l logr.Logger, mc *operatorv1.ManagedCluster, voltronSecret *corev1.Secret) (*tunnelCertificates, error) {
+	secret := &corev1.Secret{}
+	err := r.client.Get(ctx, types.NamespacedName{Name: render.ManagedClusterSecretName(mc.Name), Namespace: render.OperatorNamespace()}, secret)
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+
+	certs := &tunnelCertificates{
+		key:         secret.Data[render.ManagedClusterKeyKey],
+		cert:        secret.Data[render.ManagedClusterCertKey],
+		pendingKey:  secret.Data[render.ManagedClusterPendingKeyKey],
+		pendingCert: secret.Data[render.ManagedClusterPendingCertKey],
+	}
+	if len(certs.pendingKey) == 0 ||
+		!render.IsManagedClusterCertificateValid(certs.pendingCert, voltronSecret) ||
+		!render.IsManagedClusterCertificateCurrent(certs.pendingCert, voltronSecret) {
+		certs.pendingKey, certs.pendingCert = nil, nil
+	}
+
+	if len(certs.key) == 0 || !render.IsManagedClusterCertificateValid(certs.cert, voltronSecret) {
+		if certs.pendingCert != nil {
+			l.Info("Activating the renewed tunnel certificate")
+			certs.key, certs.cert = certs.pendingKey, certs.pendingCert
+			certs.pendingKey, certs.pendingCert = nil, nil
+			return certs, nil
+		}
+		l.Info("Issuing a tunnel certificate")
+		certs.key, certs.cert, err = render.CreateManagedClusterCertificate(mc.Name, voltronSecret)
+		if err != nil {
+			return nil, err
+		}
+		return certs, nil
+	}
+
+	expiry, err := render.CertificateExpiry(certs.cert)
+	if err != nil {
+		return nil, err
+	}
+	if time.Now().Add(render.TunnelCertificateRenewBefore).Before(expiry) && render.IsManagedClusterCertificateCurrent(certs.cert, voltronSecret) {
+		// Not due for renewal.
+		certs.pendingKey, certs.pendingCert = nil, nil
+		return certs, nil
+	}
+
+	if certs.pendingCert == nil {
+		l.Info("Issuing a renewed tunnel certificate", "expiry", expiry)
+		certs.pendingKey, certs.pendingCert, err = render.CreateManagedClusterCertificate(mc.Name, voltronSecret)
+		if err != nil {
+			return nil, err
+		}
+		return certs, nil
+	}
+
+	pendingFingerprint, err := render.ManagedClusterCertificateFingerprint(certs.pendingCert)
+	if err != nil {
+		return nil, err
+	}
+	if mc.Annotations[render.ManagedClusterAcknowledgedFingerprintAnnotation] == pendingFingerprint {
+		l.Info("Activating the renewed tunnel certificate", "fingerprint", pendingFingerprint)
+		certs.key, certs.cert = certs.pendingKey, certs.pendingCert
+		certs.pendingKey, certs.pendingCert = nil, nil
+	}
+	return certs, nil
+}
+
+// isConnected returns true if Voltron reports an open tunnel to the named managed cluster.
+func isConnected(ctx context.Context, cli client.Client, name string) (bool, error) {
+	calicoCluster := &v3.ManagedCluster{}
+	if err := cli.Get(ctx, types.NamespacedName{Name: name}, calicoCluster); err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	for _, c := range calicoCluster.Status.Conditions {
+		if c.Type == v3.ManagedClusterStatusTypeConnected {
+			return c.Status == v3.ManagedClusterStatusValueTrue, nil
+		}
+	}
+	return false, nil
+}
+
+func updateStatus(ctx context.Context, cli client.Client, l logr.Logger, mc *operatorv1.ManagedCluster, status operatorv1.ManagedClusterStatus) error {
+	if mc.Status.ManifestSecretName == status.ManifestSecretName &&
+		mc.Status.CertificateFingerprint == status.CertificateFingerprint &&
+		mc.Status.CertificateExpiry.Equal(status.CertificateExpiry) &&
+		mc.Status.RenewalPending == status.RenewalPending &&
+		mc.Status.PendingCertificateFingerprint == status.PendingCertificateFingerprint &&
+		mc.Status.Connected == status.Connected &&
+		mc.Status.LastSeen.Equal(status.LastSeen) {
+		return nil
+	}
+	if mc.Status.Connected != status.Connected {
+		l.Info("Managed cluster connection changed", "connected", status.Connected)
+	}
+	mc.Status = status
+	return cli.Status().Update(ctx, mc)
+}
@@ -0,0 +1,216 @@
+// Copyright (c) 2020 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package managedcluster
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/tigera/operator/pkg/apis"
+	operatorv1 "github.com/tigera/operator/pkg/apis/operator/v1"
+	"github.com/tigera/operator/pkg/render"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Managed cluster tunnel certificate rotation", func() {
+	var r *ReconcileManagedCluster
+	var ctx context.Context
+	var voltronSecret *corev1.Secret
+	var mc *operatorv1.ManagedCluster
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(apis.AddToScheme(scheme)).NotTo(HaveOccurred())
+		Expect(corev1.AddToScheme(scheme)).NotTo(HaveOccurred())
+		r = &ReconcileManagedCluster{client: fake.NewFakeClientWithScheme(scheme), scheme: scheme}
+		ctx = context.Background()
+
+		instance := &operatorv1.Manager{
+			ObjectMeta: metav1.ObjectMeta{Name: "tigera-secure"},
+			Spec:       operatorv1.ManagerSpec{Auth: &operatorv1.Auth{Type: operatorv1.AuthTypeBasic}},
+		}
+		component, err := render.Manager(instance, nil, nil,
+			render.NewElasticsearchClusterConfig("cluster", 1, 1), nil, nil, false, "", nil, nil, nil, true, nil, "")
+		Expect(err).NotTo(HaveOccurred())
+		for _, obj := range component.Objects() {
+			if s, ok := obj.(*corev1.Secret); ok && s.Name == render.VoltronTunnelSecretName && s.Namespace == render.OperatorNamespace() {
+				voltronSecret = s
+			}
+		}
+		Expect(voltronSecret).NotTo(BeNil())
+
+		mc = &operatorv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster-a"}}
+	})
+
+	// createCertificate issues a tunnel certificate with the given expiry, signed by the Voltron CA.
+	createCertificate := func(notAfter time.Time) ([]byte, []byte) {
+		caBlock, _ := pem.Decode(voltronSecret.Data["cert"])
+		Expect(caBlock).NotTo(BeNil())
+		caCert, err := x509.ParseCertificate(caBlock.Bytes)
+		Expect(err).NotTo(HaveOccurred())
+		keyBlock, _ := pem.Decode(voltronSecret.Data["key"])
+		Expect(keyBlock).NotTo(BeNil())
+		caKey, err := x509.ParsePKCS1PrivateKey(keyBlock.Bytes)
+		Expect(err).NotTo(HaveOccurred())
+
+		privateKey, err := rsa.GenerateKey(rand.Reader, render.VoltronKeySizeBits)
+		Expect(err).NotTo(HaveOccurred())
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(2),
+			Subject:      pkix.Name{CommonName: mc.Name},
+			NotBefore:    notAfter.Add(-365 * 24 * time.Hour),
+			NotAfter:     notAfter,
+			KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+			ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		}
+		cert, err := x509.CreateCertificate(rand.Reader, template, caCert, &privateKey.PublicKey, caKey)
+		Expect(err).NotTo(HaveOccurred())
+
+		var keyPem, certPem bytes.Buffer
+		Expect(pem.Encode(&keyPem, &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(privateKey)})).To(Succeed())
+		Expect(pem.Encode(&certPem, &pem.Block{Type: "CERTIFICATE", Bytes: cert})).To(Succeed())
+		return keyPem.Bytes(), certPem.Bytes()
+	}
+
+	// storeCertificates writes the tunnel certificates of the managed cluster as the ManagedCluster render does.
+	storeCertificates := func(key, cert, pendingKey, pendingCert []byte) {
+		data := map[string][]byte{render.ManagedClusterKeyKey: key, render.ManagedClusterCertKey: cert}
+		if pendingCert != nil {
+			data[render.ManagedClusterPendingKeyKey] = pendingKey
+			data[render.ManagedClusterPendingCertKey] = pendingCert
+		}
+		Expect(r.client.Create(ctx, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: render.ManagedClusterSecretName(mc.Name), Namespace: render.OperatorNamespace()},
+			Data:       data,
+		})).To(Succeed())
+	}
+
+	fingerprint := func(cert []byte) string {
+		f, err := render.ManagedClusterCertificateFingerprint(cert)
+		Expect(err).NotTo(HaveOccurred())
+		return f
+	}
+
+	It("should issue a certificate for a new managed cluster", func() {
+		certs, err := r.getOrCreateCertificates(ctx, log, mc, voltronSecret)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(render.IsManagedClusterCertificateValid(certs.cert, voltronSecret)).To(BeTrue())
+		Expect(certs.key).NotTo(BeEmpty())
+		Expect(certs.pendingCert).To(BeNil())
+	})
+
+	It("should keep a certificate that is not due for renewal", func() {
+		key, cert := createCertificate(time.Now().Add(90 * 24 * time.Hour))
+		storeCertificates(key, cert, nil, nil)
+
+		certs, err := r.getOrCreateCertificates(ctx, log, mc, voltronSecret)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(certs.cert).To(Equal(cert))
+		Expect(certs.key).To(Equal(key))
+		Expect(certs.pendingCert).To(BeNil())
+	})
+
+	It("should issue a pending certificate ahead of expiry and keep accepting the current one", func() {
+		key, cert := createCertificate(time.Now().Add(render.TunnelCertificateRenewBefore / 2))
+		storeCertificates(key, cert, nil, nil)
+
+		certs, err := r.getOrCreateCertificates(ctx, log, mc, voltronSecret)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(certs.cert).To(Equal(cert))
+		Expect(certs.pendingCert).NotTo(BeNil())
+		Expect(certs.pendingCert).NotTo(Equal(cert))
+		Expect(render.IsManagedClusterCertificateValid(certs.pendingCert, voltronSecret)).To(BeTrue())
+	})
+
+	It("should keep the pending certificate pending until it is acknowledged", func() {
+		key, cert := createCertificate(time.Now().Add(render.TunnelCertificateRenewBefore / 2))
+		pendingKey, pendingCert := createCertificate(time.Now().Add(365 * 24 * time.Hour))
+		storeCertificates(key, cert, pendingKey, pendingCert)
+
+		certs, err := r.getOrCreateCertificates(ctx, log, mc, voltronSecret)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(certs.cert).To(Equal(cert))
+		Expect(certs.pendingCert).To(Equal(pendingCert))
+
+		By("acknowledging a different certificate")
+		mc.Annotations = map[string]string{render.ManagedClusterAcknowledgedFingerprintAnnotation: fingerprint(cert)}
+		certs, err = r.getOrCreateCertificates(ctx, log, mc, voltronSecret)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(certs.cert).To(Equal(cert))
+		Expect(certs.pendingCert).To(Equal(pendingCert))
+	})
+
+	It("should activate the pending certificate once it is acknowledged", func() {
+		key, cert := createCertificate(time.Now().Add(render.TunnelCertificateRenewBefore / 2))
+		pendingKey, pendingCert := createCertificate(time.Now().Add(365 * 24 * time.Hour))
+		storeCertificates(key, cert, pendingKey, pendingCert)
+		mc.Annotations = map[string]string{render.ManagedClusterAcknowledgedFingerprintAnnotation: fingerprint(pendingCert)}
+
+		certs, err := r.getOrCreateCertificates(ctx, log, mc, voltronSecret)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(certs.cert).To(Equal(pendingCert))
+		Expect(certs.key).To(Equal(pendingKey))
+		Expect(certs.pendingCert).To(BeNil())
+	})
+
+	It("should activate the pending certificate once the current one has expired", func() {
+		key, cert := createCertificate(time.Now().Add(-time.Hour))
+		pendingKey, pendingCert := createCertificate(time.Now().Add(365 * 24 * time.Hour))
+		storeCertificates(key, cert, pendingKey, pendingCert)
+
+		certs, err := r.getOrCreateCertificates(ctx, log, mc, voltronSecret)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(certs.cert).To(Equal(pendingCert))
+		Expect(certs.key).To(Equal(pendingKey))
+		Expect(certs.pendingCert).To(BeNil())
+	})
+
+	It("should replace an expired certificate right away when no renewal is pending", func() {
+		key, cert := createCertificate(time.Now().Add(-time.Hour))
+		storeCertificates(key, cert, nil, nil)
+
+		certs, err := r.getOrCreateCertificates(ctx, log, mc, voltronSecret)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(certs.cert).NotTo(Equal(cert))
+		Expect(render.IsManagedClusterCertificateValid(certs.cert, voltronSecret)).To(BeTrue())
+		Expect(certs.pendingCert).To(BeNil())
+	})
+
+	It("should issue a pending certificate for a certificate signed by the CA being replaced", func() {
+		key, cert := createCertificate(time.Now().Add(90 * 24 * time.Hour))
+		storeCertificates(key, cert, nil, nil)
+		Expect(render.RenewVoltronTunnelCA(voltronSecret)).To(Succeed())
+
+		certs, err := r.getOrCreateCertificates(ctx, log, mc, voltronSecret)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(certs.cert).To(Equal(cert))
+		Expect(certs.pendingCert).NotTo(BeNil())
+		Expect(render.IsManagedClusterCertificateCurrent(certs.pendingCert, voltronSecret)).To(BeTrue())
+	})
+})
@@ -0,0 +1,89 @@
+// Copyright (c) 2020 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	operatorv1 "github.com/tigera/operator/pkg/apis/operator/v1"
+	"github.com/tigera/operator/pkg/render"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// requiredConnectorSecretKeys lists the keys each connector type needs in its Secret.
+var requiredConnectorSecretKeys = map[operatorv1.IdentityConnectorType][]string{
+	operatorv1.IdentityConnectorTypeLDAP:   {render.DexSecretKeyBindDN, render.DexSecretKeyBindPW},
+	operatorv1.IdentityConnectorTypeSAML:   {render.DexSecretKeyCA},
+	operatorv1.IdentityConnectorTypeGitHub: {render.DexSecretKeyClientID, render.DexSecretKeyClientSecret},
+}
+
+// getConnectorSecrets returns the Secrets referenced by the identity broker's connectors, keyed by name.
+func getConnectorSecrets(ctx context.Context, cli client.Client, broker *operatorv1.IdentityBroker) (map[string]*corev1.Secret, error) {
+	secrets := map[string]*corev1.Secret{}
+	for _, conn := range broker.Connectors {
+		s, ok := secrets[conn.SecretName]
+		if !ok {
+			s = &corev1.Secret{}
+			if err := cli.Get(ctx, types.NamespacedName{Name: conn.SecretName, Namespace: render.OperatorNamespace()}, s); err != nil {
+				return nil, err
+			}
+			secrets[conn.SecretName] = s
+		}
+		for _, key := range requiredConnectorSecretKeys[conn.Type] {
+			if len(s.Data[key]) == 0 {
+				return nil, fmt.Errorf("Secret %s of identity connector %s does not contain the key %s", conn.SecretName, conn.Name, key)
+			}
+		}
+	}
+	return secrets, nil
+}
+
+// removeDex deletes the identity broker namespace, and with it all the identity broker resources,
+// once the identity broker is disabled. The namespace is only deleted if the operator created it.
+func removeDex(ctx context.Context, cli client.Client) error {
+	ns := &corev1.Namespace{}
+	if err := cli.Get(ctx, types.NamespacedName{Name: render.DexNamespace}, ns); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if !ownedByManager(ns.OwnerReferences) || ns.DeletionTimestamp != nil {
+		return nil
+	}
+	log.Info("Removing the identity broker")
+	if err := cli.Delete(ctx, ns); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// ownedByManager returns true if the owner references include the Manager resource, which the operator sets on
+// everything it renders for the manager.
+func ownedByManager(refs []metav1.OwnerReference) bool {
+	for _, ref := range refs {
+		if ref.Kind == "Manager" && strings.HasPrefix(ref.APIVersion, operatorv1.SchemeGroupVersion.Group+"/") {
+			return true
+		}
+	}
+	return false
+}
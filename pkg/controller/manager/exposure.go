@@ -0,0 +1,124 @@
+// Copyright (c) 2020 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"context"
+	"fmt"
+
+	routev1 "github.com/openshift/api/route/v1"
+	operatorv1 "github.com/tigera/operator/pkg/apis/operator/v1"
+	"github.com/tigera/operator/pkg/render"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const managerName = "tigera-manager"
+
+// removeStaleExposure deletes the Ingress or Route left behind when the manager exposure type changes.
+func removeStaleExposure(ctx context.Context, cli client.Client, instance *operatorv1.Manager, openshift bool) error {
+	var exposureType operatorv1.ManagerExposureType
+	if instance.Spec.Exposure != nil {
+		exposureType = instance.Spec.Exposure.Type
+	}
+	if exposureType != operatorv1.ManagerExposureTypeIngress {
+		// Clusters that do not serve Ingresses in this API group cannot have one to remove.
+		if err := removeManagerObject(ctx, cli, &networkingv1beta1.Ingress{}); err != nil && !meta.IsNoMatchError(err) {
+			return err
+		}
+	}
+	// Routes only exist on OpenShift.
+	if openshift && exposureType != operatorv1.ManagerExposureTypeRoute {
+		if err := removeManagerObject(ctx, cli, &routev1.Route{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeManagerObject deletes the object named after the manager in the manager namespace, if it
+// was rendered for the Manager. Objects created by users with the same name are left alone.
+func removeManagerObject(ctx context.Context, cli client.Client, obj runtime.Object) error {
+	if err := cli.Get(ctx, types.NamespacedName{Name: managerName, Namespace: render.ManagerNamespace}, obj); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return err
+	}
+	if !ownedByManager(accessor.GetOwnerReferences()) || accessor.GetDeletionTimestamp() != nil {
+		return nil
+	}
+	if err := cli.Delete(ctx, obj); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// managerURL returns the external URL of the manager, or an empty string if the manager is not
+// exposed or its address is not known yet.
+func managerURL(ctx context.Context, cli client.Client, instance *operatorv1.Manager) (string, error) {
+	if url := render.ManagerExternalURL(instance); url != "" {
+		return url, nil
+	}
+	if instance.Spec.Exposure == nil {
+		return "", nil
+	}
+
+	key := types.NamespacedName{Name: managerName, Namespace: render.ManagerNamespace}
+	switch instance.Spec.Exposure.Type {
+	case operatorv1.ManagerExposureTypeRoute:
+		// OpenShift generated the host. A Route that was just created may not be in the cache yet.
+		route := &routev1.Route{}
+		if err := cli.Get(ctx, key, route); err != nil {
+			if errors.IsNotFound(err) {
+				return "", nil
+			}
+			return "", err
+		}
+		if route.Spec.Host == "" {
+			return "", nil
+		}
+		return "https://" + route.Spec.Host, nil
+	case operatorv1.ManagerExposureTypeLoadBalancer:
+		svc := &corev1.Service{}
+		if err := cli.Get(ctx, key, svc); err != nil {
+			if errors.IsNotFound(err) {
+				return "", nil
+			}
+			return "", err
+		}
+		for _, ingress := range svc.Status.LoadBalancer.Ingress {
+			host := ingress.Hostname
+			if host == "" {
+				host = ingress.IP
+			}
+			if host != "" {
+				return fmt.Sprintf("https://%s:%d", host, svc.Spec.Ports[0].Port), nil
+			}
+		}
+	}
+	// The address of a NodePort service depends on the node used to reach it.
+	return "", nil
+}
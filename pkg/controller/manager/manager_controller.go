@@ -69,6 +69,11 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		return fmt.Errorf("manager-controller failed to watch APIServer resource: %v", err)
 	}
 
+	err = utils.AddAPIServiceWatch(c)
+	if err != nil {
+		return fmt.Errorf("manager-controller failed to watch APIService resource: %v", err)
+	}
+
 	err = utils.AddComplianceWatch(c)
 	if err != nil {
 		return fmt.Errorf("manager-controller failed to watch compliance resource: %v", err)
@@ -86,14 +91,27 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		}
 	}
 
-	if err = utils.AddConfigMapWatch(c, render.ManagerOIDCConfig, render.OperatorNamespace()); err != nil {
-		return fmt.Errorf("manager-controller failed to watch ConfigMap resource %s: %v", render.ManagerOIDCConfig, err)
+	// Watch all Secrets in the operator namespace, since the names of the Secrets referenced by identity
+	// broker connectors are only known at reconcile time.
+	if err = utils.AddSecretsWatch(c, "", render.OperatorNamespace()); err != nil {
+		return fmt.Errorf("manager-controller failed to watch the Secret resource: %v", err)
+	}
+
+	for _, configMapName := range []string{render.ManagerOIDCConfig, render.ManagerOIDCCABundle} {
+		if err = utils.AddConfigMapWatch(c, configMapName, render.OperatorNamespace()); err != nil {
+			return fmt.Errorf("manager-controller failed to watch ConfigMap resource %s: %v", configMapName, err)
+		}
 	}
 
 	if err = utils.AddConfigMapWatch(c, render.ElasticsearchConfigMapName, render.OperatorNamespace()); err != nil {
 		return fmt.Errorf("compliance-controller failed to watch the ConfigMap resource: %v", err)
 	}
 
+	// Watch the manager service to learn the address of a LoadBalancer.
+	if err = utils.AddServiceWatch(c, "tigera-manager", render.ManagerNamespace); err != nil {
+		return fmt.Errorf("manager-controller failed to watch the manager Service: %v", err)
+	}
+
 	if err = utils.AddNetworkWatch(c); err != nil {
 		return fmt.Errorf("manager-controller failed to watch Network resource: %v", err)
 	}
@@ -111,6 +129,8 @@ type ReconcileManager struct {
 	scheme   *runtime.Scheme
 	provider operatorv1.Provider
 	status   *status.StatusManager
+
+	oidcIssuer oidcIssuerCache
 }
 
 // GetManager returns the default manager instance with defaults populated.
@@ -122,14 +142,22 @@ func GetManager(ctx context.Context, cli client.Client) (*operatorv1.Manager, er
 		return nil, err
 	}
 
-	// Populate the instance with defaults for any fields not provided by the user.
-	if instance.Spec.Auth == nil {
+	// Populate the instance with defaults for any fields not provided by the user. The authentication
+	// used with the identity broker is not a default, so that it is not written back to the Manager.
+	if instance.Spec.Auth == nil && instance.Spec.IdentityBroker == nil {
 		instance.Spec.Auth = &operatorv1.Auth{
 			Type:      operatorv1.AuthTypeToken,
 			Authority: "",
 			ClientID:  "",
 		}
 	}
+	if instance.Spec.ClusterName == "" {
+		instance.Spec.ClusterName = "cluster"
+	}
+	if instance.Spec.Replicas == nil {
+		var replicas int32 = 1
+		instance.Spec.Replicas = &replicas
+	}
 	return instance, nil
 }
 
@@ -156,6 +184,17 @@ func (r *ReconcileManager) Reconcile(request reconcile.Request) (reconcile.Resul
 	reqLogger.V(2).Info("Loaded config", "config", instance)
 	r.status.OnCRFound()
 
+	if err = validateCustomResource(instance); err != nil {
+		reqLogger.Error(err, "Invalid Manager configuration")
+		r.status.SetDegraded("Invalid Manager configuration", err.Error())
+		return reconcile.Result{}, nil
+	}
+	if instance.Spec.Exposure != nil && instance.Spec.Exposure.Type == operatorv1.ManagerExposureTypeRoute &&
+		r.provider != operatorv1.ProviderOpenShift {
+		r.status.SetDegraded("Invalid Manager configuration", "exposure type Route is only supported on OpenShift")
+		return reconcile.Result{}, nil
+	}
+
 	// Write the manager back to the datastore.
 	if err = r.client.Update(ctx, instance); err != nil {
 		r.status.SetDegraded("Failed to write defaults", err.Error())
@@ -164,7 +203,8 @@ func (r *ReconcileManager) Reconcile(request reconcile.Request) (reconcile.Resul
 
 	if !utils.IsAPIServerReady(r.client, reqLogger) {
 		r.status.SetDegraded("Waiting for Tigera API server to be ready", "")
-		return reconcile.Result{}, nil
+		// Requeue through the rate limiter so that we back off while the aggregated API starts.
+		return reconcile.Result{Requeue: true}, nil
 	}
 
 	if err = utils.CheckLicenseKey(ctx, r.client); err != nil {
@@ -244,11 +284,16 @@ func (r *ReconcileManager) Reconcile(request reconcile.Request) (reconcile.Resul
 		return reconcile.Result{}, err
 	}
 
-	kibanaPublicCertSecret := &corev1.Secret{}
-	if err := r.client.Get(ctx, types.NamespacedName{Name: render.KibanaPublicCertSecret, Namespace: render.OperatorNamespace()}, kibanaPublicCertSecret); err != nil {
-		reqLogger.Error(err, "Failed to read Kibana public cert secret")
-		r.status.SetDegraded("Failed to read Kibana public cert secret", err.Error())
-		return reconcile.Result{}, err
+	// Kibana is only proxied when it is deployed with Elasticsearch.
+	var kibanaSecrets []*corev1.Secret
+	if esClusterConfig.KibanaEnabled() {
+		kibanaPublicCertSecret := &corev1.Secret{}
+		if err := r.client.Get(ctx, types.NamespacedName{Name: render.KibanaPublicCertSecret, Namespace: render.OperatorNamespace()}, kibanaPublicCertSecret); err != nil {
+			reqLogger.Error(err, "Failed to read Kibana public cert secret")
+			r.status.SetDegraded("Failed to read Kibana public cert secret", err.Error())
+			return reconcile.Result{}, err
+		}
+		kibanaSecrets = append(kibanaSecrets, kibanaPublicCertSecret)
 	}
 
 	oidcConfig, err := getOIDCConfig(ctx, r.client)
@@ -256,11 +301,60 @@ func (r *ReconcileManager) Reconcile(request reconcile.Request) (reconcile.Resul
 		r.status.SetDegraded("OIDC configuration not available, waiting to become available", err.Error())
 		return reconcile.Result{}, nil
 	}
-	if oidcConfig != nil && instance.Spec.Auth.Authority != "" {
+	if oidcConfig != nil && instance.Spec.Auth != nil && instance.Spec.Auth.Authority != "" {
 		r.status.SetDegraded("Both OIDC configuration and Authority cannot be set at the same time", "")
 		return reconcile.Result{}, nil
 	}
 
+	oidcCABundle, err := getOIDCCABundle(ctx, r.client)
+	if err != nil {
+		r.status.SetDegraded("Failed to read the OIDC CA bundle", err.Error())
+		return reconcile.Result{}, err
+	}
+
+	var connectorSecrets map[string]*corev1.Secret
+	var dexTLSSecret *corev1.Secret
+	if instance.Spec.IdentityBroker != nil {
+		connectorSecrets, err = getConnectorSecrets(ctx, r.client, instance.Spec.IdentityBroker)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				r.status.SetDegraded("Identity connector Secret not available yet, waiting until it becomes available", err.Error())
+				return reconcile.Result{}, nil
+			}
+			r.status.SetDegraded("Invalid identity connector Secret", err.Error())
+			return reconcile.Result{}, nil
+		}
+
+		dexTLSSecret, err = utils.ValidateCertPair(r.client, render.DexTLSSecretName, corev1.TLSPrivateKeyKey, corev1.TLSCertKey)
+		if err != nil {
+			r.status.SetDegraded("Error validating the identity broker TLS certificate", err.Error())
+			return reconcile.Result{}, err
+		}
+		if dexTLSSecret == nil {
+			if dexTLSSecret, err = render.CreateDexTLSSecret(); err != nil {
+				r.status.SetDegraded("Error creating the identity broker TLS certificate", err.Error())
+				return reconcile.Result{}, err
+			}
+		}
+
+		// Point the manager at the identity broker. This is not written back to the Manager so that
+		// disabling the broker restores the user's configuration.
+		instance.Spec.Auth = render.DexAuth(instance)
+	} else if err = removeDex(ctx, r.client); err != nil {
+		r.status.SetDegraded("Error removing the identity broker", err.Error())
+		return reconcile.Result{}, err
+	}
+
+	// The identity broker is served by the manager itself, so it cannot be checked before it is running. An issuer
+	// that fails validation is reported once the manager is rendered, so that an identity provider outage does not
+	// hold back unrelated changes.
+	var issuerErr error
+	if instance.Spec.Auth.Type == operatorv1.AuthTypeOIDC && instance.Spec.Auth.Authority != "" && instance.Spec.IdentityBroker == nil {
+		if issuerErr = r.oidcIssuer.validate(ctx, instance.Spec.Auth, oidcCABundle); issuerErr != nil {
+			log.Error(issuerErr, "Failed to validate the OIDC issuer")
+		}
+	}
+
 	var management = installation.Spec.ClusterManagementType == operatorv1.ClusterManagementTypeManagement
 	var tunnelSecret *corev1.Secret
 	if management {
@@ -277,23 +371,59 @@ func (r *ReconcileManager) Reconcile(request reconcile.Request) (reconcile.Resul
 			}
 		}
 	}
+	certs, renew, err := tunnelCertificates(tunnelSecret, instance)
+	if err != nil {
+		r.status.SetDegraded("Invalid management-cluster-connection secret", err.Error())
+		return reconcile.Result{}, nil
+	}
+	r.status.SetCertificates(certs)
+	if tunnelSecret != nil {
+		if err := rotateTunnelCA(ctx, r.client, tunnelSecret, instance, renew); err != nil {
+			r.status.SetDegraded("Error renewing the Voltron tunnel CA", err.Error())
+			return reconcile.Result{}, err
+		}
+	}
 
 	// Create a component handler to manage the rendered component.
 	handler := utils.NewComponentHandler(log, r.client, r.scheme, instance)
 
+	if instance.Spec.IdentityBroker != nil {
+		dex, err := render.Dex(instance, connectorSecrets, dexTLSSecret, pullSecrets, r.provider == operatorv1.ProviderOpenShift, installation.Spec.Registry)
+		if err != nil {
+			log.Error(err, "Error rendering the identity broker")
+			r.status.SetDegraded("Error rendering the identity broker", err.Error())
+			return reconcile.Result{}, err
+		}
+		if err := handler.CreateOrUpdate(ctx, dex, r.status); err != nil {
+			r.status.SetDegraded("Error creating / updating resource", err.Error())
+			return reconcile.Result{}, err
+		}
+	}
+
+	// The manager redirects users back to its external URL after they log in. The address of a LoadBalancer or of a
+	// Route without a host is only known once it has been created, so the manager is rendered again once it is.
+	url, err := managerURL(ctx, r.client, instance)
+	if err != nil {
+		r.status.SetDegraded("Error reading the manager's external address", err.Error())
+		return reconcile.Result{}, err
+	}
+
 	// Render the desired objects from the CRD and create or update them.
 	component, err := render.Manager(
 		instance,
 		esSecrets,
-		[]*corev1.Secret{kibanaPublicCertSecret},
+		kibanaSecrets,
 		esClusterConfig,
 		tlsSecret,
 		pullSecrets,
 		r.provider == operatorv1.ProviderOpenShift,
 		installation.Spec.Registry,
 		oidcConfig,
+		oidcCABundle,
+		dexTLSSecret,
 		management,
 		tunnelSecret,
+		url,
 	)
 	if err != nil {
 		log.Error(err, "Error rendering Manager")
@@ -306,10 +436,32 @@ func (r *ReconcileManager) Reconcile(request reconcile.Request) (reconcile.Resul
 		return reconcile.Result{}, err
 	}
 
+	if err = removeStaleExposure(ctx, r.client, instance, r.provider == operatorv1.ProviderOpenShift); err != nil {
+		r.status.SetDegraded("Error removing the previous manager exposure", err.Error())
+		return reconcile.Result{}, err
+	}
+	if err = removeStaleRoleBindings(ctx, r.client, instance); err != nil {
+		r.status.SetDegraded("Error removing stale manager role bindings", err.Error())
+		return reconcile.Result{}, err
+	}
+	if current, err := managerURL(ctx, r.client, instance); err != nil {
+		r.status.SetDegraded("Error reading the manager's external address", err.Error())
+		return reconcile.Result{}, err
+	} else if current != url {
+		return reconcile.Result{Requeue: true}, nil
+	}
+
+	if issuerErr != nil {
+		r.status.SetDegraded("Failed to validate the OIDC issuer", issuerErr.Error())
+		// The issuer may be temporarily unreachable, check it again later.
+		return reconcile.Result{RequeueAfter: oidcIssuerRetryInterval}, nil
+	}
+
 	// Clear the degraded bit if we've reached this far.
 	r.status.ClearDegraded()
 	if r.status.IsAvailable() {
 		instance.Status.Auth = instance.Spec.Auth
+		instance.Status.URL = url
 		if err = r.client.Status().Update(ctx, instance); err != nil {
 			return reconcile.Result{}, err
 		}
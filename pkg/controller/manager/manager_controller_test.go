@@ -3,6 +3,7 @@ package manager
 import (
 	"context"
 
+	routev1 "github.com/openshift/api/route/v1"
 	"github.com/tigera/operator/pkg/apis"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -10,6 +11,10 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	operatorv1 "github.com/tigera/operator/pkg/apis/operator/v1"
+	"github.com/tigera/operator/pkg/render"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -41,5 +46,167 @@ var _ = Describe("Manager controller tests", func() {
 		Expect(err).NotTo(HaveOccurred())
 		instance, err = GetManager(context.Background(), c)
 		Expect(err).NotTo(HaveOccurred())
+		Expect(instance.Spec.ClusterName).To(Equal("cluster"))
+		Expect(*instance.Spec.Replicas).To(Equal(int32(1)))
+	})
+
+	It("should not write the identity broker authentication back to the manager", func() {
+		ctx := context.Background()
+		instance = &operatorv1.Manager{
+			TypeMeta:   metav1.TypeMeta{Kind: "Manager", APIVersion: "operator.tigera.io/v1"},
+			ObjectMeta: metav1.ObjectMeta{Name: "tigera-secure"},
+			Spec: operatorv1.ManagerSpec{
+				IdentityBroker: &operatorv1.IdentityBroker{ManagerDomain: "https://manager.example.com"},
+			},
+		}
+		Expect(c.Create(ctx, instance)).NotTo(HaveOccurred())
+
+		By("enabling the identity broker")
+		var err error
+		instance, err = GetManager(ctx, c)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(instance.Spec.Auth).To(BeNil())
+		Expect(c.Update(ctx, instance)).NotTo(HaveOccurred())
+		Expect(render.DexAuth(instance).Authority).To(Equal("https://manager.example.com/dex"))
+
+		By("disabling the identity broker")
+		instance.Spec.IdentityBroker = nil
+		Expect(c.Update(ctx, instance)).NotTo(HaveOccurred())
+		instance, err = GetManager(ctx, c)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(instance.Spec.Auth).To(Equal(&operatorv1.Auth{Type: operatorv1.AuthTypeToken}))
+	})
+})
+
+var _ = Describe("Manager exposure tests", func() {
+	var c client.Client
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(apis.AddToScheme(scheme)).NotTo(HaveOccurred())
+		Expect(corev1.AddToScheme(scheme)).NotTo(HaveOccurred())
+		Expect(networkingv1beta1.AddToScheme(scheme)).NotTo(HaveOccurred())
+		c = fake.NewFakeClientWithScheme(scheme)
+	})
+
+	It("should report the address of a LoadBalancer", func() {
+		instance := &operatorv1.Manager{
+			Spec: operatorv1.ManagerSpec{
+				Exposure: &operatorv1.ManagerExposure{Type: operatorv1.ManagerExposureTypeLoadBalancer},
+			},
+		}
+		svc := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "tigera-manager", Namespace: render.ManagerNamespace},
+			Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Port: 9443}}},
+		}
+		Expect(c.Create(context.Background(), svc)).NotTo(HaveOccurred())
+
+		By("waiting for the load balancer to be provisioned")
+		url, err := managerURL(context.Background(), c, instance)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(url).To(Equal(""))
+
+		svc.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: "1.2.3.4"}}
+		Expect(c.Update(context.Background(), svc)).NotTo(HaveOccurred())
+		url, err = managerURL(context.Background(), c, instance)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(url).To(Equal("https://1.2.3.4:9443"))
+	})
+
+	It("should use the Ingress host", func() {
+		instance := &operatorv1.Manager{
+			Spec: operatorv1.ManagerSpec{
+				Exposure: &operatorv1.ManagerExposure{
+					Type:    operatorv1.ManagerExposureTypeIngress,
+					Ingress: &operatorv1.ManagerIngress{Host: "manager.example.com"},
+				},
+			},
+		}
+		url, err := managerURL(context.Background(), c, instance)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(url).To(Equal("https://manager.example.com"))
+	})
+
+	It("should wait for the host of a Route that is not in the cache yet", func() {
+		instance := &operatorv1.Manager{
+			Spec: operatorv1.ManagerSpec{
+				Exposure: &operatorv1.ManagerExposure{Type: operatorv1.ManagerExposureTypeRoute},
+			},
+		}
+		url, err := managerURL(context.Background(), c, instance)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(url).To(Equal(""))
+
+		Expect(c.Create(context.Background(), &routev1.Route{
+			ObjectMeta: metav1.ObjectMeta{Name: "tigera-manager", Namespace: render.ManagerNamespace},
+			Spec:       routev1.RouteSpec{Host: "tigera-manager.apps.example.com"},
+		})).NotTo(HaveOccurred())
+		url, err = managerURL(context.Background(), c, instance)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(url).To(Equal("https://tigera-manager.apps.example.com"))
+	})
+
+	It("should only remove the previous exposure when it was rendered for the Manager", func() {
+		ctx := context.Background()
+		key := client.ObjectKey{Name: "tigera-manager", Namespace: render.ManagerNamespace}
+		instance := &operatorv1.Manager{}
+
+		By("leaving an Ingress created by the user")
+		Expect(c.Create(ctx, &networkingv1beta1.Ingress{ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace}})).NotTo(HaveOccurred())
+		Expect(removeStaleExposure(ctx, c, instance, true)).NotTo(HaveOccurred())
+		Expect(c.Get(ctx, key, &networkingv1beta1.Ingress{})).NotTo(HaveOccurred())
+
+		By("removing the Ingress and Route rendered for the Manager")
+		owner := []metav1.OwnerReference{{APIVersion: "operator.tigera.io/v1", Kind: "Manager", Name: "tigera-secure"}}
+		ingress := &networkingv1beta1.Ingress{}
+		Expect(c.Get(ctx, key, ingress)).NotTo(HaveOccurred())
+		ingress.OwnerReferences = owner
+		Expect(c.Update(ctx, ingress)).NotTo(HaveOccurred())
+		Expect(c.Create(ctx, &routev1.Route{ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace, OwnerReferences: owner}})).NotTo(HaveOccurred())
+		Expect(removeStaleExposure(ctx, c, instance, true)).NotTo(HaveOccurred())
+		Expect(errors.IsNotFound(c.Get(ctx, key, &networkingv1beta1.Ingress{}))).To(BeTrue())
+		Expect(errors.IsNotFound(c.Get(ctx, key, &routev1.Route{}))).To(BeTrue())
+	})
+})
+
+var _ = Describe("Manager identity broker removal", func() {
+	var c client.Client
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(apis.AddToScheme(scheme)).NotTo(HaveOccurred())
+		Expect(corev1.AddToScheme(scheme)).NotTo(HaveOccurred())
+		c = fake.NewFakeClientWithScheme(scheme)
+	})
+
+	namespaceExists := func() bool {
+		err := c.Get(context.Background(), client.ObjectKey{Name: render.DexNamespace}, &corev1.Namespace{})
+		if errors.IsNotFound(err) {
+			return false
+		}
+		Expect(err).NotTo(HaveOccurred())
+		return true
+	}
+
+	It("should do nothing when the identity broker was never enabled", func() {
+		Expect(removeDex(context.Background(), c)).NotTo(HaveOccurred())
+		Expect(namespaceExists()).To(BeFalse())
+	})
+
+	It("should leave a namespace that the operator did not create", func() {
+		Expect(c.Create(context.Background(), &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: render.DexNamespace}})).NotTo(HaveOccurred())
+		Expect(removeDex(context.Background(), c)).NotTo(HaveOccurred())
+		Expect(namespaceExists()).To(BeTrue())
+	})
+
+	It("should remove the namespace rendered for the Manager", func() {
+		Expect(c.Create(context.Background(), &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name: render.DexNamespace,
+			OwnerReferences: []metav1.OwnerReference{
+				{APIVersion: "operator.tigera.io/v1", Kind: "Manager", Name: "tigera-secure"},
+			},
+		}})).NotTo(HaveOccurred())
+		Expect(removeDex(context.Background(), c)).NotTo(HaveOccurred())
+		Expect(namespaceExists()).To(BeFalse())
 	})
 })
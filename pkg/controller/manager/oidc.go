@@ -0,0 +1,169 @@
+// Copyright (c) 2020 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	operatorv1 "github.com/tigera/operator/pkg/apis/operator/v1"
+	"github.com/tigera/operator/pkg/render"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	oidcDiscoveryPath    = "/.well-known/openid-configuration"
+	oidcDiscoveryTimeout = 10 * time.Second
+
+	// oidcIssuerRetryInterval is how long to wait before validating an unreachable or invalid issuer again.
+	oidcIssuerRetryInterval = 30 * time.Second
+)
+
+// oidcDiscoveryDocument holds the fields of the issuer's discovery document that the manager relies on.
+type oidcDiscoveryDocument struct {
+	Issuer          string   `json:"issuer"`
+	JWKSURI         string   `json:"jwks_uri"`
+	ScopesSupported []string `json:"scopes_supported"`
+	ClaimsSupported []string `json:"claims_supported"`
+}
+
+// getOIDCCABundle returns the CA bundle used to verify the OIDC issuer, or nil if none is configured.
+func getOIDCCABundle(ctx context.Context, cli client.Client) (*corev1.ConfigMap, error) {
+	cm := &corev1.ConfigMap{}
+	err := cli.Get(ctx, types.NamespacedName{Name: render.ManagerOIDCCABundle, Namespace: render.OperatorNamespace()}, cm)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if _, ok := cm.Data[render.ManagerOIDCCABundleKey]; !ok {
+		return nil, fmt.Errorf("ConfigMap %s does not contain the key %s", render.ManagerOIDCCABundle, render.ManagerOIDCCABundleKey)
+	}
+	return cm, nil
+}
+
+// validateOIDCIssuer fetches the discovery document of the configured OIDC issuer and checks that
+// it matches the authority and supports the requested scopes and claims.
+func validateOIDCIssuer(ctx context.Context, auth *operatorv1.Auth, caBundle *corev1.ConfigMap) error {
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if caBundle != nil && !pool.AppendCertsFromPEM([]byte(caBundle.Data[render.ManagerOIDCCABundleKey])) {
+		return fmt.Errorf("ConfigMap %s does not contain a valid PEM encoded CA bundle", render.ManagerOIDCCABundle)
+	}
+	httpClient := &http.Client{
+		Timeout: oidcDiscoveryTimeout,
+		Transport: &http.Transport{
+			// Issuers outside the cluster may only be reachable through the egress proxy of the operator.
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: &tls.Config{RootCAs: pool},
+		},
+	}
+
+	issuer := strings.TrimSuffix(auth.Authority, "/")
+	req, err := http.NewRequest(http.MethodGet, issuer+oidcDiscoveryPath, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := httpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to fetch the OIDC discovery document: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch the OIDC discovery document: %s", resp.Status)
+	}
+
+	doc := oidcDiscoveryDocument{}
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return fmt.Errorf("failed to parse the OIDC discovery document: %v", err)
+	}
+
+	if strings.TrimSuffix(doc.Issuer, "/") != issuer {
+		return fmt.Errorf("issuer %q in the OIDC discovery document does not match the authority %q", doc.Issuer, auth.Authority)
+	}
+	if doc.JWKSURI == "" {
+		return fmt.Errorf("the OIDC discovery document does not contain a jwks_uri")
+	}
+
+	// scopes_supported and claims_supported are optional in the discovery document, only
+	// check against them if the issuer advertises them.
+	if len(doc.ScopesSupported) > 0 {
+		for _, scope := range render.OIDCScopes(auth) {
+			if !contains(doc.ScopesSupported, scope) {
+				return fmt.Errorf("scope %q is not supported by the OIDC issuer", scope)
+			}
+		}
+	}
+	if len(doc.ClaimsSupported) > 0 {
+		for _, claim := range []string{auth.UsernameClaim, auth.GroupsClaim} {
+			if claim != "" && !contains(doc.ClaimsSupported, claim) {
+				return fmt.Errorf("claim %q is not supported by the OIDC issuer", claim)
+			}
+		}
+	}
+	return nil
+}
+
+// oidcIssuerCache remembers the outcome of validating the OIDC issuer, so that the discovery document is only fetched
+// again once the issuer configuration or CA bundle changes, or once oidcIssuerRetryInterval has passed after a
+// failure. The manager controller reconciles one request at a time, so the cache is not locked.
+type oidcIssuerCache struct {
+	key     string
+	err     error
+	checked time.Time
+}
+
+// validate returns the cached outcome of validating the issuer, validating it first if needed.
+func (c *oidcIssuerCache) validate(ctx context.Context, auth *operatorv1.Auth, caBundle *corev1.ConfigMap) error {
+	key := oidcIssuerKey(auth, caBundle)
+	if key == c.key && (c.err == nil || time.Since(c.checked) < oidcIssuerRetryInterval) {
+		return c.err
+	}
+	c.key, c.err, c.checked = key, validateOIDCIssuer(ctx, auth, caBundle), time.Now()
+	return c.err
+}
+
+// oidcIssuerKey returns a hash of everything validateOIDCIssuer checks the issuer against.
+func oidcIssuerKey(auth *operatorv1.Auth, caBundle *corev1.ConfigMap) string {
+	var ca string
+	if caBundle != nil {
+		ca = caBundle.Data[render.ManagerOIDCCABundleKey]
+	}
+	return render.AnnotationHash([]string{
+		auth.Authority, strings.Join(render.OIDCScopes(auth), " "), auth.UsernameClaim, auth.GroupsClaim, ca,
+	})
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,262 @@
+// Copyright (c) 2020 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	operatorv1 "github.com/tigera/operator/pkg/apis/operator/v1"
+	"github.com/tigera/operator/pkg/render"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var _ = Describe("Manager OIDC configuration", func() {
+	Context("validateCustomResource", func() {
+		var instance *operatorv1.Manager
+
+		BeforeEach(func() {
+			instance = &operatorv1.Manager{
+				Spec: operatorv1.ManagerSpec{
+					Auth: &operatorv1.Auth{
+						Type:      operatorv1.AuthTypeOIDC,
+						Authority: "https://example.com/dex",
+						ClientID:  "tigera-manager",
+					},
+				},
+			}
+		})
+
+		It("should accept a valid OIDC configuration", func() {
+			instance.Spec.Auth.GroupsClaim = "groups"
+			instance.Spec.Auth.KubernetesAuthentication = operatorv1.KubernetesAuthenticationProxy
+			Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+		})
+
+		It("should reject a non https authority", func() {
+			instance.Spec.Auth.Authority = "http://example.com/dex"
+			Expect(validateCustomResource(instance)).To(HaveOccurred())
+		})
+
+		It("should reject OIDC settings for other auth types", func() {
+			instance.Spec.Auth.Type = operatorv1.AuthTypeToken
+			instance.Spec.Auth.GroupsClaim = "groups"
+			Expect(validateCustomResource(instance)).To(HaveOccurred())
+		})
+
+		It("should require a client ID when the proxy authenticates users", func() {
+			instance.Spec.Auth.ClientID = ""
+			instance.Spec.Auth.KubernetesAuthentication = operatorv1.KubernetesAuthenticationProxy
+			Expect(validateCustomResource(instance)).To(HaveOccurred())
+		})
+	})
+
+	Context("identity broker validation", func() {
+		var instance *operatorv1.Manager
+
+		BeforeEach(func() {
+			instance = &operatorv1.Manager{
+				Spec: operatorv1.ManagerSpec{
+					Auth: &operatorv1.Auth{Type: operatorv1.AuthTypeOIDC},
+					IdentityBroker: &operatorv1.IdentityBroker{
+						ManagerDomain: "https://manager.example.com",
+						Connectors: []operatorv1.IdentityConnector{
+							{
+								Name:       "github",
+								Type:       operatorv1.IdentityConnectorTypeGitHub,
+								SecretName: "github-credentials",
+								GitHub:     &operatorv1.GitHubConnector{},
+							},
+						},
+					},
+				},
+			}
+		})
+
+		It("should accept a valid identity broker configuration", func() {
+			Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+		})
+
+		It("should reject an authority set by the user", func() {
+			instance.Spec.Auth.Authority = "https://example.com"
+			Expect(validateCustomResource(instance)).To(HaveOccurred())
+		})
+
+		It("should reject a connector without the configuration matching its type", func() {
+			instance.Spec.IdentityBroker.Connectors[0].Type = operatorv1.IdentityConnectorTypeLDAP
+			Expect(validateCustomResource(instance)).To(HaveOccurred())
+		})
+
+		It("should validate the identity broker when auth is not set", func() {
+			instance.Spec.Auth = nil
+			Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+
+			instance.Spec.IdentityBroker.Connectors[0].Type = operatorv1.IdentityConnectorTypeLDAP
+			Expect(validateCustomResource(instance)).To(HaveOccurred())
+		})
+
+		It("should use the Ingress host when managerDomain is not set", func() {
+			instance.Spec.IdentityBroker.ManagerDomain = ""
+			Expect(validateCustomResource(instance)).To(HaveOccurred())
+
+			instance.Spec.Exposure = &operatorv1.ManagerExposure{
+				Type:    operatorv1.ManagerExposureTypeIngress,
+				Ingress: &operatorv1.ManagerIngress{Host: "manager.example.com"},
+			}
+			Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+		})
+
+		It("should reject duplicate connector names", func() {
+			instance.Spec.IdentityBroker.Connectors = append(instance.Spec.IdentityBroker.Connectors, instance.Spec.IdentityBroker.Connectors[0])
+			Expect(validateCustomResource(instance)).To(HaveOccurred())
+		})
+	})
+
+	Context("role binding validation", func() {
+		var instance *operatorv1.Manager
+
+		BeforeEach(func() {
+			instance = &operatorv1.Manager{
+				Spec: operatorv1.ManagerSpec{
+					RoleBindings: []operatorv1.UIRoleBinding{
+						{Name: "viewers", Profile: operatorv1.UIRoleProfileViewer, Groups: []string{"everyone"}},
+						{Name: "security", Profile: operatorv1.UIRoleProfileTierEditor, Groups: []string{"secops"}, Tiers: []string{"security"}},
+					},
+				},
+			}
+		})
+
+		It("should accept valid role bindings", func() {
+			Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+		})
+
+		It("should require tiers only for the tier editor profile", func() {
+			instance.Spec.RoleBindings[0].Tiers = []string{"security"}
+			Expect(validateCustomResource(instance)).To(HaveOccurred())
+
+			instance.Spec.RoleBindings[0].Tiers = nil
+			instance.Spec.RoleBindings[1].Tiers = nil
+			Expect(validateCustomResource(instance)).To(HaveOccurred())
+		})
+
+		It("should require a group or user", func() {
+			instance.Spec.RoleBindings[0].Groups = nil
+			Expect(validateCustomResource(instance)).To(HaveOccurred())
+		})
+
+		It("should reject invalid and duplicate names", func() {
+			instance.Spec.RoleBindings[0].Name = "Viewers"
+			Expect(validateCustomResource(instance)).To(HaveOccurred())
+
+			instance.Spec.RoleBindings[0].Name = "security"
+			Expect(validateCustomResource(instance)).To(HaveOccurred())
+		})
+
+		It("should reject bindings that generate conflicting resource names", func() {
+			instance.Spec.RoleBindings[0].Name = "security-security"
+			Expect(validateCustomResource(instance)).To(HaveOccurred())
+		})
+	})
+
+	Context("validateOIDCIssuer", func() {
+		var server *httptest.Server
+		var doc oidcDiscoveryDocument
+		var caBundle *corev1.ConfigMap
+		var auth *operatorv1.Auth
+		var fetches int
+
+		BeforeEach(func() {
+			fetches = 0
+			server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fetches++
+				if r.URL.Path != oidcDiscoveryPath {
+					http.NotFound(w, r)
+					return
+				}
+				_ = json.NewEncoder(w).Encode(doc)
+			}))
+			doc = oidcDiscoveryDocument{
+				Issuer:          server.URL,
+				JWKSURI:         server.URL + "/keys",
+				ScopesSupported: []string{"openid", "email", "profile", "groups"},
+				ClaimsSupported: []string{"sub", "email", "groups"},
+			}
+			caBundle = &corev1.ConfigMap{
+				Data: map[string]string{
+					render.ManagerOIDCCABundleKey: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})),
+				},
+			}
+			auth = &operatorv1.Auth{
+				Type:        operatorv1.AuthTypeOIDC,
+				Authority:   server.URL + "/",
+				ClientID:    "tigera-manager",
+				GroupsClaim: "groups",
+			}
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("should accept an issuer matching the configuration", func() {
+			Expect(validateOIDCIssuer(context.Background(), auth, caBundle)).NotTo(HaveOccurred())
+		})
+
+		It("should fail if the issuer certificate is not trusted", func() {
+			Expect(validateOIDCIssuer(context.Background(), auth, nil)).To(HaveOccurred())
+		})
+
+		It("should fail if the issuer does not match the authority", func() {
+			doc.Issuer = "https://other.example.com"
+			Expect(validateOIDCIssuer(context.Background(), auth, caBundle)).To(HaveOccurred())
+		})
+
+		It("should fail if a requested scope is not supported", func() {
+			auth.Scopes = []string{"offline_access"}
+			Expect(validateOIDCIssuer(context.Background(), auth, caBundle)).To(HaveOccurred())
+		})
+
+		It("should fail if the groups claim is not supported", func() {
+			auth.GroupsClaim = "roles"
+			Expect(validateOIDCIssuer(context.Background(), auth, caBundle)).To(HaveOccurred())
+		})
+
+		It("should only fetch the discovery document again when the configuration changes", func() {
+			cache := oidcIssuerCache{}
+			Expect(cache.validate(context.Background(), auth, caBundle)).NotTo(HaveOccurred())
+			Expect(cache.validate(context.Background(), auth, caBundle)).NotTo(HaveOccurred())
+			Expect(fetches).To(Equal(1))
+
+			By("validating a changed configuration")
+			auth.GroupsClaim = "roles"
+			Expect(cache.validate(context.Background(), auth, caBundle)).To(HaveOccurred())
+			Expect(fetches).To(Equal(2))
+
+			By("reporting the failure until the retry interval has passed")
+			Expect(cache.validate(context.Background(), auth, caBundle)).To(HaveOccurred())
+			Expect(fetches).To(Equal(2))
+			cache.checked = cache.checked.Add(-oidcIssuerRetryInterval)
+			doc.ClaimsSupported = append(doc.ClaimsSupported, "roles")
+			Expect(cache.validate(context.Background(), auth, caBundle)).NotTo(HaveOccurred())
+			Expect(fetches).To(Equal(3))
+		})
+	})
+})
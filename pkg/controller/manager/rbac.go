@@ -0,0 +1,63 @@
+// Copyright (c) 2020 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"context"
+
+	operatorv1 "github.com/tigera/operator/pkg/apis/operator/v1"
+	"github.com/tigera/operator/pkg/render"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// removeStaleRoleBindings deletes the ClusterRoleBindings and tier editor ClusterRoles that were rendered for
+// manager role bindings that have since been removed or changed.
+func removeStaleRoleBindings(ctx context.Context, cli client.Client, instance *operatorv1.Manager) error {
+	bindings := rbacv1.ClusterRoleBindingList{}
+	if err := cli.List(ctx, &bindings); err != nil {
+		return err
+	}
+	desiredBindings := render.UIRoleBindingNames(instance)
+	for i := range bindings.Items {
+		crb := &bindings.Items[i]
+		if _, ok := crb.Labels[render.UIRoleBindingLabel]; !ok || desiredBindings[crb.Name] {
+			continue
+		}
+		log.Info("Removing manager role binding", "clusterrolebinding", crb.Name)
+		if err := cli.Delete(ctx, crb); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	roles := rbacv1.ClusterRoleList{}
+	if err := cli.List(ctx, &roles); err != nil {
+		return err
+	}
+	desiredRoles := render.UITierRoleNames(instance)
+	for i := range roles.Items {
+		cr := &roles.Items[i]
+		if _, ok := cr.Labels[render.UITierLabel]; !ok || desiredRoles[cr.Name] {
+			continue
+		}
+		log.Info("Removing tier editor role", "clusterrole", cr.Name)
+		if err := cli.Delete(ctx, cr); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+// Copyright (c) 2020 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"context"
+	"time"
+
+	operatorv1 "github.com/tigera/operator/pkg/apis/operator/v1"
+	"github.com/tigera/operator/pkg/render"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// tunnelCertificates returns the certificate status to report for the Voltron tunnel CA and whether the CA must
+// be renewed. Only a CA generated by the operator is renewed, a CA provided by the user is left untouched.
+func tunnelCertificates(tunnelSecret *corev1.Secret, instance *operatorv1.Manager) ([]operatorv1.CertificateStatus, bool, error) {
+	if tunnelSecret == nil {
+		return nil, false, nil
+	}
+	notAfter, err := render.VoltronTunnelCAExpiry(tunnelSecret)
+	if err != nil {
+		return nil, false, err
+	}
+	certs := []operatorv1.CertificateStatus{
+		{Name: render.VoltronTunnelSecretName, NotAfter: metav1.NewTime(notAfter)},
+	}
+	renew := metav1.IsControlledBy(tunnelSecret, instance) && time.Now().Add(render.TunnelCertificateRenewBefore).After(notAfter)
+	return certs, renew, nil
+}
+
+// rotateTunnelCA renews the Voltron tunnel CA when it is due and retires the previous CA once it is no longer in
+// use. The two CAs overlap so that the managed clusters stay connected throughout, see render.RenewVoltronTunnelCA.
+// The secret is updated in place. Like renewal, the rotation only applies to a CA generated by the operator.
+func rotateTunnelCA(ctx context.Context, cli client.Client, tunnelSecret *corev1.Secret, instance *operatorv1.Manager, renew bool) error {
+	if !metav1.IsControlledBy(tunnelSecret, instance) {
+		return nil
+	}
+	if !render.IsVoltronTunnelCARenewing(tunnelSecret) {
+		if !renew {
+			return nil
+		}
+		// The managedcluster controller reissues the certificates of the managed clusters with the new CA.
+		log.Info("Renewing the Voltron tunnel CA ahead of expiry")
+		if err := render.RenewVoltronTunnelCA(tunnelSecret); err != nil {
+			return err
+		}
+		return cli.Update(ctx, tunnelSecret)
+	}
+
+	retire, err := previousTunnelCAUnused(ctx, cli, tunnelSecret)
+	if err != nil || !retire {
+		return err
+	}
+	log.Info("Retiring the previous Voltron tunnel CA")
+	render.RetireVoltronTunnelCA(tunnelSecret)
+	return cli.Update(ctx, tunnelSecret)
+}
+
+// previousTunnelCAUnused returns true once every managed cluster has switched to a certificate issued by the new
+// Voltron CA, or once the previous CA has expired and no managed cluster can use it anymore.
+func previousTunnelCAUnused(ctx context.Context, cli client.Client, tunnelSecret *corev1.Secret) (bool, error) {
+	notAfter, err := render.VoltronTunnelCAExpiry(tunnelSecret)
+	if err != nil {
+		return false, err
+	}
+	if time.Now().After(notAfter) {
+		return true, nil
+	}
+
+	clusters := operatorv1.ManagedClusterList{}
+	if err := cli.List(ctx, &clusters); err != nil {
+		return false, err
+	}
+	for _, mc := range clusters.Items {
+		secret := &corev1.Secret{}
+		err := cli.Get(ctx, types.NamespacedName{Name: render.ManagedClusterSecretName(mc.Name), Namespace: render.OperatorNamespace()}, secret)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				// Not registered yet, it will be issued a certificate by the new CA.
+				continue
+			}
+			return false, err
+		}
+		if !render.IsManagedClusterCertificateCurrent(secret.Data[render.ManagedClusterCertKey], tunnelSecret) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,123 @@
+// Copyright (c) 2020 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/tigera/operator/pkg/apis"
+	operatorv1 "github.com/tigera/operator/pkg/apis/operator/v1"
+	"github.com/tigera/operator/pkg/render"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Voltron tunnel CA rotation", func() {
+	var c client.Client
+	var ctx context.Context
+	var instance *operatorv1.Manager
+	var tunnelSecret *corev1.Secret
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(apis.AddToScheme(scheme)).NotTo(HaveOccurred())
+		Expect(corev1.AddToScheme(scheme)).NotTo(HaveOccurred())
+		c = fake.NewFakeClientWithScheme(scheme)
+		ctx = context.Background()
+
+		instance = &operatorv1.Manager{
+			TypeMeta:   metav1.TypeMeta{Kind: "Manager", APIVersion: "operator.tigera.io/v1"},
+			ObjectMeta: metav1.ObjectMeta{Name: "tigera-secure", UID: "manager-uid"},
+			Spec:       operatorv1.ManagerSpec{Auth: &operatorv1.Auth{Type: operatorv1.AuthTypeBasic}},
+		}
+		component, err := render.Manager(instance, nil, nil, render.NewElasticsearchClusterConfig("cluster", 1, 1),
+			nil, nil, false, "", nil, nil, nil, true, nil, "")
+		Expect(err).NotTo(HaveOccurred())
+		for _, obj := range component.Objects() {
+			if s, ok := obj.(*corev1.Secret); ok && s.Name == render.VoltronTunnelSecretName && s.Namespace == render.OperatorNamespace() {
+				tunnelSecret = s
+			}
+		}
+		Expect(tunnelSecret).NotTo(BeNil())
+		controller := true
+		tunnelSecret.OwnerReferences = []metav1.OwnerReference{{
+			APIVersion: "operator.tigera.io/v1",
+			Kind:       "Manager",
+			Name:       instance.Name,
+			UID:        instance.UID,
+			Controller: &controller,
+		}}
+		Expect(c.Create(ctx, tunnelSecret)).To(Succeed())
+	})
+
+	createManagedCluster := func(name string, cert []byte) {
+		Expect(c.Create(ctx, &operatorv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{Name: name}})).To(Succeed())
+		Expect(c.Create(ctx, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: render.ManagedClusterSecretName(name), Namespace: render.OperatorNamespace()},
+			Data:       map[string][]byte{render.ManagedClusterCertKey: cert},
+		})).To(Succeed())
+	}
+
+	It("should leave a CA that is not due for renewal alone", func() {
+		cert := tunnelSecret.Data["cert"]
+		Expect(rotateTunnelCA(ctx, c, tunnelSecret, instance, false)).To(Succeed())
+		Expect(render.IsVoltronTunnelCARenewing(tunnelSecret)).To(BeFalse())
+		Expect(tunnelSecret.Data["cert"]).To(Equal(cert))
+	})
+
+	It("should leave a CA provided by the user alone", func() {
+		tunnelSecret.OwnerReferences = nil
+		Expect(rotateTunnelCA(ctx, c, tunnelSecret, instance, true)).To(Succeed())
+		Expect(render.IsVoltronTunnelCARenewing(tunnelSecret)).To(BeFalse())
+	})
+
+	It("should retire the previous CA once every managed cluster uses a certificate of the new CA", func() {
+		_, oldCert, err := render.CreateManagedClusterCertificate("cluster-a", tunnelSecret)
+		Expect(err).NotTo(HaveOccurred())
+		createManagedCluster("cluster-a", oldCert)
+
+		By("staging a new CA next to the current one")
+		Expect(rotateTunnelCA(ctx, c, tunnelSecret, instance, true)).To(Succeed())
+		stored := &corev1.Secret{}
+		Expect(c.Get(ctx, client.ObjectKey{Name: render.VoltronTunnelSecretName, Namespace: render.OperatorNamespace()}, stored)).To(Succeed())
+		Expect(render.IsVoltronTunnelCARenewing(stored)).To(BeTrue())
+
+		By("keeping the previous CA while a managed cluster still uses it")
+		Expect(rotateTunnelCA(ctx, c, stored, instance, true)).To(Succeed())
+		Expect(render.IsVoltronTunnelCARenewing(stored)).To(BeTrue())
+		Expect(render.IsManagedClusterCertificateValid(oldCert, stored)).To(BeTrue())
+
+		By("retiring the previous CA once the managed cluster switched over")
+		_, newCert, err := render.CreateManagedClusterCertificate("cluster-a", stored)
+		Expect(err).NotTo(HaveOccurred())
+		mcSecret := &corev1.Secret{}
+		Expect(c.Get(ctx, client.ObjectKey{Name: render.ManagedClusterSecretName("cluster-a"), Namespace: render.OperatorNamespace()}, mcSecret)).To(Succeed())
+		mcSecret.Data[render.ManagedClusterCertKey] = newCert
+		Expect(c.Update(ctx, mcSecret)).To(Succeed())
+
+		Expect(rotateTunnelCA(ctx, c, stored, instance, true)).To(Succeed())
+		Expect(c.Get(ctx, client.ObjectKey{Name: render.VoltronTunnelSecretName, Namespace: render.OperatorNamespace()}, stored)).To(Succeed())
+		Expect(render.IsVoltronTunnelCARenewing(stored)).To(BeFalse())
+		Expect(render.IsManagedClusterCertificateValid(oldCert, stored)).To(BeFalse())
+		Expect(render.IsManagedClusterCertificateValid(newCert, stored)).To(BeTrue())
+	})
+})
@@ -0,0 +1,202 @@
+// Copyright (c) 2020 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	operatorv1 "github.com/tigera/operator/pkg/apis/operator/v1"
+	"github.com/tigera/operator/pkg/render"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// validateCustomResource validates that the given custom resource is correct. This
+// should be called before rendering objects.
+func validateCustomResource(instance *operatorv1.Manager) error {
+	if err := validateExposure(instance.Spec.Exposure); err != nil {
+		return err
+	}
+	if err := validateRoleBindings(instance); err != nil {
+		return err
+	}
+
+	auth := instance.Spec.Auth
+	if instance.Spec.IdentityBroker != nil {
+		if err := validateIdentityBroker(instance); err != nil {
+			return err
+		}
+		if auth != nil && auth.Type != operatorv1.AuthTypeOIDC {
+			return fmt.Errorf("auth type must be %s when the identity broker is enabled", operatorv1.AuthTypeOIDC)
+		}
+		if auth != nil && (auth.Authority != "" || auth.ClientID != "") {
+			return fmt.Errorf("authority and clientID are configured automatically and must not be set when the identity broker is enabled")
+		}
+	}
+	if auth == nil {
+		return nil
+	}
+
+	if auth.Type != operatorv1.AuthTypeOIDC {
+		if len(auth.Scopes) > 0 || auth.UsernameClaim != "" || auth.UsernamePrefix != "" ||
+			auth.GroupsClaim != "" || auth.GroupsPrefix != "" || auth.KubernetesAuthentication != "" {
+			return fmt.Errorf("scopes, claims, prefixes and kubernetesAuthentication can only be set when the auth type is %s", operatorv1.AuthTypeOIDC)
+		}
+		return nil
+	}
+
+	if auth.Authority != "" {
+		u, err := url.Parse(auth.Authority)
+		if err != nil {
+			return fmt.Errorf("authority %q is not a valid URL: %v", auth.Authority, err)
+		}
+		if u.Scheme != "https" || u.Host == "" {
+			return fmt.Errorf("authority %q must be an https URL", auth.Authority)
+		}
+	}
+
+	if auth.KubernetesAuthentication == operatorv1.KubernetesAuthenticationProxy {
+		if auth.Authority == "" || auth.ClientID == "" {
+			return fmt.Errorf("authority and clientID must be set when kubernetesAuthentication is %s", operatorv1.KubernetesAuthenticationProxy)
+		}
+	}
+	return nil
+}
+
+// validateIdentityBroker validates the identity broker configuration.
+func validateIdentityBroker(instance *operatorv1.Manager) error {
+	broker := instance.Spec.IdentityBroker
+	if broker.ManagerDomain == "" && render.ManagerExternalURL(instance) == "" {
+		return fmt.Errorf("managerDomain must be set unless the manager is exposed through an Ingress or a Route with a host")
+	}
+	if broker.ManagerDomain != "" {
+		u, err := url.Parse(broker.ManagerDomain)
+		if err != nil {
+			return fmt.Errorf("managerDomain %q is not a valid URL: %v", broker.ManagerDomain, err)
+		}
+		if u.Scheme != "https" || u.Host == "" {
+			return fmt.Errorf("managerDomain %q must be an https URL", broker.ManagerDomain)
+		}
+	}
+	return validateConnectors(broker.Connectors)
+}
+
+// validateConnectors validates the identity broker connectors.
+func validateConnectors(connectors []operatorv1.IdentityConnector) error {
+	if len(connectors) == 0 {
+		return fmt.Errorf("the identity broker requires at least one connector")
+	}
+	names := map[string]bool{}
+	for _, conn := range connectors {
+		if names[conn.Name] {
+			return fmt.Errorf("identity connector %s is configured more than once", conn.Name)
+		}
+		names[conn.Name] = true
+
+		if conn.SecretName == "" {
+			return fmt.Errorf("identity connector %s must specify secretName", conn.Name)
+		}
+		var configured bool
+		switch conn.Type {
+		case operatorv1.IdentityConnectorTypeLDAP:
+			configured = conn.LDAP != nil && conn.SAML == nil && conn.GitHub == nil
+		case operatorv1.IdentityConnectorTypeSAML:
+			configured = conn.SAML != nil && conn.LDAP == nil && conn.GitHub == nil
+		case operatorv1.IdentityConnectorTypeGitHub:
+			configured = conn.GitHub != nil && conn.LDAP == nil && conn.SAML == nil
+		default:
+			return fmt.Errorf("identity connector %s has unsupported type %s", conn.Name, conn.Type)
+		}
+		if !configured {
+			return fmt.Errorf("identity connector %s must only set the configuration matching its type %s", conn.Name, conn.Type)
+		}
+	}
+	return nil
+}
+
+// validateExposure validates the manager exposure configuration.
+func validateExposure(e *operatorv1.ManagerExposure) error {
+	if e == nil {
+		return nil
+	}
+	if e.Type != operatorv1.ManagerExposureTypeIngress && e.Ingress != nil {
+		return fmt.Errorf("exposure ingress can only be set when the exposure type is %s", operatorv1.ManagerExposureTypeIngress)
+	}
+	if e.Type != operatorv1.ManagerExposureTypeRoute && e.Route != nil {
+		return fmt.Errorf("exposure route can only be set when the exposure type is %s", operatorv1.ManagerExposureTypeRoute)
+	}
+	if e.NodePort != nil && e.Type != operatorv1.ManagerExposureTypeNodePort && e.Type != operatorv1.ManagerExposureTypeLoadBalancer {
+		return fmt.Errorf("exposure nodePort can only be set when the exposure type is %s or %s",
+			operatorv1.ManagerExposureTypeNodePort, operatorv1.ManagerExposureTypeLoadBalancer)
+	}
+	if e.Type == operatorv1.ManagerExposureTypeIngress && (e.Ingress == nil || e.Ingress.Host == "") {
+		return fmt.Errorf("exposure ingress host must be set when the exposure type is %s", operatorv1.ManagerExposureTypeIngress)
+	}
+	return nil
+}
+
+// validateRoleBindings validates the manager role bindings.
+func validateRoleBindings(instance *operatorv1.Manager) error {
+	names := map[string]bool{}
+	expectedBindings := 0
+	for _, rb := range instance.Spec.RoleBindings {
+		if errs := validation.IsDNS1123Label(rb.Name); len(errs) > 0 {
+			return fmt.Errorf("role binding name %q is invalid: %s", rb.Name, strings.Join(errs, ", "))
+		}
+		if names[rb.Name] {
+			return fmt.Errorf("role binding %s is configured more than once", rb.Name)
+		}
+		names[rb.Name] = true
+
+		if len(rb.Groups) == 0 && len(rb.Users) == 0 {
+			return fmt.Errorf("role binding %s must specify at least one group or user", rb.Name)
+		}
+		expectedBindings++
+		switch rb.Profile {
+		case operatorv1.UIRoleProfileViewer, operatorv1.UIRoleProfileNetworkAdmin:
+		case operatorv1.UIRoleProfileComplianceAuditor:
+			expectedBindings++
+		case operatorv1.UIRoleProfileTierEditor:
+			if len(rb.Tiers) == 0 {
+				return fmt.Errorf("role binding %s must specify tiers for the %s profile", rb.Name, rb.Profile)
+			}
+			tiers := map[string]bool{}
+			for _, tier := range rb.Tiers {
+				if errs := validation.IsDNS1123Label(tier); len(errs) > 0 {
+					return fmt.Errorf("role binding %s tier %q is invalid: %s", rb.Name, tier, strings.Join(errs, ", "))
+				}
+				if tiers[tier] {
+					return fmt.Errorf("role binding %s lists tier %s more than once", rb.Name, tier)
+				}
+				tiers[tier] = true
+			}
+			expectedBindings += len(rb.Tiers)
+			continue
+		default:
+			return fmt.Errorf("role binding %s has unsupported profile %s", rb.Name, rb.Profile)
+		}
+		if len(rb.Tiers) > 0 {
+			return fmt.Errorf("role binding %s can only specify tiers for the %s profile", rb.Name, operatorv1.UIRoleProfileTierEditor)
+		}
+	}
+	// The generated ClusterRoleBinding names are derived from the binding names and tiers, so e.g. binding
+	// "a" with tier "b" and binding "a-b" would collide.
+	if len(render.UIRoleBindingNames(instance)) != expectedBindings {
+		return fmt.Errorf("role binding names and tiers produce conflicting ClusterRoleBinding names; rename the role bindings")
+	}
+	return nil
+}
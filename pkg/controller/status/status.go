@@ -51,9 +51,16 @@ type StatusManager struct {
 	explicitDegradedMsg    string
 	explicitDegradedReason string
 
+	// Track progressing state as set by external controllers.
+	explicitProgressingMsg    string
+	explicitProgressingReason string
+
 	// Keep track of currently calculated status.
 	progressing []string
 	failing     []string
+
+	// Certificates reported on the TigeraStatus.
+	certificates []operator.CertificateStatus
 }
 
 func New(client client.Client, component string) *StatusManager {
@@ -87,7 +94,7 @@ func (m *StatusManager) Run() {
 			}
 
 			if m.IsProgressing() {
-				m.setProgressing("Not all pods are ready", m.progressingMessage())
+				m.setProgressing(m.progressingReason(), m.progressingMessage())
 			} else {
 				m.clearProgressing()
 			}
@@ -116,6 +123,7 @@ func (m *StatusManager) OnCRFound() {
 // status manager will clear its state.
 func (m *StatusManager) OnCRNotFound() {
 	m.ClearDegraded()
+	m.ClearProgressing()
 	m.clearAvailable()
 	m.clearProgressing()
 	m.lock.Lock()
@@ -127,6 +135,7 @@ func (m *StatusManager) OnCRNotFound() {
 	m.deployments = []types.NamespacedName{}
 	m.statefulsets = []types.NamespacedName{}
 	m.cronjobs = []types.NamespacedName{}
+	m.certificates = nil
 }
 
 // SetDaemonsets tells the status manager to monitor the health of the given daemonsets.
@@ -157,6 +166,13 @@ func (m *StatusManager) SetCronJobs(cj []types.NamespacedName) {
 	m.cronjobs = cj
 }
 
+// SetCertificates tells the status manager to report the expiry of the given certificates.
+func (m *StatusManager) SetCertificates(certs []operator.CertificateStatus) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.certificates = certs
+}
+
 // SetDegraded sets degraded state with the provided reason and message.
 func (m *StatusManager) SetDegraded(reason, msg string) {
 	m.lock.Lock()
@@ -175,6 +191,23 @@ func (m *StatusManager) ClearDegraded() {
 	m.explicitDegradedMsg = ""
 }
 
+// SetProgressing sets progressing state with the provided reason and message, for state changes that are not
+// reflected by the pods of the component.
+func (m *StatusManager) SetProgressing(reason, msg string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.explicitProgressingReason = reason
+	m.explicitProgressingMsg = msg
+}
+
+// ClearProgressing clears progressing state set with SetProgressing.
+func (m *StatusManager) ClearProgressing() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.explicitProgressingReason = ""
+	m.explicitProgressingMsg = ""
+}
+
 // IsAvailable returns true if the component is available and false otherwise.
 func (m *StatusManager) IsAvailable() bool {
 	m.lock.Lock()
@@ -192,6 +225,11 @@ func (m *StatusManager) IsProgressing() bool {
 	m.lock.Lock()
 	defer m.lock.Unlock()
 
+	// Controllers can explicitly set us progressing.
+	if m.explicitProgressingReason != "" {
+		return true
+	}
+
 	if m.progressing == nil || m.failing == nil {
 		// We haven't learned our state yet. Return false.
 		return false
@@ -420,8 +458,11 @@ func (m *StatusManager) set(conditions ...operator.TigeraStatusCondition) {
 		}
 	}
 
+	ts.Status.Certificates = m.certificates
+
 	// If nothing has changed, we don't need to update in the API.
-	if reflect.DeepEqual(ts.Status.Conditions, old.Status.Conditions) {
+	if reflect.DeepEqual(ts.Status.Conditions, old.Status.Conditions) &&
+		certificatesEqual(ts.Status.Certificates, old.Status.Certificates) {
 		return
 	}
 
@@ -436,6 +477,20 @@ func (m *StatusManager) set(conditions ...operator.TigeraStatusCondition) {
 	}
 }
 
+// certificatesEqual compares certificate expiries by instant, since expiries read back from the API are not in
+// the location the certificate was parsed in.
+func certificatesEqual(a, b []operator.CertificateStatus) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Name != b[i].Name || !a[i].NotAfter.Equal(&b[i].NotAfter) {
+			return false
+		}
+	}
+	return true
+}
+
 func (m *StatusManager) setAvailable(reason, msg string) {
 	m.lock.Lock()
 	defer m.lock.Unlock()
@@ -500,7 +555,27 @@ func (m *StatusManager) clearAvailable() {
 func (m *StatusManager) progressingMessage() string {
 	m.lock.Lock()
 	defer m.lock.Unlock()
-	return strings.Join(m.progressing, "\n")
+	msgs := []string{}
+	if m.explicitProgressingMsg != "" {
+		msgs = append(msgs, m.explicitProgressingMsg)
+	}
+	if len(m.failing) == 0 {
+		msgs = append(msgs, m.progressing...)
+	}
+	return strings.Join(msgs, "\n")
+}
+
+func (m *StatusManager) progressingReason() string {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	reasons := []string{}
+	if m.explicitProgressingReason != "" {
+		reasons = append(reasons, m.explicitProgressingReason)
+	}
+	if len(m.progressing) != 0 && len(m.failing) == 0 {
+		reasons = append(reasons, "Not all pods are ready")
+	}
+	return strings.Join(reasons, "; ")
 }
 
 func (m *StatusManager) degradedMessage() string {
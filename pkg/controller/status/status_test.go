@@ -15,11 +15,17 @@
 package status
 
 import (
+	"context"
+	"time"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 
 	"github.com/tigera/operator/pkg/apis"
+	operator "github.com/tigera/operator/pkg/apis/operator/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
@@ -104,4 +110,42 @@ var _ = Describe("Status reporting tests", func() {
 		Expect(sm.degradedMessage()).To(Equal("Controller set us degraded\nThis pod has died"))
 	})
 
+	It("should generate correct progressing reasons and messages", func() {
+		sm.failing = []string{}
+		sm.progressing = []string{}
+		Expect(sm.IsProgressing()).To(BeFalse())
+
+		By("Setting a progressing state explicitly")
+		sm.SetProgressing("Draining nodes", "Moving shards off node-1")
+		Expect(sm.IsProgressing()).To(BeTrue())
+		Expect(sm.IsAvailable()).To(BeTrue())
+		Expect(sm.progressingReason()).To(Equal("Draining nodes"))
+		Expect(sm.progressingMessage()).To(Equal("Moving shards off node-1"))
+
+		By("Adding pods that are not ready")
+		sm.progressing = []string{"Pod node-2 is not ready"}
+		Expect(sm.progressingReason()).To(Equal("Draining nodes; Not all pods are ready"))
+		Expect(sm.progressingMessage()).To(Equal("Moving shards off node-1\nPod node-2 is not ready"))
+
+		By("Clearing the explicit progressing state")
+		sm.ClearProgressing()
+		sm.progressing = []string{}
+		Expect(sm.IsProgressing()).To(BeFalse())
+	})
+
+	It("should report certificate expiry", func() {
+		notAfter := metav1.NewTime(time.Now().Add(time.Hour).Truncate(time.Second))
+		sm.SetCertificates([]operator.CertificateStatus{{Name: "test-cert", NotAfter: notAfter}})
+		sm.setAvailable("All objects available", "")
+
+		ts := &operator.TigeraStatus{}
+		Expect(client.Get(context.Background(), types.NamespacedName{Name: "test-component"}, ts)).To(Succeed())
+		Expect(ts.Status.Certificates).To(HaveLen(1))
+		Expect(ts.Status.Certificates[0].Name).To(Equal("test-cert"))
+		Expect(ts.Status.Certificates[0].NotAfter.Equal(&notAfter)).To(BeTrue())
+
+		By("clearing the certificates once the CR is gone")
+		sm.OnCRNotFound()
+		Expect(sm.certificates).To(BeNil())
+	})
 })
@@ -10,11 +10,14 @@ import (
 	esalpha1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1alpha1"
 	kibanaalpha1 "github.com/elastic/cloud-on-k8s/pkg/apis/kibana/v1alpha1"
 	"github.com/go-logr/logr"
+	routev1 "github.com/openshift/api/route/v1"
+	v3 "github.com/tigera/api/pkg/apis/projectcalico/v3"
 	apps "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -59,7 +62,7 @@ func (c componentHandler) CreateOrUpdate(ctx context.Context, component render.C
 	statefulsets := []types.NamespacedName{}
 	for _, obj := range component.Objects() {
 		// Set CR instance as the owner and controller.
-		if err := controllerutil.SetControllerReference(c.cr, obj.(metav1.ObjectMetaAccessor).GetObjectMeta(), c.scheme); err != nil {
+		if err := controllerutil.SetControllerReference(c.cr, obj.(metav1.Object), c.scheme); err != nil {
 			return err
 		}
 
@@ -139,8 +142,8 @@ func (c componentHandler) CreateOrUpdate(ctx context.Context, component render.C
 
 // mergeState returns the object to pass to Update given the current and desired object states.
 func mergeState(desired, current runtime.Object) runtime.Object {
-	currentMeta := current.(metav1.ObjectMetaAccessor).GetObjectMeta()
-	desiredMeta := desired.(metav1.ObjectMetaAccessor).GetObjectMeta()
+	currentMeta := current.(metav1.Object)
+	desiredMeta := desired.(metav1.Object)
 
 	// Merge common metadata fields.
 	desiredMeta.SetResourceVersion(currentMeta.GetResourceVersion())
@@ -154,7 +157,44 @@ func mergeState(desired, current runtime.Object) runtime.Object {
 		cs := current.(*v1.Service)
 		ds := desired.(*v1.Service)
 		ds.Spec.ClusterIP = cs.Spec.ClusterIP
+		// Keep the node ports allocated by Kubernetes, otherwise a new port is allocated on every update.
+		for i, dp := range ds.Spec.Ports {
+			if dp.NodePort != 0 || ds.Spec.Type == v1.ServiceTypeClusterIP || ds.Spec.Type == "" {
+				continue
+			}
+			for _, cp := range cs.Spec.Ports {
+				if cp.Port == dp.Port {
+					ds.Spec.Ports[i].NodePort = cp.NodePort
+				}
+			}
+		}
 		return ds
+	case *v1.PersistentVolumeClaim:
+		// The spec of a claim is immutable once it is bound, except that its storage request can grow.
+		cc := current.(*v1.PersistentVolumeClaim)
+		dc := desired.(*v1.PersistentVolumeClaim)
+		size := dc.Spec.Resources.Requests[v1.ResourceStorage]
+		dc.Spec = cc.Spec
+		if current, ok := cc.Spec.Resources.Requests[v1.ResourceStorage]; ok && size.Cmp(current) > 0 {
+			dc.Spec.Resources.Requests[v1.ResourceStorage] = size
+		}
+		return dc
+	case *routev1.Route:
+		// OpenShift generates the host of a Route if none is given, keep it on updates.
+		cr := current.(*routev1.Route)
+		dr := desired.(*routev1.Route)
+		if dr.Spec.Host == "" {
+			dr.Spec.Host = cr.Spec.Host
+		}
+		return dr
+	case *v3.ManagedCluster:
+		// The installation manifest and connection status of a Calico ManagedCluster are maintained by the
+		// Tigera API server and Voltron, only its metadata is managed by the operator.
+		cm := current.(*v3.ManagedCluster)
+		dm := desired.(*v3.ManagedCluster)
+		dm.Spec = cm.Spec
+		dm.Status = cm.Status
+		return dm
 	case *batchv1.Job:
 		cj := current.(*batchv1.Job)
 		dj := desired.(*batchv1.Job)
@@ -205,6 +245,16 @@ func mergeState(desired, current runtime.Object) runtime.Object {
 			return csa
 		}
 		return dsa
+	case *unstructured.Unstructured:
+		// The ECK v1 Elasticsearch and Kibana are unstructured, and like their v1alpha1 counterparts are only
+		// updated if the spec has changed.
+		cu := current.(*unstructured.Unstructured)
+		du := desired.(*unstructured.Unstructured)
+
+		if reflect.DeepEqual(cu.Object["spec"], du.Object["spec"]) {
+			return cu
+		}
+		return du
 	default:
 		// Default to just using the desired state, with an updated RV.
 		return desired
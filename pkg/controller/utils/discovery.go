@@ -35,6 +35,33 @@ func RequiresTigeraSecure(cfg *rest.Config) (bool, error) {
 	return false, nil
 }
 
+// ServedVersions returns the versions of the API group that the cluster serves, with the preferred version first.
+// It returns no versions if the API group is not installed.
+func ServedVersions(cfg *rest.Config, group string) ([]string, error) {
+	clientset, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	groups, err := clientset.Discovery().ServerGroups()
+	if err != nil {
+		return nil, err
+	}
+	for _, g := range groups.Groups {
+		if g.Name != group {
+			continue
+		}
+		versions := []string{g.PreferredVersion.Version}
+		for _, v := range g.Versions {
+			if v.Version != g.PreferredVersion.Version {
+				versions = append(versions, v.Version)
+			}
+		}
+		return versions, nil
+	}
+	return nil, nil
+}
+
 func AutoDiscoverProvider(cfg *rest.Config) (operatorv1.Provider, error) {
 	clientset, err := kubernetes.NewForConfig(cfg)
 	if err != nil {
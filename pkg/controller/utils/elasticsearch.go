@@ -3,7 +3,9 @@ package utils
 
 import (
 	"context"
+	"fmt"
 
+	operatorv1 "github.com/tigera/operator/pkg/apis/operator/v1"
 	"github.com/tigera/operator/pkg/render"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -48,3 +50,22 @@ func GetElasticsearchClusterConfig(ctx context.Context, cli client.Client) (*ren
 
 	return render.NewElasticsearchClusterConfigFromConfigMap(configMap)
 }
+
+// ValidateManagedClusterIndexPrefixes returns an error if the logs of two managed clusters, or of a managed cluster
+// and the management cluster itself, would be indexed under the same name. Their logs would otherwise be mixed and
+// curated with each other's settings.
+func ValidateManagedClusterIndexPrefixes(clusters []operatorv1.ManagedCluster, managementClusterName string) error {
+	owners := map[string]string{}
+	for i := range clusters {
+		mc := &clusters[i]
+		prefix := render.ManagedClusterIndexPrefix(mc)
+		if prefix == managementClusterName {
+			return fmt.Errorf("managed cluster %s indexes its logs under %q, which is used by the management cluster", mc.Name, prefix)
+		}
+		if other, ok := owners[prefix]; ok {
+			return fmt.Errorf("managed clusters %s and %s both index their logs under %q", other, mc.Name, prefix)
+		}
+		owners[prefix] = mc.Name
+	}
+	return nil
+}
@@ -14,6 +14,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	apiregv1beta1 "k8s.io/kube-aggregator/pkg/apis/apiregistration/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/event"
@@ -35,15 +36,15 @@ var DefaultTSEEInstanceKey = client.ObjectKey{Name: "tigera-secure"}
 // ContextLoggerForResource provides a logger instance with context set for the provided object.
 func ContextLoggerForResource(log logr.Logger, obj runtime.Object) logr.Logger {
 	gvk := obj.GetObjectKind().GroupVersionKind()
-	name := obj.(metav1.ObjectMetaAccessor).GetObjectMeta().GetName()
-	namespace := obj.(metav1.ObjectMetaAccessor).GetObjectMeta().GetNamespace()
+	name := obj.(metav1.Object).GetName()
+	namespace := obj.(metav1.Object).GetNamespace()
 	return log.WithValues("Name", name, "Namespace", namespace, "Kind", gvk.Kind)
 }
 
 // IgnoreObject returns true if the object has been marked as ignored by the user,
 // and returns false otherwise.
 func IgnoreObject(obj runtime.Object) bool {
-	a := obj.(metav1.ObjectMetaAccessor).GetObjectMeta().GetAnnotations()
+	a := obj.(metav1.Object).GetAnnotations()
 	if val, ok := a[unsupportedIgnoreAnnotation]; ok && val == "true" {
 		return true
 	}
@@ -62,6 +63,31 @@ func AddComplianceWatch(c controller.Controller) error {
 	return c.Watch(&source.Kind{Type: &operatorv1.Compliance{}}, &handler.EnqueueRequestForObject{})
 }
 
+// AddAPIServiceWatch watches the APIService that registers the Tigera aggregated API, so that controllers
+// depending on it are reconciled when its availability changes.
+func AddAPIServiceWatch(c controller.Controller) error {
+	pred := predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return e.Meta.GetName() == render.TigeraAPIServiceName
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return e.MetaNew.GetName() == render.TigeraAPIServiceName
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return e.Meta.GetName() == render.TigeraAPIServiceName
+		},
+	}
+	return c.Watch(&source.Kind{Type: &apiregv1beta1.APIService{}}, &handler.EnqueueRequestForObject{}, pred)
+}
+
+// GenerationChangedPredicate filters out updates that leave metadata.generation unchanged. For resources with a
+// status subresource this skips the updates a controller makes to the status of its own primary resource.
+var GenerationChangedPredicate = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		return e.MetaOld.GetGeneration() != e.MetaNew.GetGeneration()
+	},
+}
+
 type MetaMatch func(metav1.ObjectMeta) bool
 
 func AddSecretsWatch(c controller.Controller, name, namespace string, metaMatches ...MetaMatch) error {
@@ -118,6 +144,9 @@ func addNamespacedWatch(c controller.Controller, obj runtime.Object, metaMatches
 	return c.Watch(&source.Kind{Type: obj}, &handler.EnqueueRequestForObject{}, pred)
 }
 
+// IsAPIServerReady returns true if the APIServer resource is ready and the Tigera aggregated API that it
+// registers is available. Controllers that use the Tigera API should wait on this, otherwise they fail
+// discovery while the aggregated API is still starting.
 func IsAPIServerReady(client client.Client, l logr.Logger) bool {
 	instance := &operatorv1.APIServer{}
 	err := client.Get(context.Background(), DefaultTSEEInstanceKey, instance)
@@ -134,9 +163,43 @@ func IsAPIServerReady(client client.Client, l logr.Logger) bool {
 		l.V(3).Info("APIServer resource not ready")
 		return false
 	}
+
+	available, msg, err := IsAPIServiceAvailable(context.Background(), client)
+	if err != nil {
+		l.Error(err, "Unable to retrieve APIService resource")
+		return false
+	}
+	if !available {
+		l.V(3).Info("Tigera API not available", "reason", msg)
+		return false
+	}
 	return true
 }
 
+// IsAPIServiceAvailable checks the Available condition of the APIService that registers the Tigera aggregated
+// API. If the API is not available, a message describing why is returned.
+func IsAPIServiceAvailable(ctx context.Context, cli client.Client) (bool, string, error) {
+	apiService := &apiregv1beta1.APIService{}
+	err := cli.Get(ctx, client.ObjectKey{Name: render.TigeraAPIServiceName}, apiService)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return false, fmt.Sprintf("APIService %q is not registered", render.TigeraAPIServiceName), nil
+		}
+		return false, "", err
+	}
+
+	for _, c := range apiService.Status.Conditions {
+		if c.Type != apiregv1beta1.Available {
+			continue
+		}
+		if c.Status == apiregv1beta1.ConditionTrue {
+			return true, "", nil
+		}
+		return false, fmt.Sprintf("APIService %q is not available: %s: %s", render.TigeraAPIServiceName, c.Reason, c.Message), nil
+	}
+	return false, fmt.Sprintf("APIService %q has not reported its availability", render.TigeraAPIServiceName), nil
+}
+
 func IsLogStorageReady(ctx context.Context, cli client.Client) (bool, error) {
 	instance := &operatorv1.LogStorage{}
 	err := cli.Get(ctx, DefaultTSEEInstanceKey, instance)
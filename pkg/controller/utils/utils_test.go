@@ -0,0 +1,138 @@
+// Copyright (c) 2020 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/tigera/operator/pkg/apis"
+	operatorv1 "github.com/tigera/operator/pkg/apis/operator/v1"
+	"github.com/tigera/operator/pkg/render"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	apiregv1beta1 "k8s.io/kube-aggregator/pkg/apis/apiregistration/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+var _ = Describe("Tigera API readiness", func() {
+	var c client.Client
+	var ctx context.Context
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(apis.AddToScheme(scheme)).NotTo(HaveOccurred())
+		c = fake.NewFakeClientWithScheme(scheme)
+		ctx = context.Background()
+	})
+
+	apiService := func(status apiregv1beta1.ConditionStatus) *apiregv1beta1.APIService {
+		return &apiregv1beta1.APIService{
+			ObjectMeta: metav1.ObjectMeta{Name: render.TigeraAPIServiceName},
+			Status: apiregv1beta1.APIServiceStatus{
+				Conditions: []apiregv1beta1.APIServiceCondition{{
+					Type:    apiregv1beta1.Available,
+					Status:  status,
+					Reason:  "MissingEndpoints",
+					Message: "endpoints for service/tigera-api in \"tigera-system\" have no addresses",
+				}},
+			},
+		}
+	}
+
+	It("should report the API unavailable when the APIService is not registered", func() {
+		available, msg, err := IsAPIServiceAvailable(ctx, c)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(available).To(BeFalse())
+		Expect(msg).To(ContainSubstring("not registered"))
+	})
+
+	It("should report the reason the APIService is unavailable", func() {
+		Expect(c.Create(ctx, apiService(apiregv1beta1.ConditionFalse))).NotTo(HaveOccurred())
+		available, msg, err := IsAPIServiceAvailable(ctx, c)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(available).To(BeFalse())
+		Expect(msg).To(ContainSubstring("MissingEndpoints"))
+	})
+
+	It("should only consider the API server ready once the APIService is available", func() {
+		Expect(c.Create(ctx, &operatorv1.APIServer{
+			ObjectMeta: metav1.ObjectMeta{Name: "tigera-secure"},
+			Status:     operatorv1.APIServerStatus{State: operatorv1.APIServerStatusReady},
+		})).NotTo(HaveOccurred())
+		svc := apiService(apiregv1beta1.ConditionFalse)
+		Expect(c.Create(ctx, svc)).NotTo(HaveOccurred())
+		Expect(IsAPIServerReady(c, logf.Log)).To(BeFalse())
+
+		svc.Status.Conditions[0].Status = apiregv1beta1.ConditionTrue
+		Expect(c.Update(ctx, svc)).NotTo(HaveOccurred())
+		Expect(IsAPIServerReady(c, logf.Log)).To(BeTrue())
+	})
+})
+
+var _ = Describe("Managed cluster index prefixes", func() {
+	cluster := func(name, prefix string) operatorv1.ManagedCluster {
+		mc := operatorv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		if prefix != "" {
+			mc.Spec.LogStorage = &operatorv1.ManagedClusterLogStorage{IndexPrefix: prefix}
+		}
+		return mc
+	}
+
+	It("should accept distinct index prefixes", func() {
+		clusters := []operatorv1.ManagedCluster{cluster("a", ""), cluster("b", "team-b")}
+		Expect(ValidateManagedClusterIndexPrefixes(clusters, render.DefaultElasticsearchClusterName)).To(Succeed())
+	})
+
+	It("should reject managed clusters sharing an index prefix", func() {
+		clusters := []operatorv1.ManagedCluster{cluster("a", ""), cluster("b", "a")}
+		Expect(ValidateManagedClusterIndexPrefixes(clusters, render.DefaultElasticsearchClusterName)).To(HaveOccurred())
+	})
+
+	It("should reject the index prefix of the management cluster", func() {
+		clusters := []operatorv1.ManagedCluster{cluster("a", render.DefaultElasticsearchClusterName)}
+		Expect(ValidateManagedClusterIndexPrefixes(clusters, render.DefaultElasticsearchClusterName)).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("Merging the state of ECK v1 resources", func() {
+	kibana := func(count int64, resourceVersion string) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{"count": count},
+		}}
+		u.SetGroupVersionKind(render.KibanaV1GVK)
+		u.SetName(render.KibanaName)
+		u.SetNamespace(render.KibanaNamespace)
+		u.SetResourceVersion(resourceVersion)
+		return u
+	}
+
+	It("should keep the current resource when the spec is unchanged", func() {
+		current := kibana(1, "5")
+		Expect(mergeState(kibana(1, ""), current)).To(BeIdenticalTo(current))
+	})
+
+	It("should update the resource when the spec has changed", func() {
+		desired := kibana(2, "")
+		merged := mergeState(desired, kibana(1, "5")).(*unstructured.Unstructured)
+		Expect(merged).To(BeIdenticalTo(desired))
+		Expect(merged.GetResourceVersion()).To(Equal("5"))
+	})
+})
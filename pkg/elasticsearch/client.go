@@ -0,0 +1,129 @@
+// Copyright (c) 2020 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package elasticsearch is a client for the Elasticsearch APIs that the operator manages the LogStorage
+// Elasticsearch cluster through.
+package elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const requestTimeout = 30 * time.Second
+
+// Client calls the Elasticsearch API as a single user.
+type Client struct {
+	url        string
+	username   string
+	password   string
+	httpClient *http.Client
+}
+
+// NewClient returns a client for the Elasticsearch cluster at the given URL. The certificate of the cluster is
+// verified with the PEM encoded CA, or with the system roots if no CA is given.
+func NewClient(esURL, username, password string, caPEM []byte) (*Client, error) {
+	if _, err := url.Parse(esURL); err != nil {
+		return nil, err
+	}
+	var pool *x509.CertPool
+	if len(caPEM) > 0 {
+		pool = x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("the Elasticsearch CA is not a valid PEM encoded certificate")
+		}
+	}
+	return &Client{
+		url:      strings.TrimSuffix(esURL, "/"),
+		username: username,
+		password: password,
+		httpClient: &http.Client{
+			Timeout:   requestTimeout,
+			Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool}},
+		},
+	}, nil
+}
+
+// Error is returned for requests that Elasticsearch responds to with an error status.
+type Error struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("Elasticsearch responded with status %d: %s", e.StatusCode, e.Body)
+}
+
+// IsNotFound returns true if the error is an Elasticsearch response with status 404.
+func IsNotFound(err error) bool {
+	esErr, ok := err.(*Error)
+	return ok && esErr.StatusCode == http.StatusNotFound
+}
+
+// do sends a request with the JSON encoded body, if any, and decodes the JSON response into result, if given.
+func (c *Client) do(ctx context.Context, method, path string, body, result interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequest(method, c.url+path, reqBody)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return c.send(ctx, req, result)
+}
+
+// send sends the request as the user of the client and decodes the JSON response into result, if given.
+func (c *Client) send(ctx context.Context, req *http.Request, result interface{}) error {
+	req = req.WithContext(ctx)
+	req.SetBasicAuth(c.username, c.password)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &Error{StatusCode: resp.StatusCode, Body: string(respBody)}
+	}
+	if result != nil {
+		if err := json.Unmarshal(respBody, result); err != nil {
+			return fmt.Errorf("failed to decode the Elasticsearch response: %v", err)
+		}
+	}
+	return nil
+}
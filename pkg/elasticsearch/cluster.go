@@ -0,0 +1,118 @@
+// Copyright (c) 2020 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearch
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+)
+
+// NodeAllocation describes the shards allocated to a data node and the disk they take up.
+type NodeAllocation struct {
+	Name   string
+	Shards int
+	// DiskIndices is the disk space taken up by the shards on the node, in bytes.
+	DiskIndices int64
+	// DiskTotal is the disk space of the node, in bytes.
+	DiskTotal int64
+}
+
+// ClusterHealth is the health of the cluster and the shards that are not started yet.
+type ClusterHealth struct {
+	Status             string `json:"status"`
+	RelocatingShards   int    `json:"relocating_shards"`
+	InitializingShards int    `json:"initializing_shards"`
+	UnassignedShards   int    `json:"unassigned_shards"`
+}
+
+// Allocations returns the shard allocation of every data node, ordered by node name.
+func (c *Client) Allocations(ctx context.Context) ([]NodeAllocation, error) {
+	var resp []struct {
+		Node        string  `json:"node"`
+		Shards      *string `json:"shards"`
+		DiskIndices *string `json:"disk.indices"`
+		DiskTotal   *string `json:"disk.total"`
+	}
+	if err := c.do(ctx, "GET", "/_cat/allocation?format=json&bytes=b&h=node,shards,disk.indices,disk.total", nil, &resp); err != nil {
+		return nil, err
+	}
+	var nodes []NodeAllocation
+	for _, r := range resp {
+		// Unassigned shards are listed without a disk.
+		if r.DiskTotal == nil {
+			continue
+		}
+		node := NodeAllocation{Name: r.Node}
+		var err error
+		if r.Shards != nil {
+			if node.Shards, err = strconv.Atoi(*r.Shards); err != nil {
+				return nil, fmt.Errorf("invalid shard count %q of node %s", *r.Shards, r.Node)
+			}
+		}
+		if r.DiskIndices != nil {
+			if node.DiskIndices, err = strconv.ParseInt(*r.DiskIndices, 10, 64); err != nil {
+				return nil, fmt.Errorf("invalid index size %q of node %s", *r.DiskIndices, r.Node)
+			}
+		}
+		if node.DiskTotal, err = strconv.ParseInt(*r.DiskTotal, 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid disk size %q of node %s", *r.DiskTotal, r.Node)
+		}
+		nodes = append(nodes, node)
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Name < nodes[j].Name })
+	return nodes, nil
+}
+
+// IndexReplicas returns the number of replicas of each index matching the pattern.
+func (c *Client) IndexReplicas(ctx context.Context, pattern string) (map[string]int, error) {
+	resp := map[string]struct {
+		Settings struct {
+			Index struct {
+				NumberOfReplicas string `json:"number_of_replicas"`
+			} `json:"index"`
+		} `json:"settings"`
+	}{}
+	if err := c.do(ctx, "GET", "/"+url.PathEscape(pattern)+"/_settings/index.number_of_replicas", nil, &resp); err != nil {
+		return nil, err
+	}
+	replicas := map[string]int{}
+	for index, settings := range resp {
+		n, err := strconv.Atoi(settings.Settings.Index.NumberOfReplicas)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number of replicas %q of index %s", settings.Settings.Index.NumberOfReplicas, index)
+		}
+		replicas[index] = n
+	}
+	return replicas, nil
+}
+
+// SetIndexReplicas sets the number of replicas of the indices matching the pattern, which Elasticsearch then
+// allocates or removes.
+func (c *Client) SetIndexReplicas(ctx context.Context, pattern string, replicas int) error {
+	body := map[string]interface{}{
+		"index": map[string]interface{}{"number_of_replicas": replicas},
+	}
+	return c.do(ctx, "PUT", "/"+url.PathEscape(pattern)+"/_settings", body, nil)
+}
+
+// ClusterHealth returns the health of the cluster.
+func (c *Client) ClusterHealth(ctx context.Context) (ClusterHealth, error) {
+	var health ClusterHealth
+	err := c.do(ctx, "GET", "/_cluster/health", nil, &health)
+	return health, err
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2020 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearch_test
+
+import (
+	"context"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/tigera/operator/pkg/elasticsearch"
+)
+
+var _ = Describe("Elasticsearch cluster scaling", func() {
+	var server *httptest.Server
+	var requests []request
+	var responses map[string]string
+	var client *elasticsearch.Client
+	ctx := context.Background()
+
+	BeforeEach(func() {
+		requests = nil
+		responses = map[string]string{}
+		server, client = newStandIn(&requests, responses)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should list the shards and disk of the data nodes", func() {
+		responses["GET /_cat/allocation"] = `[
+			{"node":"tigera-secure-es-1","shards":"4","disk.indices":"2048","disk.total":"10240"},
+			{"node":"tigera-secure-es-0","shards":"5","disk.indices":"4096","disk.total":"10240"},
+			{"node":"UNASSIGNED","shards":"1","disk.indices":null,"disk.total":null}
+		]`
+		nodes, err := client.Allocations(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(nodes).To(Equal([]elasticsearch.NodeAllocation{
+			{Name: "tigera-secure-es-0", Shards: 5, DiskIndices: 4096, DiskTotal: 10240},
+			{Name: "tigera-secure-es-1", Shards: 4, DiskIndices: 2048, DiskTotal: 10240},
+		}))
+	})
+
+	It("should read and change the replicas of indices", func() {
+		responses["GET /tigera_secure_ee_*/_settings/index.number_of_replicas"] = `{
+			"tigera_secure_ee_flows.cluster.000001":{"settings":{"index":{"number_of_replicas":"0"}}},
+			"tigera_secure_ee_dns.cluster.000001":{"settings":{"index":{"number_of_replicas":"1"}}}
+		}`
+		responses["PUT /tigera_secure_ee_*/_settings"] = `{"acknowledged":true}`
+
+		replicas, err := client.IndexReplicas(ctx, "tigera_secure_ee_*")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(replicas).To(Equal(map[string]int{
+			"tigera_secure_ee_flows.cluster.000001": 0,
+			"tigera_secure_ee_dns.cluster.000001":   1,
+		}))
+
+		Expect(client.SetIndexReplicas(ctx, "tigera_secure_ee_*", 1)).To(Succeed())
+		Expect(requests[1].body).To(Equal(map[string]interface{}{
+			"index": map[string]interface{}{"number_of_replicas": float64(1)},
+		}))
+	})
+
+	It("should return the health of the cluster", func() {
+		responses["GET /_cluster/health"] = `{"cluster_name":"tigera-secure","status":"yellow","relocating_shards":2,"initializing_shards":1,"unassigned_shards":3}`
+		health, err := client.ClusterHealth(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(health).To(Equal(elasticsearch.ClusterHealth{Status: "yellow", RelocatingShards: 2, InitializingShards: 1, UnassignedShards: 3}))
+	})
+})
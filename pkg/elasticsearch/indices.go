@@ -0,0 +1,225 @@
+// Copyright (c) 2020 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearch
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// LifecyclePolicy is an index lifecycle policy, which moves the indices it manages through its phases.
+type LifecyclePolicy struct {
+	Phases map[string]LifecyclePhase `json:"phases"`
+}
+
+// LifecyclePhase is a phase of an index lifecycle policy, with the actions run on entering it.
+type LifecyclePhase struct {
+	MinAge  string                            `json:"min_age,omitempty"`
+	Actions map[string]map[string]interface{} `json:"actions"`
+}
+
+// IndexTemplate holds the settings applied to new indices matching its patterns.
+type IndexTemplate struct {
+	IndexPatterns []string               `json:"index_patterns"`
+	Order         int                    `json:"order"`
+	Settings      map[string]interface{} `json:"settings"`
+}
+
+// Index describes an index and the storage it takes up.
+type Index struct {
+	Name string
+	// CreationDate is the creation time of the index, in milliseconds since the epoch.
+	CreationDate int64
+	// Size is the size of the index, including replicas, in bytes.
+	Size int64
+}
+
+// LifecycleError is an index lifecycle step that failed for an index.
+type LifecycleError struct {
+	Index  string
+	Policy string
+	Reason string
+}
+
+// PutLifecyclePolicy creates or updates the index lifecycle policy.
+func (c *Client) PutLifecyclePolicy(ctx context.Context, name string, policy LifecyclePolicy) error {
+	return c.do(ctx, "PUT", "/_ilm/policy/"+url.PathEscape(name), map[string]interface{}{"policy": policy}, nil)
+}
+
+// PutIndexTemplate creates or updates the index template.
+func (c *Client) PutIndexTemplate(ctx context.Context, name string, template IndexTemplate) error {
+	return c.do(ctx, "PUT", "/_template/"+url.PathEscape(name), template, nil)
+}
+
+// AliasExists returns true if the alias exists.
+func (c *Client) AliasExists(ctx context.Context, alias string) (bool, error) {
+	err := c.do(ctx, "HEAD", "/_alias/"+url.PathEscape(alias), nil, nil)
+	if IsNotFound(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// CreateWriteIndex creates the index as the write index of the alias. The name of the index may use date math.
+func (c *Client) CreateWriteIndex(ctx context.Context, index, alias string) error {
+	body := map[string]interface{}{
+		"aliases": map[string]interface{}{
+			alias: map[string]interface{}{"is_write_index": true},
+		},
+	}
+	return c.do(ctx, "PUT", "/"+url.PathEscape(index), body, nil)
+}
+
+// IndicesOutsideAlias returns the indices matching the pattern that are not members of the alias, sorted by name.
+func (c *Client) IndicesOutsideAlias(ctx context.Context, pattern, alias string) ([]string, error) {
+	resp := map[string]struct {
+		Aliases map[string]interface{} `json:"aliases"`
+	}{}
+	if err := c.do(ctx, "GET", "/"+url.PathEscape(pattern)+"/_alias", nil, &resp); err != nil {
+		return nil, err
+	}
+	var indices []string
+	for index, aliases := range resp {
+		if _, ok := aliases.Aliases[alias]; !ok {
+			indices = append(indices, index)
+		}
+	}
+	sort.Strings(indices)
+	return indices, nil
+}
+
+// AdoptIndices adds the indices to the alias, next to its write index, and puts them under the lifecycle policy.
+// The indices are marked as complete, so the policy does not roll them over and only removes them once they reach
+// the age of its delete phase.
+func (c *Client) AdoptIndices(ctx context.Context, alias, policy string, indices []string) error {
+	var actions []interface{}
+	escaped := make([]string, 0, len(indices))
+	for _, index := range indices {
+		actions = append(actions, map[string]interface{}{
+			"add": map[string]interface{}{"index": index, "alias": alias},
+		})
+		escaped = append(escaped, url.PathEscape(index))
+	}
+	if err := c.do(ctx, "POST", "/_aliases", map[string]interface{}{"actions": actions}, nil); err != nil {
+		return err
+	}
+	settings := map[string]interface{}{
+		"index.lifecycle.name":              policy,
+		"index.lifecycle.indexing_complete": true,
+	}
+	return c.do(ctx, "PUT", "/"+strings.Join(escaped, ",")+"/_settings", settings, nil)
+}
+
+// DeleteIndex removes the index.
+func (c *Client) DeleteIndex(ctx context.Context, index string) error {
+	return c.do(ctx, "DELETE", "/"+url.PathEscape(index), nil, nil)
+}
+
+// Indices lists the indices matching the pattern, oldest first.
+func (c *Client) Indices(ctx context.Context, pattern string) ([]Index, error) {
+	var resp []struct {
+		Index        string `json:"index"`
+		CreationDate string `json:"creation.date"`
+		StoreSize    string `json:"store.size"`
+	}
+	path := "/_cat/indices/" + url.PathEscape(pattern) + "?format=json&bytes=b&h=index,creation.date,store.size"
+	if err := c.do(ctx, "GET", path, nil, &resp); err != nil {
+		return nil, err
+	}
+	indices := make([]Index, 0, len(resp))
+	for _, r := range resp {
+		created, err := strconv.ParseInt(r.CreationDate, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid creation date %q of index %s", r.CreationDate, r.Index)
+		}
+		// The size is not known while the index is being created.
+		size, _ := strconv.ParseInt(r.StoreSize, 10, 64)
+		indices = append(indices, Index{Name: r.Index, CreationDate: created, Size: size})
+	}
+	sort.Slice(indices, func(i, j int) bool { return indices[i].CreationDate < indices[j].CreationDate })
+	return indices, nil
+}
+
+// WriteIndices returns the indices matching the pattern that are the write index of an alias.
+func (c *Client) WriteIndices(ctx context.Context, pattern string) (map[string]bool, error) {
+	resp := map[string]struct {
+		Aliases map[string]struct {
+			IsWriteIndex bool `json:"is_write_index"`
+		} `json:"aliases"`
+	}{}
+	if err := c.do(ctx, "GET", "/"+url.PathEscape(pattern)+"/_alias", nil, &resp); err != nil {
+		return nil, err
+	}
+	writeIndices := map[string]bool{}
+	for index, aliases := range resp {
+		for _, alias := range aliases.Aliases {
+			if alias.IsWriteIndex {
+				writeIndices[index] = true
+			}
+		}
+	}
+	return writeIndices, nil
+}
+
+// StorageCapacity returns the total disk space of the data nodes, in bytes.
+func (c *Client) StorageCapacity(ctx context.Context) (int64, error) {
+	var resp []struct {
+		DiskTotal *string `json:"disk.total"`
+	}
+	if err := c.do(ctx, "GET", "/_cat/allocation?format=json&bytes=b&h=disk.total", nil, &resp); err != nil {
+		return 0, err
+	}
+	var total int64
+	for _, node := range resp {
+		// Unassigned shards are listed without a disk.
+		if node.DiskTotal == nil {
+			continue
+		}
+		size, err := strconv.ParseInt(*node.DiskTotal, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid disk size %q", *node.DiskTotal)
+		}
+		total += size
+	}
+	return total, nil
+}
+
+// LifecycleErrors returns the indices matching the pattern whose current lifecycle step failed.
+func (c *Client) LifecycleErrors(ctx context.Context, pattern string) ([]LifecycleError, error) {
+	var resp struct {
+		Indices map[string]struct {
+			Policy   string `json:"policy"`
+			Step     string `json:"step"`
+			StepInfo struct {
+				Reason string `json:"reason"`
+			} `json:"step_info"`
+		} `json:"indices"`
+	}
+	if err := c.do(ctx, "GET", "/"+url.PathEscape(pattern)+"/_ilm/explain", nil, &resp); err != nil {
+		return nil, err
+	}
+	var errs []LifecycleError
+	for index, explain := range resp.Indices {
+		if explain.Step == "ERROR" {
+			errs = append(errs, LifecycleError{Index: index, Policy: explain.Policy, Reason: explain.StepInfo.Reason})
+		}
+	}
+	sort.Slice(errs, func(i, j int) bool { return errs[i].Index < errs[j].Index })
+	return errs, nil
+}
@@ -0,0 +1,163 @@
+// Copyright (c) 2020 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearch_test
+
+import (
+	"context"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/tigera/operator/pkg/elasticsearch"
+)
+
+var _ = Describe("Elasticsearch index lifecycles", func() {
+	var server *httptest.Server
+	var requests []request
+	var responses map[string]string
+	var client *elasticsearch.Client
+	ctx := context.Background()
+
+	BeforeEach(func() {
+		requests = nil
+		responses = map[string]string{}
+		server, client = newStandIn(&requests, responses)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should apply a lifecycle policy and its index template", func() {
+		responses["PUT /_ilm/policy/tigera_secure_ee_flows.cluster"] = `{"acknowledged":true}`
+		responses["PUT /_template/tigera_secure_ee_flows.cluster"] = `{"acknowledged":true}`
+		Expect(client.PutLifecyclePolicy(ctx, "tigera_secure_ee_flows.cluster", elasticsearch.LifecyclePolicy{
+			Phases: map[string]elasticsearch.LifecyclePhase{
+				"delete": {MinAge: "8d", Actions: map[string]map[string]interface{}{"delete": {}}},
+			},
+		})).To(Succeed())
+		Expect(client.PutIndexTemplate(ctx, "tigera_secure_ee_flows.cluster", elasticsearch.IndexTemplate{
+			IndexPatterns: []string{"tigera_secure_ee_flows.cluster.*"},
+			Order:         1,
+			Settings:      map[string]interface{}{"index.lifecycle.name": "tigera_secure_ee_flows.cluster"},
+		})).To(Succeed())
+
+		Expect(requests).To(HaveLen(2))
+		Expect(requests[0].body).To(Equal(map[string]interface{}{
+			"policy": map[string]interface{}{
+				"phases": map[string]interface{}{
+					"delete": map[string]interface{}{
+						"min_age": "8d",
+						"actions": map[string]interface{}{"delete": map[string]interface{}{}},
+					},
+				},
+			},
+		}))
+		Expect(requests[1].body).To(Equal(map[string]interface{}{
+			"index_patterns": []interface{}{"tigera_secure_ee_flows.cluster.*"},
+			"order":          float64(1),
+			"settings":       map[string]interface{}{"index.lifecycle.name": "tigera_secure_ee_flows.cluster"},
+		}))
+	})
+
+	It("should create the write index of a missing alias", func() {
+		exists, err := client.AliasExists(ctx, "tigera_secure_ee_flows.cluster")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(exists).To(BeFalse())
+
+		responses["PUT /<tigera_secure_ee_flows.cluster.{now/d}-000001>"] = `{"acknowledged":true}`
+		Expect(client.CreateWriteIndex(ctx, "<tigera_secure_ee_flows.cluster.{now/d}-000001>", "tigera_secure_ee_flows.cluster")).To(Succeed())
+		Expect(requests[1].body).To(Equal(map[string]interface{}{
+			"aliases": map[string]interface{}{
+				"tigera_secure_ee_flows.cluster": map[string]interface{}{"is_write_index": true},
+			},
+		}))
+
+		responses["HEAD /_alias/tigera_secure_ee_flows.cluster"] = ``
+		exists, err = client.AliasExists(ctx, "tigera_secure_ee_flows.cluster")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(exists).To(BeTrue())
+	})
+
+	It("should move indices that predate the write alias behind it", func() {
+		responses["GET /tigera_secure_ee_flows.cluster.*/_alias"] = `{
+			"tigera_secure_ee_flows.cluster.20200101": {"aliases":{}},
+			"tigera_secure_ee_flows.cluster.2020.01.02-000001": {"aliases":{"tigera_secure_ee_flows.cluster":{"is_write_index":true}}}
+		}`
+		indices, err := client.IndicesOutsideAlias(ctx, "tigera_secure_ee_flows.cluster.*", "tigera_secure_ee_flows.cluster")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(indices).To(Equal([]string{"tigera_secure_ee_flows.cluster.20200101"}))
+
+		responses["POST /_aliases"] = `{"acknowledged":true}`
+		responses["PUT /tigera_secure_ee_flows.cluster.20200101/_settings"] = `{"acknowledged":true}`
+		Expect(client.AdoptIndices(ctx, "tigera_secure_ee_flows.cluster", "tigera_secure_ee_flows.cluster", indices)).To(Succeed())
+		Expect(requests[1].body).To(Equal(map[string]interface{}{
+			"actions": []interface{}{
+				map[string]interface{}{"add": map[string]interface{}{
+					"index": "tigera_secure_ee_flows.cluster.20200101",
+					"alias": "tigera_secure_ee_flows.cluster",
+				}},
+			},
+		}))
+		Expect(requests[2].body).To(Equal(map[string]interface{}{
+			"index.lifecycle.name":              "tigera_secure_ee_flows.cluster",
+			"index.lifecycle.indexing_complete": true,
+		}))
+	})
+
+	It("should list indices oldest first with their write indices", func() {
+		responses["GET /_cat/indices/tigera_secure_ee_*"] = `[
+			{"index":"tigera_secure_ee_flows.cluster.2020.01.02-000002","creation.date":"2000","store.size":null},
+			{"index":"tigera_secure_ee_flows.cluster.2020.01.01-000001","creation.date":"1000","store.size":"4096"}
+		]`
+		indices, err := client.Indices(ctx, "tigera_secure_ee_*")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(indices).To(Equal([]elasticsearch.Index{
+			{Name: "tigera_secure_ee_flows.cluster.2020.01.01-000001", CreationDate: 1000, Size: 4096},
+			{Name: "tigera_secure_ee_flows.cluster.2020.01.02-000002", CreationDate: 2000},
+		}))
+		Expect(requests[0].query).To(Equal("format=json&bytes=b&h=index,creation.date,store.size"))
+
+		responses["GET /tigera_secure_ee_*/_alias"] = `{
+			"tigera_secure_ee_flows.cluster.2020.01.01-000001": {"aliases":{"tigera_secure_ee_flows.cluster":{"is_write_index":false}}},
+			"tigera_secure_ee_flows.cluster.2020.01.02-000002": {"aliases":{"tigera_secure_ee_flows.cluster":{"is_write_index":true}}}
+		}`
+		writeIndices, err := client.WriteIndices(ctx, "tigera_secure_ee_*")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(writeIndices).To(Equal(map[string]bool{"tigera_secure_ee_flows.cluster.2020.01.02-000002": true}))
+	})
+
+	It("should sum the disk space of the data nodes", func() {
+		responses["GET /_cat/allocation"] = `[{"disk.total":"1000"},{"disk.total":"2000"},{"disk.total":null}]`
+		capacity, err := client.StorageCapacity(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(capacity).To(Equal(int64(3000)))
+	})
+
+	It("should report the indices that failed a lifecycle step", func() {
+		responses["GET /tigera_secure_ee_*/_ilm/explain"] = `{"indices":{
+			"tigera_secure_ee_dns.cluster.2020.01.01-000001": {"policy":"tigera_secure_ee_dns.cluster","step":"ERROR","step_info":{"reason":"no alias"}},
+			"tigera_secure_ee_flows.cluster.2020.01.01-000001": {"policy":"tigera_secure_ee_flows.cluster","step":"check-rollover-ready"}
+		}}`
+		errs, err := client.LifecycleErrors(ctx, "tigera_secure_ee_*")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(errs).To(Equal([]elasticsearch.LifecycleError{{
+			Index:  "tigera_secure_ee_dns.cluster.2020.01.01-000001",
+			Policy: "tigera_secure_ee_dns.cluster",
+			Reason: "no alias",
+		}}))
+	})
+})
@@ -0,0 +1,79 @@
+// Copyright (c) 2020 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"strings"
+)
+
+// savedObjectsImportResponse is the response of Kibana to an import of saved objects.
+type savedObjectsImportResponse struct {
+	Success bool `json:"success"`
+	Errors  []struct {
+		ID    string `json:"id"`
+		Type  string `json:"type"`
+		Error struct {
+			Type string `json:"type"`
+		} `json:"error"`
+	} `json:"errors"`
+}
+
+// ImportSavedObjects imports the saved objects in the NDJSON file into Kibana, overwriting existing objects with the
+// same ID. Kibana is called through the same client as Elasticsearch, so the client must be created with the URL of
+// Kibana, including its base path.
+func (c *Client) ImportSavedObjects(ctx context.Context, filename string, ndjson []byte) error {
+	// Kibana only accepts files with the .ndjson extension.
+	if !strings.HasSuffix(filename, ".ndjson") {
+		filename += ".ndjson"
+	}
+
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	part, err := w.CreateFormFile("file", filename)
+	if err != nil {
+		return err
+	}
+	if _, err := part.Write(ndjson); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.url+"/api/saved_objects/_import?overwrite=true", &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	req.Header.Set("kbn-xsrf", "true")
+
+	var resp savedObjectsImportResponse
+	if err := c.send(ctx, req, &resp); err != nil {
+		return err
+	}
+	if !resp.Success {
+		var failed []string
+		for _, e := range resp.Errors {
+			failed = append(failed, fmt.Sprintf("%s %s: %s", e.Type, e.ID, e.Error.Type))
+		}
+		return fmt.Errorf("Kibana did not import %s: %s", filename, strings.Join(failed, ", "))
+	}
+	return nil
+}
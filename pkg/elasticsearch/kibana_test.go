@@ -0,0 +1,75 @@
+// Copyright (c) 2020 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearch_test
+
+import (
+	"context"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/tigera/operator/pkg/elasticsearch"
+)
+
+var _ = Describe("Kibana saved objects", func() {
+	var server *httptest.Server
+	var client *elasticsearch.Client
+	var imported map[string]string
+	var response string
+	ctx := context.Background()
+
+	BeforeEach(func() {
+		imported = map[string]string{}
+		response = `{"success":true,"successCount":1}`
+		server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer GinkgoRecover()
+			Expect(r.Method).To(Equal(http.MethodPost))
+			Expect(r.URL.Path).To(Equal("/tigera-kibana/api/saved_objects/_import"))
+			Expect(r.URL.Query().Get("overwrite")).To(Equal("true"))
+			Expect(r.Header.Get("kbn-xsrf")).To(Equal("true"))
+
+			file, header, err := r.FormFile("file")
+			Expect(err).NotTo(HaveOccurred())
+			b, err := ioutil.ReadAll(file)
+			Expect(err).NotTo(HaveOccurred())
+			imported[header.Filename] = string(b)
+			_, _ = w.Write([]byte(response))
+		}))
+		ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+		var err error
+		client, err = elasticsearch.NewClient(server.URL+"/tigera-kibana", "elastic", "password", ca)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should import the objects as an NDJSON file", func() {
+		objects := `{"type":"dashboard","id":"flows"}` + "\n"
+		Expect(client.ImportSavedObjects(ctx, "flows", []byte(objects))).To(Succeed())
+		Expect(imported).To(Equal(map[string]string{"flows.ndjson": objects}))
+	})
+
+	It("should return the objects that Kibana did not import", func() {
+		response = `{"success":false,"errors":[{"id":"flows","type":"dashboard","error":{"type":"missing_references"}}]}`
+		err := client.ImportSavedObjects(ctx, "flows.ndjson", []byte(`{}`))
+		Expect(err).To(MatchError("Kibana did not import flows.ndjson: dashboard flows: missing_references"))
+	})
+})
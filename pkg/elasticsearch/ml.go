@@ -0,0 +1,216 @@
+// Copyright (c) 2020 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearch
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Job is the configuration of a machine learning anomaly detection job.
+type Job struct {
+	ID             string                 `json:"job_id"`
+	AnalysisLimits AnalysisLimits         `json:"analysis_limits"`
+	CustomSettings map[string]interface{} `json:"custom_settings,omitempty"`
+}
+
+// AnalysisLimits are the resource limits of a job.
+type AnalysisLimits struct {
+	ModelMemoryLimit string `json:"model_memory_limit,omitempty"`
+}
+
+// JobUpdate changes the configuration of a job. The analysis limits can only be changed while the job is closed.
+// The custom settings replace those of the job.
+type JobUpdate struct {
+	AnalysisLimits *AnalysisLimits        `json:"analysis_limits,omitempty"`
+	CustomSettings map[string]interface{} `json:"custom_settings"`
+}
+
+// JobStats is the state of a job and the data it has processed.
+type JobStats struct {
+	ID                    string         `json:"job_id"`
+	State                 string         `json:"state"`
+	AssignmentExplanation string         `json:"assignment_explanation"`
+	DataCounts            DataCounts     `json:"data_counts"`
+	ModelSizeStats        ModelSizeStats `json:"model_size_stats"`
+}
+
+// DataCounts describes the data a job has processed.
+type DataCounts struct {
+	// LatestRecordTimestamp is the time of the most recent record processed, in milliseconds since the epoch, or 0
+	// if no record has been processed.
+	LatestRecordTimestamp int64 `json:"latest_record_timestamp"`
+}
+
+// ModelSizeStats describes the memory used by the model of a job.
+type ModelSizeStats struct {
+	// MemoryStatus is ok, soft_limit or hard_limit.
+	MemoryStatus string `json:"memory_status"`
+}
+
+// Datafeed is the configuration of the datafeed that queries the data a job analyzes.
+type Datafeed struct {
+	ID        string `json:"datafeed_id"`
+	JobID     string `json:"job_id"`
+	Frequency string `json:"frequency,omitempty"`
+}
+
+// DatafeedUpdate changes the configuration of a datafeed. A running datafeed must be restarted to apply it.
+type DatafeedUpdate struct {
+	Frequency string `json:"frequency,omitempty"`
+}
+
+// DatafeedStats is the state of a datafeed.
+type DatafeedStats struct {
+	ID                    string `json:"datafeed_id"`
+	State                 string `json:"state"`
+	AssignmentExplanation string `json:"assignment_explanation"`
+}
+
+// Job states and datafeed states that are reported once a job runs.
+const (
+	JobStateOpened        = "opened"
+	JobStateOpening       = "opening"
+	JobStateFailed        = "failed"
+	DatafeedStateStarted  = "started"
+	DatafeedStateStarting = "starting"
+)
+
+// Jobs lists the anomaly detection jobs.
+func (c *Client) Jobs(ctx context.Context) ([]Job, error) {
+	var resp struct {
+		Jobs []Job `json:"jobs"`
+	}
+	if err := c.do(ctx, "GET", "/_ml/anomaly_detectors", nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Jobs, nil
+}
+
+// JobStats returns the state of the job.
+func (c *Client) JobStats(ctx context.Context, jobID string) (JobStats, error) {
+	var resp struct {
+		Jobs []JobStats `json:"jobs"`
+	}
+	if err := c.do(ctx, "GET", "/_ml/anomaly_detectors/"+url.PathEscape(jobID)+"/_stats", nil, &resp); err != nil {
+		return JobStats{}, err
+	}
+	if len(resp.Jobs) != 1 {
+		return JobStats{}, fmt.Errorf("expected the stats of job %s, got %d jobs", jobID, len(resp.Jobs))
+	}
+	return resp.Jobs[0], nil
+}
+
+// UpdateJob changes the configuration of the job.
+func (c *Client) UpdateJob(ctx context.Context, jobID string, update JobUpdate) error {
+	return c.do(ctx, "POST", "/_ml/anomaly_detectors/"+url.PathEscape(jobID)+"/_update", update, nil)
+}
+
+// OpenJob opens the job, so that it accepts data from its datafeed.
+func (c *Client) OpenJob(ctx context.Context, jobID string) error {
+	return c.do(ctx, "POST", "/_ml/anomaly_detectors/"+url.PathEscape(jobID)+"/_open", nil, nil)
+}
+
+// CloseJob closes the job, persisting its model. The datafeed of the job must be stopped first.
+func (c *Client) CloseJob(ctx context.Context, jobID string) error {
+	return c.do(ctx, "POST", "/_ml/anomaly_detectors/"+url.PathEscape(jobID)+"/_close", nil, nil)
+}
+
+// Datafeeds lists the datafeeds of the anomaly detection jobs.
+func (c *Client) Datafeeds(ctx context.Context) ([]Datafeed, error) {
+	var resp struct {
+		Datafeeds []Datafeed `json:"datafeeds"`
+	}
+	if err := c.do(ctx, "GET", "/_ml/datafeeds", nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Datafeeds, nil
+}
+
+// DatafeedStats returns the state of the datafeed.
+func (c *Client) DatafeedStats(ctx context.Context, datafeedID string) (DatafeedStats, error) {
+	var resp struct {
+		Datafeeds []DatafeedStats `json:"datafeeds"`
+	}
+	if err := c.do(ctx, "GET", "/_ml/datafeeds/"+url.PathEscape(datafeedID)+"/_stats", nil, &resp); err != nil {
+		return DatafeedStats{}, err
+	}
+	if len(resp.Datafeeds) != 1 {
+		return DatafeedStats{}, fmt.Errorf("expected the stats of datafeed %s, got %d datafeeds", datafeedID, len(resp.Datafeeds))
+	}
+	return resp.Datafeeds[0], nil
+}
+
+// UpdateDatafeed changes the configuration of the datafeed.
+func (c *Client) UpdateDatafeed(ctx context.Context, datafeedID string, update DatafeedUpdate) error {
+	return c.do(ctx, "POST", "/_ml/datafeeds/"+url.PathEscape(datafeedID)+"/_update", update, nil)
+}
+
+// StartDatafeed starts the datafeed, which runs until it is stopped. A datafeed of a job that has not processed
+// any data starts at the given time, or at the earliest data available if it is zero. Otherwise it continues from
+// the most recent record the job processed.
+func (c *Client) StartDatafeed(ctx context.Context, datafeedID string, start time.Time) error {
+	var body interface{}
+	if !start.IsZero() {
+		body = map[string]string{"start": start.UTC().Format(time.RFC3339)}
+	}
+	return c.do(ctx, "POST", "/_ml/datafeeds/"+url.PathEscape(datafeedID)+"/_start", body, nil)
+}
+
+// StopDatafeed stops the datafeed.
+func (c *Client) StopDatafeed(ctx context.Context, datafeedID string) error {
+	return c.do(ctx, "POST", "/_ml/datafeeds/"+url.PathEscape(datafeedID)+"/_stop", nil, nil)
+}
+
+var byteSizeRegexp = regexp.MustCompile(`^(\d+)(b|kb|mb|gb|tb|pb)?$`)
+
+// ParseByteSize parses an Elasticsearch byte size value, e.g. 512mb, into bytes. A value without a unit is in
+// megabytes, as model memory limits are.
+func ParseByteSize(s string) (int64, error) {
+	m := byteSizeRegexp.FindStringSubmatch(strings.ToLower(strings.TrimSpace(s)))
+	if m == nil {
+		return 0, fmt.Errorf("invalid byte size %q", s)
+	}
+	n, err := strconv.ParseInt(m[1], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid byte size %q", s)
+	}
+	shift := map[string]uint{"b": 0, "kb": 10, "": 20, "mb": 20, "gb": 30, "tb": 40, "pb": 50}[m[2]]
+	return n << shift, nil
+}
+
+var timeValueRegexp = regexp.MustCompile(`^(\d+)(d|h|m|s|ms|micros|nanos)$`)
+
+// ParseTimeValue parses an Elasticsearch time value, e.g. 150s, into a duration.
+func ParseTimeValue(s string) (time.Duration, error) {
+	m := timeValueRegexp.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return 0, fmt.Errorf("invalid time value %q", s)
+	}
+	n, err := strconv.ParseInt(m[1], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time value %q", s)
+	}
+	unit := map[string]time.Duration{
+		"d": 24 * time.Hour, "h": time.Hour, "m": time.Minute, "s": time.Second,
+		"ms": time.Millisecond, "micros": time.Microsecond, "nanos": time.Nanosecond,
+	}[m[2]]
+	return time.Duration(n) * unit, nil
+}
@@ -0,0 +1,147 @@
+// Copyright (c) 2020 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearch_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/tigera/operator/pkg/elasticsearch"
+)
+
+var _ = Describe("Elasticsearch machine learning", func() {
+	var server *httptest.Server
+	var requests []request
+	var responses map[string]string
+	var client *elasticsearch.Client
+	ctx := context.Background()
+
+	BeforeEach(func() {
+		requests = nil
+		responses = map[string]string{}
+		server, client = newStandIn(&requests, responses)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should list the jobs and their datafeeds", func() {
+		responses["GET /_ml/anomaly_detectors"] = `{"count":1,"jobs":[{
+			"job_id":"port_scan_pods",
+			"analysis_limits":{"model_memory_limit":"1024mb"},
+			"custom_settings":{"created_by":"installer"}
+		}]}`
+		responses["GET /_ml/datafeeds"] = `{"count":1,"datafeeds":[{"datafeed_id":"datafeed-port_scan_pods","job_id":"port_scan_pods","frequency":"150s"}]}`
+
+		jobs, err := client.Jobs(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(jobs).To(Equal([]elasticsearch.Job{{
+			ID:             "port_scan_pods",
+			AnalysisLimits: elasticsearch.AnalysisLimits{ModelMemoryLimit: "1024mb"},
+			CustomSettings: map[string]interface{}{"created_by": "installer"},
+		}}))
+
+		datafeeds, err := client.Datafeeds(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(datafeeds).To(Equal([]elasticsearch.Datafeed{{ID: "datafeed-port_scan_pods", JobID: "port_scan_pods", Frequency: "150s"}}))
+	})
+
+	It("should report the state of a job and its datafeed", func() {
+		responses["GET /_ml/anomaly_detectors/port_scan_pods/_stats"] = `{"count":1,"jobs":[{
+			"job_id":"port_scan_pods",
+			"state":"opened",
+			"data_counts":{"latest_record_timestamp":1577836800000},
+			"model_size_stats":{"memory_status":"soft_limit"}
+		}]}`
+		responses["GET /_ml/datafeeds/datafeed-port_scan_pods/_stats"] = `{"count":1,"datafeeds":[{"datafeed_id":"datafeed-port_scan_pods","state":"started"}]}`
+
+		stats, err := client.JobStats(ctx, "port_scan_pods")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stats).To(Equal(elasticsearch.JobStats{
+			ID:             "port_scan_pods",
+			State:          elasticsearch.JobStateOpened,
+			DataCounts:     elasticsearch.DataCounts{LatestRecordTimestamp: 1577836800000},
+			ModelSizeStats: elasticsearch.ModelSizeStats{MemoryStatus: "soft_limit"},
+		}))
+
+		feedStats, err := client.DatafeedStats(ctx, "datafeed-port_scan_pods")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(feedStats).To(Equal(elasticsearch.DatafeedStats{ID: "datafeed-port_scan_pods", State: elasticsearch.DatafeedStateStarted}))
+	})
+
+	It("should update, open and close a job", func() {
+		responses["POST /_ml/anomaly_detectors/port_scan_pods/_update"] = `{"job_id":"port_scan_pods"}`
+		responses["POST /_ml/anomaly_detectors/port_scan_pods/_open"] = `{"opened":true}`
+		responses["POST /_ml/anomaly_detectors/port_scan_pods/_close"] = `{"closed":true}`
+
+		Expect(client.UpdateJob(ctx, "port_scan_pods", elasticsearch.JobUpdate{
+			AnalysisLimits: &elasticsearch.AnalysisLimits{ModelMemoryLimit: "512mb"},
+			CustomSettings: map[string]interface{}{},
+		})).To(Succeed())
+		Expect(client.OpenJob(ctx, "port_scan_pods")).To(Succeed())
+		Expect(client.CloseJob(ctx, "port_scan_pods")).To(Succeed())
+
+		Expect(requests).To(HaveLen(3))
+		Expect(requests[0].body).To(Equal(map[string]interface{}{
+			"analysis_limits": map[string]interface{}{"model_memory_limit": "512mb"},
+			"custom_settings": map[string]interface{}{},
+		}))
+	})
+
+	It("should update, start and stop a datafeed", func() {
+		responses["POST /_ml/datafeeds/datafeed-port_scan_pods/_update"] = `{"datafeed_id":"datafeed-port_scan_pods"}`
+		responses["POST /_ml/datafeeds/datafeed-port_scan_pods/_start"] = `{"started":true}`
+		responses["POST /_ml/datafeeds/datafeed-port_scan_pods/_stop"] = `{"stopped":true}`
+
+		Expect(client.UpdateDatafeed(ctx, "datafeed-port_scan_pods", elasticsearch.DatafeedUpdate{Frequency: "300s"})).To(Succeed())
+		Expect(client.StartDatafeed(ctx, "datafeed-port_scan_pods", time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))).To(Succeed())
+		Expect(client.StartDatafeed(ctx, "datafeed-port_scan_pods", time.Time{})).To(Succeed())
+		Expect(client.StopDatafeed(ctx, "datafeed-port_scan_pods")).To(Succeed())
+
+		Expect(requests).To(HaveLen(4))
+		Expect(requests[0].body).To(Equal(map[string]interface{}{"frequency": "300s"}))
+		Expect(requests[1].body).To(Equal(map[string]interface{}{"start": "2020-01-01T00:00:00Z"}))
+		Expect(requests[2].body).To(BeNil())
+	})
+
+	It("should return Elasticsearch errors", func() {
+		err := client.OpenJob(ctx, "missing")
+		Expect(err).To(HaveOccurred())
+		Expect(elasticsearch.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should parse byte sizes and time values", func() {
+		for s, expected := range map[string]int64{"512mb": 512 << 20, "512": 512 << 20, "1GB": 1 << 30, "2048kb": 2 << 20} {
+			size, err := elasticsearch.ParseByteSize(s)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(size).To(Equal(expected), s)
+		}
+		_, err := elasticsearch.ParseByteSize("lots")
+		Expect(err).To(HaveOccurred())
+
+		for s, expected := range map[string]time.Duration{"150s": 150 * time.Second, "1d": 24 * time.Hour, "500ms": 500 * time.Millisecond} {
+			d, err := elasticsearch.ParseTimeValue(s)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(d).To(Equal(expected), s)
+		}
+		_, err = elasticsearch.ParseTimeValue("5 minutes")
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,67 @@
+// Copyright (c) 2020 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearch
+
+import (
+	"context"
+	"net/url"
+)
+
+// ClusterInfo identifies an Elasticsearch cluster.
+type ClusterInfo struct {
+	Name    string
+	Version string
+}
+
+// Role is a set of cluster and index privileges that users are granted.
+type Role struct {
+	Cluster []string          `json:"cluster,omitempty"`
+	Indices []IndexPrivileges `json:"indices,omitempty"`
+}
+
+// IndexPrivileges grants privileges on the indices matching the names, which may contain wildcards.
+type IndexPrivileges struct {
+	Names      []string `json:"names"`
+	Privileges []string `json:"privileges"`
+}
+
+// Info returns the name and version of the cluster. It fails if the cluster cannot be reached or the credentials
+// of the client are rejected.
+func (c *Client) Info(ctx context.Context) (ClusterInfo, error) {
+	var resp struct {
+		ClusterName string `json:"cluster_name"`
+		Version     struct {
+			Number string `json:"number"`
+		} `json:"version"`
+	}
+	if err := c.do(ctx, "GET", "/", nil, &resp); err != nil {
+		return ClusterInfo{}, err
+	}
+	return ClusterInfo{Name: resp.ClusterName, Version: resp.Version.Number}, nil
+}
+
+// PutRole creates or updates the role.
+func (c *Client) PutRole(ctx context.Context, name string, role Role) error {
+	return c.do(ctx, "PUT", "/_security/role/"+url.PathEscape(name), role, nil)
+}
+
+// PutUser creates or updates the native user, with the password and roles given.
+func (c *Client) PutUser(ctx context.Context, username, password string, roles []string) error {
+	body := map[string]interface{}{
+		"password": password,
+		"roles":    roles,
+	}
+	return c.do(ctx, "PUT", "/_security/user/"+url.PathEscape(username), body, nil)
+}
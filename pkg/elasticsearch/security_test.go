@@ -0,0 +1,73 @@
+// Copyright (c) 2020 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearch_test
+
+import (
+	"context"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/tigera/operator/pkg/elasticsearch"
+)
+
+var _ = Describe("Elasticsearch security", func() {
+	var server *httptest.Server
+	var requests []request
+	var responses map[string]string
+	var client *elasticsearch.Client
+	ctx := context.Background()
+
+	BeforeEach(func() {
+		requests = nil
+		responses = map[string]string{}
+		server, client = newStandIn(&requests, responses)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should identify the cluster", func() {
+		responses["GET /"] = `{"cluster_name":"logs","version":{"number":"7.6.2"}}`
+		info, err := client.Info(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(info).To(Equal(elasticsearch.ClusterInfo{Name: "logs", Version: "7.6.2"}))
+	})
+
+	It("should create a role and a user granted it", func() {
+		responses["PUT /_security/role/tigera-fluentd"] = `{"role":{"created":true}}`
+		responses["PUT /_security/user/tigera-fluentd"] = `{"created":true}`
+		Expect(client.PutRole(ctx, "tigera-fluentd", elasticsearch.Role{
+			Cluster: []string{"monitor"},
+			Indices: []elasticsearch.IndexPrivileges{{Names: []string{"tigera_secure_ee_flows.*"}, Privileges: []string{"write"}}},
+		})).To(Succeed())
+		Expect(client.PutUser(ctx, "tigera-fluentd", "secret", []string{"tigera-fluentd"})).To(Succeed())
+
+		Expect(requests).To(HaveLen(2))
+		Expect(requests[0].body).To(Equal(map[string]interface{}{
+			"cluster": []interface{}{"monitor"},
+			"indices": []interface{}{map[string]interface{}{
+				"names":      []interface{}{"tigera_secure_ee_flows.*"},
+				"privileges": []interface{}{"write"},
+			}},
+		}))
+		Expect(requests[1].body).To(Equal(map[string]interface{}{
+			"password": "secret",
+			"roles":    []interface{}{"tigera-fluentd"},
+		}))
+	})
+})
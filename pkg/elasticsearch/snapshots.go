@@ -0,0 +1,111 @@
+// Copyright (c) 2020 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ErrNoSnapshot is returned when a repository has no snapshot to restore from.
+var ErrNoSnapshot = errors.New("the snapshot repository has no successful snapshots")
+
+// SnapshotRepository is the definition of a snapshot repository.
+type SnapshotRepository struct {
+	Type     string                 `json:"type"`
+	Settings map[string]interface{} `json:"settings"`
+}
+
+// Snapshot describes a snapshot in a snapshot repository.
+type Snapshot struct {
+	Name            string   `json:"snapshot"`
+	State           string   `json:"state"`
+	Indices         []string `json:"indices"`
+	EndTimeInMillis int64    `json:"end_time_in_millis"`
+}
+
+// RestoreRequest selects the indices to restore from a snapshot.
+type RestoreRequest struct {
+	Indices            string `json:"indices"`
+	IncludeGlobalState bool   `json:"include_global_state"`
+	RenamePattern      string `json:"rename_pattern,omitempty"`
+	RenameReplacement  string `json:"rename_replacement,omitempty"`
+}
+
+// PutSnapshotRepository registers the snapshot repository, or updates the repository registered under the name.
+func (c *Client) PutSnapshotRepository(ctx context.Context, name string, repo SnapshotRepository) error {
+	return c.do(ctx, "PUT", "/_snapshot/"+url.PathEscape(name), repo, nil)
+}
+
+// Snapshots lists the snapshots in the repository.
+func (c *Client) Snapshots(ctx context.Context, repo string) ([]Snapshot, error) {
+	var resp struct {
+		Snapshots []Snapshot `json:"snapshots"`
+	}
+	if err := c.do(ctx, "GET", "/_snapshot/"+url.PathEscape(repo)+"/_all", nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Snapshots, nil
+}
+
+// LatestSnapshot returns the name of the most recent successful snapshot in the repository.
+func (c *Client) LatestSnapshot(ctx context.Context, repo string) (string, error) {
+	snapshots, err := c.Snapshots(ctx, repo)
+	if err != nil {
+		return "", err
+	}
+	var latest *Snapshot
+	for i := range snapshots {
+		s := &snapshots[i]
+		if s.State == "SUCCESS" && (latest == nil || s.EndTimeInMillis > latest.EndTimeInMillis) {
+			latest = s
+		}
+	}
+	if latest == nil {
+		return "", ErrNoSnapshot
+	}
+	return latest.Name, nil
+}
+
+// Restore starts restoring indices from the snapshot. It returns once Elasticsearch has accepted the restore,
+// ActiveSnapshotRecoveries reports when it is done.
+func (c *Client) Restore(ctx context.Context, repo, snapshot string, req RestoreRequest) error {
+	path := fmt.Sprintf("/_snapshot/%s/%s/_restore", url.PathEscape(repo), url.PathEscape(snapshot))
+	return c.do(ctx, "POST", path, req, nil)
+}
+
+// ActiveSnapshotRecoveries returns the number of shards that are being restored from a snapshot.
+func (c *Client) ActiveSnapshotRecoveries(ctx context.Context) (int, error) {
+	resp := map[string]struct {
+		Shards []struct {
+			Type string `json:"type"`
+		} `json:"shards"`
+	}{}
+	if err := c.do(ctx, "GET", "/_recovery?active_only=true", nil, &resp); err != nil {
+		return 0, err
+	}
+	active := 0
+	for _, index := range resp {
+		for _, shard := range index.Shards {
+			if strings.EqualFold(shard.Type, "SNAPSHOT") {
+				active++
+			}
+		}
+	}
+	return active, nil
+}
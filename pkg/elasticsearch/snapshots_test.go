@@ -0,0 +1,102 @@
+// Copyright (c) 2020 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearch_test
+
+import (
+	"context"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/tigera/operator/pkg/elasticsearch"
+)
+
+var _ = Describe("Elasticsearch snapshots", func() {
+	var server *httptest.Server
+	var requests []request
+	var responses map[string]string
+	var client *elasticsearch.Client
+
+	BeforeEach(func() {
+		requests = nil
+		responses = map[string]string{}
+		server, client = newStandIn(&requests, responses)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should register a snapshot repository", func() {
+		responses["PUT /_snapshot/tigera-secure-snapshots"] = `{"acknowledged":true}`
+		Expect(client.PutSnapshotRepository(context.Background(), "tigera-secure-snapshots", elasticsearch.SnapshotRepository{
+			Type:     "s3",
+			Settings: map[string]interface{}{"bucket": "backups", "client": "default"},
+		})).To(Succeed())
+
+		Expect(requests).To(HaveLen(1))
+		Expect(requests[0].body).To(Equal(map[string]interface{}{
+			"type":     "s3",
+			"settings": map[string]interface{}{"bucket": "backups", "client": "default"},
+		}))
+	})
+
+	It("should pick the most recent successful snapshot", func() {
+		responses["GET /_snapshot/tigera-secure-snapshots/_all"] = `{"snapshots":[
+			{"snapshot":"snapshot-1","state":"SUCCESS","end_time_in_millis":1000},
+			{"snapshot":"snapshot-3","state":"FAILED","end_time_in_millis":3000},
+			{"snapshot":"snapshot-2","state":"SUCCESS","end_time_in_millis":2000}
+		]}`
+		name, err := client.LatestSnapshot(context.Background(), "tigera-secure-snapshots")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(name).To(Equal("snapshot-2"))
+
+		By("failing if the repository has no successful snapshots")
+		responses["GET /_snapshot/tigera-secure-snapshots/_all"] = `{"snapshots":[]}`
+		_, err = client.LatestSnapshot(context.Background(), "tigera-secure-snapshots")
+		Expect(err).To(Equal(elasticsearch.ErrNoSnapshot))
+	})
+
+	It("should restore indices and report the shards being restored", func() {
+		responses["POST /_snapshot/tigera-secure-snapshots/snapshot-2/_restore"] = `{"accepted":true}`
+		Expect(client.Restore(context.Background(), "tigera-secure-snapshots", "snapshot-2", elasticsearch.RestoreRequest{
+			Indices:           "tigera_secure_ee_audit_*",
+			RenamePattern:     "(.+)",
+			RenameReplacement: "restored_$1",
+		})).To(Succeed())
+		Expect(requests[0].body).To(Equal(map[string]interface{}{
+			"indices":              "tigera_secure_ee_audit_*",
+			"include_global_state": false,
+			"rename_pattern":       "(.+)",
+			"rename_replacement":   "restored_$1",
+		}))
+
+		responses["GET /_recovery"] = `{
+			"restored_tigera_secure_ee_audit_kube.cluster.2020-01-01": {"shards":[{"type":"SNAPSHOT"},{"type":"SNAPSHOT"}]},
+			"tigera_secure_ee_flows.cluster.2020-01-01": {"shards":[{"type":"PEER"}]}
+		}`
+		active, err := client.ActiveSnapshotRecoveries(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(active).To(Equal(2))
+		Expect(requests[1].query).To(Equal("active_only=true"))
+	})
+
+	It("should return Elasticsearch errors", func() {
+		err := client.Restore(context.Background(), "tigera-secure-snapshots", "missing", elasticsearch.RestoreRequest{Indices: "*"})
+		Expect(err).To(HaveOccurred())
+		Expect(elasticsearch.IsNotFound(err)).To(BeTrue())
+	})
+})
@@ -0,0 +1,64 @@
+// Copyright (c) 2020 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearch_test
+
+import (
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/tigera/operator/pkg/elasticsearch"
+)
+
+// request is a request received by the Elasticsearch stand-in.
+type request struct {
+	method, path, query string
+	body                map[string]interface{}
+}
+
+// newStandIn starts an Elasticsearch stand-in that records the requests it receives and answers them with the
+// response registered for their method and path, or with 404. It returns the server and a client for it.
+func newStandIn(requests *[]request, responses map[string]string) (*httptest.Server, *elasticsearch.Client) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer GinkgoRecover()
+		user, password, ok := r.BasicAuth()
+		Expect(ok).To(BeTrue())
+		Expect(user).To(Equal("elastic"))
+		Expect(password).To(Equal("password"))
+
+		req := request{method: r.Method, path: r.URL.Path, query: r.URL.RawQuery}
+		if b, _ := ioutil.ReadAll(r.Body); len(b) > 0 {
+			Expect(json.Unmarshal(b, &req.body)).To(Succeed())
+		}
+		*requests = append(*requests, req)
+
+		resp, ok := responses[r.Method+" "+r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":"not found"}`))
+			return
+		}
+		_, _ = w.Write([]byte(resp))
+	}))
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	client, err := elasticsearch.NewClient(server.URL, "elastic", "password", ca)
+	Expect(err).NotTo(HaveOccurred())
+	return server, client
+}
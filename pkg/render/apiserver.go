@@ -34,6 +34,9 @@ const (
 	APIServerSecretKeyName  = "apiserver.key"
 	APIServerSecretCertName = "apiserver.crt"
 	apiServiceName          = "tigera-api"
+
+	// TigeraAPIServiceName is the name of the APIService that registers the Tigera aggregated API.
+	TigeraAPIServiceName = "v3.projectcalico.org"
 )
 
 var apiServiceHostname = apiServiceName + "." + APIServerNamespace + ".svc"
@@ -121,7 +124,7 @@ func (c *apiServerComponent) apiServiceRegistration(cert []byte) *v1beta1.APISer
 	s := &v1beta1.APIService{
 		TypeMeta: metav1.TypeMeta{Kind: "APIService", APIVersion: "apiregistration.k8s.io/v1beta1"},
 		ObjectMeta: metav1.ObjectMeta{
-			Name: "v3.projectcalico.org",
+			Name: TigeraAPIServiceName,
 		},
 		Spec: v1beta1.APIServiceSpec{
 			Group:                "projectcalico.org",
@@ -16,6 +16,7 @@ package render
 
 import (
 	"fmt"
+	"sort"
 
 	ocsv1 "github.com/openshift/api/security/v1"
 	v3 "github.com/tigera/api/pkg/apis/projectcalico/v3"
@@ -38,12 +39,61 @@ const (
 	ElasticsearchComplianceReporterUserSecret    = "tigera-ee-compliance-reporter-elasticsearch-access"
 	ElasticsearchComplianceSnapshotterUserSecret = "tigera-ee-compliance-snapshotter-elasticsearch-access"
 	ElasticsearchComplianceServerUserSecret      = "tigera-ee-compliance-server-elasticsearch-access"
-	ElasticsearchCuratorUserSecret               = "tigera-ee-curator-elasticsearch-access"
 )
 
+const (
+	// ComplianceReportTypeLabel is set on the custom GlobalReportTypes rendered for the Compliance CR. Its value is
+	// the name of the report type.
+	ComplianceReportTypeLabel = "operator.tigera.io/compliance-report-type"
+	// ComplianceReportLabel is set on the GlobalReports rendered for the Compliance CR. Its value is the name of the
+	// report.
+	ComplianceReportLabel = "operator.tigera.io/compliance-report"
+)
+
+// ComplianceBuiltInReportTypes are the report types that are installed unless disabled in the Compliance CR.
+var ComplianceBuiltInReportTypes = []operatorv1.ComplianceReportTypeName{
+	operatorv1.ComplianceReportTypeInventory,
+	operatorv1.ComplianceReportTypeNetworkAccess,
+	operatorv1.ComplianceReportTypePolicyAudit,
+	operatorv1.ComplianceReportTypeCISBenchmark,
+}
+
+// ComplianceReportTypeNames returns the names of the GlobalReportTypes rendered for the Compliance CR.
+func ComplianceReportTypeNames(cr *operatorv1.Compliance) map[string]bool {
+	disabled := map[operatorv1.ComplianceReportTypeName]bool{}
+	for _, rt := range cr.Spec.ReportTypes {
+		disabled[rt.Name] = rt.Disabled
+	}
+	names := map[string]bool{}
+	for _, name := range ComplianceBuiltInReportTypes {
+		if !disabled[name] {
+			names[string(name)] = true
+		}
+	}
+	for _, rt := range cr.Spec.ReportTypes {
+		if !rt.Disabled && rt.TemplatesConfigMap != "" {
+			names[string(rt.Name)] = true
+		}
+	}
+	return names
+}
+
+// ComplianceReportNames returns the names of the GlobalReports rendered for the Compliance CR.
+func ComplianceReportNames(cr *operatorv1.Compliance) map[string]bool {
+	names := map[string]bool{}
+	for _, r := range cr.Spec.Reports {
+		names[r.Name] = true
+	}
+	return names
+}
+
+// Compliance renders the compliance components. The reportTemplates map holds the template ConfigMaps referenced by
+// the report types in the Compliance CR, keyed by report type name.
 func Compliance(
 	esSecrets []*corev1.Secret,
 	installation *operatorv1.Installation,
+	compliance *operatorv1.Compliance,
+	reportTemplates map[operatorv1.ComplianceReportTypeName]*corev1.ConfigMap,
 	esClusterConfig *ElasticsearchClusterConfig,
 	pullSecrets []*corev1.Secret,
 	openshift bool,
@@ -51,6 +101,8 @@ func Compliance(
 	return &complianceComponent{
 		esSecrets:       esSecrets,
 		installation:    installation,
+		compliance:      compliance,
+		reportTemplates: reportTemplates,
 		esClusterConfig: esClusterConfig,
 		pullSecrets:     pullSecrets,
 		openshift:       openshift,
@@ -60,6 +112,8 @@ func Compliance(
 type complianceComponent struct {
 	esSecrets       []*corev1.Secret
 	installation    *operatorv1.Installation
+	compliance      *operatorv1.Compliance
+	reportTemplates map[operatorv1.ComplianceReportTypeName]*corev1.ConfigMap
 	esClusterConfig *ElasticsearchClusterConfig
 	pullSecrets     []*corev1.Secret
 	openshift       bool
@@ -92,12 +146,9 @@ func (c *complianceComponent) Objects() []runtime.Object {
 		c.complianceBenchmarkerClusterRole(),
 		c.complianceBenchmarkerClusterRoleBinding(),
 		c.complianceBenchmarkerDaemonSet(),
-
-		c.complianceGlobalReportInventory(),
-		c.complianceGlobalReportNetworkAccess(),
-		c.complianceGlobalReportPolicyAudit(),
-		c.complianceGlobalReportCISBenchmark(),
 	)
+	complianceObjs = append(complianceObjs, c.complianceGlobalReportTypes()...)
+	complianceObjs = append(complianceObjs, c.complianceGlobalReports()...)
 
 	// Compliance server is only for Standalone or Management clusters
 	if c.installation.Spec.ClusterManagementType != operatorv1.ClusterManagementTypeManaged {
@@ -276,7 +327,7 @@ func (c *complianceComponent) complianceControllerDeployment() *appsv1.Deploymen
 							Image:         constructImage(ComplianceControllerImage, c.installation.Spec.Registry),
 							Env:           envVars,
 							LivenessProbe: complianceLivenessProbe,
-						}, c.esClusterConfig.ClusterName(), ElasticsearchComplianceControllerUserSecret),
+						}, c.esClusterConfig, ElasticsearchComplianceControllerUserSecret),
 					},
 				}),
 			},
@@ -372,7 +423,7 @@ func (c *complianceComponent) complianceReporterPodTemplate() *corev1.PodTemplat
 							VolumeMounts: []corev1.VolumeMount{
 								{MountPath: "/var/log/calico", Name: "var-log-calico"},
 							},
-						}, c.esClusterConfig.ClusterName(), ElasticsearchComplianceReporterUserSecret), c.esClusterConfig.Replicas(), c.esClusterConfig.Shards(),
+						}, c.esClusterConfig, ElasticsearchComplianceReporterUserSecret), c.esClusterConfig.Replicas(), c.esClusterConfig.Shards(),
 					),
 				},
 				Volumes: []corev1.Volume{
@@ -527,7 +578,7 @@ func (c *complianceComponent) complianceServerDeployment() *appsv1.Deployment {
 								PeriodSeconds:       10,
 								FailureThreshold:    5,
 							},
-						}, c.esClusterConfig.ClusterName(), ElasticsearchComplianceServerUserSecret),
+						}, c.esClusterConfig, ElasticsearchComplianceServerUserSecret),
 					},
 				}),
 			},
@@ -592,8 +643,12 @@ func (c *complianceComponent) complianceSnapshotterDeployment() *appsv1.Deployme
 		{Name: "LOG_LEVEL", Value: "info"},
 		{Name: "TIGERA_COMPLIANCE_JOB_NAMESPACE", Value: ComplianceNamespace},
 		{Name: "TIGERA_COMPLIANCE_MAX_FAILED_JOBS_HISTORY", Value: "3"},
-		{Name: "TIGERA_COMPLIANCE_SNAPSHOT_HOUR", Value: "0"},
 	}
+	snapshotHour := int32(0)
+	if c.compliance.Spec.Snapshotter != nil && c.compliance.Spec.Snapshotter.Hour != nil {
+		snapshotHour = *c.compliance.Spec.Snapshotter.Hour
+	}
+	envVars = append(envVars, corev1.EnvVar{Name: "TIGERA_COMPLIANCE_SNAPSHOT_HOUR", Value: fmt.Sprintf("%d", snapshotHour)})
 
 	return ElasticsearchDecorateAnnotations(&appsv1.Deployment{
 		TypeMeta: metav1.TypeMeta{Kind: "Deployment", APIVersion: "apps/v1"},
@@ -635,7 +690,7 @@ func (c *complianceComponent) complianceSnapshotterDeployment() *appsv1.Deployme
 								Image:         constructImage(ComplianceSnapshotterImage, c.installation.Spec.Registry),
 								Env:           envVars,
 								LivenessProbe: complianceLivenessProbe,
-							}, c.esClusterConfig.ClusterName(), ElasticsearchComplianceSnapshotterUserSecret), c.esClusterConfig.Replicas(), c.esClusterConfig.Shards(),
+							}, c.esClusterConfig, ElasticsearchComplianceSnapshotterUserSecret), c.esClusterConfig.Replicas(), c.esClusterConfig.Shards(),
 						),
 					},
 				}),
@@ -745,7 +800,7 @@ func (c *complianceComponent) complianceBenchmarkerDaemonSet() *appsv1.DaemonSet
 					},
 				},
 				Spec: ElasticsearchPodSpecDecorate(corev1.PodSpec{
-					NodeSelector:       map[string]string{"beta.kubernetes.io/os": "linux"},
+					NodeSelector:       c.complianceBenchmarkerNodeSelector(),
 					ServiceAccountName: "tigera-compliance-benchmarker",
 					HostPID:            true,
 					Tolerations: []corev1.Toleration{
@@ -771,7 +826,7 @@ func (c *complianceComponent) complianceBenchmarkerDaemonSet() *appsv1.DaemonSet
 								Env:           envVars,
 								VolumeMounts:  volMounts,
 								LivenessProbe: complianceLivenessProbe,
-							}, c.esClusterConfig.ClusterName(), ElasticsearchComplianceBenchmarkerUserSecret), c.esClusterConfig.Replicas(), c.esClusterConfig.Shards(),
+							}, c.esClusterConfig, ElasticsearchComplianceBenchmarkerUserSecret), c.esClusterConfig.Replicas(), c.esClusterConfig.Shards(),
 						),
 					},
 					Volumes: vols,
@@ -805,6 +860,108 @@ func (c *complianceComponent) complianceBenchmarkerSecurityContextConstraints()
 	}
 }
 
+// complianceBenchmarkerNodeSelector returns the default Linux node selector merged with any node selector configured
+// in the Compliance CR.
+func (c *complianceComponent) complianceBenchmarkerNodeSelector() map[string]string {
+	nodeSelector := map[string]string{"beta.kubernetes.io/os": "linux"}
+	if c.compliance.Spec.Benchmarker != nil {
+		for k, v := range c.compliance.Spec.Benchmarker.NodeSelector {
+			nodeSelector[k] = v
+		}
+	}
+	return nodeSelector
+}
+
+// complianceGlobalReportTypes returns the built-in report types that have not been disabled, followed by any custom
+// report types configured in the Compliance CR. Report types with a template ConfigMap have their templates replaced
+// by the contents of the ConfigMap.
+func (c *complianceComponent) complianceGlobalReportTypes() []runtime.Object {
+	configured := map[operatorv1.ComplianceReportTypeName]operatorv1.ComplianceReportType{}
+	for _, rt := range c.compliance.Spec.ReportTypes {
+		configured[rt.Name] = rt
+	}
+
+	var objs []runtime.Object
+	builtIn := map[operatorv1.ComplianceReportTypeName]bool{}
+	for _, grt := range []*v3.GlobalReportType{
+		c.complianceGlobalReportInventory(),
+		c.complianceGlobalReportNetworkAccess(),
+		c.complianceGlobalReportPolicyAudit(),
+		c.complianceGlobalReportCISBenchmark(),
+	} {
+		name := operatorv1.ComplianceReportTypeName(grt.Name)
+		builtIn[name] = true
+		if configured[name].Disabled {
+			continue
+		}
+		if cm, ok := c.reportTemplates[name]; ok {
+			setReportTemplates(&grt.Spec, cm)
+		}
+		objs = append(objs, grt)
+	}
+
+	for _, rt := range c.compliance.Spec.ReportTypes {
+		if builtIn[rt.Name] || rt.Disabled {
+			continue
+		}
+		cm, ok := c.reportTemplates[rt.Name]
+		if !ok {
+			continue
+		}
+		grt := &v3.GlobalReportType{
+			TypeMeta: metav1.TypeMeta{Kind: "GlobalReportType", APIVersion: "projectcalico.org/v3"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name: string(rt.Name),
+				Labels: map[string]string{
+					"global-report-type":      string(rt.Name),
+					ComplianceReportTypeLabel: string(rt.Name),
+				},
+			},
+		}
+		setReportTemplates(&grt.Spec, cm)
+		objs = append(objs, grt)
+	}
+	return objs
+}
+
+// setReportTemplates replaces the templates of the report type spec with the templates in the ConfigMap.
+func setReportTemplates(spec *v3.ReportTypeSpec, cm *corev1.ConfigMap) {
+	var keys []string
+	for k := range cm.Data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	spec.DownloadTemplates = nil
+	for _, k := range keys {
+		if k == operatorv1.ComplianceReportTemplateUISummaryKey {
+			spec.UISummaryTemplate = v3.ReportTemplate{Name: k, Template: cm.Data[k]}
+			continue
+		}
+		spec.DownloadTemplates = append(spec.DownloadTemplates, v3.ReportTemplate{Name: k, Template: cm.Data[k]})
+	}
+}
+
+// complianceGlobalReports returns the scheduled reports configured in the Compliance CR.
+func (c *complianceComponent) complianceGlobalReports() []runtime.Object {
+	var objs []runtime.Object
+	for _, r := range c.compliance.Spec.Reports {
+		objs = append(objs, &v3.GlobalReport{
+			TypeMeta: metav1.TypeMeta{Kind: "GlobalReport", APIVersion: "projectcalico.org/v3"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   r.Name,
+				Labels: map[string]string{ComplianceReportLabel: r.Name},
+			},
+			Spec: v3.ReportSpec{
+				ReportType:      string(r.ReportType),
+				Schedule:        r.Schedule,
+				JobNodeSelector: r.JobNodeSelector,
+			},
+		})
+	}
+	return objs
+}
+
 func (c *complianceComponent) complianceGlobalReportInventory() *v3.GlobalReportType {
 	return &v3.GlobalReportType{
 		TypeMeta: metav1.TypeMeta{Kind: "GlobalReportType", APIVersion: "projectcalico.org/v3"},
@@ -18,8 +18,11 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
+	v3 "github.com/tigera/api/pkg/apis/projectcalico/v3"
 	operatorv1 "github.com/tigera/operator/pkg/apis/operator/v1"
 	"github.com/tigera/operator/pkg/render"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 )
 
 var _ = Describe("compliance rendering tests", func() {
@@ -31,7 +34,7 @@ var _ = Describe("compliance rendering tests", func() {
 					Registry:              "testregistry.com/",
 					ClusterManagementType: operatorv1.ClusterManagementTypeStandalone,
 				},
-			}, render.NewElasticsearchClusterConfig("cluster", 1, 1), nil, notOpenshift)
+			}, &operatorv1.Compliance{}, nil, render.NewElasticsearchClusterConfig("cluster", 1, 1), nil, notOpenshift)
 			resources := component.Objects()
 
 			ns := "tigera-compliance"
@@ -95,7 +98,7 @@ var _ = Describe("compliance rendering tests", func() {
 					Registry:              "testregistry.com/",
 					ClusterManagementType: operatorv1.ClusterManagementTypeManaged,
 				},
-			}, render.NewElasticsearchClusterConfig("cluster", 1, 1), nil, notOpenshift)
+			}, &operatorv1.Compliance{}, nil, render.NewElasticsearchClusterConfig("cluster", 1, 1), nil, notOpenshift)
 			resources := component.Objects()
 
 			ns := "tigera-compliance"
@@ -145,4 +148,81 @@ var _ = Describe("compliance rendering tests", func() {
 			ExpectGlobalReportType(resources[22], "cis-benchmark")
 		})
 	})
+
+	Context("Configured reports", func() {
+		installation := &operatorv1.Installation{
+			Spec: operatorv1.InstallationSpec{
+				KubernetesProvider:    operatorv1.ProviderNone,
+				Registry:              "testregistry.com/",
+				ClusterManagementType: operatorv1.ClusterManagementTypeManaged,
+			},
+		}
+
+		It("should omit disabled report types and render custom report types and reports", func() {
+			compliance := &operatorv1.Compliance{
+				Spec: operatorv1.ComplianceSpec{
+					ReportTypes: []operatorv1.ComplianceReportType{
+						{Name: operatorv1.ComplianceReportTypeNetworkAccess, Disabled: true},
+						{Name: operatorv1.ComplianceReportTypeInventory, TemplatesConfigMap: "inventory-templates"},
+						{Name: "custom", TemplatesConfigMap: "custom-templates"},
+					},
+					Reports: []operatorv1.ComplianceReport{
+						{Name: "daily-inventory", ReportType: operatorv1.ComplianceReportTypeInventory, Schedule: "0 0 * * *"},
+					},
+				},
+			}
+			templates := map[operatorv1.ComplianceReportTypeName]*corev1.ConfigMap{
+				operatorv1.ComplianceReportTypeInventory: {
+					Data: map[string]string{"ui-summary.json": "{}", "summary.csv": "summary"},
+				},
+				"custom": {
+					Data: map[string]string{"ui-summary.json": "{}", "b.csv": "b", "a.csv": "a"},
+				},
+			}
+			component := render.Compliance(nil, installation, compliance, templates, render.NewElasticsearchClusterConfig("cluster", 1, 1), nil, notOpenshift)
+			resources := component.Objects()
+
+			Expect(len(resources)).To(Equal(24))
+			ExpectGlobalReportType(resources[19], "inventory")
+			ExpectGlobalReportType(resources[20], "policy-audit")
+			ExpectGlobalReportType(resources[21], "cis-benchmark")
+			ExpectGlobalReportType(resources[22], "custom")
+			ExpectResource(resources[23], "daily-inventory", "", "projectcalico.org", "v3", "GlobalReport")
+
+			inventory := resources[19].(*v3.GlobalReportType)
+			Expect(inventory.Spec.DownloadTemplates).To(Equal([]v3.ReportTemplate{{Name: "summary.csv", Template: "summary"}}))
+			Expect(inventory.Spec.UISummaryTemplate).To(Equal(v3.ReportTemplate{Name: "ui-summary.json", Template: "{}"}))
+
+			custom := resources[22].(*v3.GlobalReportType)
+			Expect(custom.Spec.DownloadTemplates).To(Equal([]v3.ReportTemplate{{Name: "a.csv", Template: "a"}, {Name: "b.csv", Template: "b"}}))
+			Expect(custom.Labels).To(HaveKeyWithValue(render.ComplianceReportTypeLabel, "custom"))
+
+			report := resources[23].(*v3.GlobalReport)
+			Expect(report.Spec.ReportType).To(Equal("inventory"))
+			Expect(report.Spec.Schedule).To(Equal("0 0 * * *"))
+			Expect(report.Labels).To(HaveKeyWithValue(render.ComplianceReportLabel, report.Name))
+		})
+
+		It("should configure the snapshotter hour and benchmarker node selector", func() {
+			snapshotHour := int32(6)
+			compliance := &operatorv1.Compliance{
+				Spec: operatorv1.ComplianceSpec{
+					Snapshotter: &operatorv1.ComplianceSnapshotter{Hour: &snapshotHour},
+					Benchmarker: &operatorv1.ComplianceBenchmarker{NodeSelector: map[string]string{"compliance": "true"}},
+				},
+			}
+			component := render.Compliance(nil, installation, compliance, nil, render.NewElasticsearchClusterConfig("cluster", 1, 1), nil, notOpenshift)
+			resources := component.Objects()
+
+			snapshotter := GetResource(resources, "compliance-snapshotter", "tigera-compliance", "apps", "v1", "Deployment").(*appsv1.Deployment)
+			ExpectEnv(snapshotter.Spec.Template.Spec.Containers[0].Env, "TIGERA_COMPLIANCE_SNAPSHOT_HOUR", "6")
+
+			benchmarker := GetResource(resources, "compliance-benchmarker", "tigera-compliance", "apps", "v1", "DaemonSet").(*appsv1.DaemonSet)
+			Expect(benchmarker.Spec.Template.Spec.NodeSelector).To(Equal(map[string]string{
+				"beta.kubernetes.io/os": "linux",
+				"compliance":            "true",
+			}))
+		})
+	})
+
 })
@@ -0,0 +1,376 @@
+// Copyright (c) 2020 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// This renderer is responsible for the identity broker (Dex) that lets users log in to the
+// manager with LDAP, SAML or GitHub accounts.
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	operator "github.com/tigera/operator/pkg/apis/operator/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	DexName                 = "tigera-dex"
+	DexNamespace            = DexName
+	DexTLSSecretName        = "tigera-dex-tls"
+	DexPublicCertSecretName = "tigera-dex-public-cert"
+	DexConfigSecretName     = "tigera-dex-config"
+	DexClientID             = "tigera-manager"
+	DexPort                 = 5556
+
+	// The keys of the connector Secrets.
+	DexSecretKeyBindDN       = "bindDN"
+	DexSecretKeyBindPW       = "bindPW"
+	DexSecretKeyCA           = "ca.crt"
+	DexSecretKeyClientID     = "clientID"
+	DexSecretKeyClientSecret = "clientSecret"
+
+	dexConfigKey            = "config.yaml"
+	dexConfigHashAnnotation = "hash.operator.tigera.io/dex-config"
+	dexTLSHashAnnotation    = "hash.operator.tigera.io/dex-tls"
+	dexBasePath             = "/dex"
+)
+
+// DexURL is the in-cluster URL of the identity broker.
+var DexURL = fmt.Sprintf("https://%s.%s.svc.cluster.local:%d", DexName, DexNamespace, DexPort)
+
+// CreateDexTLSSecret generates the certificate the identity broker serves with.
+func CreateDexTLSSecret() (*corev1.Secret, error) {
+	return createOperatorTLSSecret(nil,
+		DexTLSSecretName,
+		corev1.TLSPrivateKeyKey,
+		corev1.TLSCertKey,
+		nil,
+		DexName,
+		fmt.Sprintf("%s.%s", DexName, DexNamespace),
+		fmt.Sprintf("%s.%s.svc", DexName, DexNamespace),
+		fmt.Sprintf("%s.%s.svc.cluster.local", DexName, DexNamespace),
+	)
+}
+
+// DexAuth returns the manager authentication configuration that uses the identity broker
+// configured in the given Manager.
+func DexAuth(cr *operator.Manager) *operator.Auth {
+	auth := &operator.Auth{}
+	if cr.Spec.Auth != nil {
+		auth = cr.Spec.Auth.DeepCopy()
+	}
+	auth.Type = operator.AuthTypeOIDC
+	auth.Authority = dexIssuer(cr)
+	auth.ClientID = DexClientID
+	auth.Scopes = []string{"email", "profile", "groups"}
+	auth.UsernameClaim = "email"
+	auth.GroupsClaim = "groups"
+	// The Kubernetes API server is not configured to trust the broker, so the manager proxy
+	// authenticates the users.
+	auth.KubernetesAuthentication = operator.KubernetesAuthenticationProxy
+	return auth
+}
+
+// dexManagerDomain returns the URL users reach the manager at, defaulting to the URL the
+// manager is exposed at.
+func dexManagerDomain(cr *operator.Manager) string {
+	domain := cr.Spec.IdentityBroker.ManagerDomain
+	if domain == "" {
+		domain = ManagerExternalURL(cr)
+	}
+	return strings.TrimSuffix(domain, "/")
+}
+
+func dexIssuer(cr *operator.Manager) string {
+	return dexManagerDomain(cr) + dexBasePath
+}
+
+// Dex renders the identity broker. The TLS secret is rendered into the operator namespace, so that
+// a generated certificate is kept, and copied into the identity broker namespace.
+func Dex(
+	cr *operator.Manager,
+	connectorSecrets map[string]*corev1.Secret,
+	tlsSecret *corev1.Secret,
+	pullSecrets []*corev1.Secret,
+	openshift bool,
+	registry string,
+) (Component, error) {
+	config, err := dexConfig(cr, connectorSecrets)
+	if err != nil {
+		return nil, err
+	}
+	return &dexComponent{
+		config:      config,
+		tlsSecrets:  append(copySecrets(OperatorNamespace(), tlsSecret), copySecrets(DexNamespace, tlsSecret)...),
+		pullSecrets: pullSecrets,
+		openshift:   openshift,
+		registry:    registry,
+	}, nil
+}
+
+type dexComponent struct {
+	config      *corev1.Secret
+	tlsSecrets  []*corev1.Secret
+	pullSecrets []*corev1.Secret
+	openshift   bool
+	registry    string
+}
+
+func (c *dexComponent) Objects() []runtime.Object {
+	objs := []runtime.Object{
+		createNamespace(DexNamespace, c.openshift),
+	}
+	objs = append(objs, copyImagePullSecrets(c.pullSecrets, DexNamespace)...)
+	objs = append(objs, secretsToRuntimeObjects(c.tlsSecrets...)...)
+	objs = append(objs,
+		c.serviceAccount(),
+		c.config,
+		c.deployment(),
+		c.service(),
+	)
+	return objs
+}
+
+func (c *dexComponent) Ready() bool {
+	return true
+}
+
+func (c *dexComponent) serviceAccount() *corev1.ServiceAccount {
+	return &corev1.ServiceAccount{
+		TypeMeta:   metav1.TypeMeta{Kind: "ServiceAccount", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: DexName, Namespace: DexNamespace},
+	}
+}
+
+// deployment returns the identity broker deployment. Dex keeps its state in memory, so it runs
+// a single replica.
+func (c *dexComponent) deployment() *appsv1.Deployment {
+	var replicas int32 = 1
+	return &appsv1.Deployment{
+		TypeMeta: metav1.TypeMeta{Kind: "Deployment", APIVersion: "apps/v1"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      DexName,
+			Namespace: DexNamespace,
+			Labels:    map[string]string{"k8s-app": DexName},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"k8s-app": DexName}},
+			Replicas: &replicas,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      DexName,
+					Namespace: DexNamespace,
+					Labels:    map[string]string{"k8s-app": DexName},
+					Annotations: map[string]string{
+						dexConfigHashAnnotation: AnnotationHash(c.config.Data),
+						dexTLSHashAnnotation:    AnnotationHash(c.tlsSecrets[0].Data),
+					},
+				},
+				Spec: corev1.PodSpec{
+					NodeSelector:       map[string]string{"beta.kubernetes.io/os": "linux"},
+					ServiceAccountName: DexName,
+					ImagePullSecrets:   getImagePullSecretReferenceList(c.pullSecrets),
+					Containers: []corev1.Container{
+						{
+							Name:    DexName,
+							Image:   constructImage(DexImageName, c.registry),
+							Command: []string{"/usr/local/bin/dex", "serve", "/etc/dex/config/" + dexConfigKey},
+							Ports: []corev1.ContainerPort{
+								{Name: "https", ContainerPort: DexPort, Protocol: corev1.ProtocolTCP},
+							},
+							ReadinessProbe: &corev1.Probe{
+								Handler: corev1.Handler{
+									HTTPGet: &corev1.HTTPGetAction{
+										Path:   dexBasePath + "/healthz",
+										Port:   intstr.FromInt(DexPort),
+										Scheme: corev1.URISchemeHTTPS,
+									},
+								},
+							},
+							VolumeMounts: []corev1.VolumeMount{
+								{Name: "config", MountPath: "/etc/dex/config", ReadOnly: true},
+								{Name: "tls", MountPath: "/etc/dex/tls", ReadOnly: true},
+							},
+							SecurityContext: securityContext(),
+						},
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name: "config",
+							VolumeSource: corev1.VolumeSource{
+								Secret: &corev1.SecretVolumeSource{SecretName: DexConfigSecretName},
+							},
+						},
+						{
+							Name: "tls",
+							VolumeSource: corev1.VolumeSource{
+								Secret: &corev1.SecretVolumeSource{SecretName: DexTLSSecretName},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (c *dexComponent) service() *corev1.Service {
+	return &corev1.Service{
+		TypeMeta:   metav1.TypeMeta{Kind: "Service", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: DexName, Namespace: DexNamespace},
+		Spec: corev1.ServiceSpec{
+			Selector: map[string]string{"k8s-app": DexName},
+			Ports: []corev1.ServicePort{
+				{
+					Name:       "https",
+					Port:       DexPort,
+					TargetPort: intstr.FromInt(DexPort),
+					Protocol:   corev1.ProtocolTCP,
+				},
+			},
+		},
+	}
+}
+
+// dexConfig renders the Dex configuration file into a Secret, since it contains the credentials
+// of the connectors.
+func dexConfig(cr *operator.Manager, connectorSecrets map[string]*corev1.Secret) (*corev1.Secret, error) {
+	issuer := dexIssuer(cr)
+	domain := dexManagerDomain(cr)
+	callback := issuer + "/callback"
+
+	connectors := []map[string]interface{}{}
+	for _, conn := range cr.Spec.IdentityBroker.Connectors {
+		var data map[string][]byte
+		if s := connectorSecrets[conn.SecretName]; s != nil {
+			data = s.Data
+		}
+
+		var connType string
+		var config map[string]interface{}
+		switch conn.Type {
+		case operator.IdentityConnectorTypeLDAP:
+			connType = "ldap"
+			config = ldapConnectorConfig(conn.LDAP, data)
+		case operator.IdentityConnectorTypeSAML:
+			connType = "saml"
+			config = map[string]interface{}{
+				"ssoURL":       conn.SAML.SSOURL,
+				"caData":       data[DexSecretKeyCA],
+				"redirectURI":  callback,
+				"entityIssuer": conn.SAML.EntityIssuer,
+				"usernameAttr": valueOrDefault(conn.SAML.UsernameAttribute, "name"),
+				"emailAttr":    valueOrDefault(conn.SAML.EmailAttribute, "email"),
+				"groupsAttr":   conn.SAML.GroupsAttribute,
+			}
+		case operator.IdentityConnectorTypeGitHub:
+			connType = "github"
+			orgs := []map[string]string{}
+			if conn.GitHub != nil {
+				for _, org := range conn.GitHub.Orgs {
+					orgs = append(orgs, map[string]string{"name": org})
+				}
+			}
+			config = map[string]interface{}{
+				"clientID":      string(data[DexSecretKeyClientID]),
+				"clientSecret":  string(data[DexSecretKeyClientSecret]),
+				"redirectURI":   callback,
+				"orgs":          orgs,
+				"teamNameField": "slug",
+			}
+		default:
+			return nil, fmt.Errorf("unsupported identity connector type %s", conn.Type)
+		}
+		connectors = append(connectors, map[string]interface{}{
+			"type":   connType,
+			"id":     conn.Name,
+			"name":   conn.Name,
+			"config": config,
+		})
+	}
+
+	// JSON is valid YAML, and encoding/json takes care of base64 encoding the CA data.
+	config, err := json.MarshalIndent(map[string]interface{}{
+		"issuer":  issuer,
+		"storage": map[string]string{"type": "memory"},
+		"web": map[string]string{
+			"https":   fmt.Sprintf("0.0.0.0:%d", DexPort),
+			"tlsCert": "/etc/dex/tls/" + corev1.TLSCertKey,
+			"tlsKey":  "/etc/dex/tls/" + corev1.TLSPrivateKeyKey,
+		},
+		"oauth2": map[string]interface{}{
+			"skipApprovalScreen": true,
+		},
+		"staticClients": []map[string]interface{}{
+			{
+				"id":           DexClientID,
+				"name":         "Tigera Secure manager",
+				"public":       true,
+				"redirectURIs": []string{domain + "/login/oidc/callback", domain + "/login/oidc/silent-callback"},
+			},
+		},
+		"connectors": connectors,
+	}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return &corev1.Secret{
+		TypeMeta:   metav1.TypeMeta{Kind: "Secret", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: DexConfigSecretName, Namespace: DexNamespace},
+		Data:       map[string][]byte{dexConfigKey: config},
+	}, nil
+}
+
+func ldapConnectorConfig(ldap *operator.LDAPConnector, data map[string][]byte) map[string]interface{} {
+	config := map[string]interface{}{
+		"host":     ldap.Host,
+		"startTLS": ldap.StartTLS,
+		"bindDN":   string(data[DexSecretKeyBindDN]),
+		"bindPW":   string(data[DexSecretKeyBindPW]),
+		"userSearch": map[string]string{
+			"baseDN":    ldap.UserSearch.BaseDN,
+			"filter":    ldap.UserSearch.Filter,
+			"username":  valueOrDefault(ldap.UserSearch.UsernameAttribute, "uid"),
+			"idAttr":    "DN",
+			"emailAttr": valueOrDefault(ldap.UserSearch.EmailAttribute, "mail"),
+			"nameAttr":  valueOrDefault(ldap.UserSearch.NameAttribute, "cn"),
+		},
+	}
+	if ca, ok := data[DexSecretKeyCA]; ok {
+		config["rootCAData"] = ca
+	}
+	if gs := ldap.GroupSearch; gs != nil {
+		config["groupSearch"] = map[string]string{
+			"baseDN":    gs.BaseDN,
+			"filter":    gs.Filter,
+			"userAttr":  valueOrDefault(gs.UserAttribute, "DN"),
+			"groupAttr": valueOrDefault(gs.GroupAttribute, "member"),
+			"nameAttr":  valueOrDefault(gs.NameAttribute, "cn"),
+		}
+	}
+	return config
+}
+
+func valueOrDefault(value, def string) string {
+	if value == "" {
+		return def
+	}
+	return value
+}
@@ -0,0 +1,175 @@
+// Copyright (c) 2020 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render_test
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	operator "github.com/tigera/operator/pkg/apis/operator/v1"
+	"github.com/tigera/operator/pkg/render"
+)
+
+var _ = Describe("Identity broker rendering tests", func() {
+	var instance *operator.Manager
+	var connectorSecrets map[string]*corev1.Secret
+	var tlsSecret *corev1.Secret
+
+	BeforeEach(func() {
+		instance = &operator.Manager{
+			Spec: operator.ManagerSpec{
+				Auth: &operator.Auth{Type: operator.AuthTypeOIDC, GroupsPrefix: "dex:"},
+				IdentityBroker: &operator.IdentityBroker{
+					ManagerDomain: "https://manager.example.com/",
+					Connectors: []operator.IdentityConnector{
+						{
+							Name:       "corp-ldap",
+							Type:       operator.IdentityConnectorTypeLDAP,
+							SecretName: "ldap-credentials",
+							LDAP: &operator.LDAPConnector{
+								Host:        "ldap.example.com:636",
+								UserSearch:  operator.LDAPUserSearch{BaseDN: "ou=people,dc=example,dc=com"},
+								GroupSearch: &operator.LDAPGroupSearch{BaseDN: "ou=groups,dc=example,dc=com"},
+							},
+						},
+						{
+							Name:       "github",
+							Type:       operator.IdentityConnectorTypeGitHub,
+							SecretName: "github-credentials",
+							GitHub:     &operator.GitHubConnector{Orgs: []string{"example"}},
+						},
+					},
+				},
+			},
+		}
+		connectorSecrets = map[string]*corev1.Secret{
+			"ldap-credentials": {
+				Data: map[string][]byte{
+					render.DexSecretKeyBindDN: []byte("cn=admin,dc=example,dc=com"),
+					render.DexSecretKeyBindPW: []byte("password"),
+					render.DexSecretKeyCA:     []byte("ca"),
+				},
+			},
+			"github-credentials": {
+				Data: map[string][]byte{
+					render.DexSecretKeyClientID:     []byte("id"),
+					render.DexSecretKeyClientSecret: []byte("secret"),
+				},
+			},
+		}
+		var err error
+		tlsSecret, err = render.CreateDexTLSSecret()
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should render all resources for the identity broker", func() {
+		component, err := render.Dex(instance, connectorSecrets, tlsSecret, nil, false, "")
+		Expect(err).NotTo(HaveOccurred())
+		resources := component.Objects()
+
+		expectedResources := []struct {
+			name    string
+			ns      string
+			group   string
+			version string
+			kind    string
+		}{
+			{name: render.DexNamespace, ns: "", group: "", version: "v1", kind: "Namespace"},
+			{name: render.DexTLSSecretName, ns: render.OperatorNamespace(), group: "", version: "v1", kind: "Secret"},
+			{name: render.DexTLSSecretName, ns: render.DexNamespace, group: "", version: "v1", kind: "Secret"},
+			{name: render.DexName, ns: render.DexNamespace, group: "", version: "v1", kind: "ServiceAccount"},
+			{name: render.DexConfigSecretName, ns: render.DexNamespace, group: "", version: "v1", kind: "Secret"},
+			{name: render.DexName, ns: render.DexNamespace, group: "apps", version: "v1", kind: "Deployment"},
+			{name: render.DexName, ns: render.DexNamespace, group: "", version: "v1", kind: "Service"},
+		}
+		Expect(len(resources)).To(Equal(len(expectedResources)))
+		for i, expectedRes := range expectedResources {
+			ExpectResource(resources[i], expectedRes.name, expectedRes.ns, expectedRes.group, expectedRes.version, expectedRes.kind)
+		}
+
+		d := resources[5].(*appsv1.Deployment)
+		Expect(d.Spec.Template.Annotations).To(HaveKey("hash.operator.tigera.io/dex-config"))
+		Expect(d.Spec.Template.Annotations).To(HaveKey("hash.operator.tigera.io/dex-tls"))
+	})
+
+	It("should configure the connectors", func() {
+		component, err := render.Dex(instance, connectorSecrets, tlsSecret, nil, false, "")
+		Expect(err).NotTo(HaveOccurred())
+		resources := component.Objects()
+
+		secret := GetResource(resources, render.DexConfigSecretName, render.DexNamespace, "", "v1", "Secret").(*corev1.Secret)
+		var config struct {
+			Issuer        string `json:"issuer"`
+			StaticClients []struct {
+				ID           string   `json:"id"`
+				RedirectURIs []string `json:"redirectURIs"`
+			} `json:"staticClients"`
+			Connectors []struct {
+				Type   string                 `json:"type"`
+				ID     string                 `json:"id"`
+				Config map[string]interface{} `json:"config"`
+			} `json:"connectors"`
+		}
+		Expect(json.Unmarshal(secret.Data["config.yaml"], &config)).To(Succeed())
+
+		Expect(config.Issuer).To(Equal("https://manager.example.com/dex"))
+		Expect(config.StaticClients).To(HaveLen(1))
+		Expect(config.StaticClients[0].ID).To(Equal(render.DexClientID))
+		Expect(config.StaticClients[0].RedirectURIs).To(ContainElement("https://manager.example.com/login/oidc/callback"))
+
+		Expect(config.Connectors).To(HaveLen(2))
+		Expect(config.Connectors[0].Type).To(Equal("ldap"))
+		Expect(config.Connectors[0].ID).To(Equal("corp-ldap"))
+		Expect(config.Connectors[0].Config["bindPW"]).To(Equal("password"))
+		// CA data is base64 encoded.
+		Expect(config.Connectors[0].Config["rootCAData"]).To(Equal("Y2E="))
+		Expect(config.Connectors[0].Config["userSearch"]).To(HaveKeyWithValue("username", "uid"))
+		Expect(config.Connectors[0].Config["groupSearch"]).To(HaveKeyWithValue("groupAttr", "member"))
+
+		Expect(config.Connectors[1].Type).To(Equal("github"))
+		Expect(config.Connectors[1].Config["clientSecret"]).To(Equal("secret"))
+		Expect(config.Connectors[1].Config["redirectURI"]).To(Equal("https://manager.example.com/dex/callback"))
+	})
+
+	It("should point the manager at the identity broker", func() {
+		auth := render.DexAuth(instance)
+		Expect(auth.Type).To(Equal(operator.AuthType(operator.AuthTypeOIDC)))
+		Expect(auth.Authority).To(Equal("https://manager.example.com/dex"))
+		Expect(auth.ClientID).To(Equal(render.DexClientID))
+		Expect(auth.GroupsPrefix).To(Equal("dex:"))
+		Expect(auth.KubernetesAuthentication).To(Equal(operator.KubernetesAuthenticationProxy))
+
+		instance.Spec.Auth = auth
+		component, err := render.Manager(instance, nil, nil, render.NewElasticsearchClusterConfig("clusterTestName", 1, 1),
+			nil, nil, false, "", nil, nil, tlsSecret, false, nil, "")
+		Expect(err).NotTo(HaveOccurred())
+		resources := component.Objects()
+
+		cert := GetResource(resources, render.DexPublicCertSecretName, render.ManagerNamespace, "", "v1", "Secret").(*corev1.Secret)
+		Expect(cert.Data).To(Equal(map[string][]byte{corev1.TLSCertKey: tlsSecret.Data[corev1.TLSCertKey]}))
+
+		d := GetResource(resources, "tigera-manager", render.ManagerNamespace, "", "v1", "Deployment").(*appsv1.Deployment)
+		voltron := d.Spec.Template.Spec.Containers[2]
+		ExpectEnv(voltron.Env, "VOLTRON_DEX_ENABLED", "true")
+		ExpectEnv(voltron.Env, "VOLTRON_DEX_URL", "https://tigera-dex.tigera-dex.svc.cluster.local:5556/")
+		ExpectEnv(voltron.Env, "VOLTRON_OIDC_AUTH_CA_BUNDLE_PATH", "/certs/dex/tls.crt")
+		Expect(voltron.VolumeMounts).To(ContainElement(corev1.VolumeMount{Name: render.DexPublicCertSecretName, MountPath: "/certs/dex"}))
+	})
+})
@@ -0,0 +1,177 @@
+// Copyright (c) 2020 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"encoding/json"
+
+	esalpha1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1alpha1"
+	kibanaalpha1 "github.com/elastic/cloud-on-k8s/pkg/apis/kibana/v1alpha1"
+	"github.com/tigera/operator/pkg/components"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// The versions of the ECK APIs that Elasticsearch and Kibana can be managed through.
+const (
+	ECKAPIVersionV1alpha1 = "v1alpha1"
+	ECKAPIVersionV1       = "v1"
+)
+
+// ECKDefaultNodeSetName is the name of the single set of Elasticsearch nodes that take every role when it is managed
+// through the ECK v1 API, which requires every node set to be named.
+const ECKDefaultNodeSetName = "default"
+
+const eckControllerVersionAnnotation = "common.k8s.elastic.co/controller-version"
+
+var (
+	ElasticsearchV1GVK = schema.GroupVersionKind{Group: "elasticsearch.k8s.elastic.co", Version: ECKAPIVersionV1, Kind: "Elasticsearch"}
+	KibanaV1GVK        = schema.GroupVersionKind{Group: "kibana.k8s.elastic.co", Version: ECKAPIVersionV1, Kind: "Kibana"}
+
+	elasticsearchV1alpha1GVK = schema.GroupVersionKind{Group: "elasticsearch.k8s.elastic.co", Version: ECKAPIVersionV1alpha1, Kind: "Elasticsearch"}
+	kibanaV1alpha1GVK        = schema.GroupVersionKind{Group: "kibana.k8s.elastic.co", Version: ECKAPIVersionV1alpha1, Kind: "Kibana"}
+)
+
+// The cloud-on-k8s release the operator builds against only has the v1alpha1 types, so the v1 Elasticsearch and
+// Kibana resources are handled as unstructured objects. Both versions share their fields except for the ones
+// renamed below, which is what the conversions rely on.
+
+// ElasticsearchV1 converts a v1alpha1 Elasticsearch to the v1 API. The node specs become node sets, which keep their
+// names so that ECK keeps their StatefulSets and volumes. An unnamed node spec is named ECKDefaultNodeSetName.
+func ElasticsearchV1(es *esalpha1.Elasticsearch) (*unstructured.Unstructured, error) {
+	obj, err := toObjectMap(es)
+	if err != nil {
+		return nil, err
+	}
+	if spec, ok := obj["spec"].(map[string]interface{}); ok {
+		nodes, _ := spec["nodes"].([]interface{})
+		for _, n := range nodes {
+			node, ok := n.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			renameField(node, "nodeCount", "count")
+			if name, _ := node["name"].(string); name == "" {
+				node["name"] = ECKDefaultNodeSetName
+			}
+		}
+		delete(spec, "nodes")
+		spec["nodeSets"] = nodes
+		// The v1 API takes a list of secure settings Secrets.
+		if secure, ok := spec["secureSettings"]; ok {
+			spec["secureSettings"] = []interface{}{secure}
+		}
+	}
+	return toUnstructured(obj, ElasticsearchV1GVK)
+}
+
+// ElasticsearchV1alpha1 converts a v1 Elasticsearch back to the v1alpha1 API, for the code that reads its node specs
+// and status. Elasticsearch resources created through the v1alpha1 API that were not yet migrated have node specs
+// rather than node sets, and are read as they are.
+func ElasticsearchV1alpha1(u *unstructured.Unstructured) (*esalpha1.Elasticsearch, error) {
+	obj := u.DeepCopy().Object
+	if spec, ok := obj["spec"].(map[string]interface{}); ok {
+		if sets, ok := spec["nodeSets"].([]interface{}); ok {
+			for _, s := range sets {
+				if set, ok := s.(map[string]interface{}); ok {
+					renameField(set, "count", "nodeCount")
+				}
+			}
+			delete(spec, "nodeSets")
+			spec["nodes"] = sets
+		}
+		if secure, ok := spec["secureSettings"].([]interface{}); ok {
+			delete(spec, "secureSettings")
+			if len(secure) > 0 {
+				spec["secureSettings"] = secure[0]
+			}
+		}
+	}
+	obj["apiVersion"] = elasticsearchV1alpha1GVK.GroupVersion().String()
+
+	es := &esalpha1.Elasticsearch{}
+	return es, fromObjectMap(obj, es)
+}
+
+// KibanaV1 converts a v1alpha1 Kibana to the v1 API.
+func KibanaV1(kb *kibanaalpha1.Kibana) (*unstructured.Unstructured, error) {
+	obj, err := toObjectMap(kb)
+	if err != nil {
+		return nil, err
+	}
+	if spec, ok := obj["spec"].(map[string]interface{}); ok {
+		renameField(spec, "nodeCount", "count")
+	}
+	return toUnstructured(obj, KibanaV1GVK)
+}
+
+// KibanaV1alpha1 converts a v1 Kibana back to the v1alpha1 API, for the code that reads its status.
+func KibanaV1alpha1(u *unstructured.Unstructured) (*kibanaalpha1.Kibana, error) {
+	obj := u.DeepCopy().Object
+	if spec, ok := obj["spec"].(map[string]interface{}); ok {
+		renameField(spec, "count", "nodeCount")
+	}
+	obj["apiVersion"] = kibanaV1alpha1GVK.GroupVersion().String()
+
+	kb := &kibanaalpha1.Kibana{}
+	return kb, fromObjectMap(obj, kb)
+}
+
+func renameField(obj map[string]interface{}, from, to string) {
+	if v, ok := obj[from]; ok {
+		delete(obj, from)
+		obj[to] = v
+	}
+}
+
+// toObjectMap returns the JSON representation of the object, which is how ECK reads it.
+func toObjectMap(v interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	obj := map[string]interface{}{}
+	return obj, json.Unmarshal(data, &obj)
+}
+
+func fromObjectMap(obj map[string]interface{}, v interface{}) error {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// toUnstructured returns the object as the given kind. It is decoded the way the client decodes the objects it reads,
+// so that the specs of the rendered and the current objects can be compared.
+func toUnstructured(obj map[string]interface{}, gvk schema.GroupVersionKind) (*unstructured.Unstructured, error) {
+	obj["apiVersion"] = gvk.GroupVersion().String()
+	obj["kind"] = gvk.Kind
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	u := &unstructured.Unstructured{}
+	if err := u.UnmarshalJSON(data); err != nil {
+		return nil, err
+	}
+	annotations := u.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[eckControllerVersionAnnotation] = components.VersionECKOperatorV1
+	u.SetAnnotations(annotations)
+	return u, nil
+}
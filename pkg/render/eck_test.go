@@ -0,0 +1,100 @@
+// Copyright (c) 2020 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render_test
+
+import (
+	cmneckalpha1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1alpha1"
+	esalpha1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1alpha1"
+	kibanaalpha1 "github.com/elastic/cloud-on-k8s/pkg/apis/kibana/v1alpha1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/tigera/operator/pkg/components"
+	"github.com/tigera/operator/pkg/render"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var _ = Describe("ECK API conversion", func() {
+	It("should convert Elasticsearch to the v1 API and back", func() {
+		es := &esalpha1.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Name: render.ElasticsearchName, Namespace: render.ElasticsearchNamespace},
+			Spec: esalpha1.ElasticsearchSpec{
+				Version: components.VersionECKElasticsearch,
+				Nodes: []esalpha1.NodeSpec{
+					{NodeCount: 1, Config: &cmneckalpha1.Config{Data: map[string]interface{}{"node.master": "true"}}},
+					{Name: "data", NodeCount: 2},
+				},
+				SecureSettings: &cmneckalpha1.SecretRef{SecretName: render.ElasticsearchSnapshotCredentialsSecret},
+			},
+		}
+
+		v1, err := render.ElasticsearchV1(es)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(v1.GroupVersionKind()).To(Equal(render.ElasticsearchV1GVK))
+		Expect(v1.GetAnnotations()).To(HaveKeyWithValue("common.k8s.elastic.co/controller-version", components.VersionECKOperatorV1))
+		secure, _, err := unstructured.NestedSlice(v1.Object, "spec", "secureSettings")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(secure).To(Equal([]interface{}{map[string]interface{}{"secretName": render.ElasticsearchSnapshotCredentialsSecret}}))
+
+		back, err := render.ElasticsearchV1alpha1(v1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(back.Spec.Nodes).To(HaveLen(2))
+		Expect(back.Spec.Nodes[0].Name).To(Equal(render.ECKDefaultNodeSetName))
+		Expect(back.Spec.Nodes[0].NodeCount).To(Equal(int32(1)))
+		Expect(back.Spec.Nodes[0].Config.Data).To(HaveKeyWithValue("node.master", "true"))
+		Expect(back.Spec.Nodes[1].Name).To(Equal("data"))
+		Expect(back.Spec.Nodes[1].NodeCount).To(Equal(int32(2)))
+		Expect(back.Spec.SecureSettings.SecretName).To(Equal(render.ElasticsearchSnapshotCredentialsSecret))
+	})
+
+	It("should read an Elasticsearch that was created through the v1alpha1 API", func() {
+		u := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "elasticsearch.k8s.elastic.co/v1",
+			"kind":       "Elasticsearch",
+			"metadata":   map[string]interface{}{"name": render.ElasticsearchName, "namespace": render.ElasticsearchNamespace},
+			"spec": map[string]interface{}{
+				"nodes": []interface{}{map[string]interface{}{"nodeCount": int64(3)}},
+			},
+			"status": map[string]interface{}{"phase": "Ready", "availableNodes": int64(3)},
+		}}
+
+		es, err := render.ElasticsearchV1alpha1(u)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(es.Spec.Nodes).To(HaveLen(1))
+		Expect(es.Spec.Nodes[0].Name).To(BeEmpty())
+		Expect(es.Spec.Nodes[0].NodeCount).To(Equal(int32(3)))
+		Expect(es.Status.Phase).To(Equal(esalpha1.ElasticsearchReadyPhase))
+	})
+
+	It("should convert Kibana to the v1 API and back", func() {
+		kb := &kibanaalpha1.Kibana{
+			ObjectMeta: metav1.ObjectMeta{Name: render.KibanaName, Namespace: render.KibanaNamespace},
+			Spec:       kibanaalpha1.KibanaSpec{NodeCount: 2},
+		}
+
+		v1, err := render.KibanaV1(kb)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(v1.GroupVersionKind()).To(Equal(render.KibanaV1GVK))
+		count, _, err := unstructured.NestedInt64(v1.Object, "spec", "count")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(count).To(Equal(int64(2)))
+
+		Expect(unstructured.SetNestedField(v1.Object, string(cmneckalpha1.AssociationEstablished), "status", "associationStatus")).To(Succeed())
+		back, err := render.KibanaV1alpha1(v1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(back.Spec.NodeCount).To(Equal(int32(2)))
+		Expect(back.Status.AssociationStatus).To(Equal(cmneckalpha1.AssociationEstablished))
+	})
+})
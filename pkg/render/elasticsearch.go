@@ -50,6 +50,7 @@ func Elasticsearch(
 	createWebhookSecret bool,
 	pullSecrets []*corev1.Secret,
 	provider operatorv1.Provider,
+	eckAPIVersion string,
 	registry string) (Component, error) {
 	var esCertSecrets, kibanaCertSecrets []runtime.Object
 	if esCertSecret == nil {
@@ -82,7 +83,7 @@ func Elasticsearch(
 
 	esCertSecrets = append(esCertSecrets, secretsToRuntimeObjects(copySecrets(ElasticsearchNamespace, esCertSecret)...)...)
 	kibanaCertSecrets = append(kibanaCertSecrets, secretsToRuntimeObjects(copySecrets(KibanaNamespace, kibanaCertSecret)...)...)
-	return &elasticsearchComponent{
+	es := &elasticsearchComponent{
 		logStorage:          logStorage,
 		clusterConfig:       clusterConfig,
 		esCertSecrets:       esCertSecrets,
@@ -90,8 +91,14 @@ func Elasticsearch(
 		createWebhookSecret: createWebhookSecret,
 		pullSecrets:         pullSecrets,
 		provider:            provider,
+		eckAPIVersion:       eckAPIVersion,
 		registry:            registry,
-	}, nil
+	}
+	var err error
+	if es.eckElasticsearch, es.eckKibana, err = es.eckResources(); err != nil {
+		return nil, err
+	}
+	return es, nil
 }
 
 type elasticsearchComponent struct {
@@ -102,7 +109,12 @@ type elasticsearchComponent struct {
 	createWebhookSecret bool
 	pullSecrets         []*corev1.Secret
 	provider            operatorv1.Provider
+	eckAPIVersion       string
 	registry            string
+
+	// The Elasticsearch and Kibana CRs in the version of the ECK APIs they are managed through.
+	eckElasticsearch runtime.Object
+	eckKibana        runtime.Object
 }
 
 func (es *elasticsearchComponent) Objects() []runtime.Object {
@@ -114,8 +126,10 @@ func (es *elasticsearchComponent) Objects() []runtime.Object {
 	objs = append(objs, es.esCertSecrets...)
 	objs = append(objs, es.clusterConfig.ConfigMap())
 
-	objs = append(objs, es.elasticsearchCluster())
-	objs = append(objs, es.kibana()...)
+	objs = append(objs, es.eckElasticsearch)
+	if es.logStorage.KibanaEnabled() {
+		objs = append(objs, es.kibana()...)
+	}
 
 	return objs
 }
@@ -126,7 +140,7 @@ func (es *elasticsearchComponent) Ready() bool {
 
 // generate the PVC required for the Elasticsearch nodes
 func (es elasticsearchComponent) pvcTemplate() corev1.PersistentVolumeClaim {
-	storageClassName := ElasticsearchStorageClass
+	storageClassName := es.storageClassName()
 	pvcTemplate := corev1.PersistentVolumeClaim{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: "elasticsearch-data", // ECK requires this name
@@ -149,18 +163,53 @@ func (es elasticsearchComponent) pvcTemplate() corev1.PersistentVolumeClaim {
 	}
 
 	// We only allow the user to overwrite the resource requirements for the pvc
-	if es.logStorage.Spec.Nodes != nil && es.logStorage.Spec.Nodes.ResourceRequirements != nil {
-		pvcTemplate.Spec.Resources = *es.logStorage.Spec.Nodes.ResourceRequirements
+	if nodes := es.logStorage.Spec.Nodes; nodes != nil {
+		if nodes.ResourceRequirements != nil {
+			pvcTemplate.Spec.Resources = *nodes.ResourceRequirements.DeepCopy()
+		}
+		if nodes.StorageSize != nil {
+			if pvcTemplate.Spec.Resources.Requests == nil {
+				pvcTemplate.Spec.Resources.Requests = corev1.ResourceList{}
+			}
+			pvcTemplate.Spec.Resources.Requests["storage"] = *nodes.StorageSize
+		}
 	}
 
 	return pvcTemplate
 }
 
+// generate the PVC for the nodes of a dedicated node set
+func (es elasticsearchComponent) storagePVCTemplate(size resource.Quantity) corev1.PersistentVolumeClaim {
+	storageClassName := es.storageClassName()
+	return corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "elasticsearch-data", // ECK requires this name
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{"storage": size},
+			},
+			StorageClassName: &storageClassName,
+		},
+	}
+}
+
+func (es elasticsearchComponent) storageClassName() string {
+	if es.logStorage.Spec.StorageClassName != "" {
+		return es.logStorage.Spec.StorageClassName
+	}
+	return ElasticsearchStorageClass
+}
+
+// ElasticsearchStatefulSetName returns the name ECK gives the StatefulSet of the Elasticsearch nodes of a node spec.
+func ElasticsearchStatefulSetName(nodeSpecName string) string {
+	return ElasticsearchName + "-es-" + nodeSpecName
+}
+
 // render the Elasticsearch CR that the ECK operator uses to create elasticsearch cluster
 func (es elasticsearchComponent) elasticsearchCluster() *esalpha1.Elasticsearch {
-	nodeConfig := es.logStorage.Spec.Nodes
-
-	return &esalpha1.Elasticsearch{
+	cluster := &esalpha1.Elasticsearch{
 		TypeMeta: metav1.TypeMeta{Kind: "Elasticsearch", APIVersion: "elasticsearch.k8s.elastic.co/v1alpha1"},
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      ElasticsearchName,
@@ -179,26 +228,191 @@ func (es elasticsearchComponent) elasticsearchCluster() *esalpha1.Elasticsearch
 					},
 				},
 			},
-			Nodes: []esalpha1.NodeSpec{
-				{
-					NodeCount: int32(nodeConfig.Count),
-					Config: &cmneckalpha1.Config{
-						Data: map[string]interface{}{
-							"node.master": "true",
-							"node.data":   "true",
-							"node.ingest": "true",
-						},
-					},
-					VolumeClaimTemplates: []corev1.PersistentVolumeClaim{es.pvcTemplate()},
-					PodTemplate: corev1.PodTemplateSpec{
-						Spec: corev1.PodSpec{
-							ImagePullSecrets: getImagePullSecretReferenceList(es.pullSecrets),
-						},
-					},
+			Nodes: es.nodeSpecs(),
+		},
+	}
+	if snapshots := es.logStorage.Spec.Snapshots; snapshots != nil && snapshots.Repository.Type == operatorv1.SnapshotRepositoryS3 {
+		// ECK adds the object store credentials to the keystore of the nodes.
+		cluster.Spec.SecureSettings = &cmneckalpha1.SecretRef{SecretName: ElasticsearchSnapshotCredentialsSecret}
+	}
+	return cluster
+}
+
+// esNodeSet is a set of Elasticsearch nodes with the same roles and resources, before it is spread over zones.
+type esNodeSet struct {
+	name                 string
+	count                int32
+	master, data, ingest bool
+	resources            *corev1.ResourceRequirements
+	pvcTemplate          corev1.PersistentVolumeClaim
+}
+
+// nodeSets returns the sets of Elasticsearch nodes configured in the LogStorage: either a single set of nodes that
+// take every role, or dedicated master, data and ingest nodes.
+func (es elasticsearchComponent) nodeSets() []esNodeSet {
+	nodes := es.logStorage.Spec.Nodes
+	if nodes.NodeSets == nil {
+		return []esNodeSet{{
+			count:       int32(nodes.Count),
+			master:      true,
+			data:        true,
+			ingest:      true,
+			pvcTemplate: es.pvcTemplate(),
+		}}
+	}
+
+	dedicated := func(name string, ns operatorv1.NodeSet) esNodeSet {
+		size := resource.MustParse("10Gi")
+		if ns.StorageSize != nil {
+			size = *ns.StorageSize
+		}
+		return esNodeSet{
+			name:        name,
+			count:       ns.Count,
+			resources:   ns.ResourceRequirements,
+			pvcTemplate: es.storagePVCTemplate(size),
+		}
+	}
+	master := dedicated("master", nodes.NodeSets.Master)
+	master.master = true
+	data := dedicated("data", nodes.NodeSets.Data)
+	data.data = true
+	sets := []esNodeSet{master, data}
+	if nodes.NodeSets.Ingest != nil {
+		ingest := dedicated("ingest", *nodes.NodeSets.Ingest)
+		ingest.ingest = true
+		sets = append(sets, ingest)
+	} else {
+		sets[1].ingest = true
+	}
+	return sets
+}
+
+// nodeSpecs translates the node sets into the ECK node specs. With zone awareness, every node set is divided over
+// the zones, with a node spec per zone that is pinned to the zone and tells Elasticsearch which zone it is in.
+func (es elasticsearchComponent) nodeSpecs() []esalpha1.NodeSpec {
+	nodes := es.logStorage.Spec.Nodes
+	var specs []esalpha1.NodeSpec
+	for _, set := range es.nodeSets() {
+		if nodes.ZoneAwareness == nil {
+			specs = append(specs, es.nodeSpec(set, set.name, set.count, ""))
+			continue
+		}
+		zones := nodes.ZoneAwareness.Zones
+		for i, zone := range zones {
+			count := set.count / int32(len(zones))
+			if int32(i) < set.count%int32(len(zones)) {
+				count++
+			}
+			if count == 0 {
+				continue
+			}
+			name := fmt.Sprintf("zone%d", i)
+			if set.name != "" {
+				name = fmt.Sprintf("%s-z%d", set.name, i)
+			}
+			specs = append(specs, es.nodeSpec(set, name, count, zone))
+		}
+	}
+	return specs
+}
+
+func (es elasticsearchComponent) nodeSpec(set esNodeSet, name string, count int32, zone string) esalpha1.NodeSpec {
+	config := map[string]interface{}{
+		"node.master": fmt.Sprint(set.master),
+		"node.data":   fmt.Sprint(set.data),
+		"node.ingest": fmt.Sprint(set.ingest),
+	}
+	podSpec := corev1.PodSpec{
+		ImagePullSecrets: getImagePullSecretReferenceList(es.pullSecrets),
+	}
+	// ECK merges the container with the Elasticsearch container it renders.
+	container := corev1.Container{Name: "elasticsearch"}
+	if set.resources != nil {
+		container.Resources = *set.resources
+	}
+
+	if snapshots := es.logStorage.Spec.Snapshots; snapshots != nil {
+		for k, v := range snapshotNodeConfig(snapshots) {
+			config[k] = v
+		}
+		switch snapshots.Repository.Type {
+		case operatorv1.SnapshotRepositoryFilesystem:
+			podSpec.Volumes = []corev1.Volume{{
+				Name: "snapshots",
+				VolumeSource: corev1.VolumeSource{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: ElasticsearchSnapshotVolume},
 				},
+			}}
+			container.VolumeMounts = []corev1.VolumeMount{{Name: "snapshots", MountPath: ElasticsearchSnapshotPath}}
+		case operatorv1.SnapshotRepositoryS3:
+			podSpec.InitContainers = []corev1.Container{{
+				Name:    "install-repository-s3",
+				Command: []string{"sh", "-c", "bin/elasticsearch-plugin install --batch repository-s3"},
+			}}
+		}
+	}
+	if set.resources != nil || len(container.VolumeMounts) > 0 {
+		podSpec.Containers = []corev1.Container{container}
+	}
+
+	var nodeAffinity *corev1.NodeAffinity
+	if zone != "" {
+		config["node.attr.zone"] = zone
+		config["cluster.routing.allocation.awareness.attributes"] = "zone"
+		nodeAffinity = &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+				NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+					MatchExpressions: []corev1.NodeSelectorRequirement{{
+						Key:      es.zoneNodeLabel(),
+						Operator: corev1.NodeSelectorOpIn,
+						Values:   []string{zone},
+					}},
+				}},
 			},
+		}
+	}
+	podAntiAffinity := es.podAntiAffinity()
+	if nodeAffinity != nil || podAntiAffinity != nil {
+		podSpec.Affinity = &corev1.Affinity{NodeAffinity: nodeAffinity, PodAntiAffinity: podAntiAffinity}
+	}
+
+	return esalpha1.NodeSpec{
+		Name:                 name,
+		NodeCount:            count,
+		Config:               &cmneckalpha1.Config{Data: config},
+		VolumeClaimTemplates: []corev1.PersistentVolumeClaim{set.pvcTemplate},
+		PodTemplate:          corev1.PodTemplateSpec{Spec: podSpec},
+	}
+}
+
+func (es elasticsearchComponent) zoneNodeLabel() string {
+	if label := es.logStorage.Spec.Nodes.ZoneAwareness.NodeLabel; label != "" {
+		return label
+	}
+	return "failure-domain.beta.kubernetes.io/zone"
+}
+
+// podAntiAffinity returns the configured anti-affinity between Elasticsearch nodes. When not configured, ECK
+// applies its default of preferring different Kubernetes nodes.
+func (es elasticsearchComponent) podAntiAffinity() *corev1.PodAntiAffinity {
+	term := corev1.PodAffinityTerm{
+		LabelSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{"elasticsearch.k8s.elastic.co/cluster-name": ElasticsearchName},
 		},
+		TopologyKey: "kubernetes.io/hostname",
 	}
+	switch es.logStorage.Spec.Nodes.PodAntiAffinity {
+	case operatorv1.PodAntiAffinityRequired:
+		return &corev1.PodAntiAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{term},
+		}
+	case operatorv1.PodAntiAffinityPreferred:
+		return &corev1.PodAntiAffinity{
+			PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{{Weight: 100, PodAffinityTerm: term}},
+		}
+	}
+	return nil
 }
 
 func (es elasticsearchComponent) eckOperator() []runtime.Object {
@@ -246,7 +460,7 @@ func (es elasticsearchComponent) eckOperatorClusterRole() *rbacv1.ClusterRole {
 			},
 			{
 				APIGroups: []string{"apps"},
-				Resources: []string{"deployments"},
+				Resources: []string{"deployments", "statefulsets"},
 				Verbs:     []string{"get", "list", "watch", "create", "update", "patch", "delete"},
 			},
 			{
@@ -341,6 +555,15 @@ func (es elasticsearchComponent) eckOperatorStatefulSet() *apps.StatefulSet {
 	gracePeriod := int64(10)
 	defaultMode := int32(420)
 
+	// The v1 APIs are managed by an ECK 1.x operator. Its webhook is left disabled, since the Elasticsearch and
+	// Kibana resources are only written by the operator, which validates the LogStorage they are rendered from.
+	image := ECKOperatorImageName
+	args := []string{"manager", "--operator-roles", "all", "--enable-debug-logs=false"}
+	if es.eckAPIVersion == ECKAPIVersionV1 {
+		image = ECKOperatorV1ImageName
+		args = []string{"manager", "--operator-roles", "all", "--log-verbosity=0", "--enable-webhook=false"}
+	}
+
 	return &apps.StatefulSet{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      ECKOperatorName,
@@ -369,9 +592,9 @@ func (es elasticsearchComponent) eckOperatorStatefulSet() *apps.StatefulSet {
 					ServiceAccountName: "elastic-operator",
 					ImagePullSecrets:   getImagePullSecretReferenceList(es.pullSecrets),
 					Containers: []corev1.Container{{
-						Image: constructImage(ECKOperatorImageName, es.registry),
+						Image: constructImage(image, es.registry),
 						Name:  "manager",
-						Args:  []string{"manager", "--operator-roles", "all", "--enable-debug-logs=false"},
+						Args:  args,
 						Env: []corev1.EnvVar{
 							{
 								Name: "OPERATOR_NAMESPACE",
@@ -383,7 +606,7 @@ func (es elasticsearchComponent) eckOperatorStatefulSet() *apps.StatefulSet {
 							},
 							{Name: "WEBHOOK_SECRET", Value: ECKWebhookSecretName},
 							{Name: "WEBHOOK_PODS_LABEL", Value: "elastic-operator"},
-							{Name: "OPERATOR_IMAGE", Value: "docker.elastic.co/eck/eck-operator:0.9.0"},
+							{Name: "OPERATOR_IMAGE", Value: constructImage(image, "")},
 						},
 						Resources: corev1.ResourceRequirements{
 							Limits: corev1.ResourceList{
@@ -427,11 +650,40 @@ func (es elasticsearchComponent) kibana() []runtime.Object {
 	objs := []runtime.Object{createNamespace(KibanaNamespace, false)}
 	objs = append(objs, secretsToRuntimeObjects(copySecrets(KibanaNamespace, es.pullSecrets...)...)...)
 	objs = append(objs, es.kibanaCertSecrets...)
-	objs = append(objs, es.kibanaCR())
+	objs = append(objs, es.eckKibana)
 	return objs
 }
 
+// eckResources returns the Elasticsearch and Kibana CRs in the version of the ECK APIs that they are managed through.
+// They are rendered for v1alpha1 and converted if the cluster serves the v1 APIs.
+func (es elasticsearchComponent) eckResources() (runtime.Object, runtime.Object, error) {
+	cluster, kibana := es.elasticsearchCluster(), es.kibanaCR()
+	if es.eckAPIVersion != ECKAPIVersionV1 {
+		return cluster, kibana, nil
+	}
+	clusterV1, err := ElasticsearchV1(cluster)
+	if err != nil {
+		return nil, nil, err
+	}
+	kibanaV1, err := KibanaV1(kibana)
+	if err != nil {
+		return nil, nil, err
+	}
+	return clusterV1, kibanaV1, nil
+}
+
 func (es elasticsearchComponent) kibanaCR() *kibanav1alpha1.Kibana {
+	nodeCount := int32(1)
+	var resources corev1.ResourceRequirements
+	if kb := es.logStorage.Spec.Kibana; kb != nil {
+		if kb.Replicas != nil {
+			nodeCount = *kb.Replicas
+		}
+		if kb.Resources != nil {
+			resources = *kb.Resources
+		}
+	}
+
 	return &kibanav1alpha1.Kibana{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      KibanaName,
@@ -454,7 +706,7 @@ func (es elasticsearchComponent) kibanaCR() *kibanav1alpha1.Kibana {
 					},
 				},
 			},
-			NodeCount: 1,
+			NodeCount: nodeCount,
 			HTTP: cmneckalpha1.HTTPConfig{
 				TLS: cmneckalpha1.TLSOptions{
 					Certificate: cmneckalpha1.SecretRef{
@@ -477,7 +729,8 @@ func (es elasticsearchComponent) kibanaCR() *kibanav1alpha1.Kibana {
 				Spec: corev1.PodSpec{
 					ImagePullSecrets: getImagePullSecretReferenceList(es.pullSecrets),
 					Containers: []corev1.Container{{
-						Name: "kibana",
+						Name:      "kibana",
+						Resources: resources,
 						ReadinessProbe: &corev1.Probe{
 							Handler: corev1.Handler{
 								HTTPGet: &corev1.HTTPGetAction{
@@ -46,8 +46,8 @@ func ElasticsearchDecorateAnnotations(obj Annotatable, config *ElasticsearchClus
 	return obj
 }
 
-func ElasticsearchContainerDecorate(c corev1.Container, cluster, secret string) corev1.Container {
-	return ElasticsearchContainerDecorateVolumeMounts(ElasticsearchContainerDecorateENVVars(c, cluster, secret))
+func ElasticsearchContainerDecorate(c corev1.Container, config *ElasticsearchClusterConfig, secret string) corev1.Container {
+	return ElasticsearchContainerDecorateVolumeMounts(ElasticsearchContainerDecorateENVVars(c, config, secret))
 }
 
 func ElasticsearchContainerDecorateIndexCreator(c corev1.Container, replicas, shards int) corev1.Container {
@@ -60,10 +60,10 @@ func ElasticsearchContainerDecorateIndexCreator(c corev1.Container, replicas, sh
 	return c
 }
 
-func ElasticsearchContainerDecorateENVVars(c corev1.Container, cluster, esUserSecretName string) corev1.Container {
-	esScheme, esHost, esPort, _ := ParseEndpoint(ElasticsearchHTTPSEndpoint)
+func ElasticsearchContainerDecorateENVVars(c corev1.Container, config *ElasticsearchClusterConfig, esUserSecretName string) corev1.Container {
+	esScheme, esHost, esPort, _ := ParseEndpoint(config.URL())
 	envVars := []corev1.EnvVar{
-		{Name: "ELASTIC_INDEX_SUFFIX", Value: cluster},
+		{Name: "ELASTIC_INDEX_SUFFIX", Value: config.ClusterName()},
 		{Name: "ELASTIC_SCHEME", Value: esScheme},
 		{Name: "ELASTIC_HOST", Value: esHost},
 		{Name: "ELASTIC_PORT", Value: esPort},
@@ -0,0 +1,67 @@
+// Copyright (c) 2020 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// ExternalElasticsearchCAKey is the key of the CA bundle in the Secret of an external Elasticsearch cluster.
+const ExternalElasticsearchCAKey = "ca.crt"
+
+// ElasticsearchExternal renders what the components need to reach an Elasticsearch cluster that is not run by the
+// operator: the cluster configuration with the URL of the cluster, the CA bundle the cluster is verified with, and
+// the Secrets of the component users.
+func ElasticsearchExternal(clusterConfig *ElasticsearchClusterConfig, caSecret *corev1.Secret, userSecrets []*corev1.Secret) Component {
+	return &elasticsearchExternal{
+		clusterConfig: clusterConfig,
+		caSecret:      caSecret,
+		userSecrets:   userSecrets,
+	}
+}
+
+type elasticsearchExternal struct {
+	clusterConfig *ElasticsearchClusterConfig
+	caSecret      *corev1.Secret
+	userSecrets   []*corev1.Secret
+}
+
+func (es elasticsearchExternal) Objects() []runtime.Object {
+	objs := []runtime.Object{
+		es.clusterConfig.ConfigMap(),
+		es.publicCertSecret(),
+	}
+	return append(objs, secretsToRuntimeObjects(es.userSecrets...)...)
+}
+
+func (es elasticsearchExternal) Ready() bool {
+	return true
+}
+
+// publicCertSecret holds the CA bundle under the name and key that components mount the Elasticsearch CA from.
+func (es elasticsearchExternal) publicCertSecret() *corev1.Secret {
+	return &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{Kind: "Secret", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ElasticsearchPublicCertSecret,
+			Namespace: OperatorNamespace(),
+		},
+		Data: map[string][]byte{
+			"tls.crt": es.caSecret.Data[ExternalElasticsearchCAKey],
+		},
+	}
+}
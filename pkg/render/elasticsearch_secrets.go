@@ -21,22 +21,30 @@ import (
 
 // elasticsearchSecrets is a Component that contains the secrets that need to be created in the tigera operator namespace
 // after Elasticsearch and Kibana are running. At this time these are the secrets containing certificate information
-// for both Elasticsearch and Kibana.
+// for both Elasticsearch and Kibana, and the secrets holding the credentials of the Elasticsearch users of the
+// components.
 type elasticsearchSecrets struct {
 	esPublicCertSecret     *corev1.Secret
 	kibanaPublicCertSecret *corev1.Secret
+	userSecrets            []*corev1.Secret
 }
 
-func ElasticsearchSecrets(esPublicCertSecret *corev1.Secret, kibanaPublicCertSecret *corev1.Secret) Component {
+func ElasticsearchSecrets(esPublicCertSecret *corev1.Secret, kibanaPublicCertSecret *corev1.Secret, userSecrets []*corev1.Secret) Component {
 	return &elasticsearchSecrets{
 		esPublicCertSecret:     esPublicCertSecret,
 		kibanaPublicCertSecret: kibanaPublicCertSecret,
+		userSecrets:            userSecrets,
 	}
 }
 
 func (es elasticsearchSecrets) Objects() []runtime.Object {
 	var objs []runtime.Object
-	objs = append(objs, secretsToRuntimeObjects(copySecrets(OperatorNamespace(), es.esPublicCertSecret, es.kibanaPublicCertSecret)...)...)
+	objs = append(objs, secretsToRuntimeObjects(copySecrets(OperatorNamespace(), es.esPublicCertSecret)...)...)
+	// There is no Kibana certificate if Kibana is disabled.
+	if es.kibanaPublicCertSecret != nil {
+		objs = append(objs, secretsToRuntimeObjects(copySecrets(OperatorNamespace(), es.kibanaPublicCertSecret)...)...)
+	}
+	objs = append(objs, secretsToRuntimeObjects(es.userSecrets...)...)
 	return objs
 }
 
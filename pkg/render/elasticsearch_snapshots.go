@@ -0,0 +1,239 @@
+// Copyright (c) 2020 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	operatorv1 "github.com/tigera/operator/pkg/apis/operator/v1"
+	"github.com/tigera/operator/pkg/elasticsearch"
+	batchv1 "k8s.io/api/batch/v1"
+	batch "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	ElasticsearchSnapshotRepository        = "tigera-secure-snapshots"
+	ElasticsearchSnapshotVolume            = "tigera-secure-es-snapshots"
+	ElasticsearchSnapshotPath              = "/usr/share/elasticsearch/snapshots"
+	ElasticsearchSnapshotCredentialsSecret = "tigera-secure-es-snapshot-credentials"
+	ElasticsearchSnapshotCronJobName       = "elastic-snapshots"
+
+	// ElasticsearchElasticUserSecret is the secret ECK stores the password of the elastic superuser in.
+	ElasticsearchElasticUserSecret = "tigera-secure-es-elastic-user"
+
+	// The keys of the object store credentials in the Secret named in the LogStorage.
+	SnapshotS3AccessKey = "access-key"
+	SnapshotS3SecretKey = "secret-key"
+
+	defaultSnapshotSchedule    = "0 1 * * *"
+	defaultSnapshotRetention   = 30
+	defaultSnapshotStorageSize = "100Gi"
+	defaultSnapshotIndices     = "tigera_secure_ee_*"
+)
+
+// ElasticsearchSnapshots renders the storage of the snapshot repository configured in the LogStorage and the
+// CronJob that takes snapshots and removes expired ones. The object store credentials are only needed for an S3
+// repository, they are copied into the keystore of the Elasticsearch nodes.
+func ElasticsearchSnapshots(logStorage *operatorv1.LogStorage, s3Credentials *corev1.Secret, pullSecrets []*corev1.Secret, registry string) Component {
+	return &elasticsearchSnapshotsComponent{
+		snapshots:     logStorage.Spec.Snapshots,
+		s3Credentials: s3Credentials,
+		pullSecrets:   pullSecrets,
+		registry:      registry,
+	}
+}
+
+type elasticsearchSnapshotsComponent struct {
+	snapshots     *operatorv1.Snapshots
+	s3Credentials *corev1.Secret
+	pullSecrets   []*corev1.Secret
+	registry      string
+}
+
+func (c *elasticsearchSnapshotsComponent) Objects() []runtime.Object {
+	var objs []runtime.Object
+	switch c.snapshots.Repository.Type {
+	case operatorv1.SnapshotRepositoryFilesystem:
+		objs = append(objs, c.volumeClaim())
+	case operatorv1.SnapshotRepositoryS3:
+		objs = append(objs, c.credentialsSecret())
+	}
+	return append(objs, c.cronJob())
+}
+
+func (c *elasticsearchSnapshotsComponent) Ready() bool {
+	return true
+}
+
+// volumeClaim is the volume of a filesystem repository, which is mounted on every Elasticsearch node.
+func (c *elasticsearchSnapshotsComponent) volumeClaim() *corev1.PersistentVolumeClaim {
+	fs := c.snapshots.Repository.Filesystem
+	size := resource.MustParse(defaultSnapshotStorageSize)
+	if fs.StorageSize != nil {
+		size = *fs.StorageSize
+	}
+	storageClassName := fs.StorageClassName
+	return &corev1.PersistentVolumeClaim{
+		TypeMeta: metav1.TypeMeta{Kind: "PersistentVolumeClaim", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ElasticsearchSnapshotVolume,
+			Namespace: ElasticsearchNamespace,
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany},
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{"storage": size},
+			},
+			StorageClassName: &storageClassName,
+		},
+	}
+}
+
+// credentialsSecret holds the object store credentials under the names of the Elasticsearch secure settings.
+func (c *elasticsearchSnapshotsComponent) credentialsSecret() *corev1.Secret {
+	return &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{Kind: "Secret", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ElasticsearchSnapshotCredentialsSecret,
+			Namespace: ElasticsearchNamespace,
+		},
+		Data: map[string][]byte{
+			"s3.client.default.access_key": c.s3Credentials.Data[SnapshotS3AccessKey],
+			"s3.client.default.secret_key": c.s3Credentials.Data[SnapshotS3SecretKey],
+		},
+	}
+}
+
+// snapshotScript takes a snapshot of the configured indices and removes the snapshots that ended before the
+// retention period.
+const snapshotScript = `set -eu
+es() { curl --fail --silent --show-error --cacert ` + ElasticsearchDefaultCertPath + ` -u "elastic:${ELASTIC_PASSWORD}" "$@"; }
+es -X PUT -H 'Content-Type: application/json' \
+  "${ELASTIC_URL}/_snapshot/${SNAPSHOT_REPOSITORY}/snapshot-$(date -u +%Y.%m.%d-%H.%M)?wait_for_completion=true" \
+  -d "{\"indices\": \"${SNAPSHOT_INDICES}\", \"include_global_state\": false}"
+cutoff=$(( $(date +%s) - SNAPSHOT_RETENTION_DAYS * 86400 ))
+es "${ELASTIC_URL}/_cat/snapshots/${SNAPSHOT_REPOSITORY}?h=id,end_epoch" | while read -r id end; do
+  if [ "${end}" -lt "${cutoff}" ]; then
+    es -X DELETE "${ELASTIC_URL}/_snapshot/${SNAPSHOT_REPOSITORY}/${id}"
+  fi
+done
+`
+
+func (c *elasticsearchSnapshotsComponent) cronJob() *batch.CronJob {
+	f := false
+	schedule := c.snapshots.Schedule
+	if schedule == "" {
+		schedule = defaultSnapshotSchedule
+	}
+	retention := int32(defaultSnapshotRetention)
+	if c.snapshots.Retention != nil {
+		retention = *c.snapshots.Retention
+	}
+	indices := defaultSnapshotIndices
+	if len(c.snapshots.Indices) > 0 {
+		indices = strings.Join(c.snapshots.Indices, ",")
+	}
+
+	return &batch.CronJob{
+		TypeMeta: metav1.TypeMeta{Kind: "CronJob", APIVersion: "batch/v1beta1"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ElasticsearchSnapshotCronJobName,
+			Namespace: ElasticsearchNamespace,
+		},
+		Spec: batch.CronJobSpec{
+			Schedule:          schedule,
+			ConcurrencyPolicy: batch.ForbidConcurrent,
+			JobTemplate: batch.JobTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: ElasticsearchSnapshotCronJobName,
+				},
+				Spec: batchv1.JobSpec{
+					Template: corev1.PodTemplateSpec{
+						Spec: ElasticsearchPodSpecDecorate(corev1.PodSpec{
+							Containers: []corev1.Container{
+								ElasticsearchContainerDecorateVolumeMounts(corev1.Container{
+									Name:    ElasticsearchSnapshotCronJobName,
+									Image:   constructImage(ECKElasticsearchImageName, c.registry),
+									Command: []string{"/bin/bash", "-c", snapshotScript},
+									Env: []corev1.EnvVar{
+										{Name: "ELASTIC_URL", Value: ElasticsearchHTTPSEndpoint},
+										{Name: "ELASTIC_PASSWORD", ValueFrom: envVarSourceFromSecret(ElasticsearchElasticUserSecret, "elastic", false)},
+										{Name: "SNAPSHOT_REPOSITORY", Value: ElasticsearchSnapshotRepository},
+										{Name: "SNAPSHOT_INDICES", Value: indices},
+										{Name: "SNAPSHOT_RETENTION_DAYS", Value: fmt.Sprint(retention)},
+									},
+									SecurityContext: &corev1.SecurityContext{
+										AllowPrivilegeEscalation: &f,
+									},
+								}),
+							},
+							ImagePullSecrets: getImagePullSecretReferenceList(c.pullSecrets),
+							RestartPolicy:    corev1.RestartPolicyOnFailure,
+						}),
+					},
+				},
+			},
+		},
+	}
+}
+
+// SnapshotRepository returns the definition of the snapshot repository configured in the LogStorage, as it is
+// registered with Elasticsearch.
+func SnapshotRepository(snapshots *operatorv1.Snapshots) elasticsearch.SnapshotRepository {
+	if snapshots.Repository.Type == operatorv1.SnapshotRepositoryS3 {
+		s3 := snapshots.Repository.S3
+		settings := map[string]interface{}{
+			"bucket":   s3.Bucket,
+			"client":   "default",
+			"compress": true,
+		}
+		if s3.BasePath != "" {
+			settings["base_path"] = s3.BasePath
+		}
+		return elasticsearch.SnapshotRepository{Type: "s3", Settings: settings}
+	}
+	return elasticsearch.SnapshotRepository{
+		Type:     "fs",
+		Settings: map[string]interface{}{"location": ElasticsearchSnapshotPath, "compress": true},
+	}
+}
+
+// snapshotNodeConfig returns the settings the Elasticsearch nodes need to reach the snapshot repository.
+func snapshotNodeConfig(snapshots *operatorv1.Snapshots) map[string]interface{} {
+	if snapshots.Repository.Type != operatorv1.SnapshotRepositoryS3 {
+		return map[string]interface{}{"path.repo": []string{ElasticsearchSnapshotPath}}
+	}
+	s3 := snapshots.Repository.S3
+	config := map[string]interface{}{}
+	if s3.Endpoint != "" {
+		// Elasticsearch takes the host and protocol of the endpoint as separate settings.
+		if u, err := url.Parse(s3.Endpoint); err == nil && u.Host != "" {
+			config["s3.client.default.endpoint"] = u.Host
+			config["s3.client.default.protocol"] = u.Scheme
+		} else {
+			config["s3.client.default.endpoint"] = s3.Endpoint
+		}
+	}
+	if s3.PathStyleAccess {
+		config["s3.client.default.path_style_access"] = "true"
+	}
+	return config
+}
@@ -0,0 +1,143 @@
+// Copyright (c) 2020 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render_test
+
+import (
+	cmneckalpha1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1alpha1"
+	esalpha1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1alpha1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	batch "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operator "github.com/tigera/operator/pkg/apis/operator/v1"
+	"github.com/tigera/operator/pkg/elasticsearch"
+	"github.com/tigera/operator/pkg/render"
+)
+
+var _ = Describe("Elasticsearch snapshot rendering tests", func() {
+	var logStorage *operator.LogStorage
+
+	BeforeEach(func() {
+		logStorage = &operator.LogStorage{
+			Spec: operator.LogStorageSpec{
+				Nodes: &operator.Nodes{Count: 1},
+			},
+		}
+	})
+
+	renderElasticsearch := func() *esalpha1.Elasticsearch {
+		component, err := render.Elasticsearch(logStorage, render.NewElasticsearchClusterConfig("cluster", 1, 5), nil, nil, false, nil, operator.ProviderNone, render.ECKAPIVersionV1alpha1, "")
+		Expect(err).NotTo(HaveOccurred())
+		return GetResource(component.Objects(), render.ElasticsearchName, render.ElasticsearchNamespace,
+			"elasticsearch.k8s.elastic.co", "v1alpha1", "Elasticsearch").(*esalpha1.Elasticsearch)
+	}
+
+	It("should store snapshots on a volume shared by the Elasticsearch nodes", func() {
+		size := resource.MustParse("200Gi")
+		logStorage.Spec.Snapshots = &operator.Snapshots{
+			Repository: operator.SnapshotRepository{
+				Type:       operator.SnapshotRepositoryFilesystem,
+				Filesystem: &operator.FilesystemSnapshotRepository{StorageClassName: "nfs", StorageSize: &size},
+			},
+		}
+
+		resources := render.ElasticsearchSnapshots(logStorage, nil, nil, "").Objects()
+		Expect(resources).To(HaveLen(2))
+		ExpectResource(resources[0], render.ElasticsearchSnapshotVolume, render.ElasticsearchNamespace, "", "v1", "PersistentVolumeClaim")
+		pvc := resources[0].(*corev1.PersistentVolumeClaim)
+		Expect(pvc.Spec.AccessModes).To(Equal([]corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany}))
+		Expect(*pvc.Spec.StorageClassName).To(Equal("nfs"))
+		Expect(pvc.Spec.Resources.Requests["storage"]).To(Equal(size))
+
+		ExpectResource(resources[1], render.ElasticsearchSnapshotCronJobName, render.ElasticsearchNamespace, "batch", "v1beta1", "CronJob")
+		cronJob := resources[1].(*batch.CronJob)
+		Expect(cronJob.Spec.Schedule).To(Equal("0 1 * * *"))
+		env := cronJob.Spec.JobTemplate.Spec.Template.Spec.Containers[0].Env
+		Expect(env).To(ContainElement(corev1.EnvVar{Name: "SNAPSHOT_REPOSITORY", Value: render.ElasticsearchSnapshotRepository}))
+		Expect(env).To(ContainElement(corev1.EnvVar{Name: "SNAPSHOT_INDICES", Value: "tigera_secure_ee_*"}))
+		Expect(env).To(ContainElement(corev1.EnvVar{Name: "SNAPSHOT_RETENTION_DAYS", Value: "30"}))
+
+		By("mounting the volume on the Elasticsearch nodes")
+		node := renderElasticsearch().Spec.Nodes[0]
+		Expect(node.Config.Data).To(HaveKeyWithValue("path.repo", []string{render.ElasticsearchSnapshotPath}))
+		Expect(node.PodTemplate.Spec.Volumes[0].PersistentVolumeClaim.ClaimName).To(Equal(render.ElasticsearchSnapshotVolume))
+		Expect(node.PodTemplate.Spec.Containers[0].Name).To(Equal("elasticsearch"))
+		Expect(node.PodTemplate.Spec.Containers[0].VolumeMounts).To(Equal([]corev1.VolumeMount{
+			{Name: "snapshots", MountPath: render.ElasticsearchSnapshotPath},
+		}))
+
+		Expect(render.SnapshotRepository(logStorage.Spec.Snapshots)).To(Equal(elasticsearch.SnapshotRepository{
+			Type:     "fs",
+			Settings: map[string]interface{}{"location": render.ElasticsearchSnapshotPath, "compress": true},
+		}))
+	})
+
+	It("should store snapshots in an S3 compatible object store", func() {
+		retention := int32(7)
+		logStorage.Spec.Snapshots = &operator.Snapshots{
+			Repository: operator.SnapshotRepository{
+				Type: operator.SnapshotRepositoryS3,
+				S3: &operator.S3SnapshotRepository{
+					Bucket:                "backups",
+					BasePath:              "cluster-a",
+					Endpoint:              "http://minio.minio.svc:9000",
+					PathStyleAccess:       true,
+					CredentialsSecretName: "minio-credentials",
+				},
+			},
+			Schedule:  "0 */6 * * *",
+			Retention: &retention,
+			Indices:   []string{"tigera_secure_ee_audit_*", "tigera_secure_ee_compliance_reports*"},
+		}
+		credentials := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "minio-credentials", Namespace: render.OperatorNamespace()},
+			Data: map[string][]byte{
+				render.SnapshotS3AccessKey: []byte("access"),
+				render.SnapshotS3SecretKey: []byte("secret"),
+			},
+		}
+
+		resources := render.ElasticsearchSnapshots(logStorage, credentials, nil, "").Objects()
+		Expect(resources).To(HaveLen(2))
+		ExpectResource(resources[0], render.ElasticsearchSnapshotCredentialsSecret, render.ElasticsearchNamespace, "", "v1", "Secret")
+		Expect(resources[0].(*corev1.Secret).Data).To(Equal(map[string][]byte{
+			"s3.client.default.access_key": []byte("access"),
+			"s3.client.default.secret_key": []byte("secret"),
+		}))
+		cronJob := resources[1].(*batch.CronJob)
+		Expect(cronJob.Spec.Schedule).To(Equal("0 */6 * * *"))
+		env := cronJob.Spec.JobTemplate.Spec.Template.Spec.Containers[0].Env
+		Expect(env).To(ContainElement(corev1.EnvVar{Name: "SNAPSHOT_INDICES", Value: "tigera_secure_ee_audit_*,tigera_secure_ee_compliance_reports*"}))
+		Expect(env).To(ContainElement(corev1.EnvVar{Name: "SNAPSHOT_RETENTION_DAYS", Value: "7"}))
+
+		By("configuring the object store client of the Elasticsearch nodes")
+		es := renderElasticsearch()
+		Expect(es.Spec.SecureSettings).To(Equal(&cmneckalpha1.SecretRef{SecretName: render.ElasticsearchSnapshotCredentialsSecret}))
+		node := es.Spec.Nodes[0]
+		Expect(node.Config.Data).To(HaveKeyWithValue("s3.client.default.endpoint", "minio.minio.svc:9000"))
+		Expect(node.Config.Data).To(HaveKeyWithValue("s3.client.default.protocol", "http"))
+		Expect(node.Config.Data).To(HaveKeyWithValue("s3.client.default.path_style_access", "true"))
+		Expect(node.PodTemplate.Spec.InitContainers).To(HaveLen(1))
+		Expect(node.PodTemplate.Spec.Containers).To(BeEmpty())
+
+		Expect(render.SnapshotRepository(logStorage.Spec.Snapshots)).To(Equal(elasticsearch.SnapshotRepository{
+			Type:     "s3",
+			Settings: map[string]interface{}{"bucket": "backups", "base_path": "cluster-a", "client": "default", "compress": true},
+		}))
+	})
+})
@@ -1,12 +1,18 @@
 package render_test
 
 import (
+	esalpha1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1alpha1"
+	kibanav1alpha1 "github.com/elastic/cloud-on-k8s/pkg/apis/kibana/v1alpha1"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	operator "github.com/tigera/operator/pkg/apis/operator/v1"
+	"github.com/tigera/operator/pkg/components"
 	"github.com/tigera/operator/pkg/render"
+	apps "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
 var _ = Describe("Elasticsearch rendering tests", func() {
@@ -58,7 +64,7 @@ var _ = Describe("Elasticsearch rendering tests", func() {
 			{"tigera-secure", "tigera-kibana", "", "", ""},
 		}
 
-		component, err := render.Elasticsearch(logStorage, esConfig, nil, nil, false, nil, operator.ProviderNone, "docker.elastic.co/eck/")
+		component, err := render.Elasticsearch(logStorage, esConfig, nil, nil, false, nil, operator.ProviderNone, render.ECKAPIVersionV1alpha1, "docker.elastic.co/eck/")
 		Expect(err).NotTo(HaveOccurred())
 
 		resources := component.Objects()
@@ -97,7 +103,7 @@ var _ = Describe("Elasticsearch rendering tests", func() {
 		}
 
 		component, err := render.Elasticsearch(logStorage, esConfig, nil, nil, false,
-			[]*corev1.Secret{{ObjectMeta: metav1.ObjectMeta{Name: "pull-secret"}}}, operator.ProviderNone, "docker.elastic.co/eck/")
+			[]*corev1.Secret{{ObjectMeta: metav1.ObjectMeta{Name: "pull-secret"}}}, operator.ProviderNone, render.ECKAPIVersionV1alpha1, "docker.elastic.co/eck/")
 		Expect(err).NotTo(HaveOccurred())
 		resources := component.Objects()
 		Expect(len(resources)).To(Equal(len(expectedResources)))
@@ -131,7 +137,7 @@ var _ = Describe("Elasticsearch rendering tests", func() {
 			{"tigera-secure", "tigera-kibana", "", "", ""},
 		}
 
-		component, err := render.Elasticsearch(logStorage, esConfig, nil, nil, false, nil, operator.ProviderOpenShift, "docker.elastic.co/eck/")
+		component, err := render.Elasticsearch(logStorage, esConfig, nil, nil, false, nil, operator.ProviderOpenShift, render.ECKAPIVersionV1alpha1, "docker.elastic.co/eck/")
 		Expect(err).NotTo(HaveOccurred())
 		resources := component.Objects()
 		Expect(len(resources)).To(Equal(len(expectedResources)))
@@ -165,7 +171,7 @@ var _ = Describe("Elasticsearch rendering tests", func() {
 			{"tigera-secure-kibana-cert", "tigera-kibana", "", "v1", "Secret"},
 			{"tigera-secure", "tigera-kibana", "", "", ""},
 		}
-		component, err := render.Elasticsearch(logStorage, esConfig, nil, nil, true, nil, operator.ProviderOpenShift, "docker.elastic.co/eck/")
+		component, err := render.Elasticsearch(logStorage, esConfig, nil, nil, true, nil, operator.ProviderOpenShift, render.ECKAPIVersionV1alpha1, "docker.elastic.co/eck/")
 		Expect(err).NotTo(HaveOccurred())
 		resources := component.Objects()
 		Expect(len(resources)).To(Equal(len(expectedResources)))
@@ -178,7 +184,7 @@ var _ = Describe("Elasticsearch rendering tests", func() {
 	It("should not render Elasticsearch or Kibana cert secrets in the operator namespace when they are provided", func() {
 		component, err := render.Elasticsearch(logStorage, esConfig,
 			&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: render.TigeraElasticsearchCertSecret}},
-			&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: render.TigeraKibanaCertSecret}}, false, nil, operator.ProviderOpenShift, "")
+			&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: render.TigeraKibanaCertSecret}}, false, nil, operator.ProviderOpenShift, render.ECKAPIVersionV1alpha1, "")
 		expectedResources := []struct {
 			name    string
 			ns      string
@@ -207,4 +213,191 @@ var _ = Describe("Elasticsearch rendering tests", func() {
 			ExpectResource(resources[i], expectedRes.name, expectedRes.ns, expectedRes.group, expectedRes.version, expectedRes.kind)
 		}
 	})
+
+	It("should render dedicated node sets spread over zones", func() {
+		storage := resource.MustParse("100Gi")
+		logStorage.Spec.Nodes = &operator.Nodes{
+			NodeSets: &operator.NodeSets{
+				Master: operator.NodeSet{Count: 3},
+				Data: operator.NodeSet{
+					Count:       5,
+					StorageSize: &storage,
+					ResourceRequirements: &corev1.ResourceRequirements{
+						Limits: corev1.ResourceList{"memory": resource.MustParse("8Gi")},
+					},
+				},
+				Ingest: &operator.NodeSet{Count: 1},
+			},
+			ZoneAwareness:   &operator.ZoneAwareness{Zones: []string{"us-east-1a", "us-east-1b"}},
+			PodAntiAffinity: operator.PodAntiAffinityRequired,
+		}
+
+		component, err := render.Elasticsearch(logStorage, esConfig, nil, nil, false, nil, operator.ProviderNone, render.ECKAPIVersionV1alpha1, "")
+		Expect(err).NotTo(HaveOccurred())
+		es := GetResource(component.Objects(), render.ElasticsearchName, render.ElasticsearchNamespace,
+			"elasticsearch.k8s.elastic.co", "v1alpha1", "Elasticsearch").(*esalpha1.Elasticsearch)
+
+		nodes := map[string]esalpha1.NodeSpec{}
+		for _, n := range es.Spec.Nodes {
+			nodes[n.Name] = n
+		}
+		Expect(nodes).To(HaveLen(5))
+		Expect(nodes["master-z0"].NodeCount).To(Equal(int32(2)))
+		Expect(nodes["master-z1"].NodeCount).To(Equal(int32(1)))
+		Expect(nodes["data-z0"].NodeCount).To(Equal(int32(3)))
+		Expect(nodes["data-z1"].NodeCount).To(Equal(int32(2)))
+		Expect(nodes["ingest-z0"].NodeCount).To(Equal(int32(1)))
+
+		By("assigning the roles of each node set")
+		Expect(nodes["master-z0"].Config.Data).To(Equal(map[string]interface{}{
+			"node.master":    "true",
+			"node.data":      "false",
+			"node.ingest":    "false",
+			"node.attr.zone": "us-east-1a",
+			"cluster.routing.allocation.awareness.attributes": "zone",
+		}))
+		Expect(nodes["data-z1"].Config.Data).To(HaveKeyWithValue("node.data", "true"))
+		Expect(nodes["data-z1"].Config.Data).To(HaveKeyWithValue("node.ingest", "false"))
+		Expect(nodes["data-z1"].Config.Data).To(HaveKeyWithValue("node.attr.zone", "us-east-1b"))
+
+		By("sizing the resources and storage of each node set")
+		data := nodes["data-z0"]
+		Expect(data.PodTemplate.Spec.Containers).To(HaveLen(1))
+		Expect(data.PodTemplate.Spec.Containers[0].Name).To(Equal("elasticsearch"))
+		Expect(data.PodTemplate.Spec.Containers[0].Resources.Limits["memory"]).To(Equal(resource.MustParse("8Gi")))
+		Expect(data.VolumeClaimTemplates[0].Spec.Resources.Requests["storage"]).To(Equal(storage))
+		Expect(nodes["master-z0"].VolumeClaimTemplates[0].Spec.Resources.Requests["storage"]).To(Equal(resource.MustParse("10Gi")))
+
+		By("pinning each node spec to its zone and keeping nodes apart")
+		affinity := data.PodTemplate.Spec.Affinity
+		Expect(affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms[0].MatchExpressions).To(Equal(
+			[]corev1.NodeSelectorRequirement{{
+				Key:      "failure-domain.beta.kubernetes.io/zone",
+				Operator: corev1.NodeSelectorOpIn,
+				Values:   []string{"us-east-1a"},
+			}}))
+		Expect(affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution).To(HaveLen(1))
+		Expect(affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution[0].TopologyKey).To(Equal("kubernetes.io/hostname"))
+	})
+
+	It("should let the data nodes take the ingest role without dedicated ingest nodes", func() {
+		logStorage.Spec.Nodes = &operator.Nodes{
+			NodeSets: &operator.NodeSets{
+				Master: operator.NodeSet{Count: 1},
+				Data:   operator.NodeSet{Count: 2},
+			},
+		}
+		component, err := render.Elasticsearch(logStorage, esConfig, nil, nil, false, nil, operator.ProviderNone, render.ECKAPIVersionV1alpha1, "")
+		Expect(err).NotTo(HaveOccurred())
+		es := GetResource(component.Objects(), render.ElasticsearchName, render.ElasticsearchNamespace,
+			"elasticsearch.k8s.elastic.co", "v1alpha1", "Elasticsearch").(*esalpha1.Elasticsearch)
+
+		Expect(es.Spec.Nodes).To(HaveLen(2))
+		Expect(es.Spec.Nodes[0].Name).To(Equal("master"))
+		Expect(es.Spec.Nodes[1].Name).To(Equal("data"))
+		Expect(es.Spec.Nodes[1].Config.Data).To(HaveKeyWithValue("node.ingest", "true"))
+		Expect(es.Spec.Nodes[1].PodTemplate.Spec.Affinity).To(BeNil())
+	})
+
+	It("should provision the volumes with the configured storage class and size", func() {
+		storage := resource.MustParse("50Gi")
+		logStorage.Spec.StorageClassName = "fast-ssd"
+		logStorage.Spec.Nodes.StorageSize = &storage
+		logStorage.Spec.Nodes.ResourceRequirements = &corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{"storage": resource.MustParse("20Gi")},
+		}
+
+		component, err := render.Elasticsearch(logStorage, esConfig, nil, nil, false, nil, operator.ProviderNone, render.ECKAPIVersionV1alpha1, "")
+		Expect(err).NotTo(HaveOccurred())
+		es := GetResource(component.Objects(), render.ElasticsearchName, render.ElasticsearchNamespace,
+			"elasticsearch.k8s.elastic.co", "v1alpha1", "Elasticsearch").(*esalpha1.Elasticsearch)
+
+		pvc := es.Spec.Nodes[0].VolumeClaimTemplates[0]
+		Expect(*pvc.Spec.StorageClassName).To(Equal("fast-ssd"))
+		Expect(pvc.Spec.Resources.Requests["storage"]).To(Equal(storage))
+
+		By("leaving the resource requirements of the LogStorage unchanged")
+		Expect(logStorage.Spec.Nodes.ResourceRequirements.Requests["storage"]).To(Equal(resource.MustParse("20Gi")))
+	})
+
+	It("should size Kibana with the configured replicas and resources", func() {
+		kibanaReplicas := int32(2)
+		logStorage.Spec.Kibana = &operator.Kibana{
+			Replicas: &kibanaReplicas,
+			Resources: &corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{"memory": resource.MustParse("2Gi")},
+			},
+		}
+
+		component, err := render.Elasticsearch(logStorage, esConfig, nil, nil, false, nil, operator.ProviderNone, render.ECKAPIVersionV1alpha1, "")
+		Expect(err).NotTo(HaveOccurred())
+		kb := GetResource(component.Objects(), render.KibanaName, render.KibanaNamespace, "", "", "").(*kibanav1alpha1.Kibana)
+		Expect(kb.Spec.NodeCount).To(Equal(int32(2)))
+		Expect(kb.Spec.PodTemplate.Spec.Containers[0].Resources.Limits["memory"]).To(Equal(resource.MustParse("2Gi")))
+	})
+
+	It("should serve Kibana under the base path the manager proxies it on", func() {
+		component, err := render.Elasticsearch(logStorage, esConfig, nil, nil, false, nil, operator.ProviderNone, render.ECKAPIVersionV1alpha1, "")
+		Expect(err).NotTo(HaveOccurred())
+		kb := GetResource(component.Objects(), render.KibanaName, render.KibanaNamespace, "", "", "").(*kibanav1alpha1.Kibana)
+		Expect(kb.Spec.Config.Data["server"]).To(Equal(map[string]interface{}{
+			"basePath":        "/tigera-kibana",
+			"rewriteBasePath": true,
+		}))
+		Expect(kb.Spec.PodTemplate.Spec.Containers[0].ReadinessProbe.HTTPGet.Path).To(Equal("/tigera-kibana/login"))
+	})
+
+	It("should not render Kibana when it is disabled", func() {
+		logStorage.Spec.Kibana = &operator.Kibana{Disabled: true}
+
+		component, err := render.Elasticsearch(logStorage, esConfig, nil, nil, false, nil, operator.ProviderNone, render.ECKAPIVersionV1alpha1, "")
+		Expect(err).NotTo(HaveOccurred())
+		for _, obj := range component.Objects() {
+			meta := obj.(metav1.ObjectMetaAccessor).GetObjectMeta()
+			Expect(meta.GetNamespace()).NotTo(Equal(render.KibanaNamespace))
+			Expect(meta.GetName()).NotTo(Equal(render.KibanaNamespace))
+			Expect(meta.GetName()).NotTo(Equal(render.TigeraKibanaCertSecret))
+		}
+	})
+
+	It("should render Elasticsearch and Kibana through the ECK v1 APIs", func() {
+		kibanaReplicas := int32(2)
+		logStorage.Spec.Nodes.Count = 3
+		logStorage.Spec.Kibana = &operator.Kibana{Replicas: &kibanaReplicas}
+
+		component, err := render.Elasticsearch(logStorage, esConfig, nil, nil, false, nil, operator.ProviderNone, render.ECKAPIVersionV1, "")
+		Expect(err).NotTo(HaveOccurred())
+		es := GetResource(component.Objects(), render.ElasticsearchName, render.ElasticsearchNamespace,
+			"elasticsearch.k8s.elastic.co", "v1", "Elasticsearch").(*unstructured.Unstructured)
+
+		nodeSets, _, err := unstructured.NestedSlice(es.Object, "spec", "nodeSets")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(nodeSets).To(HaveLen(1))
+		Expect(nodeSets[0]).To(HaveKeyWithValue("name", render.ECKDefaultNodeSetName))
+		Expect(nodeSets[0]).To(HaveKeyWithValue("count", int64(3)))
+		Expect(nodeSets[0]).NotTo(HaveKey("nodeCount"))
+		_, found, err := unstructured.NestedFieldNoCopy(es.Object, "spec", "nodes")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found).To(BeFalse())
+
+		kb := GetResource(component.Objects(), render.KibanaName, render.KibanaNamespace,
+			"kibana.k8s.elastic.co", "v1", "Kibana").(*unstructured.Unstructured)
+		count, _, err := unstructured.NestedInt64(kb.Object, "spec", "count")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(count).To(Equal(int64(2)))
+
+		By("deploying an ECK operator that manages the v1 APIs")
+		var operatorSet *apps.StatefulSet
+		for _, obj := range component.Objects() {
+			if ss, ok := obj.(*apps.StatefulSet); ok && ss.Name == render.ECKOperatorName {
+				operatorSet = ss
+			}
+		}
+		Expect(operatorSet).NotTo(BeNil())
+		Expect(operatorSet.Spec.Template.Spec.Containers[0].Image).To(Equal("docker.elastic.co/eck/eck-operator:" + components.VersionECKOperatorV1))
+		Expect(operatorSet.Spec.Template.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{
+			Name:  "OPERATOR_IMAGE",
+			Value: "docker.elastic.co/eck/eck-operator:" + components.VersionECKOperatorV1,
+		}))
+	})
 })
@@ -16,6 +16,18 @@ func NewElasticsearchClusterConfig(clusterName string, replicas int, shards int)
 	}
 }
 
+// NewExternalElasticsearchClusterConfig returns the configuration of an Elasticsearch cluster that is not run by the
+// operator, which components reach at the given URL.
+func NewExternalElasticsearchClusterConfig(clusterName, url string, replicas int, shards int) *ElasticsearchClusterConfig {
+	return &ElasticsearchClusterConfig{
+		clusterName:    clusterName,
+		url:            url,
+		replicas:       replicas,
+		shards:         shards,
+		kibanaDisabled: true,
+	}
+}
+
 func NewElasticsearchClusterConfigFromConfigMap(configMap *corev1.ConfigMap) (*ElasticsearchClusterConfig, error) {
 	var replicas, shards int
 	var err error
@@ -41,22 +53,46 @@ func NewElasticsearchClusterConfigFromConfigMap(configMap *corev1.ConfigMap) (*E
 	}
 
 	return &ElasticsearchClusterConfig{
-		clusterName: configMap.Data["clusterName"],
-		replicas:    replicas,
-		shards:      shards,
+		clusterName:    configMap.Data["clusterName"],
+		url:            configMap.Data["url"],
+		replicas:       replicas,
+		shards:         shards,
+		kibanaDisabled: configMap.Data["kibana"] == "disabled",
 	}, nil
 }
 
 type ElasticsearchClusterConfig struct {
 	clusterName string
-	replicas    int
-	shards      int
+	// url is empty for the Elasticsearch cluster run by the operator.
+	url      string
+	replicas int
+	shards   int
+	// kibanaDisabled is true if no Kibana is deployed with the cluster.
+	kibanaDisabled bool
 }
 
 func (c ElasticsearchClusterConfig) ClusterName() string {
 	return c.clusterName
 }
 
+// URL returns the URL components reach Elasticsearch at.
+func (c ElasticsearchClusterConfig) URL() string {
+	if c.url == "" {
+		return ElasticsearchHTTPSEndpoint
+	}
+	return c.url
+}
+
+// KibanaEnabled returns true if Kibana is deployed with the cluster, so components can link to it.
+func (c ElasticsearchClusterConfig) KibanaEnabled() bool {
+	return !c.kibanaDisabled
+}
+
+// DisableKibana records that no Kibana is deployed with the cluster.
+func (c *ElasticsearchClusterConfig) DisableKibana() {
+	c.kibanaDisabled = true
+}
+
 func (c ElasticsearchClusterConfig) Replicas() int {
 	return c.replicas
 }
@@ -70,7 +106,7 @@ func (c ElasticsearchClusterConfig) Annotation() string {
 }
 
 func (c ElasticsearchClusterConfig) ConfigMap() *corev1.ConfigMap {
-	return &corev1.ConfigMap{
+	configMap := &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      ElasticsearchConfigMapName,
 			Namespace: OperatorNamespace(),
@@ -81,4 +117,11 @@ func (c ElasticsearchClusterConfig) ConfigMap() *corev1.ConfigMap {
 			"shards":      strconv.Itoa(c.shards),
 		},
 	}
+	if c.url != "" {
+		configMap.Data["url"] = c.url
+	}
+	if c.kibanaDisabled {
+		configMap.Data["kibana"] = "disabled"
+	}
+	return configMap
 }
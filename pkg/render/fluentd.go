@@ -35,9 +35,12 @@ const (
 	S3FluentdSecretName                      = "log-collector-s3-credentials"
 	S3KeyIdName                              = "key-id"
 	S3KeySecretName                          = "key-secret"
+	SplunkFluentdSecretName                  = "log-collector-splunk-credentials"
+	SplunkTokenName                          = "token"
 	elasticsearchSecretsAnnotation           = "hash.operator.tigera.io/elasticsearch-secrets"
 	filterHashAnnotation                     = "hash.operator.tigera.io/fluentd-filters"
 	s3CredentialHashAnnotation               = "hash.operator.tigera.io/s3-credentials"
+	splunkCredentialHashAnnotation           = "hash.operator.tigera.io/splunk-credentials"
 	eksCloudwatchLogCredentialHashAnnotation = "hash.operator.tigera.io/eks-cloudwatch-log-credentials"
 	fluentdDefaultFlush                      = "5s"
 	ElasticsearchLogCollectorUserSecret      = "tigera-fluentd-elasticsearch-access"
@@ -58,11 +61,16 @@ type S3Credential struct {
 	KeySecret []byte
 }
 
+type SplunkCredential struct {
+	Token []byte
+}
+
 func Fluentd(
 	lc *operatorv1.LogCollector,
 	esSecrets []*corev1.Secret,
 	esClusterConfig *ElasticsearchClusterConfig,
 	s3C *S3Credential,
+	splunkC *SplunkCredential,
 	f *FluentdFilters,
 	eksConfig *EksCloudwatchLogConfig,
 
@@ -70,14 +78,15 @@ func Fluentd(
 	installation *operatorv1.Installation,
 ) Component {
 	return &fluentdComponent{
-		lc:              lc,
-		esSecrets:       esSecrets,
-		esClusterConfig: esClusterConfig,
-		s3Credential:    s3C,
-		filters:         f,
-		eksConfig:       eksConfig,
-		pullSecrets:     pullSecrets,
-		installation:    installation,
+		lc:               lc,
+		esSecrets:        esSecrets,
+		esClusterConfig:  esClusterConfig,
+		s3Credential:     s3C,
+		splunkCredential: splunkC,
+		filters:          f,
+		eksConfig:        eksConfig,
+		pullSecrets:      pullSecrets,
+		installation:     installation,
 	}
 }
 
@@ -91,14 +100,15 @@ type EksCloudwatchLogConfig struct {
 }
 
 type fluentdComponent struct {
-	lc              *operatorv1.LogCollector
-	esSecrets       []*corev1.Secret
-	esClusterConfig *ElasticsearchClusterConfig
-	s3Credential    *S3Credential
-	filters         *FluentdFilters
-	eksConfig       *EksCloudwatchLogConfig
-	pullSecrets     []*corev1.Secret
-	installation    *operatorv1.Installation
+	lc               *operatorv1.LogCollector
+	esSecrets        []*corev1.Secret
+	esClusterConfig  *ElasticsearchClusterConfig
+	s3Credential     *S3Credential
+	splunkCredential *SplunkCredential
+	filters          *FluentdFilters
+	eksConfig        *EksCloudwatchLogConfig
+	pullSecrets      []*corev1.Secret
+	installation     *operatorv1.Installation
 }
 
 func (c *fluentdComponent) Objects() []runtime.Object {
@@ -111,6 +121,9 @@ func (c *fluentdComponent) Objects() []runtime.Object {
 	if c.s3Credential != nil {
 		objs = append(objs, c.s3CredentialSecret())
 	}
+	if c.splunkCredential != nil {
+		objs = append(objs, c.splunkCredentialSecret())
+	}
 	if c.filters != nil {
 		objs = append(objs, c.filtersConfigMap())
 	}
@@ -147,6 +160,19 @@ func (c *fluentdComponent) s3CredentialSecret() *corev1.Secret {
 	}
 }
 
+func (c *fluentdComponent) splunkCredentialSecret() *corev1.Secret {
+	return &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{Kind: "Secret", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      SplunkFluentdSecretName,
+			Namespace: LogCollectorNamespace,
+		},
+		Data: map[string][]byte{
+			SplunkTokenName: c.splunkCredential.Token,
+		},
+	}
+}
+
 func (c *fluentdComponent) filtersConfigMap() *corev1.ConfigMap {
 	if c.filters == nil {
 		return nil
@@ -173,6 +199,9 @@ func (c *fluentdComponent) daemonset() *appsv1.DaemonSet {
 	if c.s3Credential != nil {
 		annots[s3CredentialHashAnnotation] = AnnotationHash(c.s3Credential)
 	}
+	if c.splunkCredential != nil {
+		annots[splunkCredentialHashAnnotation] = AnnotationHash(c.splunkCredential)
+	}
 	if c.filters != nil {
 		annots[filterHashAnnotation] = AnnotationHash(c.filters)
 	}
@@ -262,7 +291,7 @@ func (c *fluentdComponent) container() corev1.Container {
 		VolumeMounts:    volumeMounts,
 		LivenessProbe:   c.liveness(),
 		ReadinessProbe:  c.readiness(),
-	}, c.esClusterConfig.ClusterName(), ElasticsearchLogCollectorUserSecret)
+	}, c.esClusterConfig, ElasticsearchLogCollectorUserSecret)
 }
 
 func (c *fluentdComponent) envvars() []corev1.EnvVar {
@@ -331,6 +360,20 @@ func (c *fluentdComponent) envvars() []corev1.EnvVar {
 				)
 			}
 		}
+		splunk := c.lc.Spec.AdditionalStores.Splunk
+		if splunk != nil {
+			proto, host, port, _ := ParseEndpoint(splunk.Endpoint)
+			envs = append(envs,
+				corev1.EnvVar{Name: "SPLUNK_FLOW_LOG", Value: "true"},
+				corev1.EnvVar{Name: "SPLUNK_AUDIT_LOG", Value: "true"},
+				corev1.EnvVar{Name: "SPLUNK_DNS_LOG", Value: "true"},
+				corev1.EnvVar{Name: "SPLUNK_HEC_HOST", Value: host},
+				corev1.EnvVar{Name: "SPLUNK_HEC_PORT", Value: port},
+				corev1.EnvVar{Name: "SPLUNK_PROTOCOL", Value: proto},
+				corev1.EnvVar{Name: "SPLUNK_FLUSH_INTERVAL", Value: fluentdDefaultFlush},
+				corev1.EnvVar{Name: "SPLUNK_HEC_TOKEN", ValueFrom: envVarSourceFromSecret(SplunkFluentdSecretName, SplunkTokenName, false)},
+			)
+		}
 	}
 
 	if c.filters != nil {
@@ -493,13 +536,13 @@ func (c *fluentdComponent) eksLogForwarderDeployment() *appsv1.Deployment {
 						Command:      []string{"/bin/eks-log-forwarder-startup"},
 						Env:          envVars,
 						VolumeMounts: c.eksLogForwarderVolumeMounts(),
-					}, c.esClusterConfig.ClusterName(), ElasticsearchEksLogForwarderUserSecret)},
+					}, c.esClusterConfig, ElasticsearchEksLogForwarderUserSecret)},
 					Containers: []corev1.Container{ElasticsearchContainerDecorateENVVars(corev1.Container{
 						Name:         eksLogForwarderName,
 						Image:        constructImage(FluentdImageName, c.installation.Spec.Registry),
 						Env:          envVars,
 						VolumeMounts: c.eksLogForwarderVolumeMounts(),
-					}, c.esClusterConfig.ClusterName(), ElasticsearchEksLogForwarderUserSecret)},
+					}, c.esClusterConfig, ElasticsearchEksLogForwarderUserSecret)},
 					Volumes: c.eksLogForwarderVolumes(),
 				},
 			},
@@ -48,7 +48,7 @@ var _ = Describe("Tigera Secure Fluentd rendering tests", func() {
 	})
 
 	It("should render all resources for a default configuration", func() {
-		component := render.Fluentd(instance, nil, esConfigMap, s3Creds, filters, eksConfig, nil, installation)
+		component := render.Fluentd(instance, nil, esConfigMap, s3Creds, nil, filters, eksConfig, nil, installation)
 		resources := component.Objects()
 		Expect(len(resources)).To(Equal(2))
 
@@ -83,7 +83,7 @@ var _ = Describe("Tigera Secure Fluentd rendering tests", func() {
 				BucketPath: "bucketpath",
 			},
 		}
-		component := render.Fluentd(instance, nil, esConfigMap, s3Creds, filters, eksConfig, nil, installation)
+		component := render.Fluentd(instance, nil, esConfigMap, s3Creds, nil, filters, eksConfig, nil, installation)
 		resources := component.Objects()
 		Expect(len(resources)).To(Equal(3))
 
@@ -149,7 +149,7 @@ var _ = Describe("Tigera Secure Fluentd rendering tests", func() {
 				PacketSize: &ps,
 			},
 		}
-		component := render.Fluentd(instance, nil, esConfigMap, s3Creds, filters, eksConfig, nil, installation)
+		component := render.Fluentd(instance, nil, esConfigMap, s3Creds, nil, filters, eksConfig, nil, installation)
 		resources := component.Objects()
 		Expect(len(resources)).To(Equal(2))
 
@@ -213,11 +213,79 @@ var _ = Describe("Tigera Secure Fluentd rendering tests", func() {
 
 	})
 
+	It("should render with Splunk configuration", func() {
+		splunkCreds := &render.SplunkCredential{
+			Token: []byte("TokenForHEC"),
+		}
+		instance.Spec.AdditionalStores = &operatorv1.AdditionalLogStoreSpec{
+			Splunk: &operatorv1.SplunkStoreSpec{
+				Endpoint: "https://1.2.3.4:8088",
+			},
+		}
+		component := render.Fluentd(instance, nil, esConfigMap, s3Creds, splunkCreds, filters, eksConfig, nil, installation)
+		resources := component.Objects()
+		Expect(len(resources)).To(Equal(3))
+
+		// Should render the correct resources.
+		expectedResources := []struct {
+			name    string
+			ns      string
+			group   string
+			version string
+			kind    string
+		}{
+			{name: "tigera-fluentd", ns: "", group: "", version: "v1", kind: "Namespace"},
+			{name: "log-collector-splunk-credentials", ns: "tigera-fluentd", group: "", version: "v1", kind: "Secret"},
+			{name: "fluentd-node", ns: "tigera-fluentd", group: "apps", version: "v1", kind: "DaemonSet"},
+		}
+
+		i := 0
+		for _, expectedRes := range expectedResources {
+			ExpectResource(resources[i], expectedRes.name, expectedRes.ns, expectedRes.group, expectedRes.version, expectedRes.kind)
+			i++
+		}
+
+		ds := resources[2].(*apps.DaemonSet)
+		Expect(ds.Spec.Template.Spec.Containers).To(HaveLen(1))
+		Expect(ds.Spec.Template.Annotations).To(HaveKey("hash.operator.tigera.io/splunk-credentials"))
+		envs := ds.Spec.Template.Spec.Containers[0].Env
+
+		expectedEnvs := []struct {
+			name       string
+			val        string
+			secretName string
+			secretKey  string
+		}{
+			{"SPLUNK_FLOW_LOG", "true", "", ""},
+			{"SPLUNK_AUDIT_LOG", "true", "", ""},
+			{"SPLUNK_DNS_LOG", "true", "", ""},
+			{"SPLUNK_HEC_HOST", "1.2.3.4", "", ""},
+			{"SPLUNK_HEC_PORT", "8088", "", ""},
+			{"SPLUNK_PROTOCOL", "https", "", ""},
+			{"SPLUNK_FLUSH_INTERVAL", "5s", "", ""},
+			{"SPLUNK_HEC_TOKEN", "", "log-collector-splunk-credentials", "token"},
+		}
+		for _, expected := range expectedEnvs {
+			if expected.val != "" {
+				Expect(envs).To(ContainElement(corev1.EnvVar{Name: expected.name, Value: expected.val}))
+			} else {
+				Expect(envs).To(ContainElement(corev1.EnvVar{
+					Name: expected.name,
+					ValueFrom: &corev1.EnvVarSource{
+						SecretKeyRef: &corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: expected.secretName},
+							Key:                  expected.secretKey,
+						}},
+				}))
+			}
+		}
+	})
+
 	It("should render with filter", func() {
 		filters = &render.FluentdFilters{
 			Flow: "flow-filter",
 		}
-		component := render.Fluentd(instance, nil, esConfigMap, s3Creds, filters, eksConfig, nil, installation)
+		component := render.Fluentd(instance, nil, esConfigMap, s3Creds, nil, filters, eksConfig, nil, installation)
 		resources := component.Objects()
 		Expect(len(resources)).To(Equal(3))
 
@@ -262,7 +330,7 @@ var _ = Describe("Tigera Secure Fluentd rendering tests", func() {
 				KubernetesProvider: operatorv1.ProviderEKS,
 			},
 		}
-		component := render.Fluentd(instance, nil, esConfigMap, s3Creds, filters, eksConfig, nil, installation)
+		component := render.Fluentd(instance, nil, esConfigMap, s3Creds, nil, filters, eksConfig, nil, installation)
 		resources := component.Objects()
 		Expect(len(resources)).To(Equal(5))
 
@@ -3,6 +3,11 @@
 package render
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
@@ -10,6 +15,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
+
+	operator "github.com/tigera/operator/pkg/apis/operator/v1"
 )
 
 // The names of the components related to the Guardian related rendered objects.
@@ -24,34 +31,52 @@ const (
 	GuardianConfigMapName          = "tigera-guardian-config"
 	GuardianVolumeName             = "tigera-guardian-certs"
 	GuardianSecretName             = "tigera-managed-cluster-connection"
+
+	// GuardianCABundleConfigMapName is the ConfigMap in the operator namespace that overrides the CA used to
+	// verify the management cluster.
+	GuardianCABundleConfigMapName = "tigera-management-cluster-ca-bundle"
+	GuardianCABundleKey           = "ca.crt"
+
+	guardianCABundleMountPath      = "/certs/ca-bundle"
+	guardianConfigHashAnnotation   = "hash.operator.tigera.io/guardian-config"
+	guardianSecretHashAnnotation   = "hash.operator.tigera.io/guardian-secret"
+	guardianCABundleHashAnnotation = "hash.operator.tigera.io/guardian-ca-bundle"
 )
 
+// guardianNoProxy lists the destinations Guardian reaches without the proxy: the Kubernetes API server and
+// the services it proxies requests to.
+var guardianNoProxy = []string{"kubernetes.default", ".svc", ".svc.cluster.local"}
+
 func Guardian(
-	url string,
+	cr *operator.ManagementClusterConnection,
 	pullSecrets []*corev1.Secret,
 	openshift bool,
 	registry string,
 	tunnelSecret *corev1.Secret,
+	caBundle *corev1.ConfigMap,
 ) Component {
 	return &GuardianComponent{
-		url:          url,
+		cr:           cr,
 		pullSecrets:  pullSecrets,
 		openshift:    openshift,
 		registry:     registry,
 		tunnelSecret: tunnelSecret,
+		caBundle:     caBundle,
 	}
 }
 
 type GuardianComponent struct {
-	url          string
+	cr           *operator.ManagementClusterConnection
 	pullSecrets  []*v1.Secret
 	openshift    bool
 	registry     string
 	tunnelSecret *corev1.Secret
+	// The CA bundle overriding the management cluster CA, if present in the operator namespace.
+	caBundle *corev1.ConfigMap
 }
 
 func (c *GuardianComponent) Objects() []runtime.Object {
-	return []runtime.Object{
+	objs := []runtime.Object{
 		createNamespace(GuardianNamespace, c.openshift),
 		c.serviceAccount(),
 		c.clusterRole(),
@@ -61,6 +86,10 @@ func (c *GuardianComponent) Objects() []runtime.Object {
 		c.configMap(),
 		copySecrets(GuardianNamespace, c.tunnelSecret)[0],
 	}
+	if c.caBundle != nil {
+		objs = append(objs, copyConfigMaps(GuardianNamespace, c.caBundle)...)
+	}
+	return objs
 }
 
 func (c *GuardianComponent) Ready() bool {
@@ -96,7 +125,7 @@ func (c *GuardianComponent) serviceAccount() runtime.Object {
 	}
 }
 
-func (c *GuardianComponent) configMap() runtime.Object {
+func (c *GuardianComponent) configMap() *v1.ConfigMap {
 	return &v1.ConfigMap{
 		TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
 		ObjectMeta: metav1.ObjectMeta{Name: GuardianConfigMapName, Namespace: GuardianNamespace},
@@ -128,11 +157,53 @@ func (c *GuardianComponent) configMap() runtime.Object {
 					"url": "https://compliance.tigera-compliance.svc"
 				}]`,
 			// This tells Guardian how to reach Voltron
-			"tigera-guardian.voltron-url": c.url,
+			"tigera-guardian.voltron-url": c.cr.Spec.ManagementClusterAddr,
 		},
 	}
 }
 
+// tunnelEnvVars returns the environment variables configuring the connection to the management cluster.
+func (c *GuardianComponent) tunnelEnvVars() []corev1.EnvVar {
+	var env []corev1.EnvVar
+	if c.cr.Spec.ProxyURL != "" {
+		env = append(env,
+			corev1.EnvVar{Name: "HTTPS_PROXY", Value: c.cr.Spec.ProxyURL},
+			corev1.EnvVar{Name: "NO_PROXY", Value: strings.Join(guardianNoProxy, ",")},
+		)
+	}
+	if c.caBundle != nil {
+		env = append(env, corev1.EnvVar{
+			Name:  "GUARDIAN_VOLTRON_CA_PATH",
+			Value: fmt.Sprintf("%s/%s", guardianCABundleMountPath, GuardianCABundleKey),
+		})
+	}
+
+	t := c.cr.Spec.Tunnel
+	if t == nil {
+		return env
+	}
+	if t.KeepAlive != nil {
+		env = append(env, corev1.EnvVar{Name: "GUARDIAN_KEEP_ALIVE_ENABLE", Value: strconv.FormatBool(*t.KeepAlive)})
+	}
+	if t.KeepAliveInterval != nil {
+		// Guardian takes the keepalive interval in milliseconds.
+		env = append(env, corev1.EnvVar{
+			Name:  "GUARDIAN_KEEP_ALIVE_INTERVAL",
+			Value: strconv.FormatInt(int64(t.KeepAliveInterval.Duration/time.Millisecond), 10),
+		})
+	}
+	if t.DialTimeout != nil {
+		env = append(env, corev1.EnvVar{Name: "GUARDIAN_TUNNEL_DIAL_TIMEOUT", Value: t.DialTimeout.Duration.String()})
+	}
+	if t.ReconnectInterval != nil {
+		env = append(env, corev1.EnvVar{Name: "GUARDIAN_TUNNEL_DIAL_RETRY_INTERVAL", Value: t.ReconnectInterval.Duration.String()})
+	}
+	if t.ReconnectAttempts != nil {
+		env = append(env, corev1.EnvVar{Name: "GUARDIAN_TUNNEL_DIAL_RETRY_ATTEMPTS", Value: strconv.Itoa(int(*t.ReconnectAttempts))})
+	}
+	return env
+}
+
 func (c *GuardianComponent) clusterRole() runtime.Object {
 	return &rbacv1.ClusterRole{
 		TypeMeta: metav1.TypeMeta{Kind: "ClusterRole", APIVersion: "rbac.authorization.k8s.io/v1"},
@@ -197,9 +268,7 @@ func (c *GuardianComponent) deployment() runtime.Object {
 					Labels: map[string]string{
 						"k8s-app": GuardianName,
 					},
-					Annotations: map[string]string{
-						"scheduler.alpha.kubernetes.io/critical-pod": "",
-					},
+					Annotations: c.annotations(),
 				},
 				Spec: corev1.PodSpec{
 					NodeSelector: map[string]string{
@@ -216,6 +285,20 @@ func (c *GuardianComponent) deployment() runtime.Object {
 	}
 }
 
+// annotations returns the pod annotations, including hashes of the configuration so that Guardian is restarted
+// when its configuration changes.
+func (c *GuardianComponent) annotations() map[string]string {
+	annotations := map[string]string{
+		"scheduler.alpha.kubernetes.io/critical-pod": "",
+		guardianConfigHashAnnotation:                 AnnotationHash(c.configMap().Data),
+		guardianSecretHashAnnotation:                 AnnotationHash(c.tunnelSecret.Data),
+	}
+	if c.caBundle != nil {
+		annotations[guardianCABundleHashAnnotation] = AnnotationHash(c.caBundle.Data)
+	}
+	return annotations
+}
+
 func (c *GuardianComponent) tolerations() []v1.Toleration {
 	return []v1.Toleration{
 		{
@@ -232,7 +315,7 @@ func (c *GuardianComponent) tolerations() []v1.Toleration {
 }
 
 func (c *GuardianComponent) volumes() []v1.Volume {
-	return []v1.Volume{
+	volumes := []v1.Volume{
 		{
 			Name: GuardianVolumeName,
 			VolumeSource: v1.VolumeSource{
@@ -242,6 +325,33 @@ func (c *GuardianComponent) volumes() []v1.Volume {
 			},
 		},
 	}
+	if c.caBundle != nil {
+		volumes = append(volumes, v1.Volume{
+			Name: GuardianCABundleConfigMapName,
+			VolumeSource: v1.VolumeSource{
+				ConfigMap: &v1.ConfigMapVolumeSource{
+					LocalObjectReference: v1.LocalObjectReference{Name: GuardianCABundleConfigMapName},
+				},
+			},
+		})
+	}
+	return volumes
+}
+
+func (c *GuardianComponent) volumeMounts() []corev1.VolumeMount {
+	mounts := []corev1.VolumeMount{{
+		Name:      GuardianVolumeName,
+		MountPath: "/certs/",
+		ReadOnly:  true,
+	}}
+	if c.caBundle != nil {
+		mounts = append(mounts, corev1.VolumeMount{
+			Name:      GuardianCABundleConfigMapName,
+			MountPath: guardianCABundleMountPath,
+			ReadOnly:  true,
+		})
+	}
+	return mounts
 }
 
 func (c *GuardianComponent) container() []v1.Container {
@@ -250,7 +360,7 @@ func (c *GuardianComponent) container() []v1.Container {
 			Name:            GuardianDeploymentName,
 			Image:           "gcr.io/tigera-dev/experimental/brianmcmahon/tigera/guardian:latest",
 			ImagePullPolicy: "Always",
-			Env: []corev1.EnvVar{
+			Env: append([]corev1.EnvVar{
 				{
 					Name:      "GUARDIAN_PORT",
 					ValueFrom: envVarSourceFromConfigmap(GuardianConfigMapName, "tigera-guardian.port"),
@@ -267,12 +377,8 @@ func (c *GuardianComponent) container() []v1.Container {
 					Name:      "GUARDIAN_VOLTRON_URL",
 					ValueFrom: envVarSourceFromConfigmap(GuardianConfigMapName, "tigera-guardian.voltron-url"),
 				},
-			},
-			VolumeMounts: []corev1.VolumeMount{{
-				Name:      GuardianVolumeName,
-				MountPath: "/certs/",
-				ReadOnly:  true,
-			}},
+			}, c.tunnelEnvVars()...),
+			VolumeMounts: c.volumeMounts(),
 			LivenessProbe: &corev1.Probe{
 				Handler: corev1.Handler{
 					HTTPGet: &corev1.HTTPGetAction{
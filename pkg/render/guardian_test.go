@@ -1,22 +1,30 @@
 package render_test
 
 import (
+	"time"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	operatorv1 "github.com/tigera/operator/pkg/apis/operator/v1"
 	"github.com/tigera/operator/pkg/render"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 )
 
 var _ = Describe("Rendering tests", func() {
 	var g render.Component
 	var resources []runtime.Object
+	var cr *operatorv1.ManagementClusterConnection
+	var secret *corev1.Secret
 
 	BeforeEach(func() {
-		addr := "127.0.0.1:1234"
-		secret := &corev1.Secret{
+		cr = &operatorv1.ManagementClusterConnection{
+			Spec: operatorv1.ManagementClusterConnectionSpec{ManagementClusterAddr: "127.0.0.1:1234"},
+		}
+		secret = &corev1.Secret{
 			TypeMeta: metav1.TypeMeta{Kind: "Secret", APIVersion: "v1"},
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      render.GuardianSecretName,
@@ -28,11 +36,12 @@ var _ = Describe("Rendering tests", func() {
 			},
 		}
 		g = render.Guardian(
-			addr,
+			cr,
 			[]*corev1.Secret{},
 			false,
 			"my-reg",
 			secret,
+			nil,
 		)
 		resources = g.Objects()
 	})
@@ -61,4 +70,39 @@ var _ = Describe("Rendering tests", func() {
 		}
 	})
 
+	It("should configure the proxy, CA bundle and tunnel settings", func() {
+		keepAlive := false
+		var attempts int32 = 5
+		cr.Spec.ProxyURL = "http://proxy.example.com:3128"
+		cr.Spec.Tunnel = &operatorv1.TunnelConfig{
+			KeepAlive:         &keepAlive,
+			KeepAliveInterval: &metav1.Duration{Duration: 2 * time.Second},
+			DialTimeout:       &metav1.Duration{Duration: 30 * time.Second},
+			ReconnectInterval: &metav1.Duration{Duration: 10 * time.Second},
+			ReconnectAttempts: &attempts,
+		}
+		caBundle := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: render.GuardianCABundleConfigMapName, Namespace: render.OperatorNamespace()},
+			Data:       map[string]string{render.GuardianCABundleKey: "ca"},
+		}
+		resources = render.Guardian(cr, nil, false, "my-reg", secret, caBundle).Objects()
+
+		Expect(GetResource(resources, render.GuardianCABundleConfigMapName, render.GuardianNamespace, "", "v1", "ConfigMap")).NotTo(BeNil())
+		d := GetResource(resources, render.GuardianDeploymentName, render.GuardianNamespace, "apps", "v1", "Deployment").(*appsv1.Deployment)
+		Expect(d.Spec.Template.Annotations).To(HaveKey("hash.operator.tigera.io/guardian-ca-bundle"))
+		Expect(d.Spec.Template.Spec.Volumes).To(HaveLen(2))
+
+		guardian := d.Spec.Template.Spec.Containers[0]
+		ExpectEnv(guardian.Env, "HTTPS_PROXY", "http://proxy.example.com:3128")
+		ExpectEnv(guardian.Env, "NO_PROXY", "kubernetes.default,.svc,.svc.cluster.local")
+		ExpectEnv(guardian.Env, "GUARDIAN_VOLTRON_CA_PATH", "/certs/ca-bundle/ca.crt")
+		ExpectEnv(guardian.Env, "GUARDIAN_KEEP_ALIVE_ENABLE", "false")
+		ExpectEnv(guardian.Env, "GUARDIAN_KEEP_ALIVE_INTERVAL", "2000")
+		ExpectEnv(guardian.Env, "GUARDIAN_TUNNEL_DIAL_TIMEOUT", "30s")
+		ExpectEnv(guardian.Env, "GUARDIAN_TUNNEL_DIAL_RETRY_INTERVAL", "10s")
+		ExpectEnv(guardian.Env, "GUARDIAN_TUNNEL_DIAL_RETRY_ATTEMPTS", "5")
+		Expect(guardian.VolumeMounts).To(ContainElement(corev1.VolumeMount{
+			Name: render.GuardianCABundleConfigMapName, MountPath: "/certs/ca-bundle", ReadOnly: true,
+		}))
+	})
 })
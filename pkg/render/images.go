@@ -12,6 +12,7 @@ const (
 	TigeraRegistry = "gcr.io/unique-caldron-775/cnx/"
 	K8sGcrRegistry = "gcr.io/"
 	ECKRegistry    = "docker.elastic.co/"
+	DexRegistry    = "quay.io/"
 )
 
 // This section contains images used for utility operator functions.
@@ -59,11 +60,14 @@ const (
 	ManagerProxyImageName   = "tigera/voltron:" + components.VersionManagerProxy
 	ManagerEsProxyImageName = "tigera/es-proxy:" + components.VersionManagerEsProxy
 
+	// Identity broker image. This is the upstream Dex release.
+	DexImageName = "dexidp/dex:" + components.VersionDex
+
 	KibanaImageName = "tigera/kibana:" + components.VersionKibana
 
 	ECKOperatorImageName      = "eck/eck-operator:" + components.VersionECKOperator
+	ECKOperatorV1ImageName    = "eck/eck-operator:" + components.VersionECKOperatorV1
 	ECKElasticsearchImageName = "elasticsearch/elasticsearch:" + components.VersionECKElasticsearch
-	EsCuratorImageName        = "tigera/es-curator:" + components.VersionEsCurator
 
 	// Multicluster tunnel image.
 	GuardianImageName = "tigera/guardian:" + components.VersionGuardian
@@ -86,8 +90,10 @@ func constructImage(imageName string, registry string) string {
 		FlexVolumeImageName:
 
 		reg = CalicoRegistry
-	case ECKElasticsearchImageName, ECKOperatorImageName:
+	case ECKElasticsearchImageName, ECKOperatorImageName, ECKOperatorV1ImageName:
 		reg = ECKRegistry
+	case DexImageName:
+		reg = DexRegistry
 	}
 	return fmt.Sprintf("%s%s", reg, imageName)
 }
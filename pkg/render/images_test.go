@@ -29,6 +29,10 @@ var _ = Describe("No registry override", func() {
 	})
 	It("should render an ECK image correctly", func() {
 		Expect(constructImage(ECKOperatorImageName, "")).To(Equal("docker.elastic.co/eck/eck-operator:" + components.VersionECKOperator))
+		Expect(constructImage(ECKOperatorV1ImageName, "")).To(Equal("docker.elastic.co/eck/eck-operator:" + components.VersionECKOperatorV1))
+	})
+	It("should render the Dex image correctly", func() {
+		Expect(constructImage(DexImageName, "")).To(Equal("quay.io/dexidp/dex:" + components.VersionDex))
 	})
 })
 
@@ -15,6 +15,11 @@
 package render
 
 import (
+	"encoding/json"
+	"fmt"
+
+	v3 "github.com/tigera/api/pkg/apis/projectcalico/v3"
+	operatorv1 "github.com/tigera/operator/pkg/apis/operator/v1"
 	appsv1 "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -31,19 +36,42 @@ const (
 	ElasticsearchIntrusionDetectionJobUserSecret = "tigera-ee-installer-elasticsearch-access"
 
 	IntrusionDetectionInstallerJobName = "intrusion-detection-es-job-installer"
+
+	alertForwardingConfigMapName   = "intrusion-detection-alert-forwarding"
+	alertForwardingConfigKey       = "config.json"
+	alertForwardingConfigDir       = "/etc/alert-forwarding/"
+	alertForwardingHashAnnotation  = "hash.operator.tigera.io/alert-forwarding"
+	authSecretsHashAnnotation      = "hash.operator.tigera.io/auth-secrets"
+	anomalyDetectionHashAnnotation = "hash.operator.tigera.io/anomaly-detection"
+
+	defaultThreatFeedPullInterval = "24h"
+	defaultHTTPAuthHeaderName     = "Authorization"
+)
+
+// The GlobalThreatFeeds rendered for the IntrusionDetection CR and the copies of the Secrets they and the alert
+// webhooks authenticate with are labelled so the controller can remove them once they are no longer configured.
+const (
+	ThreatFeedLabel                   = "tigera.io/threat-feed"
+	IntrusionDetectionAuthSecretLabel = "tigera.io/intrusion-detection-auth"
 )
 
+// IntrusionDetection renders the intrusion detection components. The authSecrets are the Secrets referenced by the
+// threat feeds and alert webhooks in the IntrusionDetection CR, and are copied into the intrusion detection namespace.
 func IntrusionDetection(
+	ids *operatorv1.IntrusionDetection,
 	esSecrets []*corev1.Secret,
 	kibanaCertSecret *corev1.Secret,
+	authSecrets []*corev1.Secret,
 	registry string,
 	esClusterConfig *ElasticsearchClusterConfig,
 	pullSecrets []*corev1.Secret,
 	openshift bool,
 ) Component {
 	return &intrusionDetectionComponent{
+		ids:              ids,
 		esSecrets:        esSecrets,
 		kibanaCertSecret: kibanaCertSecret,
+		authSecrets:      authSecrets,
 		registry:         registry,
 		esClusterConfig:  esClusterConfig,
 		pullSecrets:      pullSecrets,
@@ -52,8 +80,10 @@ func IntrusionDetection(
 }
 
 type intrusionDetectionComponent struct {
+	ids              *operatorv1.IntrusionDetection
 	esSecrets        []*corev1.Secret
 	kibanaCertSecret *corev1.Secret
+	authSecrets      []*corev1.Secret
 	registry         string
 	esClusterConfig  *ElasticsearchClusterConfig
 	pullSecrets      []*corev1.Secret
@@ -64,9 +94,20 @@ func (c *intrusionDetectionComponent) Objects() []runtime.Object {
 	objs := []runtime.Object{createNamespace(IntrusionDetectionNamespace, c.openshift)}
 	objs = append(objs, copyImagePullSecrets(c.pullSecrets, IntrusionDetectionNamespace)...)
 	objs = append(objs, secretsToRuntimeObjects(copySecrets(IntrusionDetectionNamespace, c.esSecrets...)...)...)
-	objs = append(objs, secretsToRuntimeObjects(copySecrets(IntrusionDetectionNamespace, c.kibanaCertSecret)...)...)
+	if c.kibanaCertSecret != nil {
+		objs = append(objs, secretsToRuntimeObjects(copySecrets(IntrusionDetectionNamespace, c.kibanaCertSecret)...)...)
+	}
+	authSecrets := copySecrets(IntrusionDetectionNamespace, c.authSecrets...)
+	for _, s := range authSecrets {
+		s.Labels = map[string]string{IntrusionDetectionAuthSecretLabel: "true"}
+	}
+	objs = append(objs, secretsToRuntimeObjects(authSecrets...)...)
 
-	return append(objs,
+	if c.ids.Spec.AlertForwarding != nil {
+		objs = append(objs, c.alertForwardingConfigMap())
+	}
+
+	objs = append(objs,
 		c.intrusionDetectionServiceAccount(),
 		c.intrusionDetectionClusterRole(),
 		c.intrusionDetectionClusterRoleBinding(),
@@ -75,6 +116,113 @@ func (c *intrusionDetectionComponent) Objects() []runtime.Object {
 		c.intrusionDetectionDeployment(),
 		c.intrusionDetectionElasticsearchJob(),
 	)
+
+	for _, feed := range c.ids.Spec.ThreatFeeds {
+		objs = append(objs, globalThreatFeed(feed))
+	}
+
+	return objs
+}
+
+// globalThreatFeed returns the GlobalThreatFeed for a threat feed in the IntrusionDetection CR.
+func globalThreatFeed(feed operatorv1.ThreatFeed) *v3.GlobalThreatFeed {
+	period := defaultThreatFeedPullInterval
+	if feed.PullInterval != nil {
+		period = feed.PullInterval.Duration.String()
+	}
+
+	httpPull := &v3.HTTPPull{URL: feed.URL}
+	if feed.Auth != nil {
+		httpPull.Headers = []v3.HTTPHeader{httpHeaderFromSecret(feed.Auth)}
+	}
+
+	gtf := &v3.GlobalThreatFeed{
+		TypeMeta: metav1.TypeMeta{Kind: "GlobalThreatFeed", APIVersion: "projectcalico.org/v3"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   feed.Name,
+			Labels: map[string]string{ThreatFeedLabel: feed.Name},
+		},
+		Spec: v3.GlobalThreatFeedSpec{
+			Pull: &v3.Pull{
+				Period: period,
+				HTTP:   httpPull,
+			},
+		},
+	}
+	if len(feed.GlobalNetworkSetLabels) > 0 {
+		gtf.Spec.GlobalNetworkSet = &v3.GlobalNetworkSetSync{Labels: feed.GlobalNetworkSetLabels}
+	}
+	return gtf
+}
+
+// httpHeaderFromSecret returns a header that the intrusion detection controller reads from the copy of the Secret
+// in its own namespace.
+func httpHeaderFromSecret(auth *operatorv1.HTTPHeaderSecret) v3.HTTPHeader {
+	name := auth.HeaderName
+	if name == "" {
+		name = defaultHTTPAuthHeaderName
+	}
+	return v3.HTTPHeader{
+		Name: name,
+		ValueFrom: &v3.HTTPHeaderSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: auth.SecretName},
+				Key:                  auth.SecretKey,
+			},
+		},
+	}
+}
+
+// alertForwardingConfig is the configuration file read by the intrusion detection controller to forward alerts.
+type alertForwardingConfig struct {
+	Webhooks []alertForwardingWebhook `json:"webhooks,omitempty"`
+	Syslog   *alertForwardingSyslog   `json:"syslog,omitempty"`
+}
+
+type alertForwardingWebhook struct {
+	Name    string          `json:"name"`
+	URL     string          `json:"url"`
+	Headers []v3.HTTPHeader `json:"headers,omitempty"`
+}
+
+type alertForwardingSyslog struct {
+	Endpoint   string `json:"endpoint"`
+	PacketSize int32  `json:"packetSize"`
+}
+
+func (c *intrusionDetectionComponent) alertForwardingConfigData() string {
+	af := c.ids.Spec.AlertForwarding
+	cfg := alertForwardingConfig{}
+	for _, wh := range af.Webhooks {
+		w := alertForwardingWebhook{Name: wh.Name, URL: wh.URL}
+		if wh.Auth != nil {
+			w.Headers = []v3.HTTPHeader{httpHeaderFromSecret(wh.Auth)}
+		}
+		cfg.Webhooks = append(cfg.Webhooks, w)
+	}
+	if af.Syslog != nil {
+		cfg.Syslog = &alertForwardingSyslog{Endpoint: af.Syslog.Endpoint, PacketSize: 1024}
+		if af.Syslog.PacketSize != nil {
+			cfg.Syslog.PacketSize = *af.Syslog.PacketSize
+		}
+	}
+
+	// Marshalling a struct of strings and ints does not fail.
+	b, _ := json.Marshal(cfg)
+	return string(b)
+}
+
+func (c *intrusionDetectionComponent) alertForwardingConfigMap() *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      alertForwardingConfigMapName,
+			Namespace: IntrusionDetectionNamespace,
+		},
+		Data: map[string]string{
+			alertForwardingConfigKey: c.alertForwardingConfigData(),
+		},
+	}
 }
 
 func (c *intrusionDetectionComponent) Ready() bool {
@@ -82,6 +230,28 @@ func (c *intrusionDetectionComponent) Ready() bool {
 }
 
 func (c *intrusionDetectionComponent) intrusionDetectionElasticsearchJob() *batchv1.Job {
+	// The job is only recreated when its pod template annotations change, so include the anomaly detection settings.
+	var annots map[string]string
+	if c.ids.Spec.AnomalyDetection != nil {
+		annots = map[string]string{anomalyDetectionHashAnnotation: AnnotationHash(c.ids.Spec.AnomalyDetection)}
+	}
+
+	// The dashboards are installed in Kibana, if it is deployed.
+	var volumes []corev1.Volume
+	if c.kibanaCertSecret != nil {
+		volumes = append(volumes, corev1.Volume{
+			Name: "kibana-ca-cert-volume",
+			VolumeSource: v1.VolumeSource{
+				Secret: &v1.SecretVolumeSource{
+					SecretName: KibanaPublicCertSecret,
+					Items: []v1.KeyToPath{
+						{Key: "tls.crt", Path: "ca.pem"},
+					},
+				},
+			},
+		})
+	}
+
 	return ElasticsearchDecorateAnnotations(&batchv1.Job{
 		TypeMeta: metav1.TypeMeta{Kind: "Job", APIVersion: "batch/v1"},
 		ObjectMeta: metav1.ObjectMeta{
@@ -96,25 +266,16 @@ func (c *intrusionDetectionComponent) intrusionDetectionElasticsearchJob() *batc
 			},
 			Template: v1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
-					Labels: map[string]string{"job-name": IntrusionDetectionInstallerJobName},
+					Labels:      map[string]string{"job-name": IntrusionDetectionInstallerJobName},
+					Annotations: annots,
 				},
 				Spec: ElasticsearchPodSpecDecorate(v1.PodSpec{
 					RestartPolicy:    v1.RestartPolicyOnFailure,
 					ImagePullSecrets: getImagePullSecretReferenceList(c.pullSecrets),
 					Containers: []v1.Container{
-						ElasticsearchContainerDecorate(c.intrusionDetectionJobContainer(), c.esClusterConfig.ClusterName(), ElasticsearchIntrusionDetectionJobUserSecret),
+						ElasticsearchContainerDecorate(c.intrusionDetectionJobContainer(), c.esClusterConfig, ElasticsearchIntrusionDetectionJobUserSecret),
 					},
-					Volumes: []corev1.Volume{{
-						Name: "kibana-ca-cert-volume",
-						VolumeSource: v1.VolumeSource{
-							Secret: &v1.SecretVolumeSource{
-								SecretName: KibanaPublicCertSecret,
-								Items: []v1.KeyToPath{
-									{Key: "tls.crt", Path: "ca.pem"},
-								},
-							},
-						},
-					}},
+					Volumes: volumes,
 				}),
 			},
 		},
@@ -122,24 +283,11 @@ func (c *intrusionDetectionComponent) intrusionDetectionElasticsearchJob() *batc
 }
 
 func (c *intrusionDetectionComponent) intrusionDetectionJobContainer() v1.Container {
-	kScheme, kHost, kPort, _ := ParseEndpoint(KibanaHTTPSEndpoint)
 	secretName := ElasticsearchIntrusionDetectionJobUserSecret
-	return corev1.Container{
+	container := corev1.Container{
 		Name:  "elasticsearch-job-installer",
 		Image: constructImage(IntrusionDetectionJobInstallerImageName, c.registry),
 		Env: []corev1.EnvVar{
-			{
-				Name:  "KIBANA_HOST",
-				Value: kHost,
-			},
-			{
-				Name:  "KIBANA_PORT",
-				Value: kPort,
-			},
-			{
-				Name:  "KIBANA_SCHEME",
-				Value: kScheme,
-			},
 			{
 				Name:  "START_XPACK_TRIAL",
 				Value: "true",
@@ -152,20 +300,30 @@ func (c *intrusionDetectionComponent) intrusionDetectionJobContainer() v1.Contai
 				Name:      "PASSWORD",
 				ValueFrom: envVarSourceFromSecret(secretName, "password", false),
 			},
-			{
-				Name:  "KB_CA_CERT",
-				Value: KibanaDefaultCertPath,
-			},
 			{
 				Name:  "CLUSTER_NAME",
 				Value: c.esClusterConfig.ClusterName(),
 			},
+			{
+				Name:  "INSTALL_DEFAULT_ML_JOBS",
+				Value: fmt.Sprint(c.ids.Spec.AnomalyDetection == nil || !c.ids.Spec.AnomalyDetection.DisableDefaultJobs),
+			},
 		},
-		VolumeMounts: []corev1.VolumeMount{{
+	}
+	if c.kibanaCertSecret != nil {
+		kScheme, kHost, kPort, _ := ParseEndpoint(KibanaHTTPSEndpoint)
+		container.Env = append([]corev1.EnvVar{
+			{Name: "KIBANA_HOST", Value: kHost},
+			{Name: "KIBANA_PORT", Value: kPort},
+			{Name: "KIBANA_SCHEME", Value: kScheme},
+			{Name: "KB_CA_CERT", Value: KibanaDefaultCertPath},
+		}, container.Env...)
+		container.VolumeMounts = []corev1.VolumeMount{{
 			Name:      "kibana-ca-cert-volume",
 			MountPath: "/etc/ssl/kibana/",
-		}},
+		}}
 	}
+	return container
 }
 
 func (c *intrusionDetectionComponent) intrusionDetectionServiceAccount() *v1.ServiceAccount {
@@ -277,6 +435,23 @@ func (c *intrusionDetectionComponent) intrusionDetectionDeployment() *appsv1.Dep
 		ps = append(ps, corev1.LocalObjectReference{Name: x.Name})
 	}
 
+	annots := map[string]string{}
+	var volumes []corev1.Volume
+	if len(c.authSecrets) > 0 {
+		annots[authSecretsHashAnnotation] = secretsAnnotationHash(c.authSecrets...)
+	}
+	if c.ids.Spec.AlertForwarding != nil {
+		annots[alertForwardingHashAnnotation] = AnnotationHash(c.alertForwardingConfigData())
+		volumes = append(volumes, corev1.Volume{
+			Name: "alert-forwarding",
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{Name: alertForwardingConfigMapName},
+				},
+			},
+		})
+	}
+
 	return ElasticsearchDecorateAnnotations(&appsv1.Deployment{
 		TypeMeta: metav1.TypeMeta{Kind: "Deployment", APIVersion: "v1"},
 		ObjectMeta: metav1.ObjectMeta{
@@ -298,13 +473,15 @@ func (c *intrusionDetectionComponent) intrusionDetectionDeployment() *appsv1.Dep
 					Labels: map[string]string{
 						"k8s-app": "intrusion-detection-controller",
 					},
+					Annotations: annots,
 				},
 				Spec: ElasticsearchPodSpecDecorate(corev1.PodSpec{
 					ServiceAccountName: "intrusion-detection-controller",
 					ImagePullSecrets:   ps,
+					Volumes:            volumes,
 					Containers: []corev1.Container{
 						ElasticsearchContainerDecorateIndexCreator(
-							ElasticsearchContainerDecorate(c.intrusionDetectionControllerContainer(), c.esClusterConfig.ClusterName(), ElasticsearchIntrusionDetectionUserSecret),
+							ElasticsearchContainerDecorate(c.intrusionDetectionControllerContainer(), c.esClusterConfig, ElasticsearchIntrusionDetectionUserSecret),
 							c.esClusterConfig.Replicas(), c.esClusterConfig.Shards()),
 					},
 				}),
@@ -314,15 +491,23 @@ func (c *intrusionDetectionComponent) intrusionDetectionDeployment() *appsv1.Dep
 }
 
 func (c *intrusionDetectionComponent) intrusionDetectionControllerContainer() v1.Container {
-	return corev1.Container{
-		Name:  "controller",
-		Image: constructImage(IntrusionDetectionControllerImageName, c.registry),
-		Env: []corev1.EnvVar{
-			{
-				Name:  "CLUSTER_NAME",
-				Value: c.esClusterConfig.ClusterName(),
-			},
+	env := []corev1.EnvVar{
+		{
+			Name:  "CLUSTER_NAME",
+			Value: c.esClusterConfig.ClusterName(),
 		},
+	}
+	var volumeMounts []corev1.VolumeMount
+	if c.ids.Spec.AlertForwarding != nil {
+		env = append(env, corev1.EnvVar{Name: "ALERT_FORWARDING_CONFIG", Value: alertForwardingConfigDir + alertForwardingConfigKey})
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{Name: "alert-forwarding", MountPath: alertForwardingConfigDir, ReadOnly: true})
+	}
+
+	return corev1.Container{
+		Name:         "controller",
+		Image:        constructImage(IntrusionDetectionControllerImageName, c.registry),
+		Env:          env,
+		VolumeMounts: volumeMounts,
 		// Needed for permissions to write to the audit log
 		LivenessProbe: &corev1.Probe{
 			Handler: corev1.Handler{
@@ -15,9 +15,15 @@
 package render_test
 
 import (
+	"time"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	v3 "github.com/tigera/api/pkg/apis/projectcalico/v3"
+	operatorv1 "github.com/tigera/operator/pkg/apis/operator/v1"
 	"github.com/tigera/operator/pkg/render"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -26,7 +32,7 @@ var _ = Describe("Intrusion Detection rendering tests", func() {
 	It("should render all resources for a default configuration", func() {
 		esConfigMap := render.NewElasticsearchClusterConfig("clusterTestName", 1, 1)
 
-		component := render.IntrusionDetection(nil, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: render.TigeraKibanaCertSecret}}, "testregistry.com/", esConfigMap, nil, notOpenshift)
+		component := render.IntrusionDetection(&operatorv1.IntrusionDetection{}, nil, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: render.TigeraKibanaCertSecret}}, nil, "testregistry.com/", esConfigMap, nil, notOpenshift)
 		resources := component.Objects()
 		Expect(len(resources)).To(Equal(9))
 
@@ -55,4 +61,109 @@ var _ = Describe("Intrusion Detection rendering tests", func() {
 			i++
 		}
 	})
+
+	It("should install the Elasticsearch jobs without Kibana when it is disabled", func() {
+		esConfigMap := render.NewElasticsearchClusterConfig("clusterTestName", 1, 1)
+		esConfigMap.DisableKibana()
+
+		component := render.IntrusionDetection(&operatorv1.IntrusionDetection{}, nil, nil, nil, "testregistry.com/", esConfigMap, nil, notOpenshift)
+		resources := component.Objects()
+		Expect(resources).To(HaveLen(8))
+		Expect(GetResource(resources, render.TigeraKibanaCertSecret, "tigera-intrusion-detection", "", "", "")).To(BeNil())
+
+		job := GetResource(resources, "intrusion-detection-es-job-installer", "tigera-intrusion-detection", "batch", "v1", "Job").(*batchv1.Job)
+		for _, volume := range job.Spec.Template.Spec.Volumes {
+			Expect(volume.Name).NotTo(Equal("kibana-ca-cert-volume"))
+		}
+		installer := job.Spec.Template.Spec.Containers[0]
+		for _, mount := range installer.VolumeMounts {
+			Expect(mount.Name).NotTo(Equal("kibana-ca-cert-volume"))
+		}
+		for _, env := range installer.Env {
+			Expect(env.Name).NotTo(HavePrefix("KIBANA_"))
+		}
+		ExpectEnv(installer.Env, "CLUSTER_NAME", "clusterTestName")
+	})
+
+	It("should render threat feeds", func() {
+		esConfigMap := render.NewElasticsearchClusterConfig("clusterTestName", 1, 1)
+		ids := &operatorv1.IntrusionDetection{
+			Spec: operatorv1.IntrusionDetectionSpec{
+				ThreatFeeds: []operatorv1.ThreatFeed{
+					{
+						Name:                   "feodo",
+						URL:                    "https://feodotracker.example.com/blocklist.txt",
+						PullInterval:           &metav1.Duration{Duration: time.Hour},
+						Auth:                   &operatorv1.HTTPHeaderSecret{SecretName: "feed-token", SecretKey: "token"},
+						GlobalNetworkSetLabels: map[string]string{"feed": "feodo"},
+					},
+				},
+			},
+		}
+		authSecret := &corev1.Secret{
+			TypeMeta:   metav1.TypeMeta{Kind: "Secret", APIVersion: "v1"},
+			ObjectMeta: metav1.ObjectMeta{Name: "feed-token", Namespace: render.OperatorNamespace()},
+			Data:       map[string][]byte{"token": []byte("abc")},
+		}
+
+		component := render.IntrusionDetection(ids, nil, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: render.TigeraKibanaCertSecret}}, []*corev1.Secret{authSecret}, "testregistry.com/", esConfigMap, nil, notOpenshift)
+		resources := component.Objects()
+
+		secret := GetResource(resources, "feed-token", "tigera-intrusion-detection", "", "v1", "Secret").(*corev1.Secret)
+		Expect(secret.Labels).To(HaveKey(render.IntrusionDetectionAuthSecretLabel))
+
+		gtf := GetResource(resources, "feodo", "", "projectcalico.org", "v3", "GlobalThreatFeed").(*v3.GlobalThreatFeed)
+		Expect(gtf.Spec.Pull.Period).To(Equal("1h0m0s"))
+		Expect(gtf.Spec.Pull.HTTP.URL).To(Equal("https://feodotracker.example.com/blocklist.txt"))
+		Expect(gtf.Spec.Pull.HTTP.Headers).To(HaveLen(1))
+		Expect(gtf.Spec.Pull.HTTP.Headers[0].Name).To(Equal("Authorization"))
+		Expect(gtf.Spec.Pull.HTTP.Headers[0].ValueFrom.SecretKeyRef.Name).To(Equal("feed-token"))
+		Expect(gtf.Spec.Pull.HTTP.Headers[0].ValueFrom.SecretKeyRef.Key).To(Equal("token"))
+		Expect(gtf.Spec.GlobalNetworkSet.Labels).To(Equal(map[string]string{"feed": "feodo"}))
+		Expect(gtf.Labels).To(HaveKeyWithValue(render.ThreatFeedLabel, "feodo"))
+
+		d := GetResource(resources, "intrusion-detection-controller", "tigera-intrusion-detection", "", "v1", "Deployment").(*appsv1.Deployment)
+		Expect(d.Spec.Template.Annotations).To(HaveKey("hash.operator.tigera.io/auth-secrets"))
+	})
+
+	It("should render alert forwarding and disabled default anomaly detection jobs", func() {
+		esConfigMap := render.NewElasticsearchClusterConfig("clusterTestName", 1, 1)
+		packetSize := int32(2048)
+		ids := &operatorv1.IntrusionDetection{
+			Spec: operatorv1.IntrusionDetectionSpec{
+				AlertForwarding: &operatorv1.AlertForwarding{
+					Webhooks: []operatorv1.AlertWebhook{{Name: "soc", URL: "https://soc.example.com/alerts"}},
+					Syslog:   &operatorv1.SyslogStoreSpec{Endpoint: "tcp://1.2.3.4:601", PacketSize: &packetSize},
+				},
+				AnomalyDetection: &operatorv1.AnomalyDetectionSpec{DisableDefaultJobs: true},
+			},
+		}
+
+		component := render.IntrusionDetection(ids, nil, nil, nil, "testregistry.com/", esConfigMap, nil, notOpenshift)
+		resources := component.Objects()
+
+		cm := GetResource(resources, "intrusion-detection-alert-forwarding", "tigera-intrusion-detection", "", "v1", "ConfigMap").(*corev1.ConfigMap)
+		Expect(cm.Data["config.json"]).To(MatchJSON(`{"webhooks":[{"name":"soc","url":"https://soc.example.com/alerts"}],"syslog":{"endpoint":"tcp://1.2.3.4:601","packetSize":2048}}`))
+
+		d := GetResource(resources, "intrusion-detection-controller", "tigera-intrusion-detection", "", "v1", "Deployment").(*appsv1.Deployment)
+		ExpectEnv(d.Spec.Template.Spec.Containers[0].Env, "ALERT_FORWARDING_CONFIG", "/etc/alert-forwarding/config.json")
+		Expect(d.Spec.Template.Spec.Containers[0].VolumeMounts).To(ContainElement(corev1.VolumeMount{Name: "alert-forwarding", MountPath: "/etc/alert-forwarding/", ReadOnly: true}))
+		Expect(d.Spec.Template.Spec.Volumes).To(HaveLen(1))
+		Expect(d.Spec.Template.Spec.Volumes[0].ConfigMap.Name).To(Equal("intrusion-detection-alert-forwarding"))
+		Expect(d.Spec.Template.Annotations).To(HaveKey("hash.operator.tigera.io/alert-forwarding"))
+
+		job := GetResource(resources, "intrusion-detection-es-job-installer", "tigera-intrusion-detection", "batch", "v1", "Job").(*batchv1.Job)
+		ExpectEnv(job.Spec.Template.Spec.Containers[0].Env, "INSTALL_DEFAULT_ML_JOBS", "false")
+		Expect(job.Spec.Template.Annotations).To(HaveKey("hash.operator.tigera.io/anomaly-detection"))
+	})
+
+	It("should install the default anomaly detection jobs unless they are disabled", func() {
+		esConfigMap := render.NewElasticsearchClusterConfig("clusterTestName", 1, 1)
+		component := render.IntrusionDetection(&operatorv1.IntrusionDetection{}, nil, nil, nil, "testregistry.com/", esConfigMap, nil, notOpenshift)
+		resources := component.Objects()
+
+		job := GetResource(resources, "intrusion-detection-es-job-installer", "tigera-intrusion-detection", "batch", "v1", "Job").(*batchv1.Job)
+		ExpectEnv(job.Spec.Template.Spec.Containers[0].Env, "INSTALL_DEFAULT_ML_JOBS", "true")
+		Expect(GetResource(resources, "intrusion-detection-alert-forwarding", "tigera-intrusion-detection", "", "v1", "ConfigMap")).To(BeNil())
+	})
 })
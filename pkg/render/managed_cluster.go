@@ -0,0 +1,387 @@
+// Copyright (c) 2020 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// This renderer is responsible for the resources registering a managed cluster with a management cluster.
+package render
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/openshift/library-go/pkg/crypto"
+	v3 "github.com/tigera/api/pkg/apis/projectcalico/v3"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+
+	operator "github.com/tigera/operator/pkg/apis/operator/v1"
+)
+
+const (
+	// ManagedClusterManifestKey is the key of the manifest in the managed cluster Secret.
+	ManagedClusterManifestKey = "manifest.yaml"
+
+	// The keys of the tunnel certificates in the Guardian Secret.
+	ManagedClusterCertKey    = "managed-cluster.crt"
+	ManagedClusterKeyKey     = "managed-cluster.key"
+	ManagementClusterCertKey = "management-cluster.crt"
+
+	// The keys of a renewed tunnel certificate in the managed cluster Secret. The renewed certificate is written
+	// into the manifest and becomes active once the managed cluster reconnects with it.
+	ManagedClusterPendingCertKey = "pending-managed-cluster.crt"
+	ManagedClusterPendingKeyKey  = "pending-managed-cluster.key"
+
+	// ManagedClusterFingerprintAnnotation holds the fingerprint of the certificate that Voltron accepts from
+	// a managed cluster.
+	ManagedClusterFingerprintAnnotation = "certs.tigera.io/active-fingerprint"
+
+	// ManagedClusterAcknowledgedFingerprintAnnotation is set on a ManagedCluster to the fingerprint of its renewed
+	// tunnel certificate once the managed cluster has applied the regenerated manifest, upon which Voltron
+	// switches over to the renewed certificate. The managed cluster reports the fingerprint of the certificate it
+	// uses in the status of its ManagementClusterConnection.
+	ManagedClusterAcknowledgedFingerprintAnnotation = "certs.tigera.io/acknowledged-fingerprint"
+
+	// TunnelCertificateRenewBefore is how long before expiry the tunnel certificates are renewed. This leaves
+	// managed clusters time to apply their regenerated manifest before the current certificate expires.
+	TunnelCertificateRenewBefore = 30 * 24 * time.Hour
+
+	// Keys of the Voltron tunnel Secret. While the CA is being renewed, the new CA is kept under the next keys and
+	// the cert holds a bundle of the current and the new CA.
+	voltronTunnelCertKey     = "cert"
+	voltronTunnelKeyKey      = "key"
+	voltronTunnelNextCertKey = "next-cert"
+	voltronTunnelNextKeyKey  = "next-key"
+)
+
+// ManagedClusterSecretName returns the name of the Secret in the operator namespace holding the tunnel
+// certificate and manifest of a managed cluster.
+func ManagedClusterSecretName(name string) string {
+	return fmt.Sprintf("tigera-managed-cluster-%s", name)
+}
+
+// ManagedClusterIndexPrefix returns the name the logs of the managed cluster are indexed under.
+func ManagedClusterIndexPrefix(mc *operator.ManagedCluster) string {
+	if mc.Spec.LogStorage != nil && mc.Spec.LogStorage.IndexPrefix != "" {
+		return mc.Spec.LogStorage.IndexPrefix
+	}
+	return mc.Name
+}
+
+// CreateManagedClusterCertificate issues a tunnel certificate for the named managed cluster, signed by the
+// Voltron CA in the given Voltron tunnel secret, or by the new CA while the CA is being renewed. The PEM encoded
+// key and certificate are returned.
+func CreateManagedClusterCertificate(name string, voltronSecret *corev1.Secret) ([]byte, []byte, error) {
+	caCert, caKey, err := parseVoltronSigningCA(voltronSecret)
+	if err != nil {
+		return nil, nil, err
+	}
+	privateKey, err := rsa.GenerateKey(rand.Reader, VoltronKeySizeBits)
+	if err != nil {
+		return nil, nil, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().AddDate(0, 0, crypto.DefaultCertificateLifetimeInDays),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	cert, err := x509.CreateCertificate(rand.Reader, template, caCert, &privateKey.PublicKey, caKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var keyPem, certPem bytes.Buffer
+	if err := pem.Encode(&keyPem, &pem.Block{Type: blockTypePrivateKey, Bytes: x509.MarshalPKCS1PrivateKey(privateKey)}); err != nil {
+		return nil, nil, err
+	}
+	if err := pem.Encode(&certPem, &pem.Block{Type: blockTypeCert, Bytes: cert}); err != nil {
+		return nil, nil, err
+	}
+	return keyPem.Bytes(), certPem.Bytes(), nil
+}
+
+// ManagedClusterCertificateFingerprint returns the fingerprint Voltron uses to identify the PEM encoded
+// certificate of a managed cluster.
+func ManagedClusterCertificateFingerprint(certPEM []byte) (string, error) {
+	cert, err := parseCertificate(certPEM)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", md5.Sum(cert.Raw)), nil
+}
+
+// CertificateExpiry returns the time at which the PEM encoded certificate expires.
+func CertificateExpiry(certPEM []byte) (time.Time, error) {
+	cert, err := parseCertificate(certPEM)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return cert.NotAfter, nil
+}
+
+// VoltronTunnelCAExpiry returns the time at which the Voltron CA in the given Voltron tunnel secret expires.
+func VoltronTunnelCAExpiry(voltronSecret *corev1.Secret) (time.Time, error) {
+	return CertificateExpiry(voltronSecret.Data[voltronTunnelCertKey])
+}
+
+// IsManagedClusterCertificateValid returns true if the PEM encoded certificate was signed by a Voltron CA in the
+// given Voltron tunnel secret and has not expired. While the CA is being renewed, certificates signed by either
+// the current or the new CA are valid.
+func IsManagedClusterCertificateValid(certPEM []byte, voltronSecret *corev1.Secret) bool {
+	cert, err := parseCertificate(certPEM)
+	if err != nil || !time.Now().Before(cert.NotAfter) {
+		return false
+	}
+	if caCert, _, err := parseVoltronCA(voltronSecret); err == nil && cert.CheckSignatureFrom(caCert) == nil {
+		return true
+	}
+	return IsManagedClusterCertificateCurrent(certPEM, voltronSecret)
+}
+
+// IsManagedClusterCertificateCurrent returns true if the PEM encoded certificate was signed by the CA that
+// CreateManagedClusterCertificate issues certificates with. Certificates signed by a CA that is being replaced
+// must be reissued before that CA is retired.
+func IsManagedClusterCertificateCurrent(certPEM []byte, voltronSecret *corev1.Secret) bool {
+	cert, err := parseCertificate(certPEM)
+	if err != nil {
+		return false
+	}
+	caCert, _, err := parseVoltronSigningCA(voltronSecret)
+	if err != nil {
+		return false
+	}
+	return cert.CheckSignatureFrom(caCert) == nil
+}
+
+// IsVoltronTunnelCARenewing returns true while a new Voltron CA is staged in the given Voltron tunnel secret.
+func IsVoltronTunnelCARenewing(voltronSecret *corev1.Secret) bool {
+	return len(voltronSecret.Data[voltronTunnelNextCertKey]) > 0
+}
+
+// RenewVoltronTunnelCA stages a new Voltron CA in the given Voltron tunnel secret. Voltron keeps serving the
+// current CA while it trusts a bundle of the current and the new CA, and the managed clusters are reissued
+// certificates signed by the new CA. Once they all use their reissued certificate, RetireVoltronTunnelCA completes
+// the renewal. Managed clusters trust the bundle through their manifest, so they accept Voltron before and after.
+func RenewVoltronTunnelCA(voltronSecret *corev1.Secret) error {
+	caCert, _, err := parseVoltronCA(voltronSecret)
+	if err != nil {
+		return err
+	}
+	key, cert := ceateSelfSignedVoltronSecret()
+
+	var bundle bytes.Buffer
+	if err := pem.Encode(&bundle, &pem.Block{Type: blockTypeCert, Bytes: caCert.Raw}); err != nil {
+		return err
+	}
+	bundle.WriteString(cert)
+
+	voltronSecret.Data[voltronTunnelCertKey] = bundle.Bytes()
+	voltronSecret.Data[voltronTunnelNextCertKey] = []byte(cert)
+	voltronSecret.Data[voltronTunnelNextKeyKey] = []byte(key)
+	return nil
+}
+
+// RetireVoltronTunnelCA replaces the Voltron CA in the given Voltron tunnel secret with the CA staged by
+// RenewVoltronTunnelCA.
+func RetireVoltronTunnelCA(voltronSecret *corev1.Secret) {
+	voltronSecret.Data[voltronTunnelCertKey] = voltronSecret.Data[voltronTunnelNextCertKey]
+	voltronSecret.Data[voltronTunnelKeyKey] = voltronSecret.Data[voltronTunnelNextKeyKey]
+	delete(voltronSecret.Data, voltronTunnelNextCertKey)
+	delete(voltronSecret.Data, voltronTunnelNextKeyKey)
+}
+
+func parseCertificate(certPEM []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(certPEM)
+	if block == nil || block.Type != blockTypeCert {
+		return nil, fmt.Errorf("no certificate found in PEM data")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// parseVoltronCA returns the CA Voltron currently serves. While the CA is being renewed, the certificate is the
+// first of the bundle.
+func parseVoltronCA(voltronSecret *corev1.Secret) (*x509.Certificate, *rsa.PrivateKey, error) {
+	return parseCA(voltronSecret, voltronTunnelCertKey, voltronTunnelKeyKey)
+}
+
+// parseVoltronSigningCA returns the CA that managed cluster certificates are issued with.
+func parseVoltronSigningCA(voltronSecret *corev1.Secret) (*x509.Certificate, *rsa.PrivateKey, error) {
+	if IsVoltronTunnelCARenewing(voltronSecret) {
+		return parseCA(voltronSecret, voltronTunnelNextCertKey, voltronTunnelNextKeyKey)
+	}
+	return parseVoltronCA(voltronSecret)
+}
+
+func parseCA(voltronSecret *corev1.Secret, certKey, keyKey string) (*x509.Certificate, *rsa.PrivateKey, error) {
+	caCert, err := parseCertificate(voltronSecret.Data[certKey])
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid certificate in secret %s: %v", voltronSecret.Name, err)
+	}
+	block, _ := pem.Decode(voltronSecret.Data[keyKey])
+	if block == nil || block.Type != blockTypePrivateKey {
+		return nil, nil, fmt.Errorf("no private key found in secret %s", voltronSecret.Name)
+	}
+	caKey, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid private key in secret %s: %v", voltronSecret.Name, err)
+	}
+	return caCert, caKey, nil
+}
+
+// ManagedCluster renders the registration of a managed cluster: a Secret holding its tunnel certificate and
+// the manifest to apply on the managed cluster, and the Calico ManagedCluster that Voltron accepts tunnels for.
+// The key and cert are the tunnel certificate Voltron accepts, see CreateManagedClusterCertificate. The optional
+// pendingKey and pendingCert are a renewed certificate that is handed out in the manifest instead, ahead of
+// becoming the accepted certificate. When the Elasticsearch configuration of the management cluster is given, the
+// manifest also configures the managed cluster to index its logs under its index prefix.
+func ManagedCluster(cr *operator.ManagedCluster, key, cert, pendingKey, pendingCert []byte, voltronSecret *corev1.Secret, esClusterConfig *ElasticsearchClusterConfig) (Component, error) {
+	fingerprint, err := ManagedClusterCertificateFingerprint(cert)
+	if err != nil {
+		return nil, err
+	}
+	c := &managedClusterComponent{
+		cr:            cr,
+		key:           key,
+		cert:          cert,
+		pendingKey:    pendingKey,
+		pendingCert:   pendingCert,
+		fingerprint:   fingerprint,
+		voltronSecret: voltronSecret,
+		esConfig:      esClusterConfig,
+	}
+	if c.manifest, err = c.installationManifest(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+type managedClusterComponent struct {
+	cr            *operator.ManagedCluster
+	key           []byte
+	cert          []byte
+	pendingKey    []byte
+	pendingCert   []byte
+	fingerprint   string
+	voltronSecret *corev1.Secret
+	esConfig      *ElasticsearchClusterConfig
+	manifest      []byte
+}
+
+func (c *managedClusterComponent) Objects() []runtime.Object {
+	return []runtime.Object{
+		c.secret(),
+		c.calicoManagedCluster(),
+	}
+}
+
+func (c *managedClusterComponent) Ready() bool {
+	return true
+}
+
+func (c *managedClusterComponent) secret() *corev1.Secret {
+	s := &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{Kind: "Secret", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ManagedClusterSecretName(c.cr.Name),
+			Namespace: OperatorNamespace(),
+		},
+		Data: map[string][]byte{
+			ManagedClusterCertKey:     c.cert,
+			ManagedClusterKeyKey:      c.key,
+			ManagedClusterManifestKey: c.manifest,
+		},
+	}
+	if c.pendingCert != nil {
+		s.Data[ManagedClusterPendingCertKey] = c.pendingCert
+		s.Data[ManagedClusterPendingKeyKey] = c.pendingKey
+	}
+	return s
+}
+
+func (c *managedClusterComponent) calicoManagedCluster() *v3.ManagedCluster {
+	return &v3.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: c.cr.Name,
+			Annotations: map[string]string{
+				ManagedClusterFingerprintAnnotation: c.fingerprint,
+			},
+		},
+	}
+}
+
+// installationManifest returns the manifest that connects the managed cluster to this management cluster: the
+// ManagementClusterConnection, the Secret Guardian uses to open the tunnel and the Elasticsearch configuration. A pending certificate takes
+// precedence so that applying the manifest rotates the certificate of the managed cluster.
+func (c *managedClusterComponent) installationManifest() ([]byte, error) {
+	key, cert := c.key, c.cert
+	if c.pendingCert != nil {
+		key, cert = c.pendingKey, c.pendingCert
+	}
+	objs := []runtime.Object{
+		&operator.ManagementClusterConnection{
+			TypeMeta:   metav1.TypeMeta{Kind: "ManagementClusterConnection", APIVersion: "operator.tigera.io/v1"},
+			ObjectMeta: metav1.ObjectMeta{Name: "tigera-secure"},
+			Spec: operator.ManagementClusterConnectionSpec{
+				ManagementClusterAddr: c.cr.Spec.ManagementClusterAddr,
+			},
+		},
+		&corev1.Secret{
+			TypeMeta: metav1.TypeMeta{Kind: "Secret", APIVersion: "v1"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      GuardianSecretName,
+				Namespace: OperatorNamespace(),
+			},
+			Data: map[string][]byte{
+				ManagedClusterCertKey:    cert,
+				ManagedClusterKeyKey:     key,
+				ManagementClusterCertKey: c.voltronSecret.Data[voltronTunnelCertKey],
+			},
+		},
+	}
+
+	if c.esConfig != nil {
+		// The managed cluster indexes its logs under its index prefix, with the index settings of this cluster.
+		esConfig := NewElasticsearchClusterConfig(ManagedClusterIndexPrefix(c.cr), c.esConfig.Replicas(), c.esConfig.Shards()).ConfigMap()
+		esConfig.TypeMeta = metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"}
+		objs = append(objs, esConfig)
+	}
+
+	var manifest bytes.Buffer
+	for i, obj := range objs {
+		b, err := yaml.Marshal(obj)
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			manifest.WriteString("---\n")
+		}
+		manifest.Write(b)
+	}
+	return manifest.Bytes(), nil
+}
@@ -0,0 +1,204 @@
+// Copyright (c) 2020 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render_test
+
+import (
+	"bytes"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v3 "github.com/tigera/api/pkg/apis/projectcalico/v3"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	operator "github.com/tigera/operator/pkg/apis/operator/v1"
+	"github.com/tigera/operator/pkg/render"
+)
+
+var _ = Describe("Managed cluster rendering tests", func() {
+	var instance *operator.ManagedCluster
+	var voltronSecret *corev1.Secret
+
+	BeforeEach(func() {
+		instance = &operator.ManagedCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-a"},
+			Spec:       operator.ManagedClusterSpec{ManagementClusterAddr: "10.128.0.10:30449"},
+		}
+		// The manager generates the Voltron tunnel secret in a management cluster.
+		resources := renderObjects(&operator.Manager{Spec: operator.ManagerSpec{Auth: &operator.Auth{Type: operator.AuthTypeBasic}}}, nil)
+		voltronSecret = GetResource(resources, render.VoltronTunnelSecretName, render.OperatorNamespace(), "", "v1", "Secret").(*corev1.Secret)
+	})
+
+	It("should issue a tunnel certificate signed by the Voltron CA", func() {
+		key, cert, err := render.CreateManagedClusterCertificate(instance.Name, voltronSecret)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(key).NotTo(BeEmpty())
+		Expect(render.IsManagedClusterCertificateValid(cert, voltronSecret)).To(BeTrue())
+
+		By("rejecting the certificate once the Voltron CA changes")
+		otherResources := renderObjects(&operator.Manager{Spec: operator.ManagerSpec{Auth: &operator.Auth{Type: operator.AuthTypeBasic}}}, nil)
+		otherSecret := GetResource(otherResources, render.VoltronTunnelSecretName, render.OperatorNamespace(), "", "v1", "Secret").(*corev1.Secret)
+		Expect(render.IsManagedClusterCertificateValid(cert, otherSecret)).To(BeFalse())
+	})
+
+	It("should overlap the current and the new Voltron CA while the CA is renewed", func() {
+		_, oldCert, err := render.CreateManagedClusterCertificate(instance.Name, voltronSecret)
+		Expect(err).NotTo(HaveOccurred())
+		oldCA := voltronSecret.Data["cert"]
+		oldKey := voltronSecret.Data["key"]
+
+		Expect(render.RenewVoltronTunnelCA(voltronSecret)).To(Succeed())
+		Expect(render.IsVoltronTunnelCARenewing(voltronSecret)).To(BeTrue())
+
+		By("serving the current CA while trusting a bundle of both")
+		Expect(voltronSecret.Data["key"]).To(Equal(oldKey))
+		Expect(bytes.HasPrefix(voltronSecret.Data["cert"], oldCA)).To(BeTrue())
+		Expect(bytes.HasSuffix(voltronSecret.Data["cert"], voltronSecret.Data["next-cert"])).To(BeTrue())
+
+		By("issuing certificates with the new CA, while accepting the certificates of the current CA")
+		key, newCert, err := render.CreateManagedClusterCertificate(instance.Name, voltronSecret)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(render.IsManagedClusterCertificateValid(oldCert, voltronSecret)).To(BeTrue())
+		Expect(render.IsManagedClusterCertificateCurrent(oldCert, voltronSecret)).To(BeFalse())
+		Expect(render.IsManagedClusterCertificateValid(newCert, voltronSecret)).To(BeTrue())
+		Expect(render.IsManagedClusterCertificateCurrent(newCert, voltronSecret)).To(BeTrue())
+
+		By("handing out the bundle in the manifest")
+		component, err := render.ManagedCluster(instance, key, newCert, nil, nil, voltronSecret, nil)
+		Expect(err).NotTo(HaveOccurred())
+		docs := bytes.Split(component.Objects()[0].(*corev1.Secret).Data[render.ManagedClusterManifestKey], []byte("---\n"))
+		guardianSecret := corev1.Secret{}
+		Expect(yaml.Unmarshal(docs[1], &guardianSecret)).To(Succeed())
+		Expect(guardianSecret.Data[render.ManagementClusterCertKey]).To(Equal(voltronSecret.Data["cert"]))
+
+		By("dropping the previous CA once it is retired")
+		newCA := voltronSecret.Data["next-cert"]
+		render.RetireVoltronTunnelCA(voltronSecret)
+		Expect(render.IsVoltronTunnelCARenewing(voltronSecret)).To(BeFalse())
+		Expect(voltronSecret.Data["cert"]).To(Equal(newCA))
+		Expect(voltronSecret.Data).NotTo(HaveKey("next-key"))
+		Expect(render.IsManagedClusterCertificateValid(oldCert, voltronSecret)).To(BeFalse())
+		Expect(render.IsManagedClusterCertificateValid(newCert, voltronSecret)).To(BeTrue())
+	})
+
+	It("should render the managed cluster secret, manifest and registration", func() {
+		key, cert, err := render.CreateManagedClusterCertificate(instance.Name, voltronSecret)
+		Expect(err).NotTo(HaveOccurred())
+		fingerprint, err := render.ManagedClusterCertificateFingerprint(cert)
+		Expect(err).NotTo(HaveOccurred())
+
+		component, err := render.ManagedCluster(instance, key, cert, nil, nil, voltronSecret, nil)
+		Expect(err).NotTo(HaveOccurred())
+		resources := component.Objects()
+		Expect(resources).To(HaveLen(2))
+
+		ExpectResource(resources[0], "tigera-managed-cluster-cluster-a", render.OperatorNamespace(), "", "v1", "Secret")
+		secret := resources[0].(*corev1.Secret)
+		Expect(secret.Data[render.ManagedClusterCertKey]).To(Equal(cert))
+		Expect(secret.Data[render.ManagedClusterKeyKey]).To(Equal(key))
+
+		calicoCluster, ok := resources[1].(*v3.ManagedCluster)
+		Expect(ok).To(BeTrue())
+		Expect(calicoCluster.Name).To(Equal("cluster-a"))
+		Expect(calicoCluster.Annotations).To(HaveKeyWithValue(render.ManagedClusterFingerprintAnnotation, fingerprint))
+
+		By("generating a manifest for the managed cluster")
+		docs := bytes.Split(secret.Data[render.ManagedClusterManifestKey], []byte("---\n"))
+		Expect(docs).To(HaveLen(2))
+
+		mcc := operator.ManagementClusterConnection{}
+		Expect(yaml.Unmarshal(docs[0], &mcc)).To(Succeed())
+		Expect(mcc.Kind).To(Equal("ManagementClusterConnection"))
+		Expect(mcc.Name).To(Equal("tigera-secure"))
+		Expect(mcc.Spec.ManagementClusterAddr).To(Equal("10.128.0.10:30449"))
+
+		guardianSecret := corev1.Secret{}
+		Expect(yaml.Unmarshal(docs[1], &guardianSecret)).To(Succeed())
+		Expect(guardianSecret.Name).To(Equal(render.GuardianSecretName))
+		Expect(guardianSecret.Namespace).To(Equal(render.OperatorNamespace()))
+		Expect(guardianSecret.Data).To(Equal(map[string][]byte{
+			render.ManagedClusterCertKey:    cert,
+			render.ManagedClusterKeyKey:     key,
+			render.ManagementClusterCertKey: voltronSecret.Data["cert"],
+		}))
+	})
+
+	It("should hand out a pending certificate in the manifest while Voltron accepts the current one", func() {
+		key, cert, err := render.CreateManagedClusterCertificate(instance.Name, voltronSecret)
+		Expect(err).NotTo(HaveOccurred())
+		pendingKey, pendingCert, err := render.CreateManagedClusterCertificate(instance.Name, voltronSecret)
+		Expect(err).NotTo(HaveOccurred())
+		fingerprint, err := render.ManagedClusterCertificateFingerprint(cert)
+		Expect(err).NotTo(HaveOccurred())
+
+		component, err := render.ManagedCluster(instance, key, cert, pendingKey, pendingCert, voltronSecret, nil)
+		Expect(err).NotTo(HaveOccurred())
+		resources := component.Objects()
+
+		secret := resources[0].(*corev1.Secret)
+		Expect(secret.Data[render.ManagedClusterCertKey]).To(Equal(cert))
+		Expect(secret.Data[render.ManagedClusterPendingCertKey]).To(Equal(pendingCert))
+		Expect(secret.Data[render.ManagedClusterPendingKeyKey]).To(Equal(pendingKey))
+		calicoCluster := resources[1].(*v3.ManagedCluster)
+		Expect(calicoCluster.Annotations).To(HaveKeyWithValue(render.ManagedClusterFingerprintAnnotation, fingerprint))
+
+		docs := bytes.Split(secret.Data[render.ManagedClusterManifestKey], []byte("---\n"))
+		Expect(docs).To(HaveLen(2))
+		guardianSecret := corev1.Secret{}
+		Expect(yaml.Unmarshal(docs[1], &guardianSecret)).To(Succeed())
+		Expect(guardianSecret.Data[render.ManagedClusterCertKey]).To(Equal(pendingCert))
+		Expect(guardianSecret.Data[render.ManagedClusterKeyKey]).To(Equal(pendingKey))
+	})
+
+	It("should report the expiry of tunnel certificates", func() {
+		_, cert, err := render.CreateManagedClusterCertificate(instance.Name, voltronSecret)
+		Expect(err).NotTo(HaveOccurred())
+		expiry, err := render.CertificateExpiry(cert)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(expiry).To(BeTemporally(">", time.Now().Add(render.TunnelCertificateRenewBefore)))
+
+		caExpiry, err := render.VoltronTunnelCAExpiry(voltronSecret)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(caExpiry).To(BeTemporally(">", expiry))
+
+		_, err = render.CertificateExpiry([]byte("not a certificate"))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should configure the index prefix of the managed cluster in the manifest", func() {
+		instance.Spec.LogStorage = &operator.ManagedClusterLogStorage{IndexPrefix: "team-a"}
+		key, cert, err := render.CreateManagedClusterCertificate(instance.Name, voltronSecret)
+		Expect(err).NotTo(HaveOccurred())
+
+		esConfig := render.NewElasticsearchClusterConfig(render.DefaultElasticsearchClusterName, 2, 5)
+		component, err := render.ManagedCluster(instance, key, cert, nil, nil, voltronSecret, esConfig)
+		Expect(err).NotTo(HaveOccurred())
+		secret := component.Objects()[0].(*corev1.Secret)
+
+		docs := bytes.Split(secret.Data[render.ManagedClusterManifestKey], []byte("---\n"))
+		Expect(docs).To(HaveLen(3))
+		configMap := corev1.ConfigMap{}
+		Expect(yaml.Unmarshal(docs[2], &configMap)).To(Succeed())
+		Expect(configMap.Name).To(Equal(render.ElasticsearchConfigMapName))
+		Expect(configMap.Namespace).To(Equal(render.OperatorNamespace()))
+		Expect(configMap.Data).To(Equal(map[string]string{
+			"clusterName": "team-a",
+			"replicas":    "2",
+			"shards":      "5",
+		}))
+	})
+})
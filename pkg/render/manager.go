@@ -3,16 +3,20 @@ package render
 import (
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	v3 "github.com/tigera/api/pkg/apis/projectcalico/v3"
 	operator "github.com/tigera/operator/pkg/apis/operator/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
+	routev1 "github.com/openshift/api/route/v1"
 	ocsv1 "github.com/openshift/api/security/v1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -28,10 +32,18 @@ const (
 	ManagerOIDCConfig       = "tigera-manager-oidc-config"
 	ManagerOIDCWellknownURI = "/usr/share/nginx/html/.well-known"
 	ManagerOIDCJwksURI      = "/usr/share/nginx/html/discovery"
+	ManagerOIDCCABundle     = "tigera-manager-oidc-ca-bundle"
+	ManagerOIDCCABundleKey  = "ca.crt"
 
 	ElasticsearchManagerUserSecret = "tigera-ee-manager-elasticsearch-access"
 	tlsSecretHashAnnotation        = "hash.operator.tigera.io/tls-secret"
 	oidcConfigHashAnnotation       = "hash.operator.tigera.io/oidc-config"
+	oidcCABundleHashAnnotation     = "hash.operator.tigera.io/oidc-ca-bundle"
+	oidcCABundleMountPath          = "/certs/oidc"
+	dexCertMountPath               = "/certs/dex"
+
+	defaultManagerClusterName = "cluster"
+	defaultOIDCUsernameClaim  = "email"
 )
 
 // ManagementClusterConnection configuration constants
@@ -53,8 +65,11 @@ func Manager(
 	openshift bool,
 	registry string,
 	oidcConfig *corev1.ConfigMap,
+	oidcCABundle *corev1.ConfigMap,
+	dexTLSSecret *corev1.Secret,
 	management bool,
 	tunnelSecret *corev1.Secret,
+	externalURL string,
 ) (Component, error) {
 	tlsSecrets := []*corev1.Secret{}
 	if tlsKeyPair == nil {
@@ -96,8 +111,11 @@ func Manager(
 		openshift:       openshift,
 		registry:        registry,
 		oidcConfig:      oidcConfig,
+		oidcCABundle:    oidcCABundle,
+		dexTLSSecret:    dexTLSSecret,
 		management:      management,
 		tunnelSecrets:   tunnelSecrets,
+		externalURL:     externalURL,
 	}, nil
 }
 
@@ -111,10 +129,16 @@ type managerComponent struct {
 	openshift       bool
 	registry        string
 	oidcConfig      *corev1.ConfigMap
+	// The CA bundle used to verify the OIDC issuer, if present in the operator namespace.
+	oidcCABundle *corev1.ConfigMap
+	// The identity broker's certificate, if the identity broker is enabled.
+	dexTLSSecret *corev1.Secret
 	// If true, this is a management cluster.
 	management bool
 	// The tunnel secret if present in the operator namespace
 	tunnelSecrets []*corev1.Secret
+	// The URL users reach the manager at, if it is exposed and its address is known.
+	externalURL string
 }
 
 func (c *managerComponent) Objects() []runtime.Object {
@@ -130,11 +154,21 @@ func (c *managerComponent) Objects() []runtime.Object {
 		c.managerPolicyImpactPreviewClusterRoleBinding(),
 	)
 	objs = append(objs, c.getTLSObjects()...)
+	objs = append(objs, c.managerService())
+	if c.cr.Spec.Exposure != nil {
+		switch c.cr.Spec.Exposure.Type {
+		case operator.ManagerExposureTypeIngress:
+			objs = append(objs, c.managerIngress())
+		case operator.ManagerExposureTypeRoute:
+			objs = append(objs, c.managerRoute())
+		}
+	}
 	objs = append(objs,
-		c.managerService(),
 		c.tigeraUserClusterRole(),
 		c.tigeraNetworkAdminClusterRole(),
+		c.tigeraComplianceAuditorClusterRole(),
 	)
+	objs = append(objs, c.uiRoleBindingObjects()...)
 
 	// If we're running on openshift, we need to add in an SCC.
 	if c.openshift {
@@ -146,7 +180,13 @@ func (c *managerComponent) Objects() []runtime.Object {
 	if c.oidcConfig != nil {
 		objs = append(objs, copyConfigMaps(ManagerNamespace, c.oidcConfig)...)
 	}
-	objs = append(objs, c.managerDeployment())
+	if c.oidcCABundle != nil {
+		objs = append(objs, copyConfigMaps(ManagerNamespace, c.oidcCABundle)...)
+	}
+	if c.dexTLSSecret != nil {
+		objs = append(objs, c.dexPublicCertSecret())
+	}
+	objs = append(objs, c.managerDeployment(), c.managerPodDisruptionBudget())
 	objs = append(objs, c.globalAlertTemplates()...)
 
 	return objs
@@ -159,6 +199,23 @@ func (c *managerComponent) Ready() bool {
 // managerDeployment creates a deployment for the Tigera Secure manager component.
 func (c *managerComponent) managerDeployment() *appsv1.Deployment {
 	var replicas int32 = 1
+	if c.cr.Spec.Replicas != nil {
+		replicas = *c.cr.Spec.Replicas
+	}
+	// A single manager pod is recreated on update. With more than one replica, roll the pods
+	// one at a time so that the manager stays available.
+	strategy := appsv1.DeploymentStrategy{
+		Type: appsv1.RecreateDeploymentStrategyType,
+	}
+	if replicas > 1 {
+		maxUnavailable := intstr.FromInt(1)
+		strategy = appsv1.DeploymentStrategy{
+			Type: appsv1.RollingUpdateDeploymentStrategyType,
+			RollingUpdate: &appsv1.RollingUpdateDeployment{
+				MaxUnavailable: &maxUnavailable,
+			},
+		}
+	}
 	annotations := map[string]string{
 		// Mark this pod as a critical add-on; when enabled, the critical add-on scheduler
 		// reserves resources for critical add-on pods so that they can be rescheduled after
@@ -176,6 +233,12 @@ func (c *managerComponent) managerDeployment() *appsv1.Deployment {
 	if c.oidcConfig != nil {
 		annotations[oidcConfigHashAnnotation] = AnnotationHash(c.oidcConfig.Data)
 	}
+	if c.oidcCABundle != nil {
+		annotations[oidcCABundleHashAnnotation] = AnnotationHash(c.oidcCABundle.Data)
+	}
+	if c.dexTLSSecret != nil {
+		annotations[dexTLSHashAnnotation] = AnnotationHash(c.dexTLSSecret.Data[corev1.TLSCertKey])
+	}
 
 	d := &appsv1.Deployment{
 		TypeMeta: metav1.TypeMeta{Kind: "Deployment", APIVersion: "v1"},
@@ -193,9 +256,7 @@ func (c *managerComponent) managerDeployment() *appsv1.Deployment {
 				},
 			},
 			Replicas: &replicas,
-			Strategy: appsv1.DeploymentStrategy{
-				Type: appsv1.RecreateDeploymentStrategyType,
-			},
+			Strategy: strategy,
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "tigera-manager",
@@ -213,8 +274,8 @@ func (c *managerComponent) managerDeployment() *appsv1.Deployment {
 					Tolerations:        c.managerTolerations(),
 					ImagePullSecrets:   getImagePullSecretReferenceList(c.pullSecrets),
 					Containers: []corev1.Container{
-						ElasticsearchContainerDecorate(c.managerContainer(), c.esClusterConfig.ClusterName(), ElasticsearchManagerUserSecret),
-						ElasticsearchContainerDecorate(c.managerEsProxyContainer(), c.esClusterConfig.ClusterName(), ElasticsearchManagerUserSecret),
+						ElasticsearchContainerDecorate(c.managerContainer(), c.esClusterConfig, ElasticsearchManagerUserSecret),
+						ElasticsearchContainerDecorate(c.managerEsProxyContainer(), c.esClusterConfig, ElasticsearchManagerUserSecret),
 						c.managerProxyContainer(),
 					},
 					Volumes: c.managerVolumes(),
@@ -225,6 +286,26 @@ func (c *managerComponent) managerDeployment() *appsv1.Deployment {
 	return d
 }
 
+// managerPodDisruptionBudget limits voluntary disruptions of the manager pods to one at a time.
+func (c *managerComponent) managerPodDisruptionBudget() *policyv1beta1.PodDisruptionBudget {
+	maxUnavailable := intstr.FromInt(1)
+	return &policyv1beta1.PodDisruptionBudget{
+		TypeMeta: metav1.TypeMeta{Kind: "PodDisruptionBudget", APIVersion: "policy/v1beta1"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "tigera-manager",
+			Namespace: ManagerNamespace,
+		},
+		Spec: policyv1beta1.PodDisruptionBudgetSpec{
+			MaxUnavailable: &maxUnavailable,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"k8s-app": "tigera-manager",
+				},
+			},
+		},
+	}
+}
+
 // managerVolumes returns the volumes for the Tigera Secure manager component.
 func (c *managerComponent) managerVolumes() []v1.Volume {
 	optional := true
@@ -238,22 +319,25 @@ func (c *managerComponent) managerVolumes() []v1.Volume {
 			},
 		},
 		{
-			Name: KibanaPublicCertSecret,
+			Name: VoltronTunnelSecretName,
 			VolumeSource: v1.VolumeSource{
 				Secret: &v1.SecretVolumeSource{
-					SecretName: KibanaPublicCertSecret,
+					SecretName: VoltronTunnelSecretName,
+					Optional:   &optional,
 				},
 			},
 		},
-		{
-			Name: VoltronTunnelSecretName,
+	}
+
+	if c.esClusterConfig.KibanaEnabled() {
+		v = append(v, v1.Volume{
+			Name: KibanaPublicCertSecret,
 			VolumeSource: v1.VolumeSource{
 				Secret: &v1.SecretVolumeSource{
-					SecretName: VoltronTunnelSecretName,
-					Optional:   &optional,
+					SecretName: KibanaPublicCertSecret,
 				},
 			},
-		},
+		})
 	}
 
 	if c.oidcConfig != nil {
@@ -272,9 +356,47 @@ func (c *managerComponent) managerVolumes() []v1.Volume {
 			})
 	}
 
+	if c.oidcCABundle != nil {
+		v = append(v,
+			v1.Volume{
+				Name: ManagerOIDCCABundle,
+				VolumeSource: v1.VolumeSource{
+					ConfigMap: &v1.ConfigMapVolumeSource{
+						LocalObjectReference: v1.LocalObjectReference{
+							Name: ManagerOIDCCABundle,
+						},
+					},
+				},
+			})
+	}
+
+	if c.dexTLSSecret != nil {
+		v = append(v,
+			v1.Volume{
+				Name: DexPublicCertSecretName,
+				VolumeSource: v1.VolumeSource{
+					Secret: &v1.SecretVolumeSource{
+						SecretName: DexPublicCertSecretName,
+					},
+				},
+			})
+	}
+
 	return v
 }
 
+// dexPublicCertSecret returns a Secret holding only the identity broker's certificate, so that the
+// manager proxy can verify the broker without access to its private key.
+func (c *managerComponent) dexPublicCertSecret() *corev1.Secret {
+	return &corev1.Secret{
+		TypeMeta:   metav1.TypeMeta{Kind: "Secret", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: DexPublicCertSecretName, Namespace: ManagerNamespace},
+		Data: map[string][]byte{
+			corev1.TLSCertKey: c.dexTLSSecret.Data[corev1.TLSCertKey],
+		},
+	}
+}
+
 // managerProbe returns the probe for the manager container.
 func (c *managerComponent) managerProbe() *v1.Probe {
 	return &corev1.Probe{
@@ -327,18 +449,58 @@ func (c *managerComponent) managerEnvVars() []v1.EnvVar {
 		{Name: "CNX_COMPLIANCE_REPORTS_API_URL", Value: "/compliance/reports"},
 		{Name: "CNX_QUERY_API_URL", Value: "/api/v1/namespaces/tigera-system/services/https:tigera-api:8080/proxy"},
 		{Name: "CNX_ELASTICSEARCH_API_URL", Value: "/tigera-elasticsearch"},
-		{Name: "CNX_ELASTICSEARCH_KIBANA_URL", Value: fmt.Sprintf("/%s", KibanaBasePath)},
-		{Name: "CNX_ENABLE_ERROR_TRACKING", Value: "false"},
-		{Name: "CNX_ALP_SUPPORT", Value: "true"},
-		{Name: "CNX_CLUSTER_NAME", Value: "cluster"},
-		{Name: "CNX_POLICY_RECOMMENDATION_SUPPORT", Value: "true"},
-		{Name: "ENABLE_MULTI_CLUSTER_MANAGEMENT", Value: strconv.FormatBool(c.management)},
 	}
+	// Kibana is served by the manager proxy under its base path.
+	if c.esClusterConfig.KibanaEnabled() {
+		envs = append(envs, v1.EnvVar{Name: "CNX_ELASTICSEARCH_KIBANA_URL", Value: fmt.Sprintf("/%s", KibanaBasePath)})
+	}
+	envs = append(envs,
+		v1.EnvVar{Name: "CNX_ENABLE_ERROR_TRACKING", Value: "false"},
+		v1.EnvVar{Name: "CNX_ALP_SUPPORT", Value: strconv.FormatBool(c.alpEnabled())},
+		v1.EnvVar{Name: "CNX_CLUSTER_NAME", Value: c.clusterName()},
+		v1.EnvVar{Name: "CNX_POLICY_RECOMMENDATION_SUPPORT", Value: strconv.FormatBool(c.policyRecommendationEnabled())},
+		v1.EnvVar{Name: "ENABLE_MULTI_CLUSTER_MANAGEMENT", Value: strconv.FormatBool(c.management)},
+	)
+
+	var level operator.LogLevel
+	if c.cr.Spec.LogLevels != nil {
+		level = c.cr.Spec.LogLevels.Manager
+	}
+	envs = append(envs, v1.EnvVar{Name: "CNX_LOG_LEVEL", Value: logLevel(level)})
 
 	envs = append(envs, c.managerOAuth2EnvVars()...)
 	return envs
 }
 
+// clusterName returns the name the manager displays for this cluster.
+func (c *managerComponent) clusterName() string {
+	if c.cr.Spec.ClusterName == "" {
+		return defaultManagerClusterName
+	}
+	return c.cr.Spec.ClusterName
+}
+
+// policyRecommendationEnabled returns whether policy recommendations are enabled, defaulting to true.
+func (c *managerComponent) policyRecommendationEnabled() bool {
+	f := c.cr.Spec.Features
+	return f == nil || f.PolicyRecommendation == nil || *f.PolicyRecommendation
+}
+
+// alpEnabled returns whether the application layer policy features are enabled, defaulting to true.
+func (c *managerComponent) alpEnabled() bool {
+	f := c.cr.Spec.Features
+	return f == nil || f.ApplicationLayerPolicy == nil || *f.ApplicationLayerPolicy
+}
+
+// logLevel converts the given log level to the lower case form understood by the manager
+// containers, defaulting to info.
+func logLevel(level operator.LogLevel) string {
+	if level == "" {
+		level = operator.LogLevelInfo
+	}
+	return strings.ToLower(string(level))
+}
+
 // managerContainer returns the manager container.
 func (c *managerComponent) managerContainer() corev1.Container {
 	tm := corev1.Container{
@@ -371,6 +533,12 @@ func (c *managerComponent) managerOAuth2EnvVars() []v1.EnvVar {
 		oidcEnvs := []corev1.EnvVar{
 			{Name: "CNX_WEB_OIDC_AUTHORITY", Value: c.cr.Spec.Auth.Authority},
 			{Name: "CNX_WEB_OIDC_CLIENT_ID", Value: c.cr.Spec.Auth.ClientID},
+			{Name: "CNX_WEB_OIDC_SCOPES", Value: strings.Join(OIDCScopes(c.cr.Spec.Auth), " ")},
+			{Name: "CNX_WEB_OIDC_USERNAME_CLAIM", Value: oidcUsernameClaim(c.cr.Spec.Auth)},
+		}
+		// The identity broker only accepts the redirect URIs it registered for the manager domain.
+		if c.cr.Spec.IdentityBroker == nil && c.externalURL != "" {
+			oidcEnvs = append(oidcEnvs, corev1.EnvVar{Name: "CNX_WEB_OIDC_REDIRECT_URI", Value: c.externalURL + "/login/oidc/callback"})
 		}
 		envs = append(envs, oidcEnvs...)
 	case operator.AuthTypeOAuth:
@@ -378,31 +546,114 @@ func (c *managerComponent) managerOAuth2EnvVars() []v1.EnvVar {
 			{Name: "CNX_WEB_OAUTH_AUTHORITY", Value: c.cr.Spec.Auth.Authority},
 			{Name: "CNX_WEB_OAUTH_CLIENT_ID", Value: c.cr.Spec.Auth.ClientID},
 		}
+		if c.externalURL != "" {
+			oauthEnvs = append(oauthEnvs, corev1.EnvVar{Name: "CNX_WEB_OAUTH_REDIRECT_URI", Value: c.externalURL + "/login/oauth/callback"})
+		}
 		envs = append(envs, oauthEnvs...)
 	}
 	return envs
 }
 
+// OIDCScopes returns the scopes the manager requests from the OIDC issuer.
+func OIDCScopes(auth *operator.Auth) []string {
+	scopes := []string{"openid"}
+	if len(auth.Scopes) == 0 {
+		return append(scopes, "email", "profile")
+	}
+	for _, s := range auth.Scopes {
+		if s != "openid" {
+			scopes = append(scopes, s)
+		}
+	}
+	return scopes
+}
+
+// oidcUsernameClaim returns the ID token claim used as the username, defaulting to email.
+func oidcUsernameClaim(auth *operator.Auth) string {
+	if auth.UsernameClaim == "" {
+		return defaultOIDCUsernameClaim
+	}
+	return auth.UsernameClaim
+}
+
+// oidcProxyAuthentication returns true if the manager proxy must authenticate OIDC tokens and
+// impersonate the user towards the Kubernetes API.
+func (c *managerComponent) oidcProxyAuthentication() bool {
+	return c.cr.Spec.Auth != nil &&
+		c.cr.Spec.Auth.Type == operator.AuthTypeOIDC &&
+		c.cr.Spec.Auth.KubernetesAuthentication == operator.KubernetesAuthenticationProxy
+}
+
+// managerProxyOIDCEnvVars returns the envvars that configure OIDC token authentication in the manager proxy.
+func (c *managerComponent) managerProxyOIDCEnvVars() []corev1.EnvVar {
+	if !c.oidcProxyAuthentication() {
+		return nil
+	}
+	auth := c.cr.Spec.Auth
+	envs := []corev1.EnvVar{
+		{Name: "VOLTRON_OIDC_AUTH_ENABLED", Value: "true"},
+		{Name: "VOLTRON_OIDC_AUTH_ISSUER", Value: auth.Authority},
+		{Name: "VOLTRON_OIDC_AUTH_CLIENT_ID", Value: auth.ClientID},
+		{Name: "VOLTRON_OIDC_AUTH_USERNAME_CLAIM", Value: oidcUsernameClaim(auth)},
+		{Name: "VOLTRON_OIDC_AUTH_USERNAME_PREFIX", Value: auth.UsernamePrefix},
+		{Name: "VOLTRON_OIDC_AUTH_GROUPS_CLAIM", Value: auth.GroupsClaim},
+		{Name: "VOLTRON_OIDC_AUTH_GROUPS_PREFIX", Value: auth.GroupsPrefix},
+	}
+	if c.dexTLSSecret != nil {
+		// The proxy serves the identity broker under /dex and fetches its keys directly from it.
+		envs = append(envs,
+			corev1.EnvVar{Name: "VOLTRON_DEX_ENABLED", Value: "true"},
+			corev1.EnvVar{Name: "VOLTRON_DEX_URL", Value: DexURL + "/"},
+			corev1.EnvVar{Name: "VOLTRON_OIDC_AUTH_JWKS_URL", Value: DexURL + dexBasePath + "/keys"},
+			corev1.EnvVar{Name: "VOLTRON_OIDC_AUTH_CA_BUNDLE_PATH", Value: fmt.Sprintf("%s/%s", dexCertMountPath, corev1.TLSCertKey)},
+		)
+	} else if c.oidcCABundle != nil {
+		envs = append(envs, corev1.EnvVar{
+			Name:  "VOLTRON_OIDC_AUTH_CA_BUNDLE_PATH",
+			Value: fmt.Sprintf("%s/%s", oidcCABundleMountPath, ManagerOIDCCABundleKey),
+		})
+	}
+	return envs
+}
+
 // managerProxyContainer returns the container for the manager proxy container.
 func (c *managerComponent) managerProxyContainer() corev1.Container {
+	var level operator.LogLevel
+	if c.cr.Spec.LogLevels != nil {
+		level = c.cr.Spec.LogLevels.Proxy
+	}
+	volumeMounts := []corev1.VolumeMount{
+		{Name: ManagerTLSSecretName, MountPath: "/certs/https"},
+		{Name: VoltronTunnelSecretName, MountPath: "/certs/tunnel/"},
+	}
+	env := []corev1.EnvVar{
+		{Name: "VOLTRON_PORT", Value: defaultVoltronPort},
+		{Name: "VOLTRON_COMPLIANCE_ENDPOINT", Value: fmt.Sprintf("https://compliance.%s.svc", ComplianceNamespace)},
+		{Name: "VOLTRON_LOGLEVEL", Value: logLevel(level)},
+	}
+	if c.esClusterConfig.KibanaEnabled() {
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{Name: KibanaPublicCertSecret, MountPath: "/certs/kibana"})
+		env = append(env,
+			corev1.EnvVar{Name: "VOLTRON_KIBANA_ENDPOINT", Value: KibanaHTTPSEndpoint},
+			corev1.EnvVar{Name: "VOLTRON_KIBANA_BASE_PATH", Value: fmt.Sprintf("/%s/", KibanaBasePath)},
+			corev1.EnvVar{Name: "VOLTRON_KIBANA_CA_BUNDLE_PATH", Value: "/certs/kibana/tls.crt"},
+		)
+	}
+	env = append(env,
+		corev1.EnvVar{Name: "VOLTRON_ENABLE_MULTI_CLUSTER_MANAGEMENT", Value: strconv.FormatBool(c.management)},
+		corev1.EnvVar{Name: "VOLTRON_TUNNEL_PORT", Value: defaultTunnelVoltronPort},
+	)
+	if c.oidcCABundle != nil {
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{Name: ManagerOIDCCABundle, MountPath: oidcCABundleMountPath})
+	}
+	if c.dexTLSSecret != nil {
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{Name: DexPublicCertSecretName, MountPath: dexCertMountPath})
+	}
 	return corev1.Container{
-		Name:  VoltronName,
-		Image: constructImage(ManagerProxyImageName, c.registry),
-		Env: []corev1.EnvVar{
-			{Name: "VOLTRON_PORT", Value: defaultVoltronPort},
-			{Name: "VOLTRON_COMPLIANCE_ENDPOINT", Value: fmt.Sprintf("https://compliance.%s.svc", ComplianceNamespace)},
-			{Name: "VOLTRON_LOGLEVEL", Value: "info"},
-			{Name: "VOLTRON_KIBANA_ENDPOINT", Value: KibanaHTTPSEndpoint},
-			{Name: "VOLTRON_KIBANA_BASE_PATH", Value: fmt.Sprintf("/%s/", KibanaBasePath)},
-			{Name: "VOLTRON_KIBANA_CA_BUNDLE_PATH", Value: "/certs/kibana/tls.crt"},
-			{Name: "VOLTRON_ENABLE_MULTI_CLUSTER_MANAGEMENT", Value: strconv.FormatBool(c.management)},
-			{Name: "VOLTRON_TUNNEL_PORT", Value: defaultTunnelVoltronPort},
-		},
-		VolumeMounts: []corev1.VolumeMount{
-			{Name: ManagerTLSSecretName, MountPath: "/certs/https"},
-			{Name: KibanaPublicCertSecret, MountPath: "/certs/kibana"},
-			{Name: VoltronTunnelSecretName, MountPath: "/certs/tunnel/"},
-		},
+		Name:            VoltronName,
+		Image:           constructImage(ManagerProxyImageName, c.registry),
+		Env:             append(env, c.managerProxyOIDCEnvVars()...),
+		VolumeMounts:    volumeMounts,
 		LivenessProbe:   c.managerProxyProbe(),
 		SecurityContext: securityContext(),
 	}
@@ -410,9 +661,16 @@ func (c *managerComponent) managerProxyContainer() corev1.Container {
 
 // managerEsProxyContainer returns the ES proxy container
 func (c *managerComponent) managerEsProxyContainer() corev1.Container {
+	var level operator.LogLevel
+	if c.cr.Spec.LogLevels != nil {
+		level = c.cr.Spec.LogLevels.ESProxy
+	}
 	apiServer := corev1.Container{
-		Name:            "tigera-es-proxy",
-		Image:           constructImage(ManagerEsProxyImageName, c.registry),
+		Name:  "tigera-es-proxy",
+		Image: constructImage(ManagerEsProxyImageName, c.registry),
+		Env: []corev1.EnvVar{
+			{Name: "LOG_LEVEL", Value: logLevel(level)},
+		},
 		LivenessProbe:   c.managerEsProxyProbe(),
 		SecurityContext: securityContext(),
 	}
@@ -438,7 +696,7 @@ func (c *managerComponent) managerTolerations() []v1.Toleration {
 
 // managerService returns the service exposing the Tigera Secure web app.
 func (c *managerComponent) managerService() *v1.Service {
-	return &corev1.Service{
+	svc := &corev1.Service{
 		TypeMeta: metav1.TypeMeta{Kind: "Service", APIVersion: "v1"},
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "tigera-manager",
@@ -457,6 +715,108 @@ func (c *managerComponent) managerService() *v1.Service {
 			},
 		},
 	}
+	if e := c.cr.Spec.Exposure; e != nil &&
+		(e.Type == operator.ManagerExposureTypeLoadBalancer || e.Type == operator.ManagerExposureTypeNodePort) {
+		svc.Spec.Type = corev1.ServiceType(e.Type)
+		if e.NodePort != nil {
+			svc.Spec.Ports[0].NodePort = *e.NodePort
+		}
+	}
+	return svc
+}
+
+// ManagerExternalURL returns the URL users reach the manager at when it can be derived from the
+// Manager spec, or an empty string otherwise.
+func ManagerExternalURL(cr *operator.Manager) string {
+	e := cr.Spec.Exposure
+	if e == nil {
+		return ""
+	}
+	switch {
+	case e.Type == operator.ManagerExposureTypeIngress && e.Ingress != nil:
+		return "https://" + e.Ingress.Host
+	case e.Type == operator.ManagerExposureTypeRoute && e.Route != nil && e.Route.Host != "":
+		return "https://" + e.Route.Host
+	}
+	return ""
+}
+
+// managerIngress returns the Ingress exposing the manager. The manager only serves HTTPS, so the
+// Ingress controller is told to use HTTPS towards it.
+func (c *managerComponent) managerIngress() *networkingv1beta1.Ingress {
+	spec := c.cr.Spec.Exposure.Ingress
+	annotations := map[string]string{
+		"nginx.ingress.kubernetes.io/backend-protocol": "HTTPS",
+	}
+	if spec.Class != "" {
+		annotations["kubernetes.io/ingress.class"] = spec.Class
+	}
+	for k, v := range spec.Annotations {
+		annotations[k] = v
+	}
+
+	ingress := &networkingv1beta1.Ingress{
+		TypeMeta: metav1.TypeMeta{Kind: "Ingress", APIVersion: "networking.k8s.io/v1beta1"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "tigera-manager",
+			Namespace:   ManagerNamespace,
+			Annotations: annotations,
+		},
+		Spec: networkingv1beta1.IngressSpec{
+			Rules: []networkingv1beta1.IngressRule{
+				{
+					Host: spec.Host,
+					IngressRuleValue: networkingv1beta1.IngressRuleValue{
+						HTTP: &networkingv1beta1.HTTPIngressRuleValue{
+							Paths: []networkingv1beta1.HTTPIngressPath{
+								{
+									Path: "/",
+									Backend: networkingv1beta1.IngressBackend{
+										ServiceName: "tigera-manager",
+										ServicePort: intstr.FromInt(managerPort),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	if spec.TLSSecretName != "" {
+		ingress.Spec.TLS = []networkingv1beta1.IngressTLS{
+			{Hosts: []string{spec.Host}, SecretName: spec.TLSSecretName},
+		}
+	}
+	return ingress
+}
+
+// managerRoute returns the OpenShift Route exposing the manager. TLS is passed through so that
+// the manager's own certificate is served.
+func (c *managerComponent) managerRoute() *routev1.Route {
+	var host string
+	if c.cr.Spec.Exposure.Route != nil {
+		host = c.cr.Spec.Exposure.Route.Host
+	}
+	return &routev1.Route{
+		TypeMeta: metav1.TypeMeta{Kind: "Route", APIVersion: "route.openshift.io/v1"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "tigera-manager",
+			Namespace: ManagerNamespace,
+		},
+		Spec: routev1.RouteSpec{
+			Host: host,
+			To: routev1.RouteTargetReference{
+				Kind: "Service",
+				Name: "tigera-manager",
+			},
+			Port: &routev1.RoutePort{TargetPort: intstr.FromInt(managerTargetPort)},
+			TLS: &routev1.TLSConfig{
+				Termination:                   routev1.TLSTerminationPassthrough,
+				InsecureEdgeTerminationPolicy: routev1.InsecureEdgeTerminationPolicyRedirect,
+			},
+		},
+	}
 }
 
 // managerService returns the service exposing the Tigera Secure web app.
@@ -469,8 +829,8 @@ func voltronTunnelSecret() *v1.Secret {
 			Namespace: OperatorNamespace(),
 		},
 		Data: map[string][]byte{
-			"cert": []byte(cert),
-			"key":  []byte(key),
+			voltronTunnelCertKey: []byte(cert),
+			voltronTunnelKeyKey:  []byte(key),
 		},
 	}
 }
@@ -485,7 +845,7 @@ func (c *managerComponent) managerServiceAccount() *v1.ServiceAccount {
 
 // managerClusterRole returns a clusterrole that allows authn/authz review requests.
 func (c *managerComponent) managerClusterRole() *rbacv1.ClusterRole {
-	return &rbacv1.ClusterRole{
+	cr := &rbacv1.ClusterRole{
 		TypeMeta: metav1.TypeMeta{Kind: "ClusterRole", APIVersion: "rbac.authorization.k8s.io/v1"},
 		ObjectMeta: metav1.ObjectMeta{
 			Name: "tigera-manager-role",
@@ -508,6 +868,15 @@ func (c *managerComponent) managerClusterRole() *rbacv1.ClusterRole {
 			},
 		},
 	}
+	if c.oidcProxyAuthentication() {
+		// The proxy authenticates OIDC users itself and acts on their behalf.
+		cr.Rules = append(cr.Rules, rbacv1.PolicyRule{
+			APIGroups: []string{""},
+			Resources: []string{"users", "groups", "serviceaccounts"},
+			Verbs:     []string{"impersonate"},
+		})
+	}
+	return cr
 }
 
 // managerClusterRoleBinding returns a clusterrolebinding that gives the tigera-manager serviceaccount
@@ -601,7 +970,7 @@ func (c *managerComponent) tigeraUserClusterRole() *rbacv1.ClusterRole {
 	return &rbacv1.ClusterRole{
 		TypeMeta: metav1.TypeMeta{Kind: "ClusterRole", APIVersion: "rbac.authorization.k8s.io/v1"},
 		ObjectMeta: metav1.ObjectMeta{
-			Name: "tigera-ui-user",
+			Name: TigeraUIUserClusterRoleName,
 		},
 		Rules: []rbacv1.PolicyRule{
 			// List requests that the Tigera manager needs.
@@ -683,7 +1052,7 @@ func (c *managerComponent) tigeraNetworkAdminClusterRole() *rbacv1.ClusterRole {
 	return &rbacv1.ClusterRole{
 		TypeMeta: metav1.TypeMeta{Kind: "ClusterRole", APIVersion: "rbac.authorization.k8s.io/v1"},
 		ObjectMeta: metav1.ObjectMeta{
-			Name: "tigera-network-admin",
+			Name: TigeraNetworkAdminClusterRoleName,
 		},
 		Rules: []rbacv1.PolicyRule{
 			// Full access to all network policies
@@ -0,0 +1,219 @@
+// Copyright (c) 2020 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"fmt"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	operator "github.com/tigera/operator/pkg/apis/operator/v1"
+)
+
+const (
+	TigeraUIUserClusterRoleName            = "tigera-ui-user"
+	TigeraNetworkAdminClusterRoleName      = "tigera-network-admin"
+	TigeraComplianceAuditorClusterRoleName = "tigera-compliance-auditor"
+
+	// UIRoleBindingLabel is set on the ClusterRoleBindings rendered for a manager role binding. Its
+	// value is the name of the role binding.
+	UIRoleBindingLabel = "operator.tigera.io/ui-role-binding"
+	// UITierLabel is set on the ClusterRoles granting edit access to a tier. Its value is the tier.
+	UITierLabel = "operator.tigera.io/ui-tier"
+)
+
+// UIRoleBindingNames returns the names of the ClusterRoleBindings rendered for the manager role bindings.
+func UIRoleBindingNames(cr *operator.Manager) map[string]bool {
+	names := map[string]bool{}
+	for _, rb := range cr.Spec.RoleBindings {
+		names[uiRoleBindingName(rb.Name)] = true
+		if rb.Profile == operator.UIRoleProfileComplianceAuditor {
+			names[uiRoleBindingName(rb.Name)+"-user"] = true
+		}
+		if rb.Profile == operator.UIRoleProfileTierEditor {
+			for _, tier := range rb.Tiers {
+				names[uiTierRoleBindingName(rb.Name, tier)] = true
+			}
+		}
+	}
+	return names
+}
+
+// UITierRoleNames returns the names of the tier editor ClusterRoles rendered for the manager role bindings.
+func UITierRoleNames(cr *operator.Manager) map[string]bool {
+	names := map[string]bool{}
+	for _, rb := range cr.Spec.RoleBindings {
+		if rb.Profile == operator.UIRoleProfileTierEditor {
+			for _, tier := range rb.Tiers {
+				names[uiTierRoleName(tier)] = true
+			}
+		}
+	}
+	return names
+}
+
+func uiRoleBindingName(name string) string {
+	return fmt.Sprintf("tigera-ui-%s", name)
+}
+
+func uiTierRoleBindingName(name, tier string) string {
+	return fmt.Sprintf("tigera-ui-%s-%s", name, tier)
+}
+
+func uiTierRoleName(tier string) string {
+	return fmt.Sprintf("tigera-tier-editor-%s", tier)
+}
+
+// profileClusterRole returns the ClusterRole granting the base permissions of a profile. Compliance
+// auditors and tier editors are also bound to the user role, so the manager itself is usable.
+func profileClusterRole(profile operator.UIRoleProfile) string {
+	switch profile {
+	case operator.UIRoleProfileNetworkAdmin:
+		return TigeraNetworkAdminClusterRoleName
+	case operator.UIRoleProfileComplianceAuditor:
+		return TigeraComplianceAuditorClusterRoleName
+	default:
+		return TigeraUIUserClusterRoleName
+	}
+}
+
+// uiRoleBindingObjects returns the ClusterRoles and ClusterRoleBindings for the manager role bindings.
+func (c *managerComponent) uiRoleBindingObjects() []runtime.Object {
+	var objs []runtime.Object
+	tiers := map[string]bool{}
+	for _, rb := range c.cr.Spec.RoleBindings {
+		subjects := c.uiRoleBindingSubjects(rb)
+		objs = append(objs, uiClusterRoleBinding(uiRoleBindingName(rb.Name), rb.Name, profileClusterRole(rb.Profile), subjects))
+		if rb.Profile == operator.UIRoleProfileComplianceAuditor {
+			objs = append(objs, uiClusterRoleBinding(uiRoleBindingName(rb.Name)+"-user", rb.Name, TigeraUIUserClusterRoleName, subjects))
+		}
+		if rb.Profile != operator.UIRoleProfileTierEditor {
+			continue
+		}
+		for _, tier := range rb.Tiers {
+			if !tiers[tier] {
+				tiers[tier] = true
+				objs = append(objs, tierEditorClusterRole(tier))
+			}
+			objs = append(objs, uiClusterRoleBinding(uiTierRoleBindingName(rb.Name, tier), rb.Name, uiTierRoleName(tier), subjects))
+		}
+	}
+	return objs
+}
+
+// uiRoleBindingSubjects returns the users and groups of a role binding as seen by Kubernetes.
+func (c *managerComponent) uiRoleBindingSubjects(rb operator.UIRoleBinding) []rbacv1.Subject {
+	var usernamePrefix, groupsPrefix string
+	if c.cr.Spec.Auth != nil {
+		usernamePrefix = c.cr.Spec.Auth.UsernamePrefix
+		groupsPrefix = c.cr.Spec.Auth.GroupsPrefix
+	}
+	var subjects []rbacv1.Subject
+	for _, group := range rb.Groups {
+		subjects = append(subjects, rbacv1.Subject{
+			Kind:     "Group",
+			APIGroup: "rbac.authorization.k8s.io",
+			Name:     groupsPrefix + group,
+		})
+	}
+	for _, user := range rb.Users {
+		subjects = append(subjects, rbacv1.Subject{
+			Kind:     "User",
+			APIGroup: "rbac.authorization.k8s.io",
+			Name:     usernamePrefix + user,
+		})
+	}
+	return subjects
+}
+
+func uiClusterRoleBinding(name, rbName, role string, subjects []rbacv1.Subject) *rbacv1.ClusterRoleBinding {
+	return &rbacv1.ClusterRoleBinding{
+		TypeMeta: metav1.TypeMeta{Kind: "ClusterRoleBinding", APIVersion: "rbac.authorization.k8s.io/v1"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{UIRoleBindingLabel: rbName},
+		},
+		RoleRef: rbacv1.RoleRef{
+			Kind:     "ClusterRole",
+			Name:     role,
+			APIGroup: "rbac.authorization.k8s.io",
+		},
+		Subjects: subjects,
+	}
+}
+
+// tierEditorClusterRole returns a cluster role granting full access to the policies in a tier.
+func tierEditorClusterRole(tier string) *rbacv1.ClusterRole {
+	return &rbacv1.ClusterRole{
+		TypeMeta: metav1.TypeMeta{Kind: "ClusterRole", APIVersion: "rbac.authorization.k8s.io/v1"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   uiTierRoleName(tier),
+			Labels: map[string]string{UITierLabel: tier},
+		},
+		Rules: []rbacv1.PolicyRule{
+			// Access to the tier itself, required to access any policy in it.
+			{
+				APIGroups:     []string{"projectcalico.org"},
+				Resources:     []string{"tiers"},
+				ResourceNames: []string{tier},
+				Verbs:         []string{"get"},
+			},
+			// Full access to the policies in the tier. Tiered policy names are prefixed with the tier name.
+			{
+				APIGroups: []string{"projectcalico.org"},
+				Resources: []string{
+					"tier.networkpolicies",
+					"tier.globalnetworkpolicies",
+					"tier.stagednetworkpolicies",
+					"tier.stagedglobalnetworkpolicies",
+				},
+				ResourceNames: []string{tier + ".*"},
+				Verbs:         []string{"*"},
+			},
+		},
+	}
+}
+
+// tigeraComplianceAuditorClusterRole returns a cluster role for a Tigera Secure compliance auditor.
+func (c *managerComponent) tigeraComplianceAuditorClusterRole() *rbacv1.ClusterRole {
+	return &rbacv1.ClusterRole{
+		TypeMeta: metav1.TypeMeta{Kind: "ClusterRole", APIVersion: "rbac.authorization.k8s.io/v1"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: TigeraComplianceAuditorClusterRoleName,
+		},
+		Rules: []rbacv1.PolicyRule{
+			// View report configuration and generation status, and download reports.
+			{
+				APIGroups: []string{"projectcalico.org"},
+				Resources: []string{"globalreports", "globalreports/status"},
+				Verbs:     []string{"get", "list", "watch"},
+			},
+			{
+				APIGroups: []string{"projectcalico.org"},
+				Resources: []string{"globalreporttypes"},
+				Verbs:     []string{"get", "list", "watch"},
+			},
+			// Access to audit logs.
+			{
+				APIGroups:     []string{"lma.tigera.io"},
+				Resources:     []string{"*"},
+				ResourceNames: []string{"audit*"},
+				Verbs:         []string{"get"},
+			},
+		},
+	}
+}
@@ -18,13 +18,18 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/pem"
+	"strings"
 	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	routev1 "github.com/openshift/api/route/v1"
 	"github.com/openshift/library-go/pkg/crypto"
 	v1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 
@@ -53,7 +58,7 @@ var _ = Describe("Tigera Secure Manager rendering tests", func() {
 
 	It("should render all resources for a default configuration", func() {
 		resources := renderObjects(instance, nil)
-		Expect(len(resources)).To(Equal(22))
+		Expect(len(resources)).To(Equal(24))
 
 		// Should render the correct resources.
 		expectedResources := []struct {
@@ -74,9 +79,11 @@ var _ = Describe("Tigera Secure Manager rendering tests", func() {
 			{name: "tigera-manager", ns: "tigera-manager", group: "", version: "v1", kind: "Service"},
 			{name: "tigera-ui-user", ns: "", group: "rbac.authorization.k8s.io", version: "v1", kind: "ClusterRole"},
 			{name: "tigera-network-admin", ns: "", group: "rbac.authorization.k8s.io", version: "v1", kind: "ClusterRole"},
+			{name: "tigera-compliance-auditor", ns: "", group: "rbac.authorization.k8s.io", version: "v1", kind: "ClusterRole"},
 			{name: render.VoltronTunnelSecretName, ns: "tigera-operator", group: "", version: "v1", kind: "Secret"},
 			{name: render.VoltronTunnelSecretName, ns: "tigera-manager", group: "", version: "v1", kind: "Secret"},
 			{name: "tigera-manager", ns: "tigera-manager", group: "", version: "v1", kind: "Deployment"},
+			{name: "tigera-manager", ns: "tigera-manager", group: "policy", version: "v1beta1", kind: "PodDisruptionBudget"},
 		}
 
 		i := 0
@@ -86,14 +93,14 @@ var _ = Describe("Tigera Secure Manager rendering tests", func() {
 		}
 	})
 
-	It("should ensure cnx policy recommendation support is always set to true", func() {
+	It("should enable cnx policy recommendation support by default", func() {
 		resources := renderObjects(instance, nil)
-		Expect(len(resources)).To(Equal(22))
+		Expect(len(resources)).To(Equal(24))
 
 		// Should render the correct resource based on test case.
 		Expect(GetResource(resources, "tigera-manager", "tigera-manager", "", "v1", "Deployment")).ToNot(BeNil())
 
-		d := resources[13].(*v1.Deployment)
+		d := resources[14].(*v1.Deployment)
 
 		Expect(len(d.Spec.Template.Spec.Containers)).To(Equal(3))
 		Expect(d.Spec.Template.Spec.Containers[0].Name).To(Equal("tigera-manager"))
@@ -101,6 +108,73 @@ var _ = Describe("Tigera Secure Manager rendering tests", func() {
 		Expect(d.Spec.Template.Spec.Containers[0].Env[8].Value).To(Equal("true"))
 	})
 
+	It("should configure the cluster name, features and log levels", func() {
+		disabled := false
+		instance.Spec.ClusterName = "team-a"
+		instance.Spec.Features = &operator.ManagerFeatures{
+			PolicyRecommendation:   &disabled,
+			ApplicationLayerPolicy: &disabled,
+		}
+		instance.Spec.LogLevels = &operator.ManagerLogLevels{
+			Manager: operator.LogLevelError,
+			Proxy:   operator.LogLevelDebug,
+			ESProxy: operator.LogLevelWarning,
+		}
+		resources := renderObjects(instance, nil)
+
+		d := GetResource(resources, "tigera-manager", "tigera-manager", "", "v1", "Deployment").(*v1.Deployment)
+		manager := d.Spec.Template.Spec.Containers[0]
+		ExpectEnv(manager.Env, "CNX_CLUSTER_NAME", "team-a")
+		ExpectEnv(manager.Env, "CNX_POLICY_RECOMMENDATION_SUPPORT", "false")
+		ExpectEnv(manager.Env, "CNX_ALP_SUPPORT", "false")
+		ExpectEnv(manager.Env, "CNX_LOG_LEVEL", "error")
+
+		esProxy := d.Spec.Template.Spec.Containers[1]
+		Expect(esProxy.Name).To(Equal("tigera-es-proxy"))
+		ExpectEnv(esProxy.Env, "LOG_LEVEL", "warning")
+
+		voltron := d.Spec.Template.Spec.Containers[2]
+		ExpectEnv(voltron.Env, "VOLTRON_LOGLEVEL", "debug")
+	})
+
+	It("should only proxy Kibana when it is deployed", func() {
+		resources := renderObjects(instance, nil)
+		d := GetResource(resources, "tigera-manager", "tigera-manager", "", "v1", "Deployment").(*v1.Deployment)
+		ExpectEnv(d.Spec.Template.Spec.Containers[0].Env, "CNX_ELASTICSEARCH_KIBANA_URL", "/tigera-kibana")
+		ExpectEnv(d.Spec.Template.Spec.Containers[2].Env, "VOLTRON_KIBANA_BASE_PATH", "/tigera-kibana/")
+
+		esConfig := render.NewElasticsearchClusterConfig("clusterTestName", 1, 1)
+		esConfig.DisableKibana()
+		component, err := render.Manager(instance, nil, nil, esConfig, nil, nil, false, "", nil, nil, nil, false, nil, "")
+		Expect(err).NotTo(HaveOccurred())
+		d = GetResource(component.Objects(), "tigera-manager", "tigera-manager", "", "v1", "Deployment").(*v1.Deployment)
+		for _, env := range append(d.Spec.Template.Spec.Containers[0].Env, d.Spec.Template.Spec.Containers[2].Env...) {
+			Expect(env.Name).NotTo(ContainSubstring("KIBANA"))
+		}
+		for _, volume := range d.Spec.Template.Spec.Volumes {
+			Expect(volume.Name).NotTo(Equal(render.KibanaPublicCertSecret))
+		}
+	})
+
+	It("should roll multiple replicas one at a time", func() {
+		resources := renderObjects(instance, nil)
+		d := GetResource(resources, "tigera-manager", "tigera-manager", "", "v1", "Deployment").(*v1.Deployment)
+		Expect(*d.Spec.Replicas).To(Equal(int32(1)))
+		Expect(d.Spec.Strategy.Type).To(Equal(v1.RecreateDeploymentStrategyType))
+
+		var replicas int32 = 3
+		instance.Spec.Replicas = &replicas
+		resources = renderObjects(instance, nil)
+		d = GetResource(resources, "tigera-manager", "tigera-manager", "", "v1", "Deployment").(*v1.Deployment)
+		Expect(*d.Spec.Replicas).To(Equal(int32(3)))
+		Expect(d.Spec.Strategy.Type).To(Equal(v1.RollingUpdateDeploymentStrategyType))
+		Expect(d.Spec.Strategy.RollingUpdate.MaxUnavailable.IntValue()).To(Equal(1))
+
+		pdb := GetResource(resources, "tigera-manager", "tigera-manager", "policy", "v1beta1", "PodDisruptionBudget").(*policyv1beta1.PodDisruptionBudget)
+		Expect(pdb.Spec.MaxUnavailable.IntValue()).To(Equal(1))
+		Expect(pdb.Spec.Selector.MatchLabels).To(Equal(map[string]string{"k8s-app": "tigera-manager"}))
+	})
+
 	It("should render OIDC configmaps given OIDC configuration", func() {
 		instance.Spec.Auth.Type = operator.AuthTypeOIDC
 		oidcConfig := &corev1.ConfigMap{
@@ -112,10 +186,10 @@ var _ = Describe("Tigera Secure Manager rendering tests", func() {
 		}
 		// Should render the correct resource based on test case.
 		resources := renderObjects(instance, oidcConfig)
-		Expect(len(resources)).To(Equal(23))
+		Expect(len(resources)).To(Equal(25))
 
 		Expect(GetResource(resources, render.ManagerOIDCConfig, "tigera-manager", "", "v1", "ConfigMap")).ToNot(BeNil())
-		d := resources[14].(*v1.Deployment)
+		d := resources[15].(*v1.Deployment)
 
 		Expect(d.Spec.Template.Spec.Containers[0].Env).To(ContainElement(oidcEnvVar))
 
@@ -140,29 +214,186 @@ var _ = Describe("Tigera Secure Manager rendering tests", func() {
 
 		// Should render the correct resource based on test case.
 		resources := renderObjects(instance, nil)
-		Expect(len(resources)).To(Equal(22))
-		d := resources[13].(*v1.Deployment)
+		Expect(len(resources)).To(Equal(24))
+		d := resources[14].(*v1.Deployment)
 		// tigera-manager volumes/volumeMounts checks.
 		Expect(len(d.Spec.Template.Spec.Volumes)).To(Equal(4))
 		Expect(d.Spec.Template.Spec.Containers[0].Env).To(ContainElement(oidcEnvVar))
 		Expect(len(d.Spec.Template.Spec.Containers[0].VolumeMounts)).To(Equal(1))
 	})
 
+	It("should redirect users back to the external URL of the manager", func() {
+		redirectURI := func(auth operator.AuthType, url string) []corev1.EnvVar {
+			instance.Spec.Auth.Type = auth
+			component, err := render.Manager(instance, nil, nil, render.NewElasticsearchClusterConfig("clusterTestName", 1, 1),
+				nil, nil, false, "", nil, nil, nil, false, nil, url)
+			Expect(err).NotTo(HaveOccurred())
+			d := GetResource(component.Objects(), "tigera-manager", "tigera-manager", "", "v1", "Deployment").(*v1.Deployment)
+			var envs []corev1.EnvVar
+			for _, env := range d.Spec.Template.Spec.Containers[0].Env {
+				if strings.HasSuffix(env.Name, "_REDIRECT_URI") {
+					envs = append(envs, env)
+				}
+			}
+			return envs
+		}
+
+		Expect(redirectURI(operator.AuthTypeOIDC, "")).To(BeEmpty())
+		Expect(redirectURI(operator.AuthTypeOIDC, "https://manager.example.com")).To(ConsistOf(
+			corev1.EnvVar{Name: "CNX_WEB_OIDC_REDIRECT_URI", Value: "https://manager.example.com/login/oidc/callback"}))
+		Expect(redirectURI(operator.AuthTypeOAuth, "https://manager.example.com")).To(ConsistOf(
+			corev1.EnvVar{Name: "CNX_WEB_OAUTH_REDIRECT_URI", Value: "https://manager.example.com/login/oauth/callback"}))
+		Expect(redirectURI(operator.AuthTypeToken, "https://manager.example.com")).To(BeEmpty())
+	})
+
+	It("should authenticate OIDC users in the proxy when configured", func() {
+		instance.Spec.Auth = &operator.Auth{
+			Type:                     operator.AuthTypeOIDC,
+			Authority:                "https://example.com/dex",
+			ClientID:                 "tigera-manager",
+			Scopes:                   []string{"email", "groups"},
+			UsernamePrefix:           "oidc:",
+			GroupsClaim:              "groups",
+			GroupsPrefix:             "oidc:",
+			KubernetesAuthentication: operator.KubernetesAuthenticationProxy,
+		}
+		caBundle := &corev1.ConfigMap{
+			TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+			ObjectMeta: metav1.ObjectMeta{Name: render.ManagerOIDCCABundle, Namespace: render.OperatorNamespace()},
+			Data:       map[string]string{render.ManagerOIDCCABundleKey: "ca"},
+		}
+		component, err := render.Manager(instance, nil, nil, render.NewElasticsearchClusterConfig("clusterTestName", 1, 1),
+			nil, nil, false, "", nil, caBundle, nil, false, nil, "")
+		Expect(err).NotTo(HaveOccurred())
+		resources := component.Objects()
+
+		Expect(GetResource(resources, render.ManagerOIDCCABundle, render.ManagerNamespace, "", "v1", "ConfigMap")).ToNot(BeNil())
+
+		d := GetResource(resources, "tigera-manager", "tigera-manager", "", "v1", "Deployment").(*v1.Deployment)
+		ExpectEnv(d.Spec.Template.Spec.Containers[0].Env, "CNX_WEB_OIDC_SCOPES", "openid email groups")
+		ExpectEnv(d.Spec.Template.Spec.Containers[0].Env, "CNX_WEB_OIDC_USERNAME_CLAIM", "email")
+
+		voltron := d.Spec.Template.Spec.Containers[2]
+		ExpectEnv(voltron.Env, "VOLTRON_OIDC_AUTH_ENABLED", "true")
+		ExpectEnv(voltron.Env, "VOLTRON_OIDC_AUTH_ISSUER", "https://example.com/dex")
+		ExpectEnv(voltron.Env, "VOLTRON_OIDC_AUTH_USERNAME_PREFIX", "oidc:")
+		ExpectEnv(voltron.Env, "VOLTRON_OIDC_AUTH_GROUPS_CLAIM", "groups")
+		ExpectEnv(voltron.Env, "VOLTRON_OIDC_AUTH_CA_BUNDLE_PATH", "/certs/oidc/ca.crt")
+		Expect(voltron.VolumeMounts).To(ContainElement(corev1.VolumeMount{Name: render.ManagerOIDCCABundle, MountPath: "/certs/oidc"}))
+		Expect(d.Spec.Template.Annotations).To(HaveKey("hash.operator.tigera.io/oidc-ca-bundle"))
+
+		role := GetResource(resources, "tigera-manager-role", "", "rbac.authorization.k8s.io", "v1", "ClusterRole").(*rbacv1.ClusterRole)
+		Expect(role.Rules).To(ContainElement(rbacv1.PolicyRule{
+			APIGroups: []string{""},
+			Resources: []string{"users", "groups", "serviceaccounts"},
+			Verbs:     []string{"impersonate"},
+		}))
+	})
+
+	It("should expose the manager through an Ingress", func() {
+		instance.Spec.Exposure = &operator.ManagerExposure{
+			Type: operator.ManagerExposureTypeIngress,
+			Ingress: &operator.ManagerIngress{
+				Host:          "manager.example.com",
+				Class:         "nginx",
+				TLSSecretName: "manager-ingress-tls",
+			},
+		}
+		Expect(render.ManagerExternalURL(instance)).To(Equal("https://manager.example.com"))
+
+		resources := renderObjects(instance, nil)
+		ingress := GetResource(resources, "tigera-manager", "tigera-manager", "networking.k8s.io", "v1beta1", "Ingress").(*networkingv1beta1.Ingress)
+		Expect(ingress.Annotations).To(HaveKeyWithValue("kubernetes.io/ingress.class", "nginx"))
+		Expect(ingress.Annotations).To(HaveKeyWithValue("nginx.ingress.kubernetes.io/backend-protocol", "HTTPS"))
+		Expect(ingress.Spec.Rules).To(HaveLen(1))
+		Expect(ingress.Spec.Rules[0].Host).To(Equal("manager.example.com"))
+		Expect(ingress.Spec.Rules[0].HTTP.Paths[0].Backend.ServiceName).To(Equal("tigera-manager"))
+		Expect(ingress.Spec.TLS).To(Equal([]networkingv1beta1.IngressTLS{{Hosts: []string{"manager.example.com"}, SecretName: "manager-ingress-tls"}}))
+	})
+
+	It("should expose the manager through an OpenShift Route", func() {
+		instance.Spec.Exposure = &operator.ManagerExposure{Type: operator.ManagerExposureTypeRoute}
+		Expect(render.ManagerExternalURL(instance)).To(Equal(""))
+
+		resources := renderObjects(instance, nil)
+		route := GetResource(resources, "tigera-manager", "tigera-manager", "route.openshift.io", "v1", "Route").(*routev1.Route)
+		Expect(route.Spec.Host).To(Equal(""))
+		Expect(route.Spec.To.Name).To(Equal("tigera-manager"))
+		Expect(route.Spec.TLS.Termination).To(Equal(routev1.TLSTerminationPassthrough))
+	})
+
+	It("should expose the manager through a NodePort service", func() {
+		var nodePort int32 = 30443
+		instance.Spec.Exposure = &operator.ManagerExposure{Type: operator.ManagerExposureTypeNodePort, NodePort: &nodePort}
+
+		resources := renderObjects(instance, nil)
+		svc := GetResource(resources, "tigera-manager", "tigera-manager", "", "v1", "Service").(*corev1.Service)
+		Expect(svc.Spec.Type).To(Equal(corev1.ServiceTypeNodePort))
+		Expect(svc.Spec.Ports[0].NodePort).To(Equal(nodePort))
+	})
+
+	It("should render role bindings for the configured profiles", func() {
+		instance.Spec.Auth = &operator.Auth{Type: operator.AuthTypeOIDC, GroupsPrefix: "oidc:"}
+		instance.Spec.RoleBindings = []operator.UIRoleBinding{
+			{Name: "admins", Profile: operator.UIRoleProfileNetworkAdmin, Groups: []string{"netops"}},
+			{Name: "auditors", Profile: operator.UIRoleProfileComplianceAuditor, Users: []string{"jane@example.com"}},
+			{Name: "security", Profile: operator.UIRoleProfileTierEditor, Groups: []string{"secops"}, Tiers: []string{"security", "platform"}},
+		}
+		resources := renderObjects(instance, nil)
+
+		admins := GetResource(resources, "tigera-ui-admins", "", "rbac.authorization.k8s.io", "v1", "ClusterRoleBinding").(*rbacv1.ClusterRoleBinding)
+		Expect(admins.Labels).To(HaveKeyWithValue(render.UIRoleBindingLabel, "admins"))
+		Expect(admins.RoleRef.Name).To(Equal(render.TigeraNetworkAdminClusterRoleName))
+		Expect(admins.Subjects).To(ConsistOf(rbacv1.Subject{Kind: "Group", APIGroup: "rbac.authorization.k8s.io", Name: "oidc:netops"}))
+
+		auditors := GetResource(resources, "tigera-ui-auditors", "", "rbac.authorization.k8s.io", "v1", "ClusterRoleBinding").(*rbacv1.ClusterRoleBinding)
+		Expect(auditors.RoleRef.Name).To(Equal(render.TigeraComplianceAuditorClusterRoleName))
+		Expect(auditors.Subjects).To(ConsistOf(rbacv1.Subject{Kind: "User", APIGroup: "rbac.authorization.k8s.io", Name: "jane@example.com"}))
+		auditorsUser := GetResource(resources, "tigera-ui-auditors-user", "", "rbac.authorization.k8s.io", "v1", "ClusterRoleBinding").(*rbacv1.ClusterRoleBinding)
+		Expect(auditorsUser.RoleRef.Name).To(Equal(render.TigeraUIUserClusterRoleName))
+
+		security := GetResource(resources, "tigera-ui-security", "", "rbac.authorization.k8s.io", "v1", "ClusterRoleBinding").(*rbacv1.ClusterRoleBinding)
+		Expect(security.RoleRef.Name).To(Equal(render.TigeraUIUserClusterRoleName))
+		for _, tier := range []string{"security", "platform"} {
+			role := GetResource(resources, "tigera-tier-editor-"+tier, "", "rbac.authorization.k8s.io", "v1", "ClusterRole").(*rbacv1.ClusterRole)
+			Expect(role.Labels).To(HaveKeyWithValue(render.UITierLabel, tier))
+			Expect(role.Rules[0].ResourceNames).To(Equal([]string{tier}))
+			Expect(role.Rules[1].Resources).To(ContainElement("tier.networkpolicies"))
+			Expect(role.Rules[1].ResourceNames).To(Equal([]string{tier + ".*"}))
+
+			binding := GetResource(resources, "tigera-ui-security-"+tier, "", "rbac.authorization.k8s.io", "v1", "ClusterRoleBinding").(*rbacv1.ClusterRoleBinding)
+			Expect(binding.RoleRef.Name).To(Equal("tigera-tier-editor-" + tier))
+		}
+
+		Expect(render.UIRoleBindingNames(instance)).To(Equal(map[string]bool{
+			"tigera-ui-admins":            true,
+			"tigera-ui-auditors":          true,
+			"tigera-ui-auditors-user":     true,
+			"tigera-ui-security":          true,
+			"tigera-ui-security-security": true,
+			"tigera-ui-security-platform": true,
+		}))
+		Expect(render.UITierRoleNames(instance)).To(Equal(map[string]bool{
+			"tigera-tier-editor-security": true,
+			"tigera-tier-editor-platform": true,
+		}))
+	})
+
 	It("should render multicluster settings properly", func() {
 		resources := renderObjects(instance, nil)
-		Expect(len(resources)).To(Equal(22))
+		Expect(len(resources)).To(Equal(24))
 
 		By("creating a valid self-signed cert")
 		// Use the x509 package to validate that the cert was signed with the privatekey
-		validateSecret(resources[11].(*corev1.Secret))
 		validateSecret(resources[12].(*corev1.Secret))
+		validateSecret(resources[13].(*corev1.Secret))
 
 		By("configuring the manager deployment")
-		manager := resources[13].(*v1.Deployment).Spec.Template.Spec.Containers[0]
+		manager := resources[14].(*v1.Deployment).Spec.Template.Spec.Containers[0]
 		Expect(manager.Name).To(Equal("tigera-manager"))
 		ExpectEnv(manager.Env, "ENABLE_MULTI_CLUSTER_MANAGEMENT", "true")
 
-		voltron := resources[13].(*v1.Deployment).Spec.Template.Spec.Containers[2]
+		voltron := resources[14].(*v1.Deployment).Spec.Template.Spec.Containers[2]
 		Expect(voltron.Name).To(Equal("tigera-voltron"))
 		ExpectEnv(voltron.Env, "VOLTRON_ENABLE_MULTI_CLUSTER_MANAGEMENT", "true")
 	})
@@ -216,8 +447,11 @@ func renderObjects(instance *operator.Manager, oidcConfig *corev1.ConfigMap) []r
 		false,
 		"",
 		oidcConfig,
+		nil,
+		nil,
 		true,
-		nil)
+		nil,
+		"")
 	Expect(err).To(BeNil(), "Expected Manager to create successfully %s", err)
 	resources := component.Objects()
 	return resources
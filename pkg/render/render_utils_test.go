@@ -29,8 +29,8 @@ import (
 
 func ExpectResource(resource runtime.Object, name, ns, group, version, kind string) {
 	gvk := schema.GroupVersionKind{Group: group, Version: version, Kind: kind}
-	actualName := resource.(metav1.ObjectMetaAccessor).GetObjectMeta().GetName()
-	actualNS := resource.(metav1.ObjectMetaAccessor).GetObjectMeta().GetNamespace()
+	actualName := resource.(metav1.Object).GetName()
+	actualNS := resource.(metav1.Object).GetNamespace()
 	Expect(actualName).To(Equal(name), fmt.Sprintf("Rendered %s resource in namespace %s has wrong name", kind, ns))
 	Expect(actualNS).To(Equal(ns), fmt.Sprintf("Rendered resource %s/%s has wrong namespace", kind, name))
 	Expect(resource.GetObjectKind().GroupVersionKind()).To(Equal(gvk), fmt.Sprintf("Rendered resource %s does not match expected GVK", name))
@@ -39,8 +39,8 @@ func ExpectResource(resource runtime.Object, name, ns, group, version, kind stri
 func GetResource(resources []runtime.Object, name, ns, group, version, kind string) runtime.Object {
 	for _, resource := range resources {
 		gvk := schema.GroupVersionKind{Group: group, Version: version, Kind: kind}
-		if name == resource.(metav1.ObjectMetaAccessor).GetObjectMeta().GetName() &&
-			ns == resource.(metav1.ObjectMetaAccessor).GetObjectMeta().GetNamespace() &&
+		if name == resource.(metav1.Object).GetName() &&
+			ns == resource.(metav1.Object).GetNamespace() &&
 			gvk == resource.GetObjectKind().GroupVersionKind() {
 			return resource
 		}
@@ -58,7 +58,7 @@ func GetContainer(containers []v1.Container, name string) *v1.Container {
 }
 
 func ExpectGlobalReportType(resource runtime.Object, name string) {
-	actualName := resource.(metav1.ObjectMetaAccessor).GetObjectMeta().GetName()
+	actualName := resource.(metav1.Object).GetName()
 	Expect(actualName).To(Equal(name), "Rendered resource has wrong name")
 	gvk := schema.GroupVersionKind{Group: "projectcalico.org", Version: "v3", Kind: "GlobalReportType"}
 	Expect(resource.GetObjectKind().GroupVersionKind()).To(Equal(gvk), fmt.Sprintf("Rendered resource %s does not match expected GVK", name))
@@ -57,7 +57,7 @@ func template() *x509.Certificate {
 		DNSNames:              []string{VoltronDnsName},
 		Subject:               pkix.Name{CommonName: "tigera-voltron"},
 		NotBefore:             time.Now(),
-		// For now use the same lifetime as the other certs we generate. This will change when we implement rotation.
+		// The manager controller renews the CA ahead of expiry, see TunnelCertificateRenewBefore.
 		NotAfter: time.Now().AddDate(0, 0, crypto.DefaultCACertificateLifetimeInDays),
 		KeyUsage: x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign | x509.KeyUsageKeyEncipherment,
 	}